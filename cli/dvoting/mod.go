@@ -30,6 +30,8 @@ import (
 	"io"
 	"os"
 
+	"github.com/dedis/d-voting/cli/verify"
+	certificate "github.com/dedis/d-voting/services/certificate/tcosi/controller"
 	dkg "github.com/dedis/d-voting/services/dkg/pedersen/controller"
 	"github.com/dedis/d-voting/services/dkg/pedersen/json"
 	shuffle "github.com/dedis/d-voting/services/shuffle/neff/controller"
@@ -46,6 +48,7 @@ import (
 	mino "go.dedis.ch/dela/mino/minogrpc/controller"
 	proxy "go.dedis.ch/dela/mino/proxy/http/controller"
 
+	_ "github.com/dedis/d-voting/services/certificate/tcosi/json"
 	_ "github.com/dedis/d-voting/services/shuffle/neff/json"
 
 	gapi "go.dedis.ch/dela-apps/gapi/controller"
@@ -55,6 +58,7 @@ func main() {
 	err := run(os.Args)
 	if err != nil {
 		fmt.Printf("%+v\n", err)
+		os.Exit(1)
 	}
 }
 
@@ -76,15 +80,19 @@ func runWithCfg(args []string, cfg config) error {
 		db.NewController(),
 		mino.NewController(),
 		cosipbft.NewController(),
+		// the e-voting controller injects the serde context used by the
+		// contract and the services, it must start before them.
+		evoting.NewController(),
 		dkg.NewController(),
 		signed.NewManagerController(),
 		pool.NewController(),
 		access.NewController(),
 		proxy.NewController(),
 		shuffle.NewController(),
-		evoting.NewController(),
+		certificate.NewController(),
 		gapi.NewController(),
 		metrics.NewController(),
+		verify.NewController(),
 		postinstall.NewController(),
 	)
 
@@ -19,6 +19,13 @@ import (
 )
 
 func TestDvoting_Main(t *testing.T) {
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	// main exits with an error code on invalid arguments, like the flags of
+	// the test binary.
+	os.Args = []string{args[0], "--help"}
+
 	main()
 }
 
@@ -7,6 +7,8 @@ import (
 
 	evoting "github.com/dedis/d-voting/contracts/evoting/controller"
 	prom "github.com/dedis/d-voting/metrics/controller"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	tcosi "github.com/dedis/d-voting/services/certificate/tcosi/controller"
 	dkg "github.com/dedis/d-voting/services/dkg/pedersen/controller"
 	neff "github.com/dedis/d-voting/services/shuffle/neff/controller"
 	"go.dedis.ch/dela"
@@ -23,6 +25,13 @@ var proxyFac func(string) proxy.Proxy = http.NewHTTP
 const defaultProxyAddr = "127.0.0.1:0"
 const defaultPromAddr = "127.0.0.1:0"
 
+// proxyKeysFile is the file, in the config folder, where the keys of the web
+// backends are saved.
+const proxyKeysFile = "proxykeys.json"
+
+// defaultProxyKey is the name of the key given with the proxykey flag.
+const defaultProxyKey = "default"
+
 // NewController returns a new controller initializer
 func NewController() node.Initializer {
 	return controller{}
@@ -54,8 +63,10 @@ func (m controller) SetCommands(builder node.Builder) {
 			Required: false,
 		},
 		cli.StringFlag{
-			Name:     "proxykey",
-			Usage:    "the frontend public key that signs requests, hex encoded",
+			Name: "proxykey",
+			Usage: "the frontend public key that signs requests, hex encoded. " +
+				"It is added to the proxy keys as \"default\" if there is no key " +
+				"with that name yet",
 			Required: false,
 		},
 	)
@@ -88,6 +99,40 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to auto init shuffle: %v", err)
 	}
 
+	//
+	// Init the certificate
+	//
+
+	cinit := tcosi.InitAction{}
+
+	err = cinit.Execute(node.Context{
+		Injector: inj,
+		Flags: node.FlagSet{
+			"signer": filepath.Join(ctx.Path("config"), "private.key"),
+		},
+		Out: os.Stdout,
+	})
+
+	if err != nil {
+		return xerrors.Errorf("failed to auto init certificate: %v", err)
+	}
+
+	//
+	// Load the keys of the web backends
+	//
+
+	proxykeys, err := ptypes.LoadKeyRing(filepath.Join(ctx.Path("config"), proxyKeysFile))
+	if err != nil {
+		return xerrors.Errorf("failed to load proxy keys: %v", err)
+	}
+
+	err = addDefaultProxyKey(proxykeys, ctx.String("proxykey"))
+	if err != nil {
+		return xerrors.Errorf("failed to add proxy key: %v", err)
+	}
+
+	inj.Inject(proxykeys)
+
 	//
 	// Start the proxy server
 	//
@@ -120,8 +165,7 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 	err = eregister.Execute(node.Context{
 		Injector: inj,
 		Flags: node.FlagSet{
-			"signer":   filepath.Join(ctx.Path("config"), "private.key"),
-			"proxykey": ctx.String("proxykey"),
+			"signer": filepath.Join(ctx.Path("config"), "private.key"),
 		},
 		Out: os.Stdout,
 	})
@@ -160,6 +204,21 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to register neff handlers: %v", err)
 	}
 
+	//
+	// Register the Certificate proxy handlers
+	//
+
+	cregister := tcosi.RegisterHandlersAction{}
+	err = cregister.Execute(node.Context{
+		Injector: inj,
+		Flags:    ctx,
+		Out:      os.Stdout,
+	})
+
+	if err != nil {
+		return xerrors.Errorf("failed to register certificate handlers: %v", err)
+	}
+
 	//
 	// Start the Prometheus server
 	//
@@ -177,6 +236,43 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 	return nil
 }
 
+// addDefaultProxyKey adds the key given with the proxykey flag as the
+// "default" key, unless there is already a key with that name. A retired
+// default key is thus not trusted again after a restart.
+func addDefaultProxyKey(proxykeys *ptypes.KeyRing, proxykeyHex string) error {
+	if proxykeyHex == "" {
+		return nil
+	}
+
+	proxykey, err := ptypes.DecodeProxyKey(proxykeyHex)
+	if err != nil {
+		return xerrors.Errorf("failed to decode proxykey: %v", err)
+	}
+
+	for _, key := range proxykeys.Keys() {
+		if key.Name != defaultProxyKey {
+			continue
+		}
+
+		if !key.Key.Equal(proxykey) {
+			dela.Logger.Warn().Msgf("the proxykey flag is ignored, the %q key "+
+				"is already set to another key", defaultProxyKey)
+		}
+
+		return nil
+	}
+
+	err = proxykeys.Add(ptypes.ProxyKey{
+		Name: defaultProxyKey,
+		Key:  proxykey,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to add key: %v", err)
+	}
+
+	return nil
+}
+
 // OnStop implements node.Initializer.
 func (controller) OnStop(inj node.Injector) error {
 	return nil
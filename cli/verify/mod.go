@@ -0,0 +1,76 @@
+// Package verify implements the offline verification of an election record.
+// It does not need a running node.
+package verify
+
+import (
+	"io"
+	"os"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/record"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+	"golang.org/x/xerrors"
+)
+
+// NewController returns a new controller initializer
+func NewController() node.Initializer {
+	return controller{out: os.Stdout}
+}
+
+// controller is an initializer with the verify command.
+//
+// - implements node.Initializer
+type controller struct {
+	out io.Writer
+}
+
+// SetCommands implements node.Initializer.
+func (m controller) SetCommands(builder node.Builder) {
+	cmd := builder.SetCommand("verify")
+	cmd.SetDescription("verify an election record offline")
+	cmd.SetFlags(cli.StringFlag{
+		Name:     "record",
+		Usage:    "path to the election record",
+		Required: true,
+	})
+	cmd.SetAction(m.verify)
+}
+
+// OnStart implements node.Initializer.
+func (controller) OnStart(cli.Flags, node.Injector) error {
+	return nil
+}
+
+// OnStop implements node.Initializer.
+func (controller) OnStop(node.Injector) error {
+	return nil
+}
+
+// verify reads the record and verifies it. The error names the first step
+// that failed.
+func (m controller) verify(flags cli.Flags) error {
+	r, err := record.ReadFile(flags.String("record"))
+	if err != nil {
+		return xerrors.Errorf("failed to read record: %v", err)
+	}
+
+	// the record is verified in the format the node wrote it
+	ctx, err := evoting.NewSerdeContext(r.Format)
+	if err != nil {
+		return xerrors.Errorf("failed to create serde context: %v", err)
+	}
+
+	err = record.Verify(ctx, r, record.NewFormFactory(), m.out)
+	if err != nil {
+		var stepErr record.StepError
+		if xerrors.As(err, &stepErr) {
+			return xerrors.Errorf("verification failed at step %q: %v",
+				stepErr.Step, stepErr.Err)
+		}
+
+		return xerrors.Errorf("verification failed: %v", err)
+	}
+
+	return nil
+}
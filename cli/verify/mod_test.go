@@ -0,0 +1,102 @@
+package verify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/record"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+)
+
+func TestController_SetCommands(t *testing.T) {
+	call := &fake.Call{}
+	NewController().SetCommands(fakeBuilder{call: call})
+
+	require.Equal(t, 4, call.Len())
+	require.Equal(t, "verify", call.Get(0, 0))
+	require.Equal(t, "verify an election record offline", call.Get(1, 0))
+	require.Len(t, call.Get(2, 0), 1)
+}
+
+func TestController_Verify(t *testing.T) {
+	c := controller{out: new(bytes.Buffer)}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "record.json")
+
+	err := c.verify(node.FlagSet{"record": path})
+	require.ErrorContains(t, err, "failed to read record: failed to open record")
+
+	err = os.WriteFile(path, []byte(`{"Type": "d-voting/election-record", "Version": 0}`), 0644)
+	require.NoError(t, err)
+
+	err = c.verify(node.FlagSet{"record": path})
+	require.EqualError(t, err, "failed to read record: unsupported record version: 0")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	err = record.Record{Type: record.Type, Version: record.Version, Format: "XML"}.Write(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	err = c.verify(node.FlagSet{"record": path})
+	require.EqualError(t, err, "failed to create serde context: unknown serde format: \"XML\"")
+
+	f, err = os.Create(path)
+	require.NoError(t, err)
+
+	err = record.Record{Type: record.Type, Version: record.Version, Format: "JSON", Form: []byte("{}")}.Write(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	err = c.verify(node.FlagSet{"record": path})
+	require.ErrorContains(t, err, "verification failed at step \"form\"")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeCommandBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeCommandBuilder) SetSubCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return b
+}
+
+func (b fakeCommandBuilder) SetDescription(value string) {
+	b.call.Add(value)
+}
+
+func (b fakeCommandBuilder) SetFlags(flags ...cli.Flag) {
+	b.call.Add(flags)
+}
+
+func (b fakeCommandBuilder) SetAction(a cli.Action) {
+	b.call.Add(a)
+}
+
+type fakeBuilder struct {
+	call *fake.Call
+}
+
+func (b fakeBuilder) SetCommand(name string) cli.CommandBuilder {
+	b.call.Add(name)
+	return fakeCommandBuilder(b)
+}
+
+func (b fakeBuilder) SetStartFlags(flags ...cli.Flag) {
+	b.call.Add(flags)
+}
+
+func (b fakeBuilder) MakeAction(tmpl node.ActionTemplate) cli.Action {
+	b.call.Add(tmpl)
+	return nil
+}
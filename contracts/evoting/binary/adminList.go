@@ -0,0 +1,45 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// adminListFormat defines how the list of admins is encoded/decoded using the
+// binary format.
+//
+// - implements serde.FormatEngine
+type adminListFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (adminListFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	m, ok := msg.(types.AdminList)
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+
+	var e encoder
+
+	e.version()
+	e.ints(m.AdminList)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (adminListFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+	d.version()
+
+	adminList := types.AdminList{
+		AdminList: d.ints(),
+	}
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode admin list: %v", err)
+	}
+
+	return adminList, nil
+}
@@ -0,0 +1,43 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// ciphervoteFormat is the binary format to encode and decode a ciphervote.
+//
+// - implements serde.FormatEngine
+type ciphervoteFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (ciphervoteFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	ciphervote, ok := msg.(types.Ciphervote)
+	if !ok {
+		return nil, xerrors.Errorf("unexpected type: %T", msg)
+	}
+
+	var e encoder
+
+	err := e.ciphervote(ciphervote)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode ciphervote: %v", err)
+	}
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (ciphervoteFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+
+	ciphervote := d.ciphervote()
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode ciphervote: %v", err)
+	}
+
+	return ciphervote, nil
+}
@@ -0,0 +1,340 @@
+package binary
+
+import (
+	"encoding/binary"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+var suite = suites.MustFind("Ed25519")
+
+// encoder appends the fields of a message to a buffer. Integers are varints
+// and buffers, strings and lists are prefixed by their length.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uvarint(v uint64) {
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// version writes the schema version of a stored record. The binary format
+// came with the version 1 of the records, so it has no older version to
+// migrate from.
+func (e *encoder) version() {
+	e.uvarint(types.SchemaVersion)
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.uvarint(1)
+	} else {
+		e.uvarint(0)
+	}
+}
+
+func (e *encoder) varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *encoder) bytes(buf []byte) {
+	e.uvarint(uint64(len(buf)))
+	e.buf = append(e.buf, buf...)
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) bytesList(list [][]byte) {
+	e.uvarint(uint64(len(list)))
+	for _, buf := range list {
+		e.bytes(buf)
+	}
+}
+
+func (e *encoder) strings(list []string) {
+	e.uvarint(uint64(len(list)))
+	for _, s := range list {
+		e.string(s)
+	}
+}
+
+func (e *encoder) ints(list []int) {
+	e.uvarint(uint64(len(list)))
+	for _, v := range list {
+		e.varint(int64(v))
+	}
+}
+
+func (e *encoder) point(p kyber.Point) error {
+	buf, err := p.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("failed to marshal point: %v", err)
+	}
+
+	e.bytes(buf)
+
+	return nil
+}
+
+func (e *encoder) ciphervote(ciphervote types.Ciphervote) error {
+	e.uvarint(uint64(len(ciphervote)))
+
+	for _, egpair := range ciphervote {
+		err := e.point(egpair.K)
+		if err != nil {
+			return xerrors.Errorf("failed to encode K: %v", err)
+		}
+
+		err = e.point(egpair.C)
+		if err != nil {
+			return xerrors.Errorf("failed to encode C: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) ciphervotes(ciphervotes []types.Ciphervote) error {
+	e.uvarint(uint64(len(ciphervotes)))
+
+	for i, ciphervote := range ciphervotes {
+		err := e.ciphervote(ciphervote)
+		if err != nil {
+			return xerrors.Errorf("failed to encode ciphervote %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *encoder) pubshares(unit types.PubsharesUnit) error {
+	e.uvarint(uint64(len(unit)))
+
+	for _, ballotShares := range unit {
+		e.uvarint(uint64(len(ballotShares)))
+
+		for _, pubshare := range ballotShares {
+			err := e.point(pubshare)
+			if err != nil {
+				return xerrors.Errorf("failed to encode pubshare: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decoder reads the fields written by an encoder. The first error is kept and
+// makes the following reads return zero values, so that it is checked once
+// at the end with done.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = xerrors.Errorf(format, args...)
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("invalid varint")
+		return 0
+	}
+
+	d.data = d.data[n:]
+
+	return v
+}
+
+// version reads the schema version of a stored record, which the caller uses
+// to read the fields added by the later versions.
+func (d *decoder) version() uint64 {
+	v := d.uvarint()
+	if d.err == nil && (v < 1 || v > types.SchemaVersion) {
+		d.fail("unsupported version: %d", v)
+	}
+
+	return v
+}
+
+func (d *decoder) bool() bool {
+	return d.uvarint() != 0
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail("invalid varint")
+		return 0
+	}
+
+	d.data = d.data[n:]
+
+	return v
+}
+
+// length reads the length of a buffer or of a list. As every element takes at
+// least one byte, a length larger than the remaining data is rejected before
+// anything is allocated.
+func (d *decoder) length() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail("length %d exceeds the %d remaining bytes", n, len(d.data))
+		return 0
+	}
+
+	return int(n)
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+
+	buf := make([]byte, n)
+	copy(buf, d.data)
+	d.data = d.data[n:]
+
+	return buf
+}
+
+func (d *decoder) string() string {
+	n := d.length()
+
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+
+	return s
+}
+
+func (d *decoder) bytesList() [][]byte {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+
+	list := make([][]byte, n)
+	for i := range list {
+		list[i] = d.bytes()
+	}
+
+	return list
+}
+
+func (d *decoder) strings() []string {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+
+	list := make([]string, n)
+	for i := range list {
+		list[i] = d.string()
+	}
+
+	return list
+}
+
+func (d *decoder) ints() []int {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+
+	list := make([]int, n)
+	for i := range list {
+		list[i] = int(d.varint())
+	}
+
+	return list
+}
+
+func (d *decoder) point() kyber.Point {
+	buf := d.bytes()
+	if d.err != nil {
+		return nil
+	}
+
+	p := suite.Point()
+
+	err := p.UnmarshalBinary(buf)
+	if err != nil {
+		d.fail("failed to unmarshal point: %v", err)
+		return nil
+	}
+
+	return p
+}
+
+func (d *decoder) ciphervote() types.Ciphervote {
+	n := d.length()
+
+	ciphervote := make(types.Ciphervote, n)
+	for i := range ciphervote {
+		ciphervote[i] = types.EGPair{
+			K: d.point(),
+			C: d.point(),
+		}
+	}
+
+	return ciphervote
+}
+
+func (d *decoder) ciphervotes() []types.Ciphervote {
+	n := d.length()
+
+	ciphervotes := make([]types.Ciphervote, n)
+	for i := range ciphervotes {
+		ciphervotes[i] = d.ciphervote()
+	}
+
+	return ciphervotes
+}
+
+func (d *decoder) pubshares() types.PubsharesUnit {
+	n := d.length()
+
+	unit := make(types.PubsharesUnit, n)
+	for i := range unit {
+		unit[i] = make([]types.Pubshare, d.length())
+
+		for j := range unit[i] {
+			unit[i][j] = d.point()
+		}
+	}
+
+	return unit
+}
+
+// done returns the first error of the decoder, or an error if some bytes were
+// not read.
+func (d *decoder) done() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if len(d.data) != 0 {
+		return xerrors.Errorf("%d trailing bytes", len(d.data))
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	ctypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// formFormat defines how the form messages are encoded/decoded using the
+// binary format. The fields are written in the order of the JSON format.
+//
+// - implements serde.FormatEngine
+type formFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (formFormat) Encode(ctx serde.Context, message serde.Message) ([]byte, error) {
+	m, ok := message.(types.Form)
+	if !ok {
+		return nil, xerrors.Errorf("unknown format: %T", message)
+	}
+
+	// the binary format has no version 0, such a form is migrated first
+	if m.Legacy != nil {
+		return nil, xerrors.Errorf("form of version 0 must be migrated")
+	}
+
+	configuration, err := ctx.Marshal(m.Configuration)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal configuration: %v", err)
+	}
+
+	var pubkey []byte
+
+	if m.Pubkey != nil {
+		pubkey, err = m.Pubkey.MarshalBinary()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshall public key: %v", err)
+		}
+	}
+
+	rosterBuf, err := m.Roster.Serialize(sjson.NewContext())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize roster: %v", err)
+	}
+
+	var e encoder
+
+	e.version()
+	e.bytes(configuration)
+	e.string(m.FormID)
+	e.uvarint(uint64(m.Status))
+	e.bytes(pubkey)
+	e.varint(int64(m.BallotSize))
+	e.bytesList(m.SuffragiaStoreKeys)
+	e.uvarint(uint64(m.BallotCount))
+	e.uvarint(uint64(m.VoterCount))
+	e.bytesList(m.SuffragiaHashes)
+
+	e.uvarint(uint64(m.BallotsTree.Size))
+	e.bytesList(m.BallotsTree.Frontier)
+	e.bytes(m.BallotsTree.Root)
+
+	e.bytesList(m.ShuffleStoreKeys)
+	e.bytesList(m.ShuffleHashes)
+	e.bytesList(m.ShufflerPublicKeys)
+	e.varint(int64(m.ShuffleThreshold))
+
+	e.uvarint(uint64(len(m.PubsharesUnits.Pubshares)))
+	for i, unit := range m.PubsharesUnits.Pubshares {
+		err = e.pubshares(unit)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encode submission %d of pubShares: %v", i, err)
+		}
+	}
+	e.bytesList(m.PubsharesUnits.PubKeys)
+	e.ints(m.PubsharesUnits.Indexes)
+
+	e.bytesList(m.ResultsStoreKeys)
+	e.bytesList(m.ResultsHashes)
+	e.uvarint(uint64(m.ResultsCount))
+	e.uvarint(uint64(m.ResultsPerBatch))
+	e.bytes(m.Certificate)
+	e.bytes(rosterBuf)
+	e.ints(m.Owners)
+	e.ints(m.Voters)
+	e.bool(m.Imported)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (formFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+	version := d.version()
+
+	configuration := d.bytes()
+	formID := d.string()
+	status := d.uvarint()
+	pubkey := d.bytes()
+	ballotSize := d.varint()
+	suffragias := d.bytesList()
+	ballotCount := d.uvarint()
+	voterCount := d.uvarint()
+	suffragiaHashes := d.bytesList()
+
+	ballotsTree := types.BallotsTree{
+		Size:     uint32(d.uvarint()),
+		Frontier: d.bytesList(),
+		Root:     d.bytes(),
+	}
+
+	shuffles := d.bytesList()
+	shuffleHashes := d.bytesList()
+	shufflerPublicKeys := d.bytesList()
+	shuffleThreshold := d.varint()
+
+	pubshares := make([]types.PubsharesUnit, d.length())
+	for i := range pubshares {
+		pubshares[i] = d.pubshares()
+	}
+
+	units := types.PubsharesUnits{
+		Pubshares: pubshares,
+		PubKeys:   d.bytesList(),
+		Indexes:   d.ints(),
+	}
+
+	results := d.bytesList()
+	resultsHashes := d.bytesList()
+	resultsCount := d.uvarint()
+	resultsPerBatch := d.uvarint()
+	certificate := d.bytes()
+	rosterBuf := d.bytes()
+	owners := d.ints()
+	voters := d.ints()
+
+	// Imported was added in version 2
+	imported := false
+	if version >= 2 {
+		imported = d.bool()
+	}
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode form: %v", err)
+	}
+
+	var conf types.Configuration
+
+	err = ctx.Unmarshal(configuration, &conf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal configuration: %v", err)
+	}
+
+	var pubKey kyber.Point
+
+	if pubkey != nil {
+		pubKey = suite.Point()
+		err = pubKey.UnmarshalBinary(pubkey)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal pubkey: %v", err)
+		}
+	}
+
+	fac := ctx.GetFactory(ctypes.RosterKey{})
+	rosterFac, ok := fac.(authority.Factory)
+	if !ok {
+		return nil, xerrors.Errorf("failed to get roster factory: %T", fac)
+	}
+
+	roster, err := rosterFac.AuthorityOf(sjson.NewContext(), rosterBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode roster: %v", err)
+	}
+
+	return types.Form{
+		Configuration:      conf,
+		FormID:             formID,
+		Status:             types.Status(status),
+		Pubkey:             pubKey,
+		BallotSize:         int(ballotSize),
+		SuffragiaStoreKeys: suffragias,
+		SuffragiaHashes:    suffragiaHashes,
+		BallotCount:        uint32(ballotCount),
+		VoterCount:         uint32(voterCount),
+		BallotsTree:        ballotsTree,
+		ShuffleStoreKeys:   shuffles,
+		ShuffleHashes:      shuffleHashes,
+		ShufflerPublicKeys: shufflerPublicKeys,
+		ShuffleThreshold:   int(shuffleThreshold),
+		PubsharesUnits:     units,
+		ResultsStoreKeys:   results,
+		ResultsHashes:      resultsHashes,
+		ResultsCount:       uint32(resultsCount),
+		ResultsPerBatch:    uint32(resultsPerBatch),
+		Certificate:        certificate,
+		Roster:             roster,
+		Owners:             owners,
+		Voters:             voters,
+		Imported:           imported,
+	}, nil
+}
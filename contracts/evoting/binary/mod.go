@@ -0,0 +1,59 @@
+// Package binary implements a compact binary format for the messages of the
+// e-voting smart contract. Kyber points are written as their marshalled bytes
+// instead of being base64 encoded in JSON, which makes the forms, the ballots
+// and the transactions smaller and faster to parse.
+//
+// The values that have no binary layout, such as the configuration of a form
+// or the decrypted ballots, are marshalled in JSON by the context. The roster
+// of a form is a dela message and is always serialized in JSON.
+package binary
+
+import (
+	"encoding/json"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+)
+
+// Format is the name of the binary format.
+const Format = serde.Format("BINARY")
+
+// Register the binary formats for the form, ciphervote, and transaction
+
+func init() {
+	types.RegisterFormFormat(Format, formFormat{})
+	types.RegisterSuffragiaFormat(Format, suffragiaFormat{})
+	types.RegisterResultsFormat(Format, resultsFormat{})
+	types.RegisterVotersFormat(Format, votersFormat{})
+	types.RegisterShuffleFormat(Format, shuffleFormat{})
+	types.RegisterCiphervoteFormat(Format, ciphervoteFormat{})
+	types.RegisterTransactionFormat(Format, transactionFormat{})
+	types.RegisterAdminListFormat(Format, adminListFormat{})
+}
+
+// NewContext returns a context for the binary format.
+func NewContext() serde.Context {
+	return serde.NewContext(contextEngine{})
+}
+
+// contextEngine is the context engine of the binary format. The format
+// engines write the messages themselves, the context only marshals the
+// values that have no binary layout, in JSON.
+//
+// - implements serde.ContextEngine
+type contextEngine struct{}
+
+// GetFormat implements serde.ContextEngine. It returns the binary format name.
+func (contextEngine) GetFormat() serde.Format {
+	return Format
+}
+
+// Marshal implements serde.ContextEngine.
+func (contextEngine) Marshal(m interface{}) ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// Unmarshal implements serde.ContextEngine.
+func (contextEngine) Unmarshal(data []byte, m interface{}) error {
+	return json.Unmarshal(data, m)
+}
@@ -0,0 +1,324 @@
+package binary
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+
+	// Register the JSON format to compare the sizes
+	_ "github.com/dedis/d-voting/contracts/evoting/json"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+	dfake "go.dedis.ch/dela/testing/fake"
+)
+
+var ctx = NewContext()
+
+func TestNewContext(t *testing.T) {
+	require.Equal(t, Format, ctx.GetFormat())
+
+	buf, err := ctx.Marshal(types.Title{En: "title"})
+	require.NoError(t, err)
+
+	var title types.Title
+
+	err = ctx.Unmarshal(buf, &title)
+	require.NoError(t, err)
+	require.Equal(t, "title", title.En)
+}
+
+func TestFormFormat(t *testing.T) {
+	rosterFac := authority.NewFactory(dfake.AddressFactory{}, bls.NewPublicKeyFactory())
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+
+	form := types.Form{
+		Configuration: types.Configuration{
+			Title:    types.Title{En: "title"},
+			Scaffold: []types.Subject{{ID: "aa", Title: types.Title{En: "subject"}}},
+		},
+		FormID:             "abcd",
+		Status:             types.Closed,
+		Pubkey:             suite.Point().Pick(suite.RandomStream()),
+		BallotSize:         29,
+		SuffragiaStoreKeys: [][]byte{{1}, {2}},
+		SuffragiaHashes:    [][]byte{{3}, {4}},
+		BallotCount:        3,
+		VoterCount:         2,
+		BallotsTree:        types.BallotsTree{Size: 3, Frontier: [][]byte{{5}, {6}}, Root: []byte{7}},
+		ShuffleStoreKeys:   [][]byte{{8}},
+		ShuffleHashes:      [][]byte{{9}},
+		ShufflerPublicKeys: [][]byte{{10}},
+		ShuffleThreshold:   2,
+		PubsharesUnits: types.PubsharesUnits{
+			Pubshares: []types.PubsharesUnit{{{suite.Point().Pick(suite.RandomStream())}}},
+			PubKeys:   [][]byte{{11}},
+			Indexes:   []int{0},
+		},
+		ResultsStoreKeys: [][]byte{{12}},
+		ResultsHashes:    [][]byte{{13}},
+		ResultsCount:     3,
+		ResultsPerBatch:  100,
+		Certificate:      []byte{14},
+		Roster:           makeRoster(3),
+		Owners:           []int{123456},
+		Voters:           []int{234567, -1},
+	}
+
+	data, err := form.Serialize(ctx)
+	require.NoError(t, err)
+
+	jsonData, err := form.Serialize(sjson.NewContext())
+	require.NoError(t, err)
+	require.Less(t, len(data), len(jsonData))
+
+	msg, err := formFac.Deserialize(ctx, data)
+	require.NoError(t, err)
+
+	decoded := msg.(types.Form)
+	require.Equal(t, form.Configuration, decoded.Configuration)
+	require.Equal(t, form.FormID, decoded.FormID)
+	require.Equal(t, form.BallotsTree, decoded.BallotsTree)
+	require.Equal(t, form.PubsharesUnits.Indexes, decoded.PubsharesUnits.Indexes)
+	require.True(t, form.Pubkey.Equal(decoded.Pubkey))
+	require.Equal(t, 3, decoded.Roster.Len())
+	require.Equal(t, form.Voters, decoded.Voters)
+
+	requireSameEncoding(t, data, decoded)
+
+	// a form without public key nor shuffles
+	form = types.Form{FormID: "abcd", Roster: makeRoster(1)}
+
+	data, err = form.Serialize(ctx)
+	require.NoError(t, err)
+
+	msg, err = formFac.Deserialize(ctx, data)
+	require.NoError(t, err)
+	require.Nil(t, msg.(types.Form).Pubkey)
+
+	_, err = formFac.Deserialize(ctx, append(data, 0))
+	require.EqualError(t, err, "failed to decode: failed to decode form: 1 trailing bytes")
+
+	_, err = formFac.Deserialize(ctx, data[:len(data)-1])
+	require.EqualError(t, err, "failed to decode: failed to decode form: invalid varint")
+
+	_, err = formFac.Deserialize(ctx, []byte{types.SchemaVersion, 0xff, 0x01})
+	require.EqualError(t, err, "failed to decode: failed to decode form: "+
+		"length 255 exceeds the 0 remaining bytes")
+
+	_, err = formFac.Deserialize(ctx, append([]byte{types.SchemaVersion + 1}, data[1:]...))
+	require.EqualError(t, err, fmt.Sprintf("failed to decode: failed to decode form: "+
+		"unsupported version: %d", types.SchemaVersion+1))
+
+	// a form of version 1 has no Imported field
+	form.Imported = true
+
+	data, err = form.Serialize(ctx)
+	require.NoError(t, err)
+
+	msg, err = formFac.Deserialize(ctx, data)
+	require.NoError(t, err)
+	require.True(t, msg.(types.Form).Imported)
+
+	msg, err = formFac.Deserialize(ctx, append([]byte{1}, data[1:len(data)-1]...))
+	require.NoError(t, err)
+	require.False(t, msg.(types.Form).Imported)
+
+	_, err = formFormat{}.Encode(ctx, types.Ciphervote{})
+	require.EqualError(t, err, "unknown format: types.Ciphervote")
+}
+
+func TestCiphervoteFormat(t *testing.T) {
+	ciphervote := makeCiphervote(3)
+
+	data, err := ciphervote.Serialize(ctx)
+	require.NoError(t, err)
+
+	msg, err := types.CiphervoteFactory{}.Deserialize(ctx, data)
+	require.NoError(t, err)
+	require.Len(t, msg.(types.Ciphervote), 3)
+
+	requireSameEncoding(t, data, msg)
+
+	_, err = types.CiphervoteFactory{}.Deserialize(ctx, []byte{1, 1, 0})
+	require.ErrorContains(t, err, "failed to unmarshal point")
+}
+
+func TestStoredFormats(t *testing.T) {
+	snap := fake.NewSnapshot()
+
+	form := types.Form{FormID: "deadbeef"}
+
+	err := form.CastVote(ctx, snap, "user1", makeCiphervote(2))
+	require.NoError(t, err)
+
+	err = form.CastVote(ctx, snap, "user2", makeCiphervote(2))
+	require.NoError(t, err)
+
+	suff, err := form.Suffragia(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1", "user2"}, suff.VoterIDs)
+	require.Len(t, suff.Ciphervotes, 2)
+
+	shuffle := types.ShuffleInstance{
+		ShuffledBallots:   suff.Ciphervotes,
+		ShuffleProofs:     []byte("proof"),
+		ShufflerPublicKey: []byte("key"),
+	}
+
+	err = form.StoreShuffle(ctx, snap, shuffle)
+	require.NoError(t, err)
+
+	last, err := form.LastShuffle(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, shuffle.ShuffleProofs, last.ShuffleProofs)
+	require.Len(t, last.ShuffledBallots, 2)
+
+	// the shuffle was stored in binary, the JSON format can't read it
+	_, err = form.LastShuffle(sjson.NewContext(), snap)
+	require.ErrorContains(t, err, "couldn't unmarshal shuffle")
+
+	ballots := []types.Ballot{{TextResultIDs: []types.ID{"aa"}, TextResult: [][]string{{"text"}}}}
+
+	err = form.StoreResults(ctx, snap, ballots)
+	require.NoError(t, err)
+
+	results, err := form.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, ballots, results)
+}
+
+func TestTransactionFormat(t *testing.T) {
+	txFac := types.NewTransactionFactory(types.CiphervoteFactory{})
+
+	pubshare := suite.Point().Pick(suite.RandomStream())
+
+	txs := []serde.Message{
+		types.CreateForm{Configuration: types.Configuration{Title: types.Title{En: "title"}}, UserID: "123456"},
+		types.OpenForm{FormID: "abcd", UserID: "123456"},
+		types.CastVote{FormID: "abcd", VoterID: "234567", Ballot: makeCiphervote(2)},
+		types.CloseForm{FormID: "abcd", UserID: "123456"},
+		types.ShuffleBallots{
+			FormID:          "abcd",
+			Round:           1,
+			ShuffledBallots: []types.Ciphervote{makeCiphervote(1), makeCiphervote(1)},
+			RandomVector:    types.RandomVector{{1}, {2}},
+			Proof:           []byte("proof"),
+			Signature:       []byte("signature"),
+			PublicKey:       []byte("key"),
+			UserID:          "123456",
+		},
+		types.RegisterPubShares{
+			FormID:    "abcd",
+			Index:     2,
+			Pubshares: types.PubsharesUnit{{pubshare}, {pubshare}},
+			Signature: []byte("signature"),
+			PublicKey: []byte("key"),
+		},
+		types.CombineShares{FormID: "abcd", UserID: "123456"},
+		types.SubmitCertificate{FormID: "abcd", Certificate: []byte("certificate")},
+		types.CancelForm{FormID: "abcd", UserID: "123456"},
+		types.DeleteForm{FormID: "abcd", UserID: "123456"},
+		types.AddAdmin{TargetUserID: "234567", PerformingUserID: "123456"},
+		types.RemoveAdmin{TargetUserID: "234567", PerformingUserID: "123456"},
+		types.AddOwner{FormID: "abcd", TargetUserID: "234567", PerformingUserID: "123456"},
+		types.RemoveOwner{FormID: "abcd", TargetUserID: "234567", PerformingUserID: "123456"},
+		types.AddVoter{FormID: "abcd", TargetUserID: "234567", PerformingUserID: "123456"},
+		types.RemoveVoter{FormID: "abcd", TargetUserID: "234567", PerformingUserID: "123456"},
+		types.Migrate{UserID: "123456"},
+		types.ImportForm{Form: []byte("form"), Batches: [][]byte{{1}, {2}}, UserID: "123456"},
+		types.RepairForm{FormID: "abcd", UserID: "123456"},
+		types.CastVote{FormID: "abcd", VoterID: "234567", Ballot: makeCiphervote(2),
+			Signature: []byte("signature"), Nonce: 300},
+		types.RegisterVoterKey{FormID: "abcd", VoterID: "234567", PublicKey: []byte("key"),
+			Signature: []byte("signature"), Endorsement: []byte("endorsement")},
+		types.CastVotes{FormID: "abcd", Votes: []types.BatchVote{
+			{VoterID: "234567", Ballot: makeCiphervote(2)},
+			{VoterID: "345678", Ballot: makeCiphervote(2), Signature: []byte("signature"),
+				Nonce: 2},
+		}},
+	}
+
+	for _, tx := range txs {
+		data, err := tx.Serialize(ctx)
+		require.NoError(t, err)
+
+		msg, err := txFac.Deserialize(ctx, data)
+		require.NoError(t, err)
+		require.IsType(t, tx, msg)
+
+		requireSameEncoding(t, data, msg)
+
+		_, err = txFac.Deserialize(ctx, append(data, 0))
+		require.EqualError(t, err, "failed to decode: failed to decode transaction: 1 trailing bytes")
+	}
+
+	msg, err := txFac.Deserialize(ctx, mustSerialize(t, txs[0]))
+	require.NoError(t, err)
+	require.Equal(t, txs[0], msg)
+
+	_, err = txFac.Deserialize(ctx, nil)
+	require.EqualError(t, err, "failed to decode: empty transaction")
+
+	_, err = txFac.Deserialize(ctx, []byte{0xff})
+	require.EqualError(t, err, "failed to decode: unknown transaction type: 255")
+
+	_, err = txFac.Deserialize(ctx, []byte{createFormTag, 2, '{', '}'})
+	require.EqualError(t, err, "failed to decode: failed to decode transaction: invalid varint")
+
+	_, err = txFac.Deserialize(ctx, []byte{createFormTag, 1, '{', 0})
+	require.ErrorContains(t, err, "failed to decode: failed to unmarshal configuration")
+
+	_, err = transactionFormat{}.Encode(ctx, types.Ciphervote{})
+	require.EqualError(t, err, "unknown type: 'types.Ciphervote")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// requireSameEncoding checks that the decoded message is encoded back to the
+// same bytes. Points can't be compared directly as their internal
+// representation depends on how they were built.
+func requireSameEncoding(t *testing.T, data []byte, msg serde.Message) {
+	buf, err := msg.Serialize(ctx)
+	require.NoError(t, err)
+	require.Equal(t, data, buf)
+}
+
+func mustSerialize(t *testing.T, msg serde.Message) []byte {
+	data, err := msg.Serialize(ctx)
+	require.NoError(t, err)
+
+	return data
+}
+
+func makeCiphervote(n int) types.Ciphervote {
+	ciphervote := make(types.Ciphervote, n)
+
+	for i := range ciphervote {
+		ciphervote[i] = types.EGPair{
+			K: suite.Point().Pick(suite.RandomStream()),
+			C: suite.Point().Pick(suite.RandomStream()),
+		}
+	}
+
+	return ciphervote
+}
+
+func makeRoster(n int) authority.Authority {
+	addrs := make([]mino.Address, n)
+	pubkeys := make([]crypto.PublicKey, n)
+
+	for i := range addrs {
+		addrs[i] = dfake.NewAddress(i)
+		pubkeys[i] = bls.NewSigner().GetPublicKey()
+	}
+
+	return authority.New(addrs, pubkeys)
+}
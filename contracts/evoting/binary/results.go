@@ -0,0 +1,54 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// resultsFormat defines how the batches of decrypted ballots are
+// encoded/decoded using the binary format. The decrypted ballots have no
+// point to compact, they are marshalled by the context.
+//
+// - implements serde.FormatEngine
+type resultsFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (resultsFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	m, ok := msg.(types.ResultsBatch)
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+
+	ballots, err := ctx.Marshal(m.Ballots)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal ballots: %v", err)
+	}
+
+	var e encoder
+
+	e.bytes(ballots)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (resultsFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+
+	buf := d.bytes()
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode results batch: %v", err)
+	}
+
+	var batch types.ResultsBatch
+
+	err = ctx.Unmarshal(buf, &batch.Ballots)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal ballots: %v", err)
+	}
+
+	return batch, nil
+}
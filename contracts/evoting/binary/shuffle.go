@@ -0,0 +1,51 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// shuffleFormat defines how the shuffles are encoded/decoded using the binary
+// format.
+//
+// - implements serde.FormatEngine
+type shuffleFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (shuffleFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	m, ok := msg.(types.ShuffleInstance)
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+
+	var e encoder
+
+	err := e.ciphervotes(m.ShuffledBallots)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode shuffle: %v", err)
+	}
+
+	e.bytes(m.ShuffleProofs)
+	e.bytes(m.ShufflerPublicKey)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (shuffleFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+
+	shuffle := types.ShuffleInstance{
+		ShuffledBallots:   d.ciphervotes(),
+		ShuffleProofs:     d.bytes(),
+		ShufflerPublicKey: d.bytes(),
+	}
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode shuffle: %v", err)
+	}
+
+	return shuffle, nil
+}
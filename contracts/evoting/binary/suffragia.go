@@ -0,0 +1,54 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// suffragiaFormat defines how the batches of ballots are encoded/decoded
+// using the binary format.
+//
+// - implements serde.FormatEngine
+type suffragiaFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (suffragiaFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	m, ok := msg.(types.Suffragia)
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+
+	var e encoder
+
+	e.version()
+	e.strings(m.VoterIDs)
+
+	err := e.ciphervotes(m.Ciphervotes)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode suffragia: %v", err)
+	}
+
+	e.bytesList(m.Digests)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (suffragiaFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+	d.version()
+
+	suff := types.Suffragia{
+		VoterIDs:    d.strings(),
+		Ciphervotes: d.ciphervotes(),
+		Digests:     d.bytesList(),
+	}
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode suffragia: %v", err)
+	}
+
+	return suff, nil
+}
@@ -0,0 +1,376 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// The first byte of a transaction tells its type.
+const (
+	createFormTag byte = iota + 1
+	openFormTag
+	castVoteTag
+	closeFormTag
+	shuffleBallotsTag
+	registerPubSharesTag
+	combineSharesTag
+	submitCertificateTag
+	cancelFormTag
+	deleteFormTag
+	addAdminTag
+	removeAdminTag
+	addOwnerTag
+	removeOwnerTag
+	addVoterTag
+	removeVoterTag
+	migrateTag
+	importFormTag
+	repairFormTag
+	signedCastVoteTag
+	registerVoterKeyTag
+	castVotesTag
+)
+
+// transactionFormat defines the binary format of a transaction
+//
+// - implements serde.FormatEngine
+type transactionFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	var e encoder
+
+	switch t := msg.(type) {
+	case types.CreateForm:
+		configuration, err := ctx.Marshal(t.Configuration)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal configuration: %v", err)
+		}
+
+		e.buf = append(e.buf, createFormTag)
+		e.bytes(configuration)
+		e.string(t.UserID)
+	case types.OpenForm:
+		e.buf = append(e.buf, openFormTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.CastVote:
+		// the signed ballots have their own tag, so that the unsigned ones are
+		// encoded as before
+		if t.Signature == nil {
+			e.buf = append(e.buf, castVoteTag)
+		} else {
+			e.buf = append(e.buf, signedCastVoteTag)
+		}
+
+		e.string(t.FormID)
+		e.string(t.VoterID)
+
+		err := e.ciphervote(t.Ballot)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encode ballot: %v", err)
+		}
+
+		if t.Signature != nil {
+			e.bytes(t.Signature)
+			e.uvarint(t.Nonce)
+		}
+	case types.CastVotes:
+		e.buf = append(e.buf, castVotesTag)
+		e.string(t.FormID)
+		e.uvarint(uint64(len(t.Votes)))
+
+		for i, vote := range t.Votes {
+			e.string(vote.VoterID)
+
+			err := e.ciphervote(vote.Ballot)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to encode ballot %d: %v", i, err)
+			}
+
+			e.bytes(vote.Signature)
+			e.uvarint(vote.Nonce)
+		}
+	case types.CloseForm:
+		e.buf = append(e.buf, closeFormTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.ShuffleBallots:
+		e.buf = append(e.buf, shuffleBallotsTag)
+		e.string(t.FormID)
+		e.varint(int64(t.Round))
+
+		err := e.ciphervotes(t.ShuffledBallots)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encode shuffled ballots: %v", err)
+		}
+
+		e.bytesList(t.RandomVector)
+		e.bytes(t.Proof)
+		e.bytes(t.Signature)
+		e.bytes(t.PublicKey)
+		e.string(t.UserID)
+	case types.RegisterPubShares:
+		e.buf = append(e.buf, registerPubSharesTag)
+		e.string(t.FormID)
+		e.varint(int64(t.Index))
+
+		err := e.pubshares(t.Pubshares)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encode pubShares: %v", err)
+		}
+
+		e.bytes(t.Signature)
+		e.bytes(t.PublicKey)
+	case types.CombineShares:
+		e.buf = append(e.buf, combineSharesTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.SubmitCertificate:
+		e.buf = append(e.buf, submitCertificateTag)
+		e.string(t.FormID)
+		e.bytes(t.Certificate)
+	case types.CancelForm:
+		e.buf = append(e.buf, cancelFormTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.DeleteForm:
+		e.buf = append(e.buf, deleteFormTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.AddAdmin:
+		e.buf = append(e.buf, addAdminTag)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.RemoveAdmin:
+		e.buf = append(e.buf, removeAdminTag)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.AddOwner:
+		e.buf = append(e.buf, addOwnerTag)
+		e.string(t.FormID)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.RemoveOwner:
+		e.buf = append(e.buf, removeOwnerTag)
+		e.string(t.FormID)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.AddVoter:
+		e.buf = append(e.buf, addVoterTag)
+		e.string(t.FormID)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.RemoveVoter:
+		e.buf = append(e.buf, removeVoterTag)
+		e.string(t.FormID)
+		e.string(t.TargetUserID)
+		e.string(t.PerformingUserID)
+	case types.Migrate:
+		e.buf = append(e.buf, migrateTag)
+		e.string(t.UserID)
+	case types.ImportForm:
+		e.buf = append(e.buf, importFormTag)
+		e.bytes(t.Form)
+		e.bytesList(t.Batches)
+		e.string(t.UserID)
+	case types.RepairForm:
+		e.buf = append(e.buf, repairFormTag)
+		e.string(t.FormID)
+		e.string(t.UserID)
+	case types.RegisterVoterKey:
+		e.buf = append(e.buf, registerVoterKeyTag)
+		e.string(t.FormID)
+		e.string(t.VoterID)
+		e.bytes(t.PublicKey)
+		e.bytes(t.Signature)
+		e.bytes(t.Endorsement)
+	default:
+		return nil, xerrors.Errorf("unknown type: '%T", msg)
+	}
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	if len(data) == 0 {
+		return nil, xerrors.Errorf("empty transaction")
+	}
+
+	msg, d, err := decodeTransaction(ctx, data[0], &decoder{data: data[1:]})
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode transaction: %v", err)
+	}
+
+	return msg, nil
+}
+
+func decodeTransaction(ctx serde.Context, tag byte, d *decoder) (serde.Message, *decoder, error) {
+	switch tag {
+	case createFormTag:
+		configuration := d.bytes()
+		userID := d.string()
+
+		if d.err != nil {
+			return nil, d, nil
+		}
+
+		var conf types.Configuration
+
+		err := ctx.Unmarshal(configuration, &conf)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("failed to unmarshal configuration: %v", err)
+		}
+
+		return types.CreateForm{
+			Configuration: conf,
+			UserID:        userID,
+		}, d, nil
+	case openFormTag:
+		return types.OpenForm{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case castVoteTag:
+		return types.CastVote{
+			FormID:  d.string(),
+			VoterID: d.string(),
+			Ballot:  d.ciphervote(),
+		}, d, nil
+	case signedCastVoteTag:
+		return types.CastVote{
+			FormID:    d.string(),
+			VoterID:   d.string(),
+			Ballot:    d.ciphervote(),
+			Signature: d.bytes(),
+			Nonce:     d.uvarint(),
+		}, d, nil
+	case castVotesTag:
+		formID := d.string()
+
+		votes := make([]types.BatchVote, d.length())
+		for i := range votes {
+			votes[i] = types.BatchVote{
+				VoterID:   d.string(),
+				Ballot:    d.ciphervote(),
+				Signature: d.bytes(),
+				Nonce:     d.uvarint(),
+			}
+		}
+
+		return types.CastVotes{
+			FormID: formID,
+			Votes:  votes,
+		}, d, nil
+	case closeFormTag:
+		return types.CloseForm{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case shuffleBallotsTag:
+		return types.ShuffleBallots{
+			FormID:          d.string(),
+			Round:           int(d.varint()),
+			ShuffledBallots: d.ciphervotes(),
+			RandomVector:    d.bytesList(),
+			Proof:           d.bytes(),
+			Signature:       d.bytes(),
+			PublicKey:       d.bytes(),
+			UserID:          d.string(),
+		}, d, nil
+	case registerPubSharesTag:
+		return types.RegisterPubShares{
+			FormID:    d.string(),
+			Index:     int(d.varint()),
+			Pubshares: d.pubshares(),
+			Signature: d.bytes(),
+			PublicKey: d.bytes(),
+		}, d, nil
+	case combineSharesTag:
+		return types.CombineShares{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case submitCertificateTag:
+		return types.SubmitCertificate{
+			FormID:      d.string(),
+			Certificate: d.bytes(),
+		}, d, nil
+	case cancelFormTag:
+		return types.CancelForm{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case deleteFormTag:
+		return types.DeleteForm{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case addAdminTag:
+		return types.AddAdmin{
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case removeAdminTag:
+		return types.RemoveAdmin{
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case addOwnerTag:
+		return types.AddOwner{
+			FormID:           d.string(),
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case removeOwnerTag:
+		return types.RemoveOwner{
+			FormID:           d.string(),
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case addVoterTag:
+		return types.AddVoter{
+			FormID:           d.string(),
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case removeVoterTag:
+		return types.RemoveVoter{
+			FormID:           d.string(),
+			TargetUserID:     d.string(),
+			PerformingUserID: d.string(),
+		}, d, nil
+	case migrateTag:
+		return types.Migrate{
+			UserID: d.string(),
+		}, d, nil
+	case importFormTag:
+		return types.ImportForm{
+			Form:    d.bytes(),
+			Batches: d.bytesList(),
+			UserID:  d.string(),
+		}, d, nil
+	case repairFormTag:
+		return types.RepairForm{
+			FormID: d.string(),
+			UserID: d.string(),
+		}, d, nil
+	case registerVoterKeyTag:
+		return types.RegisterVoterKey{
+			FormID:      d.string(),
+			VoterID:     d.string(),
+			PublicKey:   d.bytes(),
+			Signature:   d.bytes(),
+			Endorsement: d.bytes(),
+		}, d, nil
+	}
+
+	return nil, nil, xerrors.Errorf("unknown transaction type: %d", tag)
+}
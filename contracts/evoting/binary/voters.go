@@ -0,0 +1,43 @@
+package binary
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// votersFormat defines how the batches of voters are encoded/decoded using
+// the binary format.
+//
+// - implements serde.FormatEngine
+type votersFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (votersFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	m, ok := msg.(types.VotersBatch)
+	if !ok {
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+
+	var e encoder
+
+	e.strings(m.VoterIDs)
+
+	return e.buf, nil
+}
+
+// Decode implements serde.FormatEngine
+func (votersFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	d := decoder{data: data}
+
+	batch := types.VotersBatch{
+		VoterIDs: d.strings(),
+	}
+
+	err := d.done()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode voters batch: %v", err)
+	}
+
+	return batch, nil
+}
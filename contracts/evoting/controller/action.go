@@ -2,6 +2,7 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -9,27 +10,35 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"go.dedis.ch/kyber/v3/suites"
 
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/export"
+	"github.com/dedis/d-voting/contracts/evoting/record"
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/internal/testing/fake"
 	eproxy "github.com/dedis/d-voting/proxy"
+	pclient "github.com/dedis/d-voting/proxy/client"
 	"github.com/dedis/d-voting/proxy/txnmanager"
 	ptypes "github.com/dedis/d-voting/proxy/types"
 	"github.com/dedis/d-voting/services/dkg"
+	"github.com/dedis/d-voting/services/health"
 	"github.com/dedis/d-voting/services/shuffle"
 	"github.com/gorilla/mux"
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/store/prefixed"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/core/txn/signed"
@@ -45,22 +54,26 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	formPath    = "/evoting/forms"
+	formPath = "/evoting/forms"
 	// FormPathSlash is the path to the form with a trailing slash
 	FormPathSlash    = formPath + "/"
 	formIDPath       = FormPathSlash + "{formID}"
 	transactionSlash = "/evoting/transactions/"
 
 	evotingPathSlash = "/evoting/"
-
-	transactionPath        = transactionSlash + "{token}"
-	unexpectedStatus       = "unexpected status: %s, body: %s"
-	failRetrieveDecryption = "failed to retrieve decryption key: %v"
-	selectString           = "select:"
-	getFormErr             = "failed to get form: %v"
-	castFailed             = "failed to cast vote: %v"
-	responseBody           = "response body: "
+	healthPath       = "/health"
+	readyPath        = "/ready"
+
+	transactionPath = transactionSlash + "{token}"
+	selectString    = "select:"
+	getFormErr      = "failed to get form: %v"
+	castFailed      = "failed to cast vote: %v"
+
+	submitTimeout = 2 * time.Minute
+	// scenarioTimeout is the maximum duration of the scenario test
+	scenarioTimeout = 10 * time.Minute
+	// scenarioUserID is the owner of the form of the scenario test
+	scenarioUserID = "UserID"
 )
 
 var suite = suites.MustFind("ed25519")
@@ -155,29 +168,49 @@ func (a *RegisterAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf("failed to resolve authority factory: %v", err)
 	}
 
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context: %v", err)
+	}
+
 	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
 	mngr := getManager(signer, client)
 
-	proxykeyHex := ctx.Flags.String("proxykey")
-
-	proxykeyBuf, err := hex.DecodeString(proxykeyHex)
+	var proxykeys *ptypes.KeyRing
+	err = ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf("failed to decode proxykeyHex: %v", err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
-	proxykey := suite.Point()
+	var pinger *health.Pinger
+	err = ctx.Injector.Resolve(&pinger)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve health pinger: %v", err)
+	}
 
-	err = proxykey.UnmarshalBinary(proxykeyBuf)
+	var handlers *eproxy.HandlerSets
+	err = ctx.Injector.Resolve(&handlers)
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal proxy key: %v", err)
+		return xerrors.Errorf("failed to resolve handler sets: %v", err)
 	}
 
-	transactionManager := txnmanager.NewTransactionManager(mngr, p, sjson.NewContext(), proxykey, blocks, signer, validation)
+	transactionManager := txnmanager.NewTransactionManager(mngr, p, sjson.NewContext(), serdeCtx, blocks,
+		signer, validation)
+
+	ep := eproxy.NewForm(ordering, p, serdeCtx, formFac, proxykeys, transactionManager)
 
-	ep := eproxy.NewForm(ordering, p, sjson.NewContext(), formFac, proxykey, transactionManager)
+	events := eproxy.NewEvents(ordering, serdeCtx, formFac)
+
+	nodeHealth := eproxy.NewHealth(ordering, blocks, p, pinger, dkg, shuffleActor, handlers)
 
 	router := mux.NewRouter()
 
+	router.HandleFunc(healthPath, nodeHealth.Health).Methods("GET")
+	router.HandleFunc(readyPath, nodeHealth.Ready).Methods("GET")
+
+	router.HandleFunc(evotingPathSlash+"events", events.Events).Methods("GET")
+	router.HandleFunc(evotingPathSlash+"events", eproxy.AllowCORS).Methods("OPTIONS")
 	router.HandleFunc(evotingPathSlash+"addadmin", ep.AddAdmin).Methods("POST")
 	router.HandleFunc(evotingPathSlash+"removeadmin", ep.RemoveAdmin).Methods("POST")
 	router.HandleFunc(evotingPathSlash+"adminlist", ep.AdminList).Methods("GET")
@@ -193,66 +226,226 @@ func (a *RegisterAction) Execute(ctx node.Context) error {
 	router.HandleFunc(formIDPath, eproxy.AllowCORS).Methods("OPTIONS")
 	router.HandleFunc(formIDPath, ep.DeleteForm).Methods("DELETE")
 	router.HandleFunc(formIDPath+"/vote", ep.NewFormVote).Methods("POST")
+	router.HandleFunc(formIDPath+"/votes", ep.NewFormVotes).Methods("POST")
+	router.HandleFunc(formIDPath+"/voterkeys", ep.RegisterVoterKey).Methods("POST")
+	router.HandleFunc(formIDPath+"/ballots/validate", ep.ValidateBallot).Methods("POST")
+	router.HandleFunc(formIDPath+"/ballots/validate", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc(formIDPath+"/results", ep.Results).Methods("GET")
+	router.HandleFunc(formIDPath+"/results", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc(formIDPath+"/voters", ep.Voters).Methods("GET")
+	router.HandleFunc(formIDPath+"/voters", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc(formIDPath+"/certificate", ep.Certificate).Methods("GET")
+	router.HandleFunc(formIDPath+"/certificate", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc(formIDPath+"/record", ep.Record).Methods("GET")
+	router.HandleFunc(formIDPath+"/record", eproxy.AllowCORS).Methods("OPTIONS")
+	router.HandleFunc(formIDPath+"/ballots/{digest}/proof", ep.BallotProof).Methods("GET")
+	router.HandleFunc(formIDPath+"/ballots/{digest}/proof", eproxy.AllowCORS).Methods("OPTIONS")
 	router.HandleFunc(transactionPath, transactionManager.StatusHandlerGet).Methods("GET")
 
 	router.NotFoundHandler = http.HandlerFunc(eproxy.NotFoundHandler)
 	router.MethodNotAllowedHandler = http.HandlerFunc(eproxy.NotAllowedHandler)
 
+	err = eproxy.AddRoutes(router)
+	if err != nil {
+		return xerrors.Errorf("failed to add the routes to the OpenAPI document: %v", err)
+	}
+
 	proxy.RegisterHandler(evotingPathSlash, router.ServeHTTP)
+	proxy.RegisterHandler(eproxy.OpenAPIPath, eproxy.OpenAPI)
 	proxy.RegisterHandler(formPath, router.ServeHTTP)
 	proxy.RegisterHandler(FormPathSlash, router.ServeHTTP)
 	proxy.RegisterHandler(transactionSlash, router.ServeHTTP)
+	proxy.RegisterHandler(healthPath, router.ServeHTTP)
+	proxy.RegisterHandler(readyPath, router.ServeHTTP)
+
+	handlers.Add(eproxy.EvotingHandlers)
 
 	dela.Logger.Info().Msg("d-voting proxy handlers registered")
 
 	return nil
 }
 
-// getSigner creates a signer from a file.
-func getSigner(filePath string) (crypto.Signer, error) {
-	l := loader.NewFileLoader(filePath)
+// exportAction is an action to export the results of a form into files
+//
+// - implements node.ActionTemplate
+type exportAction struct{}
 
-	signerData, err := l.Load()
+// Execute implements node.ActionTemplate. It reads the form from the local
+// store and writes the per-ballot table, the counts, the JSON document and one
+// .blt file per rank question in the output directory.
+func (a *exportAction) Execute(ctx node.Context) error {
+	var orderingSvc ordering.Service
+	err := ctx.Injector.Resolve(&orderingSvc)
 	if err != nil {
-		return nil, xerrors.Errorf("Failed to load signer from %q: %v", filePath, err)
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
 	}
 
-	signer, err := bls.NewSignerFromBytes(signerData)
+	var rosterFac authority.Factory
+	err = ctx.Injector.Resolve(&rosterFac)
 	if err != nil {
-		return nil, xerrors.Errorf("Failed to unmarshal signer: %v", err)
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
 	}
 
-	return signer, nil
+	formID := ctx.Flags.String("formID")
+	outDir := ctx.Flags.String("out")
+	seats := ctx.Flags.Int("seats")
+
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context: %v", err)
+	}
+
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+
+	form, err := types.FormFromStore(serdeCtx, formFac, formID,
+		orderingSvc.GetStore())
+	if err != nil {
+		return xerrors.Errorf(getFormErr, err)
+	}
+
+	if form.Status != types.ResultAvailable {
+		return xerrors.Errorf("the results are not available, current status: %d",
+			form.Status)
+	}
+
+	ballots, err := form.Results(serdeCtx, orderingSvc.GetStore(), 0, 0)
+	if err != nil {
+		return xerrors.Errorf("failed to get results: %v", err)
+	}
+
+	results := export.NewResults(formID, form.Configuration, ballots)
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return xerrors.Errorf("failed to create output directory: %v", err)
+	}
+
+	files := map[string]func(io.Writer) error{
+		formID + "-ballots.csv": results.WriteBallotsCSV,
+		formID + "-counts.csv":  results.WriteCountsCSV,
+		formID + ".json":        results.WriteJSON,
+	}
+
+	for i, questionID := range results.RankQuestions() {
+		questionID := questionID
+
+		files[fmt.Sprintf("%s-%d.blt", formID, i)] = func(w io.Writer) error {
+			return results.WriteBLT(w, questionID, seats)
+		}
+	}
+
+	for name, write := range files {
+		err = writeFile(filepath.Join(outDir, name), write)
+		if err != nil {
+			return xerrors.Errorf("failed to export %s: %v", name, err)
+		}
+
+		fmt.Fprintf(ctx.Out, "%s\n", filepath.Join(outDir, name))
+	}
+
+	return nil
 }
 
-// scenarioTestAction is an action to run a test scenario
+// exportRecordAction is an action to export the election record of a form
 //
 // - implements node.ActionTemplate
-type scenarioTestAction struct {
+type exportRecordAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the form and the batches it
+// references from the local store and writes them in a single record file.
+func (a *exportRecordAction) Execute(ctx node.Context) error {
+	var orderingSvc ordering.Service
+	err := ctx.Injector.Resolve(&orderingSvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	var rosterFac authority.Factory
+	err = ctx.Injector.Resolve(&rosterFac)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
+	}
+
+	formID := ctx.Flags.String("formID")
+
+	out := ctx.Flags.String("out")
+	if out == "" {
+		out = formID + "-record.json"
+	}
+
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context: %v", err)
+	}
+
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+
+	form, err := types.FormFromStore(serdeCtx, formFac, formID,
+		orderingSvc.GetStore())
+	if err != nil {
+		return xerrors.Errorf(getFormErr, err)
+	}
+
+	rec, err := record.New(serdeCtx, form, orderingSvc.GetStore())
+	if err != nil {
+		return xerrors.Errorf("failed to create record: %v", err)
+	}
+
+	err = writeFile(out, rec.Write)
+	if err != nil {
+		return xerrors.Errorf("failed to export record: %v", err)
+	}
+
+	fmt.Fprintf(ctx.Out, "%s\n", out)
+
+	return nil
 }
 
-// Execute implements node.ActionTemplate. It creates a form and
-// simulates the full voting process
-func (a *scenarioTestAction) Execute(ctx node.Context) error {
-	secretkeyHex := ctx.Flags.String("secretkey")
+// migrateAction is an action to store again the records of the contract at
+// the current schema version
+//
+// - implements node.ActionTemplate
+type migrateAction struct{}
 
-	secretkeyBuf, err := hex.DecodeString(secretkeyHex)
+// Execute implements node.ActionTemplate. It submits a MIGRATE transaction on
+// behalf of the given admin and waits for it to be accepted.
+func (a *migrateAction) Execute(ctx node.Context) error {
+	sub, err := newSubmitter(ctx)
 	if err != nil {
-		return xerrors.Errorf("failed to decode secretkeyHex: %v", err)
+		return xerrors.Errorf("failed to create submitter: %v", err)
 	}
 
-	secret := suite.Scalar()
+	migrate := types.Migrate{
+		UserID: ctx.Flags.String("userID"),
+	}
 
-	err = secret.UnmarshalBinary(secretkeyBuf)
+	err = sub.submit(evoting.CmdMigrate, migrate)
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal secret key: %v", err)
+		return xerrors.Errorf("failed to migrate: %v", err)
 	}
 
-	proxyAddr1 := ctx.Flags.String("proxy-addr1")
-	proxyAddr2 := ctx.Flags.String("proxy-addr2")
-	proxyAddr3 := ctx.Flags.String("proxy-addr3")
+	fmt.Fprintf(ctx.Out, "records migrated to version %d\n", types.SchemaVersion)
 
-	fmt.Println("Welcome in the scenario test")
+	return nil
+}
+
+// exportStateAction is an action to export the admin list and the finished
+// forms of the chain, to import them on another chain
+//
+// - implements node.ActionTemplate
+type exportStateAction struct{}
+
+// Execute implements node.ActionTemplate. It reads the admin list, the list of
+// the forms and the records of the finished forms from the local store and
+// writes them in a single file.
+func (a *exportStateAction) Execute(ctx node.Context) error {
+	var orderingSvc ordering.Service
+	err := ctx.Injector.Resolve(&orderingSvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
 
 	var rosterFac authority.Factory
 	err = ctx.Injector.Resolve(&rosterFac)
@@ -260,599 +453,891 @@ func (a *scenarioTestAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf("failed to resolve authority factory: %v", err)
 	}
 
-	serdecontext := sjson.NewContext()
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context: %v", err)
+	}
+
 	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+	rd := orderingSvc.GetStore()
 
-	var service ordering.Service
-	err = ctx.Injector.Resolve(&service)
-	if err != nil {
-		return xerrors.Errorf("failed to resolve service: %v", err)
+	state := record.State{
+		Type:    record.StateType,
+		Version: record.StateVersion,
+		Format:  string(serdeCtx.GetFormat()),
 	}
 
-	// ###################################### CREATE SIMPLE FORM ######
+	adminList, err := types.AdminListFromStore(serdeCtx, types.AdminListFactory{}, rd,
+		evoting.AdminListId)
+	if err != nil && err.Error() != "No list found" {
+		return xerrors.Errorf("failed to get the admin list: %v", err)
+	}
 
-	formID, form, formIDBuf, err := setupSimpleForm(ctx, secret,
-		proxyAddr1, serdecontext, formFac, service)
+	state.AdminList = adminList.AdminList
 
+	metadataBuf, err := rd.Get([]byte(evoting.FormsMetadataKey))
 	if err != nil {
-		return xerrors.Errorf("failed to simple form: %v", err)
+		return xerrors.Errorf("failed to get the forms metadata: %v", err)
 	}
 
-	// ##################################### SETUP DKG #########################
+	var metadata types.FormsMetadata
 
-	fmt.Fprintln(ctx.Out, "Init DKG")
+	if len(metadataBuf) != 0 {
+		err = json.Unmarshal(metadataBuf, &metadata)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal the forms metadata: %v", err)
+		}
+	}
 
-	proxys := []string{proxyAddr1, proxyAddr2, proxyAddr3}
+	adminListID := sha256.Sum256([]byte(evoting.AdminListId))
 
-	// Initializing the DKG for the nodes.
-	for i := 1; i <= 3; i++ {
-		fmt.Fprintf(ctx.Out, "Node %d ", i)
-		err = initDKG(secret, proxys[i], formID)
+	for _, formID := range metadata.FormsIDs {
+		// the ID of the admin list is also registered in the metadata
+		if formID == hex.EncodeToString(adminListID[:]) {
+			continue
+		}
+
+		state.FormsIDs = append(state.FormsIDs, formID)
+
+		form, err := types.FormFromStore(serdeCtx, formFac, formID, rd)
+		if err != nil {
+			return xerrors.Errorf(getFormErr, err)
+		}
+
+		if form.Status != types.ResultAvailable && form.Status != types.Canceled {
+			continue
+		}
+
+		rec, err := record.New(serdeCtx, form, rd)
 		if err != nil {
-			return xerrors.Errorf("failed to init dkg %d: %v", i, err)
+			return xerrors.Errorf("failed to create record of form %s: %v", formID, err)
 		}
 
+		state.Records = append(state.Records, rec)
 	}
 
-	fmt.Fprintf(ctx.Out, "Setup DKG on node 1")
+	out := ctx.Flags.String("out")
 
-	_, err = updateDKG(secret, proxyAddr1, formID, "setup")
+	err = writeFile(out, state.Write)
 	if err != nil {
-		return xerrors.Errorf("failed to setup dkg on node 1: %v", err)
+		return xerrors.Errorf("failed to export state: %v", err)
 	}
 
-	// ##################################### OPEN FORM #####################
+	fmt.Fprintf(ctx.Out, "%s: %d admins, %d forms, %d finished\n", out,
+		len(state.AdminList), len(state.FormsIDs), len(state.Records))
+
+	return nil
+}
 
-	fmt.Fprintf(ctx.Out, "Open form")
+// importStateAction is an action to import a state exported from another
+// chain
+//
+// - implements node.ActionTemplate
+type importStateAction struct{}
 
-	_, err = updateForm(secret, proxyAddr1, formID, "open")
+// Execute implements node.ActionTemplate. It adds the missing admins and
+// submits an IMPORT_FORM transaction for each finished form that is not yet on
+// the chain, on behalf of the given admin.
+func (a *importStateAction) Execute(ctx node.Context) error {
+	state, err := record.ReadStateFile(ctx.Flags.String("file"))
 	if err != nil {
-		return xerrors.Errorf("failed to open form: %v", err)
+		return xerrors.Errorf("failed to read state: %v", err)
 	}
 
-	// ##################################### GET FORM INFO #################
+	sub, err := newSubmitter(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to create submitter: %v", err)
+	}
 
-	fmt.Fprintln(ctx.Out, "Get form")
+	if state.Format != string(sub.context.GetFormat()) {
+		return xerrors.Errorf("state format %q does not match the chain format %q",
+			state.Format, sub.context.GetFormat())
+	}
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	var rosterFac authority.Factory
+	err = ctx.Injector.Resolve(&rosterFac)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
 	}
 
-	logFormStatus(form)
-	dela.Logger.Info().Msgf("Pubkey of the form : %x", form.Pubkey)
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+	userID := ctx.Flags.String("userID")
+	rd := sub.ordering.GetStore()
 
-	// ############################# ATTEMPT TO CLOSE FORM #################
+	adminList, err := types.AdminListFromStore(sub.context, types.AdminListFactory{}, rd,
+		evoting.AdminListId)
+	if err != nil && err.Error() != "No list found" {
+		return xerrors.Errorf("failed to get the admin list: %v", err)
+	}
 
-	fmt.Fprintln(ctx.Out, "Close form")
+	// On a chain without admins, the first one is trusted on first use.
+	if len(adminList.AdminList) == 0 {
+		err = sub.submit(evoting.CmdAddAdmin, types.AddAdmin{
+			TargetUserID:     userID,
+			PerformingUserID: userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to add admin %s: %v", userID, err)
+		}
 
-	status, err := updateForm(secret, proxyAddr1, formID, "close")
-	if status != http.StatusInternalServerError {
-		return xerrors.Errorf("unexpected error: %d: %v", status, err)
+		err = adminList.AddAdmin(userID)
+		if err != nil {
+			return xerrors.Errorf("failed to add admin %s: %v", userID, err)
+		}
 	}
 
-	// ##################################### CAST BALLOTS ######################
+	for _, admin := range state.AdminList {
+		index, err := adminList.GetAdminIndex(strconv.Itoa(admin))
+		if err != nil {
+			return xerrors.Errorf("failed to check admin %d: %v", admin, err)
+		}
 
-	fmt.Fprintln(ctx.Out, "cast ballots")
+		if index >= 0 {
+			continue
+		}
 
-	// Create the ballots
-	b1 := string(selectString + encodeID("bb") + ":0,0,1,0\n" +
-		"text:" + encodeID("ee") + ":eWVz\n\n") //encoding of "yes"
+		err = sub.submit(evoting.CmdAddAdmin, types.AddAdmin{
+			TargetUserID:     strconv.Itoa(admin),
+			PerformingUserID: userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to add admin %d: %v", admin, err)
+		}
+	}
 
-	b2 := string(selectString + encodeID("bb") + ":1,1,0,0\n" +
-		"text:" + encodeID("ee") + ":amE=\n\n") //encoding of "ja
+	imported := 0
 
-	b3 := string(selectString + encodeID("bb") + ":0,0,0,1\n" +
-		"text:" + encodeID("ee") + ":b3Vp\n\n") //encoding of "oui"
+	for _, rec := range state.Records {
+		_, err = types.FormFromStore(sub.context, formFac, rec.FormID, rd)
+		if err == nil {
+			dela.Logger.Info().Msgf("form %s already exists, skipped", rec.FormID)
+			continue
+		}
 
-	var dkg dkg.DKG
-	err = ctx.Injector.Resolve(&dkg)
-	if err != nil {
-		return xerrors.Errorf("failed to resolve DKG: %v", err)
-	}
+		form, err := rec.GetForm(sub.context, formFac)
+		if err != nil {
+			return xerrors.Errorf("failed to read form %s: %v", rec.FormID, err)
+		}
 
-	dkgActor, exists := dkg.GetActor(formIDBuf)
-	if !exists {
-		return xerrors.Errorf("failed to get actor: %v", err)
+		batches, err := rec.BatchList(form.BatchKeys())
+		if err != nil {
+			return xerrors.Errorf("failed to read batches of form %s: %v", rec.FormID, err)
+		}
+
+		err = sub.submit(evoting.CmdImportForm, types.ImportForm{
+			Form:    rec.Form,
+			Batches: batches,
+			UserID:  userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to import form %s: %v", rec.FormID, err)
+		}
+
+		imported++
 	}
 
-	// Ballot 1
-	ballot1, err := marshallBallot(b1, dkgActor, form.ChunksPerBallot())
+	fmt.Fprintf(ctx.Out, "%d forms imported\n", imported)
+
+	return nil
+}
+
+// fsckAction is an action to check the integrity of the store of the contract
+//
+// - implements node.ActionTemplate
+type fsckAction struct{}
+
+// Execute implements node.ActionTemplate. It prints the problems found in the
+// local store and, with the repair flag, submits a REPAIR_FORM transaction for
+// each form with repairable problems, on behalf of the given admin.
+func (a *fsckAction) Execute(ctx node.Context) error {
+	var orderingSvc ordering.Service
+	err := ctx.Injector.Resolve(&orderingSvc)
 	if err != nil {
-		return xerrors.Errorf("failed to marshall ballot : %v", err)
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
 	}
 
-	castVoteRequest := ptypes.CastVoteRequest{
-		VoterID: "user1",
-		Ballot:  ballot1,
+	var rosterFac authority.Factory
+	err = ctx.Injector.Resolve(&rosterFac)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve authority factory: %v", err)
 	}
 
-	signed, err := createSignedRequest(secret, castVoteRequest)
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
 	if err != nil {
-		return createSignedErr(err)
+		return xerrors.Errorf("failed to resolve serde.Context: %v", err)
 	}
 
-	fmt.Fprintln(ctx.Out, "cast first ballot")
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+	rd := prefixed.NewReadable(evoting.ContractUID, orderingSvc.GetStore())
 
-	respBody, err := castVote(formID, signed, proxyAddr1)
+	problems, err := evoting.Fsck(serdeCtx, formFac, rd)
 	if err != nil {
-		return xerrors.Errorf(castFailed, err)
+		return xerrors.Errorf("failed to check the store: %v", err)
 	}
 
-	dela.Logger.Info().Msg(responseBody + respBody)
+	toRepair := []string{}
+	repairable := 0
 
-	// Ballot 2
-	ballot2, err := marshallBallot(b2, dkgActor, form.ChunksPerBallot())
-	if err != nil {
-		return xerrors.Errorf("failed to marshall ballot : %v", err)
+	for _, problem := range problems {
+		fmt.Fprintln(ctx.Out, problem)
+
+		if !problem.Repairable {
+			continue
+		}
+
+		repairable++
+
+		if len(toRepair) == 0 || toRepair[len(toRepair)-1] != problem.FormID {
+			toRepair = append(toRepair, problem.FormID)
+		}
 	}
 
-	castVoteRequest = ptypes.CastVoteRequest{
-		VoterID: "user2",
-		Ballot:  ballot2,
+	fmt.Fprintf(ctx.Out, "%d problems, %d repairable\n", len(problems), repairable)
+
+	if !ctx.Flags.Bool("repair") || len(toRepair) == 0 {
+		return nil
+	}
+
+	userID := ctx.Flags.String("userID")
+	if userID == "" {
+		return xerrors.Errorf("the userID of an admin is required to repair")
 	}
 
-	signed, err = createSignedRequest(secret, castVoteRequest)
+	sub, err := newSubmitter(ctx)
 	if err != nil {
-		return createSignedErr(err)
+		return xerrors.Errorf("failed to create submitter: %v", err)
+	}
+
+	for _, formID := range toRepair {
+		err = sub.submit(evoting.CmdRepairForm, types.RepairForm{
+			FormID: formID,
+			UserID: userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to repair form %s: %v", formID, err)
+		}
 	}
 
-	fmt.Fprintln(ctx.Out, "cast second ballot")
+	fmt.Fprintf(ctx.Out, "%d forms repaired\n", len(toRepair))
 
-	respBody, err = castVote(formID, signed, proxyAddr1)
+	return nil
+}
+
+// proxyKeysAddAction is an action to trust a new key of a web backend
+//
+// - implements node.ActionTemplate
+type proxyKeysAddAction struct{}
+
+// Execute implements node.ActionTemplate. It adds the key to the proxy keys
+// of the node, valid from the "from" time, or now, until the "until" time, if
+// any.
+func (a *proxyKeysAddAction) Execute(ctx node.Context) error {
+	var proxykeys *ptypes.KeyRing
+	err := ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf(castFailed, err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
-	dela.Logger.Info().Msg(responseBody + respBody)
+	key, err := ptypes.DecodeProxyKey(ctx.Flags.String("key"))
+	if err != nil {
+		return xerrors.Errorf("failed to decode key: %v", err)
+	}
 
-	// Ballot 3
-	ballot3, err := marshallBallot(b3, dkgActor, form.ChunksPerBallot())
+	notBefore, err := parseKeyTime(ctx.Flags.String("from"), time.Now())
 	if err != nil {
-		return xerrors.Errorf("failed to marshall ballot: %v", err)
+		return xerrors.Errorf("failed to parse from: %v", err)
 	}
 
-	castVoteRequest = ptypes.CastVoteRequest{
-		VoterID: "user3",
-		Ballot:  ballot3,
+	notAfter, err := parseKeyTime(ctx.Flags.String("until"), time.Time{})
+	if err != nil {
+		return xerrors.Errorf("failed to parse until: %v", err)
 	}
 
-	signed, err = createSignedRequest(secret, castVoteRequest)
+	err = proxykeys.Add(ptypes.ProxyKey{
+		Name:      ctx.Flags.String("name"),
+		Key:       key,
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+	})
 	if err != nil {
-		return createSignedErr(err)
+		return xerrors.Errorf("failed to add key: %v", err)
 	}
 
-	fmt.Fprintln(ctx.Out, "cast third ballot")
+	fmt.Fprintf(ctx.Out, "key %q added\n", ctx.Flags.String("name"))
+
+	return nil
+}
+
+// proxyKeysRetireAction is an action to stop trusting a key of a web backend
+//
+// - implements node.ActionTemplate
+type proxyKeysRetireAction struct{}
 
-	respBody, err = castVote(formID, signed, proxyAddr1)
+// Execute implements node.ActionTemplate. It retires the key at the "at" time,
+// or now.
+func (a *proxyKeysRetireAction) Execute(ctx node.Context) error {
+	var proxykeys *ptypes.KeyRing
+	err := ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf(castFailed, err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
-	dela.Logger.Info().Msg(responseBody + respBody)
-
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	at, err := parseKeyTime(ctx.Flags.String("at"), time.Now())
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to parse at: %v", err)
 	}
 
-	suff, err := form.Suffragia(serdecontext, service.GetStore())
+	err = proxykeys.Retire(ctx.Flags.String("name"), at)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to retire key: %v", err)
 	}
-	encryptedBallots := suff.Ciphervotes
-	dela.Logger.Info().Msg("Length encrypted ballots: " + strconv.Itoa(len(encryptedBallots)))
-	dela.Logger.Info().Msgf("Ballot of user1: %s", encryptedBallots[0])
-	dela.Logger.Info().Msgf("Ballot of user2: %s", encryptedBallots[1])
-	dela.Logger.Info().Msgf("Ballot of user3: %s", encryptedBallots[2])
 
-	// ############################# CLOSE FORM FOR REAL ###################
+	fmt.Fprintf(ctx.Out, "key %q retired at %s\n", ctx.Flags.String("name"),
+		at.UTC().Format(time.RFC3339))
 
-	fmt.Fprintln(ctx.Out, "Close form (for real)")
+	return nil
+}
 
-	_, err = updateForm(secret, proxyAddr1, formID, "close")
-	if err != nil {
-		return xerrors.Errorf("failed to close form: %v", err)
-	}
+// proxyKeysListAction is an action to list the keys of the web backends
+//
+// - implements node.ActionTemplate
+type proxyKeysListAction struct{}
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+// Execute implements node.ActionTemplate. It prints a line per key with its
+// name, its hex encoding, its validity period and whether it is valid now.
+func (a *proxyKeysListAction) Execute(ctx node.Context) error {
+	var proxykeys *ptypes.KeyRing
+	err := ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
-	dela.Logger.Info().Msg("Title of the form: " + form.Configuration.Title.En)
-	dela.Logger.Info().Msg("Status of the form: " + strconv.Itoa(int(form.Status)))
+	now := time.Now()
 
-	// ###################################### SHUFFLE BALLOTS ##################
+	for _, key := range proxykeys.Keys() {
+		buf, err := key.Key.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal key %q: %v", key.Name, err)
+		}
 
-	fmt.Fprintln(ctx.Out, "shuffle ballots")
+		status := "valid"
+		if !key.ValidAt(now) {
+			status = "not valid"
+		}
 
-	shuffleRequest := ptypes.UpdateShuffle{
-		Action: "shuffle",
+		fmt.Fprintf(ctx.Out, "%s\t%x\t%s\t%s\t%s\n", key.Name, buf,
+			formatKeyTime(key.NotBefore), formatKeyTime(key.NotAfter), status)
 	}
 
-	signed, err = createSignedRequest(secret, shuffleRequest)
+	return nil
+}
+
+// parseKeyTime parses a RFC3339 time, or returns the default time if the
+// value is empty.
+func parseKeyTime(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
 	if err != nil {
-		return createSignedErr(err)
+		return time.Time{}, xerrors.Errorf("failed to parse time: %v", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPut, proxyAddr1+"/evoting/services/shuffle/"+formID, bytes.NewBuffer(signed))
+	return t, nil
+}
+
+// formatKeyTime returns the RFC3339 format of the time, or "-" if it is zero.
+func formatKeyTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// submitter submits the transactions of the CLI commands, signed with the
+// signer given by the "signer" flag, and waits for them to be accepted.
+type submitter struct {
+	pool     pool.Pool
+	ordering ordering.Service
+	mngr     txn.Manager
+	context  serde.Context
+}
+
+func newSubmitter(ctx node.Context) (submitter, error) {
+	signer, err := getSigner(ctx.Flags.String("signer"))
 	if err != nil {
-		return xerrors.Errorf("failed to create shuffle request: %v", err)
+		return submitter{}, xerrors.Errorf("failed to get the signer: %v", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	var p pool.Pool
+	err = ctx.Injector.Resolve(&p)
 	if err != nil {
-		return xerrors.Errorf("failed to execute the shuffle query: %v", err)
+		return submitter{}, xerrors.Errorf("failed to resolve pool.Pool: %v", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return xerrors.Errorf("unexpected shuffle status: %s - %s", resp.Status, buf)
+	var orderingSvc ordering.Service
+	err = ctx.Injector.Resolve(&orderingSvc)
+	if err != nil {
+		return submitter{}, xerrors.Errorf("failed to resolve ordering.Service: %v", err)
 	}
 
-	// time.Sleep(20 * time.Second)
+	var validation validation.Service
+	err = ctx.Injector.Resolve(&validation)
+	if err != nil {
+		return submitter{}, xerrors.Errorf("failed to resolve validation: %v", err)
+	}
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	var serdeCtx serde.Context
+	err = ctx.Injector.Resolve(&serdeCtx)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return submitter{}, xerrors.Errorf("failed to resolve serde.Context: %v", err)
 	}
 
-	logFormStatus(form)
-	dela.Logger.Info().Msg("Number of shuffled ballots : " + strconv.Itoa(len(form.ShuffleInstances)))
-	suff, err = form.Suffragia(serdecontext, service.GetStore())
+	mngr := getManager(signer, client{srvc: orderingSvc, mgr: validation})
+
+	err = mngr.Sync()
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return submitter{}, xerrors.Errorf("failed to sync manager: %v", err)
 	}
-	dela.Logger.Info().Msg("Number of encrypted ballots : " + strconv.Itoa(len(suff.Ciphervotes)))
 
-	// ###################################### REQUEST PUBLIC SHARES ############
+	return submitter{
+		pool:     p,
+		ordering: orderingSvc,
+		mngr:     mngr,
+		context:  serdeCtx,
+	}, nil
+}
 
-	fmt.Fprintln(ctx.Out, "request public shares")
+// submit sends the command with the given transaction and waits for it to be
+// accepted.
+func (s submitter) submit(cmd evoting.Command, msg serde.Message) error {
+	data, err := msg.Serialize(s.context)
+	if err != nil {
+		return xerrors.Errorf("failed to serialize transaction: %v", err)
+	}
 
-	_, err = updateDKG(secret, proxyAddr1, formID, "computePubshares")
+	tx, err := s.mngr.Make(
+		txn.Arg{Key: native.ContractArg, Value: []byte(evoting.ContractName)},
+		txn.Arg{Key: evoting.CmdArg, Value: []byte(cmd)},
+		txn.Arg{Key: evoting.FormArg, Value: data},
+	)
 	if err != nil {
-		return xerrors.Errorf("failed to compute pubshares: %v", err)
+		return xerrors.Errorf("failed to make transaction: %v", err)
 	}
 
-	time.Sleep(10 * time.Second)
+	watchCtx, cancel := context.WithTimeout(context.Background(), submitTimeout)
+	defer cancel()
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	events := s.ordering.Watch(watchCtx)
+
+	err = s.pool.Add(tx)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to add transaction: %v", err)
 	}
 
-	validSubmissions := len(form.PubsharesUnits.Pubshares)
+	for event := range events {
+		for _, res := range event.Transactions {
+			if !bytes.Equal(res.GetTransaction().GetID(), tx.GetID()) {
+				continue
+			}
 
-	logFormStatus(form)
-	dela.Logger.Info().Msg("Number of Pubshare units submitted: " + strconv.Itoa(validSubmissions))
+			accepted, status := res.GetStatus()
+			if !accepted {
+				return xerrors.Errorf("transaction denied: %s", status)
+			}
 
-	// ###################################### DECRYPT BALLOTS ##################
+			return nil
+		}
+	}
 
-	fmt.Fprintln(ctx.Out, "decrypt ballots")
+	return xerrors.Errorf("transaction not included before the timeout")
+}
 
-	_, err = updateForm(secret, proxyAddr1, formID, "combineShares")
+// writeFile creates the file at path and fills it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
 	if err != nil {
-		return xerrors.Errorf("failed to combine shares: %v", err)
+		return xerrors.Errorf("failed to create file: %v", err)
 	}
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	defer file.Close()
+
+	err = write(file)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to write file: %v", err)
 	}
 
-	// dela.Logger.Info().Msg("----------------------- Form : " +
-	// string(proof.GetValue()))
-
-	logFormStatus(form)
-	dela.Logger.Info().Msg("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+	return nil
+}
 
-	// ###################################### GET FORM RESULT ##############
+// getSigner creates a signer from a file.
+func getSigner(filePath string) (crypto.Signer, error) {
+	l := loader.NewFileLoader(filePath)
 
-	fmt.Fprintln(ctx.Out, "Get form result")
+	signerData, err := l.Load()
+	if err != nil {
+		return nil, xerrors.Errorf("Failed to load signer from %q: %v", filePath, err)
+	}
 
-	form, err = types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	signer, err := bls.NewSignerFromBytes(signerData)
 	if err != nil {
-		return xerrors.Errorf(getFormErr, err)
+		return nil, xerrors.Errorf("Failed to unmarshal signer: %v", err)
 	}
 
-	logFormStatus(form)
-	dela.Logger.Info().Msg("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+	return signer, nil
+}
 
-	if len(form.DecryptedBallots) != 3 {
-		return xerrors.Errorf("unexpected number of decrypted ballot: %d != 3", len(form.DecryptedBallots))
-	}
+// scenarioTestAction is an action to run a test scenario
+//
+// - implements node.ActionTemplate
+type scenarioTestAction struct {
+}
 
-	// dela.Logger.Info().Msg(form.DecryptedBallots[0].Vote)
-	// dela.Logger.Info().Msg(form.DecryptedBallots[1].Vote)
-	// dela.Logger.Info().Msg(form.DecryptedBallots[2].Vote)
+// Execute implements node.ActionTemplate. It creates a form and
+// simulates the full voting process through the proxies of the nodes.
+func (a *scenarioTestAction) Execute(ctx node.Context) error {
+	secretkeyHex := ctx.Flags.String("secretkey")
 
-	// ###################################### GET ALL FORM ##############
+	secretkeyBuf, err := hex.DecodeString(secretkeyHex)
+	if err != nil {
+		return xerrors.Errorf("failed to decode secretkeyHex: %v", err)
+	}
 
-	resp, err = http.Get(proxyAddr1 + formPath)
+	secret := suite.Scalar()
 
+	err = secret.UnmarshalBinary(secretkeyBuf)
 	if err != nil {
-		return xerrors.Errorf("failed to get all forms")
+		return xerrors.Errorf("failed to unmarshal secret key: %v", err)
 	}
 
-	var allForms ptypes.GetFormsResponse
+	proxyAddrs := []string{
+		ctx.Flags.String("proxy-addr1"),
+		ctx.Flags.String("proxy-addr2"),
+		ctx.Flags.String("proxy-addr3"),
+	}
 
-	decoder := json.NewDecoder(resp.Body)
+	fmt.Println("Welcome in the scenario test")
 
-	err = decoder.Decode(&allForms)
+	var dkg dkg.DKG
+	err = ctx.Injector.Resolve(&dkg)
 	if err != nil {
-		return xerrors.Errorf("failed to decode getAllForms: %v", err)
+		return xerrors.Errorf("failed to resolve DKG: %v", err)
 	}
 
-	dela.Logger.Info().Msgf("All forms: %v", allForms)
-
-	if len(allForms.Forms) != 1 && allForms.Forms[0].FormID != formID {
-		return xerrors.Errorf("unexpected allForms: %v", allForms)
+	proxies := make([]*pclient.Client, len(proxyAddrs))
+	for i, addr := range proxyAddrs {
+		proxies[i] = pclient.NewClient(addr, pclient.WithSecret(secret))
 	}
 
-	return nil
-}
+	proxy1 := proxies[0]
 
-func setupSimpleForm(ctx node.Context, secret kyber.Scalar, proxyAddr1 string,
-	serdecontext serde.Context, formFac types.FormFactory,
-	service ordering.Service) (string, types.Form, []byte, error) {
+	c, cancel := context.WithTimeout(context.Background(), scenarioTimeout)
+	defer cancel()
 
-	fmt.Fprintln(ctx.Out, "Create form")
+	// ###################################### CREATE SIMPLE FORM ######
 
-	// Define the configuration
-	configuration := fake.BasicConfiguration
+	fmt.Fprintln(ctx.Out, "Create form")
 
-	createSimpleFormRequest := ptypes.CreateFormRequest{
-		Configuration: configuration,
-		UserID:        "UserID",
+	created, err := proxy1.CreateForm(c, ptypes.CreateFormRequest{
+		Configuration: fake.BasicConfiguration,
+		UserID:        scenarioUserID,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to create form: %v", err)
 	}
 
-	signed, err := createSignedRequest(secret, createSimpleFormRequest)
+	_, err = proxy1.Wait(c, created.Token)
 	if err != nil {
-		return "", types.Form{}, nil, createSignedErr(err)
+		return xerrors.Errorf("failed to wait for the form: %v", err)
 	}
 
-	fmt.Fprintln(ctx.Out, "create form js:", signed)
-
-	url := proxyAddr1 + formPath
-	fmt.Fprintln(ctx.Out, "POST", url)
+	formID := created.FormID
 
-	resp, err := http.Post(url, contentType, bytes.NewBuffer(signed))
+	formIDBuf, err := hex.DecodeString(formID)
 	if err != nil {
-		return "", types.Form{}, nil, xerrors.Errorf(failRetrieveDecryption, err)
+		return xerrors.Errorf("failed to decode formID '%s': %v", formID, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return "", types.Form{}, nil, xerrors.Errorf(unexpectedStatus, resp.Status, buf)
+	form, err := proxy1.GetForm(c, formID)
+	if err != nil {
+		return xerrors.Errorf(getFormErr, err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", types.Form{}, nil, xerrors.Errorf("failed to read the body of the response: %v", err)
+	// sanity check, the formID returned and the one of the form must be the
+	// same.
+	if form.FormID != formID {
+		return xerrors.Errorf("formID mismatch: %s != %s", form.FormID, formID)
 	}
 
-	fmt.Fprintln(ctx.Out, "response body:", string(body))
+	logFormStatus(form)
+
+	// ##################################### SETUP DKG #########################
 
-	resp.Body.Close()
+	fmt.Fprintln(ctx.Out, "Init DKG")
 
-	var formResponse ptypes.CreateFormResponse
+	// Initializing the DKG for the nodes.
+	for i, proxy := range proxies {
+		fmt.Fprintf(ctx.Out, "Node %d\n", i+1)
 
-	err = json.Unmarshal(body, &formResponse)
-	if err != nil {
-		return "", types.Form{}, nil, xerrors.Errorf("failed to unmarshal create form response: %v - %s", err, body)
+		err = proxy.InitDKG(c, formID)
+		if err != nil {
+			return xerrors.Errorf("failed to init dkg %d: %v", i+1, err)
+		}
 	}
 
-	formID := formResponse.FormID
+	fmt.Fprintln(ctx.Out, "Setup DKG on node 1")
 
-	formIDBuf, err := hex.DecodeString(formID)
+	err = proxy1.UpdateDKG(c, formID, "setup")
 	if err != nil {
-		return "", types.Form{}, nil, xerrors.Errorf("failed to decode formID '%s': %v", formID, err)
+		return xerrors.Errorf("failed to setup dkg on node 1: %v", err)
 	}
 
-	form, err := types.FormFromStore(serdecontext, formFac, formID, service.GetStore())
+	err = proxy1.WaitDKGSetup(c, formID)
 	if err != nil {
-		return "", types.Form{}, nil, xerrors.Errorf(getFormErr, err)
+		return xerrors.Errorf("failed to setup dkg on node 1: %v", err)
 	}
 
-	// sanity check, the formID returned and the one stored in the form
-	// type must be the same.
-	if form.FormID != formID {
-		return "", types.Form{}, nil, xerrors.Errorf("formID mismatch: %s != %s", form.FormID, formID)
+	// ##################################### OPEN FORM #####################
+
+	fmt.Fprintln(ctx.Out, "Open form")
+
+	err = updateForm(c, proxy1, formID, "open")
+	if err != nil {
+		return xerrors.Errorf("failed to open form: %v", err)
 	}
 
-	fmt.Fprintf(ctx.Out, "Title of the form: "+form.Configuration.Title.En)
-	fmt.Fprintf(ctx.Out, "ID of the form: "+form.FormID)
-	fmt.Fprintf(ctx.Out, "Status of the form: "+strconv.Itoa(int(form.Status)))
+	form, err = proxy1.GetForm(c, formID)
+	if err != nil {
+		return xerrors.Errorf(getFormErr, err)
+	}
 
-	return formID, form, formIDBuf, nil
-}
+	logFormStatus(form)
+	dela.Logger.Info().Msgf("Pubkey of the form : %s", form.Pubkey)
 
-func logFormStatus(form types.Form) {
-	dela.Logger.Info().Msg("Title of the form : " + form.Configuration.Title.En)
-	dela.Logger.Info().Msg("ID of the form : " + form.FormID)
-	dela.Logger.Info().Msg("Status of the form : " + strconv.Itoa(int(form.Status)))
-}
+	// ############################# ATTEMPT TO CLOSE FORM #################
 
-func encodeID(ID string) types.ID {
-	return types.ID(base64.StdEncoding.EncodeToString([]byte(ID)))
-}
+	fmt.Fprintln(ctx.Out, "Close form")
 
-func marshallBallot(voteStr string, actor dkg.Actor, chunks int) (ptypes.CiphervoteJSON, error) {
+	// the form can't be closed without ballots
+	err = updateForm(c, proxy1, formID, "close")
 
-	var ballot = make(ptypes.CiphervoteJSON, chunks)
-	vote := strings.NewReader(voteStr)
+	var rejected *pclient.RejectedError
+	if !xerrors.As(err, &rejected) {
+		return xerrors.Errorf("unexpected error: %v", err)
+	}
 
-	buf := make([]byte, 29)
+	// ##################################### CAST BALLOTS ######################
 
-	for i := 0; i < chunks; i++ {
-		var K, C kyber.Point
-		var err error
+	fmt.Fprintln(ctx.Out, "cast ballots")
 
-		n, err := vote.Read(buf)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to read: %v", err)
-		}
+	dkgActor, exists := dkg.GetActor(formIDBuf)
+	if !exists {
+		return xerrors.Errorf("failed to get actor: %s", formID)
+	}
 
-		K, C, _, err = actor.Encrypt(buf[:n])
+	ballots := []string{
+		selectString + string(encodeID("bb")) + ":0,0,1,0\n" +
+			"text:" + string(encodeID("ee")) + ":eWVz\n\n", //encoding of "yes"
+		selectString + string(encodeID("bb")) + ":1,1,0,0\n" +
+			"text:" + string(encodeID("ee")) + ":amE=\n\n", //encoding of "ja
+		selectString + string(encodeID("bb")) + ":0,0,0,1\n" +
+			"text:" + string(encodeID("ee")) + ":b3Vp\n\n", //encoding of "oui"
+	}
 
+	for i, b := range ballots {
+		ballot, err := marshallBallot(b, dkgActor, form.ChunksPerBallot)
 		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
+			return xerrors.Errorf("failed to marshall ballot: %v", err)
 		}
 
-		kbuff, err := K.MarshalBinary()
-		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal K: %v", err)
+		castVoteRequest := ptypes.CastVoteRequest{
+			VoterID: "user" + strconv.Itoa(i+1),
+			Ballot:  ballot,
 		}
 
-		cbuff, err := C.MarshalBinary()
-		if err != nil {
-			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal C: %v", err)
-		}
+		fmt.Fprintf(ctx.Out, "cast ballot %d\n", i+1)
 
-		ballot[i] = ptypes.EGPairJSON{
-			K: kbuff,
-			C: cbuff,
+		_, err = proxy1.SubmitAndWait(c, func() (txnmanager.TransactionClientInfo, error) {
+			return proxy1.CastVote(c, formID, castVoteRequest)
+		})
+		if err != nil {
+			return xerrors.Errorf(castFailed, err)
 		}
 	}
 
-	return ballot, nil
-}
-
-// formID is hex-encoded
-func castVote(formID string, signed []byte, proxyAddr string) (string, error) {
-	resp, err := http.Post(proxyAddr+FormPathSlash+formID+"/vote", contentType, bytes.NewBuffer(signed))
-
+	form, err = proxy1.GetForm(c, formID)
 	if err != nil {
-		return "", xerrors.Errorf(failRetrieveDecryption, err)
+		return xerrors.Errorf(getFormErr, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return "", xerrors.Errorf(unexpectedStatus, resp.Status, buf)
+	dela.Logger.Info().Msg("Number of voters: " + strconv.Itoa(int(form.VoterCount)))
+
+	if int(form.VoterCount) != len(ballots) {
+		return xerrors.Errorf("unexpected number of voters: %d != %d", form.VoterCount, len(ballots))
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	// ############################# CLOSE FORM FOR REAL ###################
+
+	fmt.Fprintln(ctx.Out, "Close form (for real)")
+
+	err = updateForm(c, proxy1, formID, "close")
 	if err != nil {
-		return "", xerrors.Errorf("failed to read the body of the response: %v", err)
+		return xerrors.Errorf("failed to close form: %v", err)
 	}
 
-	resp.Body.Close()
+	// ###################################### SHUFFLE BALLOTS ##################
 
-	return string(body), nil
-}
+	fmt.Fprintln(ctx.Out, "shuffle ballots")
 
-func updateForm(secret kyber.Scalar, proxyAddr, formIDHex, action string) (int, error) {
-	msg := ptypes.UpdateFormRequest{
-		Action: action,
+	err = proxy1.Shuffle(c, formID)
+	if err != nil {
+		return xerrors.Errorf("failed to shuffle: %v", err)
 	}
 
-	signed, err := createSignedRequest(secret, msg)
+	_, err = proxy1.WaitShuffle(c, formID)
 	if err != nil {
-		return 0, createSignedErr(err)
+		return xerrors.Errorf("failed to wait for the shuffle: %v", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPut, proxyAddr+FormPathSlash+formIDHex, bytes.NewBuffer(signed))
-
+	form, err = proxy1.WaitFormStatus(c, formID, uint16(types.ShuffledBallots))
 	if err != nil {
-		return 0, xerrors.Errorf("failed to create request: %v", err)
+		return xerrors.Errorf("failed to wait for the shuffle: %v", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	logFormStatus(form)
+
+	// ###################################### REQUEST PUBLIC SHARES ############
+
+	fmt.Fprintln(ctx.Out, "request public shares")
+
+	err = proxy1.UpdateDKG(c, formID, "computePubshares")
 	if err != nil {
-		return 0, xerrors.Errorf(failRetrieveDecryption, err)
+		return xerrors.Errorf("failed to compute pubshares: %v", err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return resp.StatusCode, xerrors.Errorf(unexpectedStatus, resp.Status, buf)
+	form, err = proxy1.WaitFormStatus(c, formID, uint16(types.PubSharesSubmitted))
+	if err != nil {
+		return xerrors.Errorf("failed to wait for the pubshares: %v", err)
 	}
 
-	return 0, nil
-}
+	logFormStatus(form)
 
-func initDKG(secret kyber.Scalar, proxyAddr, formIDHex string) error {
-	setupDKG := ptypes.NewDKGRequest{
-		FormID: formIDHex,
-	}
+	// ###################################### DECRYPT BALLOTS ##################
+
+	fmt.Fprintln(ctx.Out, "decrypt ballots")
 
-	signed, err := createSignedRequest(secret, setupDKG)
+	err = updateForm(c, proxy1, formID, "combineShares")
 	if err != nil {
-		return createSignedErr(err)
+		return xerrors.Errorf("failed to combine shares: %v", err)
 	}
 
-	resp, err := http.Post(proxyAddr+"/evoting/services/dkg/actors", contentType, bytes.NewBuffer(signed))
+	// ###################################### GET FORM RESULT ##############
+
+	fmt.Fprintln(ctx.Out, "Get form result")
+
+	form, err = proxy1.GetForm(c, formID)
 	if err != nil {
-		return xerrors.Errorf("failed to post request: %v", err)
+		return xerrors.Errorf(getFormErr, err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return xerrors.Errorf(unexpectedStatus, resp.Status, buf)
+	logFormStatus(form)
+
+	results, err := proxy1.GetResults(c, formID, pclient.ResultsQuery{})
+	if err != nil {
+		return xerrors.Errorf("failed to get results: %v", err)
 	}
 
-	return nil
-}
+	dela.Logger.Info().Msg("Number of decrypted ballots : " + strconv.Itoa(results.Total))
 
-func updateDKG(secret kyber.Scalar, proxyAddr, formIDHex, action string) (int, error) {
-	msg := ptypes.UpdateDKG{
-		Action: action,
+	if results.Total != len(ballots) {
+		return xerrors.Errorf("unexpected number of decrypted ballot: %d != %d",
+			results.Total, len(ballots))
 	}
 
-	signed, err := createSignedRequest(secret, msg)
-	if err != nil {
-		return 0, createSignedErr(err)
-	}
+	// ###################################### GET ALL FORM ##############
 
-	req, err := http.NewRequest(http.MethodPut, proxyAddr+"/evoting/services/dkg/actors/"+formIDHex, bytes.NewBuffer(signed))
+	allForms, err := proxy1.GetForms(c, pclient.FormsQuery{})
 	if err != nil {
-		return 0, xerrors.Errorf("failed to create request: %v", err)
+		return xerrors.Errorf("failed to get all forms: %v", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return 0, xerrors.Errorf("failed to execute the query: %v", err)
+	dela.Logger.Info().Msgf("All forms: %v", allForms)
+
+	found := false
+	for _, f := range allForms.Forms {
+		found = found || f.FormID == formID
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		buf, _ := io.ReadAll(resp.Body)
-		return resp.StatusCode, xerrors.Errorf(unexpectedStatus, resp.Status, buf)
+	if !found {
+		return xerrors.Errorf("form %s not in allForms: %v", formID, allForms)
 	}
 
-	return 0, nil
+	return nil
 }
 
-func createSignedErr(err error) error {
-	return xerrors.Errorf("failed to create signed request: %v", err)
+// updateForm performs the action on the form and waits for its transaction.
+func updateForm(ctx context.Context, proxy *pclient.Client, formID, action string) error {
+	_, err := proxy.SubmitAndWait(ctx, func() (txnmanager.TransactionClientInfo, error) {
+		return proxy.UpdateForm(ctx, formID, ptypes.UpdateFormRequest{
+			Action: action,
+			UserID: scenarioUserID,
+		})
+	})
+
+	return err
 }
 
-func createSignedRequest(secret kyber.Scalar, msg interface{}) ([]byte, error) {
-	jsonMsg, err := json.Marshal(msg)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to marshal json: %v", err)
-	}
+func logFormStatus(form ptypes.GetFormResponse) {
+	dela.Logger.Info().Msg("Title of the form : " + form.Configuration.Title.En)
+	dela.Logger.Info().Msg("ID of the form : " + form.FormID)
+	dela.Logger.Info().Msg("Status of the form : " + strconv.Itoa(int(form.Status)))
+}
 
-	payload := base64.URLEncoding.EncodeToString(jsonMsg)
+func encodeID(ID string) types.ID {
+	return types.ID(base64.StdEncoding.EncodeToString([]byte(ID)))
+}
 
-	hash := sha256.New()
+func marshallBallot(voteStr string, actor dkg.Actor, chunks int) (ptypes.CiphervoteJSON, error) {
 
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
+	var ballot = make(ptypes.CiphervoteJSON, chunks)
+	vote := strings.NewReader(voteStr)
 
-	signature, err := schnorr.Sign(suite, secret, md)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to sign: %v", err)
-	}
+	buf := make([]byte, 29)
 
-	signed := ptypes.SignedRequest{
-		Payload:   payload,
-		Signature: hex.EncodeToString(signature),
-	}
+	for i := 0; i < chunks; i++ {
+		var K, C kyber.Point
+		var err error
 
-	signedJSON, err := json.Marshal(signed)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to create json signed: %v", err)
+		n, err := vote.Read(buf)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read: %v", err)
+		}
+
+		K, C, _, err = actor.Encrypt(buf[:n])
+
+		if err != nil {
+			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to encrypt the plaintext: %v", err)
+		}
+
+		kbuff, err := K.MarshalBinary()
+		if err != nil {
+			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal K: %v", err)
+		}
+
+		cbuff, err := C.MarshalBinary()
+		if err != nil {
+			return ptypes.CiphervoteJSON{}, xerrors.Errorf("failed to marshal C: %v", err)
+		}
+
+		ballot[i] = ptypes.EGPairJSON{
+			K: kbuff,
+			C: cbuff,
+		}
 	}
 
-	return signedJSON, nil
+	return ballot, nil
 }
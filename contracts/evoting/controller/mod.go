@@ -1,11 +1,17 @@
 package controller
 
 import (
+	"github.com/dedis/d-voting/contracts/evoting"
+	eproxy "github.com/dedis/d-voting/proxy"
+	"github.com/dedis/d-voting/services/health"
 	"go.dedis.ch/dela/cli"
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/access"
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
 )
 
 // NewController returns a new controller initializer
@@ -21,6 +27,15 @@ type controller struct {
 
 // Build implements node.Initializer.
 func (m controller) SetCommands(builder node.Builder) {
+	builder.SetStartFlags(
+		cli.StringFlag{
+			Name: "serdeformat",
+			Usage: "the format of the forms and the transactions, JSON or BINARY. " +
+				"All the nodes of a chain must use the same format",
+			Required: false,
+			Value:    string(serde.FormatJSON),
+		},
+	)
 
 	cmd := builder.SetCommand("e-voting")
 	cmd.SetDescription("interact with the evoting service")
@@ -38,6 +53,167 @@ func (m controller) SetCommands(builder node.Builder) {
 	)
 	sub.SetAction(builder.MakeAction(&RegisterAction{}))
 
+	// dvoting --config /tmp/node1 e-voting export --formID <hex> --out results
+	sub = cmd.SetSubCommand("export")
+	sub.SetDescription("export the results of a form in CSV, JSON and BLT files")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "formID",
+			Usage:    "the ID of the form, hex encoded",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "the directory where the files are written",
+			Value: ".",
+		},
+		cli.IntFlag{
+			Name:  "seats",
+			Usage: "the number of seats written in the BLT files",
+			Value: 1,
+		},
+	)
+	sub.SetAction(builder.MakeAction(&exportAction{}))
+
+	// dvoting --config /tmp/node1 e-voting export-record --formID <hex>
+	sub = cmd.SetSubCommand("export-record")
+	sub.SetDescription("export the election record of a form, to verify it offline")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "formID",
+			Usage:    "the ID of the form, hex encoded",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "the file where the record is written, <formID>-record.json by default",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&exportRecordAction{}))
+
+	// dvoting --config /tmp/node1 e-voting migrate --signer private.key \
+	//   --userID <SCIPER>
+	sub = cmd.SetSubCommand("migrate")
+	sub.SetDescription("store again the forms, the ballots and the admin list " +
+		"at the current schema version")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "signer",
+			Usage:    "Path to signer's private key",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "userID",
+			Usage:    "the SCIPER of an admin",
+			Required: true,
+		},
+	)
+	sub.SetAction(builder.MakeAction(&migrateAction{}))
+
+	// dvoting --config /tmp/node1 e-voting export-state --out state.json
+	sub = cmd.SetSubCommand("export-state")
+	sub.SetDescription("export the admin list and the finished forms of the chain")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:  "out",
+			Usage: "the file where the state is written",
+			Value: "state.json",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&exportStateAction{}))
+
+	// dvoting --config /tmp/node1 e-voting import-state --file state.json \
+	//   --signer private.key --userID <SCIPER>
+	sub = cmd.SetSubCommand("import-state")
+	sub.SetDescription("import the admin list and the finished forms exported " +
+		"from another chain")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "file",
+			Usage:    "the file of the state, written by export-state",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "signer",
+			Usage:    "Path to signer's private key",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "userID",
+			Usage:    "the SCIPER of an admin, or of the first admin of a new chain",
+			Required: true,
+		},
+	)
+	sub.SetAction(builder.MakeAction(&importStateAction{}))
+
+	// dvoting --config /tmp/node1 e-voting fsck [--repair --signer private.key \
+	//   --userID <SCIPER>]
+	sub = cmd.SetSubCommand("fsck")
+	sub.SetDescription("check the integrity of the forms in the store")
+	sub.SetFlags(
+		cli.BoolFlag{
+			Name:  "repair",
+			Usage: "submit a transaction to repair each form with repairable problems",
+		},
+		cli.StringFlag{
+			Name:  "signer",
+			Usage: "Path to signer's private key, to repair",
+		},
+		cli.StringFlag{
+			Name:  "userID",
+			Usage: "the SCIPER of an admin, to repair",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&fsckAction{}))
+
+	// dvoting --config /tmp/node1 e-voting proxykeys-add --name backend-2 \
+	//   --key <hex> [--from <RFC3339>] [--until <RFC3339>]
+	sub = cmd.SetSubCommand("proxykeys-add")
+	sub.SetDescription("trust a new key of a web backend to sign the requests")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "name",
+			Usage:    "the name of the key, recorded for the requests it signs",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     "key",
+			Usage:    "the public key, hex encoded",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "from",
+			Usage: "the RFC3339 time from which the key is valid, now by default",
+		},
+		cli.StringFlag{
+			Name:  "until",
+			Usage: "the RFC3339 time from which the key is retired, never by default",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&proxyKeysAddAction{}))
+
+	// dvoting --config /tmp/node1 e-voting proxykeys-retire --name default \
+	//   [--at <RFC3339>]
+	sub = cmd.SetSubCommand("proxykeys-retire")
+	sub.SetDescription("stop trusting a key of a web backend")
+	sub.SetFlags(
+		cli.StringFlag{
+			Name:     "name",
+			Usage:    "the name of the key",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  "at",
+			Usage: "the RFC3339 time from which the key is retired, now by default",
+		},
+	)
+	sub.SetAction(builder.MakeAction(&proxyKeysRetireAction{}))
+
+	// dvoting --config /tmp/node1 e-voting proxykeys-list
+	sub = cmd.SetSubCommand("proxykeys-list")
+	sub.SetDescription("list the keys of the web backends")
+	sub.SetAction(builder.MakeAction(&proxyKeysListAction{}))
+
 	// dvoting --config /tmp/node1 e-voting scenarioTest
 	sub = cmd.SetSubCommand("scenarioTest")
 	sub.SetDescription("evoting scenario test")
@@ -66,8 +242,38 @@ func (m controller) SetCommands(builder node.Builder) {
 	sub.SetAction(builder.MakeAction(&scenarioTestAction{}))
 }
 
-// OnStart implements node.Initializer. It creates and registers a pedersen DKG.
+// OnStart implements node.Initializer. It injects the serde context of the
+// chosen format, which is then used by the contract and the services, once it
+// checked that the state of the chain is in this format. It also
+// creates the health RPC, so that the other nodes can ping this one, and the
+// record of the handlers registered on the proxy.
 func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
+	serdeCtx, err := evoting.NewSerdeContext(ctx.String("serdeformat"))
+	if err != nil {
+		return xerrors.Errorf("failed to create serde context: %v", err)
+	}
+
+	var no mino.Mino
+	err = inj.Resolve(&no)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve mino.Mino: %v", err)
+	}
+
+	var orderingSvc ordering.Service
+	err = inj.Resolve(&orderingSvc)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	err = evoting.CheckStateFormat(orderingSvc.GetStore(), serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("the serde format doesn't match the state: %v", err)
+	}
+
+	inj.Inject(serdeCtx)
+	inj.Inject(health.NewPinger(no))
+	inj.Inject(eproxy.NewHandlerSets())
+
 	return nil
 }
 
@@ -3,13 +3,65 @@ package controller
 import (
 	"testing"
 
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/binary"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	eproxy "github.com/dedis/d-voting/proxy"
+	"github.com/dedis/d-voting/services/health"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/store/prefixed"
+	"go.dedis.ch/dela/serde"
 )
 
 func TestController_OnStart(t *testing.T) {
-	err := NewController().OnStart(node.FlagSet{}, nil)
-	require.Nil(t, err)
+	inj := node.NewInjector()
+
+	err := NewController().OnStart(node.FlagSet{"serdeformat": "XML"}, inj)
+	require.EqualError(t, err, "failed to create serde context: unknown serde format: \"XML\"")
+
+	err = NewController().OnStart(node.FlagSet{"serdeformat": "BINARY"}, inj)
+	require.EqualError(t, err,
+		"failed to resolve mino.Mino: couldn't find dependency for 'mino.Mino'")
+
+	inj.Inject(fake.Mino{})
+
+	err = NewController().OnStart(node.FlagSet{"serdeformat": "BINARY"}, inj)
+	require.EqualError(t, err, "failed to resolve ordering.Service: "+
+		"couldn't find dependency for 'ordering.Service'")
+
+	// the state of the chain is in the JSON format
+	snap := fake.NewSnapshot()
+	formatKey := prefixed.NewPrefixedKey([]byte(evoting.ContractUID), []byte(evoting.StateFormatKey))
+
+	err = snap.Set(formatKey, []byte(serde.FormatJSON))
+	require.NoError(t, err)
+
+	inj.Inject(&fake.Service{BallotSnap: snap})
+
+	err = NewController().OnStart(node.FlagSet{"serdeformat": "BINARY"}, inj)
+	require.EqualError(t, err, "the serde format doesn't match the state: "+
+		"the state is in the JSON format, not BINARY")
+
+	err = snap.Set(formatKey, []byte(binary.Format))
+	require.NoError(t, err)
+
+	err = NewController().OnStart(node.FlagSet{"serdeformat": "BINARY"}, inj)
+	require.NoError(t, err)
+
+	var ctx serde.Context
+	err = inj.Resolve(&ctx)
+	require.NoError(t, err)
+	require.Equal(t, binary.Format, ctx.GetFormat())
+
+	var pinger *health.Pinger
+	err = inj.Resolve(&pinger)
+	require.NoError(t, err)
+
+	var handlers *eproxy.HandlerSets
+	err = inj.Resolve(&handlers)
+	require.NoError(t, err)
+	require.Empty(t, handlers.List())
 }
 
 func TestController_OnStop(t *testing.T) {
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"strings"
 
@@ -24,6 +25,8 @@ import (
 	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/proof"
 	"go.dedis.ch/kyber/v3/shuffle"
 	"golang.org/x/xerrors"
@@ -81,10 +84,10 @@ func (e evotingCommand) createForm(snap store.Snapshot, step execution.Step) err
 		return err
 	}
 	if !isAdmin {
-		return xerrors.Errorf("The performing user is not an admin.")
+		return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
 	}
 
-	roster, err := e.rosterFac.AuthorityOf(e.context, rosterBuf)
+	roster, err := e.rosterFac.AuthorityOf(sjson.NewContext(), rosterBuf)
 	if err != nil {
 		return xerrors.Errorf("failed to get roster: %v", err)
 	}
@@ -119,10 +122,8 @@ func (e evotingCommand) createForm(snap store.Snapshot, step execution.Step) err
 		Configuration: tx.Configuration,
 		Status:        types.Initial,
 		// Pubkey is set by the opening command
-		BallotSize:       tx.Configuration.MaxBallotSize(),
-		PubsharesUnits:   units,
-		ShuffleInstances: []types.ShuffleInstance{},
-		DecryptedBallots: []types.Ballot{},
+		BallotSize:     tx.Configuration.MaxBallotSize(),
+		PubsharesUnits: units,
 		// We set the participant in the e-voting once for all. If it happens
 		// that 1/3 of the participants go away, the form will never end.
 		Roster:           roster,
@@ -143,6 +144,11 @@ func (e evotingCommand) createForm(snap store.Snapshot, step execution.Step) err
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	err = updateFormMetadataStore(snap, form.FormID)
 	if err != nil {
 		return xerrors.Errorf("failed to update the metadata in the store: %v", err)
@@ -186,6 +192,38 @@ func updateFormMetadataStore(snap store.Snapshot, formID string) error {
 	return nil
 }
 
+// removeFormMetadataStore removes the form from the form metadata store
+func removeFormMetadataStore(snap store.Snapshot, formID string) error {
+	formsMetadataBuf, err := snap.Get([]byte(FormsMetadataKey))
+	if err != nil {
+		return xerrors.Errorf("failed to get key '%s': %v", FormsMetadataKey, err)
+	}
+
+	formsMetadata := &types.FormsMetadata{
+		FormsIDs: types.FormIDs{},
+	}
+
+	if len(formsMetadataBuf) != 0 {
+		err := json.Unmarshal(formsMetadataBuf, formsMetadata)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal FormsMetadata: %v", err)
+		}
+	}
+
+	formsMetadata.FormsIDs.Remove(formID)
+
+	formMetadataJSON, err := json.Marshal(formsMetadata)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal FormsMetadata: %v", err)
+	}
+
+	err = snap.Set([]byte(FormsMetadataKey), formMetadataJSON)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
+	return nil
+}
+
 // openForm set the public key on the form. The public key is fetched
 // from the DKG actor. It works only if DKG is set up.
 func (e evotingCommand) openForm(snap store.Snapshot, step execution.Step) error {
@@ -211,11 +249,12 @@ func (e evotingCommand) openForm(snap store.Snapshot, step execution.Step) error
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
 	}
 
 	if form.Status != types.Initial {
-		return xerrors.Errorf("the form was opened before, current status: %d", form.Status)
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form was opened before, current status: %d", form.Status)
 	}
 
 	form.Status = types.Open
@@ -247,6 +286,11 @@ func (e evotingCommand) openForm(snap store.Snapshot, step execution.Step) error
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -269,26 +313,113 @@ func (e evotingCommand) castVote(snap store.Snapshot, step execution.Step) error
 	}
 
 	if form.Status != types.Open {
-		return xerrors.Errorf("the form is not open, current status: %d", form.Status)
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not open, current status: %d", form.Status)
 	}
 
-	isOwner, err := e.isRole(form, tx.VoterID, Voters)
+	err = e.checkVote(snap, &form, tx.VoterID, tx.Ballot, tx.Signature, tx.Nonce)
 	if err != nil {
-		return xerrors.Errorf(errIsRole, err)
+		return err
 	}
 
-	if !isOwner {
-		return xerrors.Errorf(errNoVoterPerms, tx.VoterID)
+	err = form.CastVote(e.context, snap, tx.VoterID, tx.Ballot)
+	if err != nil {
+		return xerrors.Errorf("couldn't cast vote: %v", err)
 	}
 
-	if len(tx.Ballot) != form.ChunksPerBallot() {
-		return xerrors.Errorf("the ballot has unexpected length: %d != %d",
-			len(tx.Ballot), form.ChunksPerBallot())
+	if form.Configuration.SignedBallots {
+		err = form.SetVoteNonce(snap, tx.VoterID, tx.Nonce)
+		if err != nil {
+			return xerrors.Errorf("couldn't set vote nonce: %v", err)
+		}
 	}
 
-	err = form.CastVote(e.context, snap, tx.VoterID, tx.Ballot)
+	formBuf, err := form.Serialize(e.context)
 	if err != nil {
-		return xerrors.Errorf("couldn't cast vote: %v", err)
+		return xerrors.Errorf("failed to marshal Form : %v", err)
+	}
+
+	err = snap.Set(formID, formBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
+
+	PromFormBallots.WithLabelValues(form.FormID).Set(float64(form.BallotCount))
+
+	return nil
+}
+
+// castVotes implements commands. It performs the CAST_VOTES command. All the
+// votes are checked first, and the transaction is rejected with the
+// BallotErrors if one of them is invalid, so that no vote is cast.
+func (e evotingCommand) castVotes(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.CastVotes)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	form, formID, err := e.getForm(tx.FormID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetForm, err)
+	}
+
+	if form.Status != types.Open {
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not open, current status: %d", form.Status)
+	}
+
+	if len(tx.Votes) == 0 || len(tx.Votes) > types.MaxBatchVotes {
+		return xerrors.Errorf("the batch has %d votes, expected between 1 and %d",
+			len(tx.Votes), types.MaxBatchVotes)
+	}
+
+	errs := types.BallotErrors{}
+	voters := make(map[string]bool, len(tx.Votes))
+
+	for i, vote := range tx.Votes {
+		if voters[vote.VoterID] {
+			errs[i] = fmt.Sprintf("voter %s has another ballot in the batch", vote.VoterID)
+			continue
+		}
+
+		voters[vote.VoterID] = true
+
+		err = e.checkVote(snap, &form, vote.VoterID, vote.Ballot, vote.Signature, vote.Nonce)
+		if err != nil {
+			errs[i] = err.Error()
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// the votes are cast on a stage so that a failed vote doesn't leave the
+	// ballots of the previous ones in the store
+	stage := newStagedSnapshot(snap)
+
+	for i, vote := range tx.Votes {
+		err = form.CastVote(e.context, stage, vote.VoterID, vote.Ballot)
+		if err != nil {
+			return xerrors.Errorf("couldn't cast vote %d: %v", i, err)
+		}
+
+		if form.Configuration.SignedBallots {
+			err = form.SetVoteNonce(stage, vote.VoterID, vote.Nonce)
+			if err != nil {
+				return xerrors.Errorf("couldn't set vote nonce %d: %v", i, err)
+			}
+		}
+	}
+
+	err = stage.commit()
+	if err != nil {
+		return xerrors.Errorf("failed to commit votes: %v", err)
 	}
 
 	formBuf, err := form.Serialize(e.context)
@@ -306,6 +437,94 @@ func (e evotingCommand) castVote(snap store.Snapshot, step execution.Step) error
 	return nil
 }
 
+// checkVote checks that the voter can cast the ballot on the open form.
+func (e evotingCommand) checkVote(snap store.Snapshot, form *types.Form, voterID string,
+	ballot types.Ciphervote, signature []byte, nonce uint64) error {
+
+	isVoter, err := e.isRole(*form, voterID, Voters)
+	if err != nil {
+		return xerrors.Errorf(errIsRole, err)
+	}
+
+	if !isVoter {
+		return types.NewRejection(types.RejectForbidden, errNoVoterPerms, voterID)
+	}
+
+	if len(ballot) != form.ChunksPerBallot() {
+		return types.NewRejection(types.RejectInvalidBallot,
+			"the ballot has unexpected length: %d != %d", len(ballot), form.ChunksPerBallot())
+	}
+
+	if form.Configuration.SignedBallots {
+		err = form.VerifyVote(snap, voterID, nonce, ballot, signature)
+		if err != nil {
+			return types.NewRejection(types.RejectInvalidBallot, "failed to verify ballot: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// registerVoterKey implements commands. It performs the REGISTER_VOTER_KEY
+// command. A voter registers a single key, endorsed by the VoterKeysAuthority
+// of the form, before the form is open, so that the keys can be checked before
+// any ballot is cast.
+func (e evotingCommand) registerVoterKey(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.RegisterVoterKey)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	form, _, err := e.getForm(tx.FormID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetForm, err)
+	}
+
+	if !form.Configuration.SignedBallots {
+		return xerrors.Errorf("the form doesn't use signed ballots")
+	}
+
+	if form.Status != types.Initial {
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not in its initial state, current status: %d", form.Status)
+	}
+
+	isVoter, err := e.isRole(form, tx.VoterID, Voters)
+	if err != nil {
+		return xerrors.Errorf(errIsRole, err)
+	}
+
+	if !isVoter {
+		return types.NewRejection(types.RejectForbidden, errNoVoterPerms, tx.VoterID)
+	}
+
+	registered, err := form.VoterKey(snap, tx.VoterID)
+	if err != nil {
+		return xerrors.Errorf("failed to get voter key: %v", err)
+	}
+
+	if registered != nil {
+		return xerrors.Errorf("voter %s already registered a key", tx.VoterID)
+	}
+
+	publicKey, err := tx.Verify(form.Configuration.VoterKeysAuthority)
+	if err != nil {
+		return xerrors.Errorf("failed to verify the key: %v", err)
+	}
+
+	err = form.SetVoterKey(snap, tx.VoterID, publicKey)
+	if err != nil {
+		return xerrors.Errorf("failed to set voter key: %v", err)
+	}
+
+	return nil
+}
+
 // shuffleBallots implements commands. It performs the SHUFFLE_BALLOTS command
 func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step) error {
 
@@ -330,7 +549,8 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 	}
 
 	if form.Status != types.Closed {
-		return xerrors.Errorf("the form is not in state closed (current: %d != closed: %d)",
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not in state closed (current: %d != closed: %d)",
 			form.Status, types.Closed)
 	}
 
@@ -340,11 +560,11 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
 	}
 
 	// Round starts at 0
-	expectedRound := len(form.ShuffleInstances)
+	expectedRound := form.ShuffleCount()
 
 	if tx.Round != expectedRound {
 		return xerrors.Errorf("wrong shuffle round: expected round '%d', "+
@@ -360,8 +580,8 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 
 	// Check the node who submitted the shuffle did not already submit an
 	// accepted shuffle
-	for i, shuffleInstance := range form.ShuffleInstances {
-		if bytes.Equal(shufflerPublicKey, shuffleInstance.ShufflerPublicKey) {
+	for i, publicKey := range form.ShufflerPublicKeys {
+		if bytes.Equal(shufflerPublicKey, publicKey) {
 			return xerrors.Errorf("a node already submitted a shuffle that "+
 				"has been accepted in round %d", i)
 		}
@@ -375,7 +595,7 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 
 	txSignature := tx.Signature
 
-	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, txSignature)
+	signature, err := bls.NewSignatureFactory().SignatureOf(sjson.NewContext(), txSignature)
 	if err != nil {
 		return xerrors.Errorf("could node deserialize shuffle signature : %v", err)
 	}
@@ -402,9 +622,9 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 	}
 
 	// Check that the random vector is correct
-	semiRandomStream, err := NewSemiRandomStream(hash)
+	expectedVector, err := DeriveRandomVector(hash, form.ChunksPerBallot())
 	if err != nil {
-		return xerrors.Errorf("could not create semi-random stream: %v", err)
+		return xerrors.Errorf("could not derive random vector: %v", err)
 	}
 
 	if form.ChunksPerBallot() != len(randomVector) {
@@ -413,19 +633,16 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 	}
 
 	for i := 0; i < form.ChunksPerBallot(); i++ {
-		v := suite.Scalar().Pick(semiRandomStream)
-		if !randomVector[i].Equal(v) {
+		if !randomVector[i].Equal(expectedVector[i]) {
 			return xerrors.Errorf("random vector from shuffle transaction is " +
 				"different than expected random vector")
 		}
 	}
 
 	if len(tx.ShuffledBallots) == 0 {
-		return xerrors.Errorf("there are no shuffled ballots")
+		return types.NewRejection(types.RejectInvalidStatus, "there are no shuffled ballots")
 	}
 
-	XX, YY := types.CiphervotesToPairs(tx.ShuffledBallots)
-
 	var ciphervotes []types.Ciphervote
 
 	if tx.Round == 0 {
@@ -436,22 +653,19 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 		ciphervotes = suff.Ciphervotes
 	} else {
 		// get the form's last shuffled ballots
-		lastIndex := len(form.ShuffleInstances) - 1
-		ciphervotes = form.ShuffleInstances[lastIndex].ShuffledBallots
+		lastShuffle, err := form.LastShuffle(e.context, snap)
+		if err != nil {
+			return xerrors.Errorf("couldn't get last shuffle: %v", err)
+		}
+		ciphervotes = lastShuffle.ShuffledBallots
 	}
 
 	if len(ciphervotes) < 2 {
 		return xerrors.Errorf("not enough votes: %d < 2", len(ciphervotes))
 	}
 
-	X, Y := types.CiphervotesToPairs(ciphervotes)
-
-	XXUp, YYUp, XXDown, YYDown := shuffle.GetSequenceVerifiable(suite, X, Y, XX,
-		YY, randomVector)
-
-	verifier := shuffle.Verifier(suite, nil, form.Pubkey, XXUp, YYUp, XXDown, YYDown)
-
-	err = e.prover(suite, shufflingProtocolName, verifier, tx.Proof)
+	err = verifyShuffle(e.prover, form.Pubkey, ciphervotes, tx.ShuffledBallots,
+		randomVector, tx.Proof)
 	if err != nil {
 		return xerrors.Errorf("proof verification failed: %v", err)
 	}
@@ -463,12 +677,15 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 		ShufflerPublicKey: shufflerPublicKey,
 	}
 
-	form.ShuffleInstances = append(form.ShuffleInstances, currentShuffleInstance)
+	err = form.StoreShuffle(e.context, snap, currentShuffleInstance)
+	if err != nil {
+		return xerrors.Errorf("failed to store shuffle: %v", err)
+	}
 
-	PromFormShufflingInstances.WithLabelValues(form.FormID).Set(float64(len(form.ShuffleInstances)))
+	PromFormShufflingInstances.WithLabelValues(form.FormID).Set(float64(form.ShuffleCount()))
 
 	// in case we have enough shuffled ballots, we update the status
-	if len(form.ShuffleInstances) >= form.ShuffleThreshold {
+	if form.ShuffleCount() >= form.ShuffleThreshold {
 		form.Status = types.ShuffledBallots
 		PromFormStatus.WithLabelValues(form.FormID).Set(float64(form.Status))
 	}
@@ -483,6 +700,11 @@ func (e evotingCommand) shuffleBallots(snap store.Snapshot, step execution.Step)
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -538,7 +760,8 @@ func (e evotingCommand) closeForm(snap store.Snapshot, step execution.Step) erro
 	}
 
 	if form.Status != types.Open {
-		return xerrors.Errorf("the form is not open, current status: %d", form.Status)
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not open, current status: %d", form.Status)
 	}
 
 	isOwner, err := e.isRole(form, tx.UserID, Owners)
@@ -547,7 +770,7 @@ func (e evotingCommand) closeForm(snap store.Snapshot, step execution.Step) erro
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
 	}
 
 	if form.BallotCount <= 1 {
@@ -555,6 +778,7 @@ func (e evotingCommand) closeForm(snap store.Snapshot, step execution.Step) erro
 	}
 
 	form.Status = types.Closed
+	form.BallotsTree.Close()
 	PromFormStatus.WithLabelValues(form.FormID).Set(float64(form.Status))
 
 	formBuf, err := form.Serialize(e.context)
@@ -567,6 +791,11 @@ func (e evotingCommand) closeForm(snap store.Snapshot, step execution.Step) erro
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -589,7 +818,7 @@ func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.St
 	}
 
 	if form.Status != types.ShuffledBallots {
-		return xerrors.Errorf("the ballots have not been shuffled")
+		return types.NewRejection(types.RejectInvalidStatus, "the ballots have not been shuffled")
 	}
 
 	err = isMemberOf(form.Roster, tx.PublicKey)
@@ -605,7 +834,7 @@ func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.St
 	// Check the node indeed signed the transaction:
 	txSignature := tx.Signature
 
-	signature, err := bls.NewSignatureFactory().SignatureOf(e.context, txSignature)
+	signature, err := bls.NewSignatureFactory().SignatureOf(sjson.NewContext(), txSignature)
 	if err != nil {
 		return xerrors.Errorf("could node deserialize pubShare signature: %v", err)
 	}
@@ -626,8 +855,12 @@ func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.St
 	}
 
 	// coherence check on the length of the shares submitted
-	shuffledBallots := form.ShuffleInstances[len(form.ShuffleInstances)-1].
-		ShuffledBallots
+	lastShuffle, err := form.LastShuffle(e.context, snap)
+	if err != nil {
+		return xerrors.Errorf("failed to get last shuffle: %v", err)
+	}
+
+	shuffledBallots := lastShuffle.ShuffledBallots
 	if len(tx.Pubshares) != len(shuffledBallots) {
 		return xerrors.Errorf("unexpected size of pubshares submission: %d != %d",
 			len(tx.Pubshares), len(shuffledBallots))
@@ -679,6 +912,11 @@ func (e evotingCommand) registerPubshares(snap store.Snapshot, step execution.St
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -701,8 +939,8 @@ func (e evotingCommand) combineShares(snap store.Snapshot, step execution.Step)
 	}
 
 	if form.Status != types.PubSharesSubmitted {
-		return xerrors.Errorf("the public shares have not been submitted,"+
-			" current status: %d", form.Status)
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the public shares have not been submitted, current status: %d", form.Status)
 	}
 
 	isOwner, err := e.isRole(form, tx.UserID, Owners)
@@ -711,45 +949,80 @@ func (e evotingCommand) combineShares(snap store.Snapshot, step execution.Step)
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
 	}
 
-	allPubShares := form.PubsharesUnits.Pubshares
+	lastShuffle, err := form.LastShuffle(e.context, snap)
+	if err != nil {
+		return xerrors.Errorf("failed to get last shuffle: %v", err)
+	}
 
-	shufflesSize := len(form.ShuffleInstances)
+	decryptedBallots, err := DecryptBallots(form, lastShuffle.ShuffledBallots)
+	if err != nil {
+		return xerrors.Errorf("failed to decrypt ballots: %v", err)
+	}
 
-	shuffledBallotsSize := len(form.ShuffleInstances[shufflesSize-1].ShuffledBallots)
-	ballotSize := len(form.ShuffleInstances[shufflesSize-1].ShuffledBallots[0])
+	err = form.StoreResults(e.context, snap, decryptedBallots)
+	if err != nil {
+		return xerrors.Errorf("failed to store results: %v", err)
+	}
 
-	decryptedBallots := make([]types.Ballot, shuffledBallotsSize)
+	form.Status = types.ResultAvailable
+	PromFormStatus.WithLabelValues(form.FormID).Set(float64(form.Status))
 
-	for i := 0; i < shuffledBallotsSize; i++ {
-		// decryption of one ballot:
-		marshalledBallot := strings.Builder{}
+	formBuf, err := form.Serialize(e.context)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal Form : %v", err)
+	}
 
-		for j := 0; j < ballotSize; j++ {
-			chunk, err := decrypt(i, j, allPubShares, form.PubsharesUnits.Indexes)
-			if err != nil {
-				return xerrors.Errorf("failed to decrypt (K, C): %v", err)
-			}
+	err = snap.Set(formID, formBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
 
-			marshalledBallot.Write(chunk)
-		}
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
 
-		var ballot types.Ballot
-		err = ballot.Unmarshal(marshalledBallot.String(), form)
+	return nil
+}
 
-		if err != nil {
-			dela.Logger.Warn().Msgf("Failed to unmarshal a ballot: %v", err)
-		}
+// submitCertificate implements commands. It performs the SUBMIT_CERTIFICATE
+// command. The certificate is only stored if it is a valid collective
+// signature of the results by the roster of the form.
+func (e evotingCommand) submitCertificate(snap store.Snapshot, step execution.Step) error {
 
-		decryptedBallots[i] = ballot
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
 	}
 
-	form.DecryptedBallots = decryptedBallots
+	tx, ok := msg.(types.SubmitCertificate)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
 
-	form.Status = types.ResultAvailable
-	PromFormStatus.WithLabelValues(form.FormID).Set(float64(form.Status))
+	form, formID, err := e.getForm(tx.FormID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetForm, err)
+	}
+
+	if form.Status != types.ResultAvailable {
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the results are not available, current status: %d", form.Status)
+	}
+
+	if len(form.Certificate) != 0 {
+		return types.NewRejection(types.RejectInvalidStatus, "the results are already certified")
+	}
+
+	form.Certificate = tx.Certificate
+
+	err = form.VerifyCertificate()
+	if err != nil {
+		return xerrors.Errorf("invalid certificate: %v", err)
+	}
 
 	formBuf, err := form.Serialize(e.context)
 	if err != nil {
@@ -788,7 +1061,7 @@ func (e evotingCommand) cancelForm(snap store.Snapshot, step execution.Step) err
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
 	}
 
 	form.Status = types.Canceled
@@ -804,6 +1077,11 @@ func (e evotingCommand) cancelForm(snap store.Snapshot, step execution.Step) err
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -831,7 +1109,12 @@ func (e evotingCommand) deleteForm(snap store.Snapshot, step execution.Step) err
 	}
 
 	if !isOwner {
-		return xerrors.Errorf(errNoOwnerPerms, tx.UserID)
+		return types.NewRejection(types.RejectForbidden, errNoOwnerPerms, tx.UserID)
+	}
+
+	err = form.DeleteStore(e.context, snap)
+	if err != nil {
+		return xerrors.Errorf("failed to delete the data of the form: %v", err)
 	}
 
 	err = snap.Delete(formID)
@@ -839,11 +1122,16 @@ func (e evotingCommand) deleteForm(snap store.Snapshot, step execution.Step) err
 		return xerrors.Errorf("failed to delete form: %v", err)
 	}
 
-	err = updateFormMetadataStore(snap, form.FormID)
+	err = removeFormMetadataStore(snap, form.FormID)
 	if err != nil {
 		return xerrors.Errorf("failed to update the metadata in the store: %v", err)
 	}
 
+	err = removeFormIndexes(snap, form.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -889,7 +1177,7 @@ func (e evotingCommand) manageAdminList(snap store.Snapshot, step execution.Step
 			return nil
 		}
 		if !isAdmin {
-			return xerrors.Errorf("The performing user is not an admin.")
+			return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
 		}
 
 		err = list.AddAdmin(txAddAdmin.TargetUserID)
@@ -903,7 +1191,7 @@ func (e evotingCommand) manageAdminList(snap store.Snapshot, step execution.Step
 			return err
 		}
 		if !isAdmin {
-			return xerrors.Errorf("The performing user is not an admin.")
+			return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
 		}
 
 		err = list.RemoveAdmin(txRemoveAdmin.TargetUserID)
@@ -1028,7 +1316,8 @@ func (e evotingCommand) manageOwnersVotersForm(snap store.Snapshot, step executi
 		}
 
 		if !isOwner {
-			return xerrors.Errorf(errNoOwnerPerms, txAddVoter.PerformingUserID)
+			return types.NewRejection(types.RejectForbidden,
+				errNoOwnerPerms, txAddVoter.PerformingUserID)
 		}
 
 		err = form.AddVoter(txAddVoter.TargetUserID)
@@ -1047,7 +1336,8 @@ func (e evotingCommand) manageOwnersVotersForm(snap store.Snapshot, step executi
 		}
 
 		if !isOwner {
-			return xerrors.Errorf(errNoOwnerPerms, txRemoveVoter.PerformingUserID)
+			return types.NewRejection(types.RejectForbidden,
+				errNoOwnerPerms, txRemoveVoter.PerformingUserID)
 		}
 
 		err = form.RemoveVoter(txRemoveVoter.TargetUserID)
@@ -1066,7 +1356,8 @@ func (e evotingCommand) manageOwnersVotersForm(snap store.Snapshot, step executi
 		}
 
 		if !isOwner {
-			return xerrors.Errorf(errNoOwnerPerms, txAddOwner.PerformingUserID)
+			return types.NewRejection(types.RejectForbidden,
+				errNoOwnerPerms, txAddOwner.PerformingUserID)
 		}
 
 		err = form.AddOwner(txAddOwner.TargetUserID)
@@ -1085,7 +1376,8 @@ func (e evotingCommand) manageOwnersVotersForm(snap store.Snapshot, step executi
 		}
 
 		if !isOwner {
-			return xerrors.Errorf(errNoOwnerPerms, txRemoveOwner.PerformingUserID)
+			return types.NewRejection(types.RejectForbidden,
+				errNoOwnerPerms, txRemoveOwner.PerformingUserID)
 		}
 
 		err = form.RemoveOwner(txRemoveOwner.TargetUserID)
@@ -1106,6 +1398,187 @@ func (e evotingCommand) manageOwnersVotersForm(snap store.Snapshot, step executi
 		return xerrors.Errorf("failed to set value: %v", err)
 	}
 
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
+	return nil
+}
+
+// migrate implements commands. It performs the MIGRATE command, which stores
+// again the admin list, every form and their batches of ballots so that they
+// are written at the current schema version. The records are otherwise
+// migrated only when they are next updated.
+func (e evotingCommand) migrate(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.Migrate)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	isAdmin, list, err := e.fetchAdmin(snap, tx.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
+	}
+
+	h := sha256.New()
+	h.Write([]byte(AdminListId))
+	adminListIDBuf := h.Sum(nil)
+
+	listBuf, err := list.Serialize(e.context)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal AdminList : %v", err)
+	}
+
+	err = snap.Set(adminListIDBuf, listBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
+
+	formsMetadataBuf, err := snap.Get([]byte(FormsMetadataKey))
+	if err != nil {
+		return xerrors.Errorf("failed to get key '%s': %v", FormsMetadataKey, err)
+	}
+
+	var formsMetadata types.FormsMetadata
+
+	err = json.Unmarshal(formsMetadataBuf, &formsMetadata)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal FormsMetadata: %v", err)
+	}
+
+	for _, formIDHex := range formsMetadata.FormsIDs {
+		// the ID of the admin list is also registered in the metadata
+		if formIDHex == hex.EncodeToString(adminListIDBuf) {
+			continue
+		}
+
+		form, formID, err := e.getForm(formIDHex, snap)
+		if err != nil {
+			return xerrors.Errorf(errGetForm, err)
+		}
+
+		err = form.RewriteSuffragia(e.context, snap)
+		if err != nil {
+			return xerrors.Errorf("failed to migrate the ballots of form %s: %v",
+				formIDHex, err)
+		}
+
+		// the forms created before the indexes are added to them
+		err = updateFormIndexes(snap, form)
+		if err != nil {
+			return xerrors.Errorf("failed to update the indexes of form %s: %v",
+				formIDHex, err)
+		}
+
+		formBuf, err := form.Serialize(e.context)
+		if err != nil {
+			return xerrors.Errorf("failed to marshal Form : %v", err)
+		}
+
+		err = snap.Set(formID, formBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to set value: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// importForm implements commands. It performs the IMPORT_FORM command, which
+// copies a finished form of another chain with its batches. The batches must
+// match the hashes kept by the form, so that the results and the proofs can
+// still be verified, and the form is marked as imported.
+func (e evotingCommand) importForm(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.ImportForm)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	isAdmin, _, err := e.fetchAdmin(snap, tx.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
+	}
+
+	message, err := e.formFac.Deserialize(e.context, tx.Form)
+	if err != nil {
+		return xerrors.Errorf("failed to deserialize Form: %v", err)
+	}
+
+	form, ok := message.(types.Form)
+	if !ok {
+		return xerrors.Errorf("wrong message type: %T", message)
+	}
+
+	if form.Legacy != nil {
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is of version 0 and must be migrated on its chain first")
+	}
+
+	if form.Status != types.ResultAvailable && form.Status != types.Canceled {
+		return types.NewRejection(types.RejectInvalidStatus,
+			"the form is not finished: status %d", form.Status)
+	}
+
+	formIDBuf, err := hex.DecodeString(form.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to decode formID: %v", err)
+	}
+
+	existing, err := snap.Get(formIDBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to get key %q: %v", formIDBuf, err)
+	}
+
+	if len(existing) != 0 {
+		return xerrors.Errorf("form %s already exists", form.FormID)
+	}
+
+	err = form.ImportBatches(e.context, snap, tx.Batches)
+	if err != nil {
+		return xerrors.Errorf("failed to import batches: %v", err)
+	}
+
+	form.Imported = true
+
+	formBuf, err := form.Serialize(e.context)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal Form : %v", err)
+	}
+
+	err = snap.Set(formIDBuf, formBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
+
+	err = updateFormMetadataStore(snap, form.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to update the metadata in the store: %v", err)
+	}
+
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
 	return nil
 }
 
@@ -1175,7 +1648,8 @@ func (s SemiRandomStream) XORKeyStream(dst, src []byte) {
 }
 
 // getForm gets the form from the snap. Returns the form ID NOT hex
-// encoded.
+// encoded. A form of version 0 is migrated in the snap, so that the commands
+// only update forms of the current layout.
 func (e evotingCommand) getForm(formIDHex string,
 	snap store.Snapshot) (types.Form, []byte, error) {
 
@@ -1191,6 +1665,11 @@ func (e evotingCommand) getForm(formIDHex string,
 		return form, nil, xerrors.Errorf("failed to get key %q: %v", formIDBuf, err)
 	}
 
+	err = form.MigrateLegacy(e.context, snap)
+	if err != nil {
+		return form, nil, xerrors.Errorf("failed to migrate form: %v", err)
+	}
+
 	return form, formIDBuf, nil
 }
 
@@ -1252,3 +1731,84 @@ func decrypt(ballot int, pair int, allPubShares []types.PubsharesUnit, indexes [
 
 	return decryptedMessage, nil
 }
+
+// DeriveRandomVector returns the random vector that the shuffle of a round
+// must use. It is derived from the hash of the shuffle transaction, so that
+// the shuffler cannot choose it.
+func DeriveRandomVector(hash []byte, chunksPerBallot int) ([]kyber.Scalar, error) {
+	semiRandomStream, err := NewSemiRandomStream(hash)
+	if err != nil {
+		return nil, xerrors.Errorf("could not create semi-random stream: %v", err)
+	}
+
+	randomVector := make([]kyber.Scalar, chunksPerBallot)
+
+	for i := range randomVector {
+		randomVector[i] = suite.Scalar().Pick(semiRandomStream)
+	}
+
+	return randomVector, nil
+}
+
+// VerifyShuffle checks the proof that the shuffled ballots are a shuffle of
+// the ciphervotes, re-encrypted under pubkey.
+func VerifyShuffle(pubkey kyber.Point, ciphervotes, shuffledBallots []types.Ciphervote,
+	randomVector []kyber.Scalar, shuffleProof []byte) error {
+
+	return verifyShuffle(proof.HashVerify, pubkey, ciphervotes, shuffledBallots,
+		randomVector, shuffleProof)
+}
+
+func verifyShuffle(p prover, pubkey kyber.Point, ciphervotes, shuffledBallots []types.Ciphervote,
+	randomVector []kyber.Scalar, shuffleProof []byte) error {
+
+	X, Y := types.CiphervotesToPairs(ciphervotes)
+	XX, YY := types.CiphervotesToPairs(shuffledBallots)
+
+	XXUp, YYUp, XXDown, YYDown := shuffle.GetSequenceVerifiable(suite, X, Y, XX,
+		YY, randomVector)
+
+	verifier := shuffle.Verifier(suite, nil, pubkey, XXUp, YYUp, XXDown, YYDown)
+
+	return p(suite, shufflingProtocolName, verifier, shuffleProof)
+}
+
+// DecryptBallots combines the public shares of the form to decrypt the
+// ballots of the last shuffle. A ballot that cannot be unmarshalled is kept
+// empty, so that it is counted as invalid.
+func DecryptBallots(form types.Form, shuffledBallots []types.Ciphervote) ([]types.Ballot, error) {
+	allPubShares := form.PubsharesUnits.Pubshares
+
+	if len(shuffledBallots) == 0 {
+		return nil, xerrors.Errorf("there are no shuffled ballots")
+	}
+
+	ballotSize := len(shuffledBallots[0])
+
+	decryptedBallots := make([]types.Ballot, len(shuffledBallots))
+
+	for i := range shuffledBallots {
+		// decryption of one ballot:
+		marshalledBallot := strings.Builder{}
+
+		for j := 0; j < ballotSize; j++ {
+			chunk, err := decrypt(i, j, allPubShares, form.PubsharesUnits.Indexes)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to decrypt (K, C): %v", err)
+			}
+
+			marshalledBallot.Write(chunk)
+		}
+
+		var ballot types.Ballot
+		err := ballot.Unmarshal(marshalledBallot.String(), form)
+
+		if err != nil {
+			dela.Logger.Warn().Msgf("Failed to unmarshal a ballot: %v", err)
+		}
+
+		decryptedBallots[i] = ballot
+	}
+
+	return decryptedBallots, nil
+}
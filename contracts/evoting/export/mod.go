@@ -0,0 +1,455 @@
+// Package export turns the decrypted ballots of a form into files that can be
+// processed outside of the nodes: anonymised per-ballot tables, aggregate
+// counts and .blt files for STV tools.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	selectType = "select"
+	rankType   = "rank"
+	textType   = "text"
+)
+
+// Column is a column of the per-ballot table. There is one column per choice
+// of each question.
+type Column struct {
+	QuestionID types.ID
+	Type       string
+	Question   string
+	Choice     string
+}
+
+// Count is the aggregated result of one choice. For rank questions there is
+// one count per choice and position, Position being 1-based. It is 0 for the
+// other types of questions.
+type Count struct {
+	QuestionID types.ID
+	Type       string
+	Question   string
+	Choice     string
+	Position   int
+	Count      int
+}
+
+// Results holds the anonymised results of a form, ready to be exported.
+type Results struct {
+	FormID string
+	Title  string
+
+	Columns []Column
+	// Ballots contains one row per valid ballot, each value corresponding to
+	// the column at the same index.
+	Ballots [][]string
+	Counts  []Count
+
+	// InvalidBallots is the number of ballots that could not be decoded. They
+	// are not part of Ballots nor Counts.
+	InvalidBallots int
+
+	questions []question
+	ranks     [][][]int8
+}
+
+// question is a flattened question of the configuration
+type question struct {
+	id      types.ID
+	kind    string
+	title   string
+	choices []string
+}
+
+// NewResults computes the results of the form from its decrypted ballots.
+func NewResults(formID string, configuration types.Configuration,
+	ballots []types.Ballot) Results {
+
+	res := Results{
+		FormID: formID,
+		Title:  titleOf(configuration.Title),
+	}
+
+	configuration.ForEachQuestion(func(id types.ID, q types.Question) {
+		res.questions = append(res.questions, newQuestion(id, q))
+	})
+
+	res.ranks = make([][][]int8, len(res.questions))
+
+	for _, q := range res.questions {
+		for _, choice := range q.choices {
+			res.Columns = append(res.Columns, Column{
+				QuestionID: q.id,
+				Type:       q.kind,
+				Question:   q.title,
+				Choice:     choice,
+			})
+		}
+	}
+
+	counts := make([][][]int, len(res.questions))
+	for i, q := range res.questions {
+		counts[i] = make([][]int, len(q.choices))
+		for j := range counts[i] {
+			positions := 1
+			if q.kind == rankType {
+				positions = len(q.choices)
+			}
+			counts[i][j] = make([]int, positions)
+		}
+	}
+
+	for _, ballot := range ballots {
+		if isInvalid(ballot) {
+			res.InvalidBallots++
+			continue
+		}
+
+		row := make([]string, 0, len(res.Columns))
+
+		for i, q := range res.questions {
+			row = append(row, res.addAnswers(i, q, ballot, counts[i])...)
+		}
+
+		res.Ballots = append(res.Ballots, row)
+	}
+
+	for i, q := range res.questions {
+		for j, choice := range q.choices {
+			for k, count := range counts[i][j] {
+				position := 0
+				if q.kind == rankType {
+					position = k + 1
+				}
+
+				res.Counts = append(res.Counts, Count{
+					QuestionID: q.id,
+					Type:       q.kind,
+					Question:   q.title,
+					Choice:     choice,
+					Position:   position,
+					Count:      count,
+				})
+			}
+		}
+	}
+
+	return res
+}
+
+// RankQuestions returns the IDs of the rank questions, for which a .blt file
+// can be produced.
+func (r Results) RankQuestions() []types.ID {
+	var ids []types.ID
+
+	for _, q := range r.questions {
+		if q.kind == rankType {
+			ids = append(ids, q.id)
+		}
+	}
+
+	return ids
+}
+
+// WriteBallotsCSV writes the per-ballot table as CSV. The header contains the
+// question and the choice of each column.
+func (r Results) WriteBallotsCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := make([]string, len(r.Columns)+1)
+	header[0] = "Ballot"
+
+	for i, column := range r.Columns {
+		header[i+1] = column.Question + " / " + column.Choice
+	}
+
+	err := out.Write(header)
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for i, ballot := range r.Ballots {
+		err = out.Write(append([]string{strconv.Itoa(i + 1)}, ballot...))
+		if err != nil {
+			return xerrors.Errorf("failed to write ballot %d: %v", i, err)
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// WriteCountsCSV writes the aggregate counts as CSV.
+func (r Results) WriteCountsCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	err := out.Write([]string{"QuestionID", "Type", "Question", "Choice", "Position", "Count"})
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for _, count := range r.Counts {
+		position := ""
+		if count.Position > 0 {
+			position = strconv.Itoa(count.Position)
+		}
+
+		err = out.Write([]string{string(count.QuestionID), count.Type,
+			count.Question, count.Choice, position, strconv.Itoa(count.Count)})
+		if err != nil {
+			return xerrors.Errorf("failed to write count: %v", err)
+		}
+	}
+
+	out.Flush()
+
+	return out.Error()
+}
+
+// WriteJSON writes the per-ballot table and the counts as a single JSON
+// document.
+func (r Results) WriteJSON(w io.Writer) error {
+	ballots := r.Ballots
+	if ballots == nil {
+		ballots = [][]string{}
+	}
+
+	err := json.NewEncoder(w).Encode(struct {
+		FormID         string
+		Title          string
+		Columns        []Column
+		Ballots        [][]string
+		Counts         []Count
+		InvalidBallots int
+	}{
+		FormID:         r.FormID,
+		Title:          r.Title,
+		Columns:        r.Columns,
+		Ballots:        ballots,
+		Counts:         r.Counts,
+		InvalidBallots: r.InvalidBallots,
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to encode results: %v", err)
+	}
+
+	return nil
+}
+
+// WriteBLT writes the ballots of a rank question in the .blt format used by
+// STV tools. Ballots without any ranked choice are skipped, and the choices of
+// the same rank are written as a tie.
+func (r Results) WriteBLT(w io.Writer, questionID types.ID, seats int) error {
+	index := -1
+
+	for i, q := range r.questions {
+		if q.id == questionID {
+			index = i
+		}
+	}
+
+	if index < 0 || r.questions[index].kind != rankType {
+		return xerrors.Errorf("no rank question with ID %q", questionID)
+	}
+
+	if seats <= 0 {
+		return xerrors.Errorf("invalid number of seats: %d", seats)
+	}
+
+	q := r.questions[index]
+
+	_, err := fmt.Fprintf(w, "%d %d\n", len(q.choices), seats)
+	if err != nil {
+		return xerrors.Errorf("failed to write header: %v", err)
+	}
+
+	for _, ranks := range r.ranks[index] {
+		preferences := make([]int, 0, len(ranks))
+
+		for choice, rank := range ranks {
+			if rank >= 0 {
+				preferences = append(preferences, choice)
+			}
+		}
+
+		if len(preferences) == 0 {
+			continue
+		}
+
+		sort.SliceStable(preferences, func(i, j int) bool {
+			return ranks[preferences[i]] < ranks[preferences[j]]
+		})
+
+		line := strings.Builder{}
+		line.WriteString("1")
+
+		// the choices of the same rank are tied, separated by "="
+		for i, choice := range preferences {
+			if i > 0 && ranks[choice] == ranks[preferences[i-1]] {
+				line.WriteString("=")
+			} else {
+				line.WriteString(" ")
+			}
+
+			line.WriteString(strconv.Itoa(choice + 1))
+		}
+
+		_, err = fmt.Fprintln(w, line.String()+" 0")
+		if err != nil {
+			return xerrors.Errorf("failed to write ballot: %v", err)
+		}
+	}
+
+	_, err = fmt.Fprintln(w, "0")
+	if err != nil {
+		return xerrors.Errorf("failed to write end of ballots: %v", err)
+	}
+
+	for _, choice := range q.choices {
+		_, err = fmt.Fprintln(w, bltQuote(choice))
+		if err != nil {
+			return xerrors.Errorf("failed to write candidate: %v", err)
+		}
+	}
+
+	_, err = fmt.Fprintln(w, bltQuote(q.title))
+	if err != nil {
+		return xerrors.Errorf("failed to write title: %v", err)
+	}
+
+	return nil
+}
+
+// bltQuote returns the name in double quotes for a .blt file. Only the double
+// quotes of the name are escaped, the other characters are kept as they are.
+func bltQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+}
+
+// addAnswers returns the values of the ballot for each choice of the question
+// and updates the counts.
+func (r *Results) addAnswers(index int, q question, ballot types.Ballot,
+	counts [][]int) []string {
+
+	values := make([]string, len(q.choices))
+
+	switch q.kind {
+	case selectType:
+		for i, id := range ballot.SelectResultIDs {
+			if id != q.id {
+				continue
+			}
+
+			for j, selected := range ballot.SelectResult[i] {
+				if j >= len(values) {
+					break
+				}
+
+				values[j] = "0"
+				if selected {
+					values[j] = "1"
+					counts[j][0]++
+				}
+			}
+		}
+	case rankType:
+		for i, id := range ballot.RankResultIDs {
+			if id != q.id {
+				continue
+			}
+
+			r.ranks[index] = append(r.ranks[index], ballot.RankResult[i])
+
+			for j, rank := range ballot.RankResult[i] {
+				if j >= len(values) || rank < 0 || int(rank) >= len(counts[j]) {
+					continue
+				}
+
+				values[j] = strconv.Itoa(int(rank) + 1)
+				counts[j][rank]++
+			}
+		}
+	case textType:
+		for i, id := range ballot.TextResultIDs {
+			if id != q.id {
+				continue
+			}
+
+			for j, text := range ballot.TextResult[i] {
+				if j >= len(values) {
+					break
+				}
+
+				values[j] = text
+				if text != "" {
+					counts[j][0]++
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// isInvalid returns true if the ballot was invalidated during decryption, in
+// which case it holds no answer at all.
+func isInvalid(ballot types.Ballot) bool {
+	return len(ballot.SelectResultIDs) == 0 && len(ballot.RankResultIDs) == 0 &&
+		len(ballot.TextResultIDs) == 0
+}
+
+func newQuestion(id types.ID, q types.Question) question {
+	res := question{id: id}
+
+	var choices []types.Choice
+
+	switch t := q.(type) {
+	case types.Select:
+		res.kind = selectType
+		res.title = titleOf(t.Title)
+		choices = t.Choices
+	case types.Rank:
+		res.kind = rankType
+		res.title = titleOf(t.Title)
+		choices = t.Choices
+	case types.Text:
+		res.kind = textType
+		res.title = titleOf(t.Title)
+		choices = t.Choices
+	}
+
+	if res.title == "" {
+		res.title = string(id)
+	}
+
+	res.choices = make([]string, len(choices))
+	for i, choice := range choices {
+		res.choices[i] = choice.Choice
+		if res.choices[i] == "" {
+			res.choices[i] = strconv.Itoa(i + 1)
+		}
+	}
+
+	return res
+}
+
+// titleOf returns the first non-empty translation of a title, English first.
+func titleOf(title types.Title) string {
+	for _, t := range []string{title.En, title.Fr, title.De} {
+		if t != "" {
+			return t
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/stretchr/testify/require"
+)
+
+var configuration = types.Configuration{
+	Title: types.Title{En: "Election"},
+	Scaffold: []types.Subject{{
+		ID:    "subject",
+		Order: []types.ID{"s", "r", "t"},
+		Selects: []types.Select{{
+			ID:      "s",
+			Title:   types.Title{En: "Pick"},
+			Choices: []types.Choice{{Choice: "yes"}, {Choice: "no"}},
+		}},
+		Ranks: []types.Rank{{
+			ID:      "r",
+			Title:   types.Title{Fr: "Classer"},
+			Choices: []types.Choice{{Choice: "a"}, {Choice: "b"}, {Choice: "c"}},
+		}},
+		Texts: []types.Text{{
+			ID:      "t",
+			Choices: []types.Choice{{Choice: "name"}},
+		}},
+	}},
+}
+
+var ballots = []types.Ballot{
+	{
+		SelectResultIDs: []types.ID{"s"},
+		SelectResult:    [][]bool{{true, false}},
+		RankResultIDs:   []types.ID{"r"},
+		RankResult:      [][]int8{{1, 0, 2}},
+		TextResultIDs:   []types.ID{"t"},
+		TextResult:      [][]string{{"alice"}},
+	},
+	{
+		SelectResultIDs: []types.ID{"s"},
+		SelectResult:    [][]bool{{false, true}},
+		RankResultIDs:   []types.ID{"r"},
+		RankResult:      [][]int8{{0, -1, -1}},
+		TextResultIDs:   []types.ID{"t"},
+		TextResult:      [][]string{{""}},
+	},
+	{},
+}
+
+func TestResults_New(t *testing.T) {
+	res := NewResults("abcd", configuration, ballots)
+
+	require.Equal(t, "Election", res.Title)
+	require.Equal(t, 1, res.InvalidBallots)
+	require.Len(t, res.Columns, 6)
+	require.Equal(t, "Classer", res.Columns[2].Question)
+	require.Equal(t, "t", res.Columns[5].Question)
+
+	require.Equal(t, [][]string{
+		{"1", "0", "2", "1", "3", "alice"},
+		{"0", "1", "1", "", "", ""},
+	}, res.Ballots)
+
+	// 2 select counts, 3x3 rank counts and 1 text count
+	require.Len(t, res.Counts, 12)
+	require.Equal(t, 1, res.Counts[0].Count)
+	require.Equal(t, Count{QuestionID: "r", Type: rankType, Question: "Classer",
+		Choice: "a", Position: 1, Count: 1}, res.Counts[2])
+	require.Equal(t, 1, res.Counts[11].Count)
+
+	require.Equal(t, []types.ID{"r"}, res.RankQuestions())
+}
+
+func TestResults_WriteCSV(t *testing.T) {
+	res := NewResults("abcd", configuration, ballots)
+
+	buf := new(bytes.Buffer)
+
+	err := res.WriteBallotsCSV(buf)
+	require.NoError(t, err)
+	require.Equal(t, "Ballot,Pick / yes,Pick / no,Classer / a,Classer / b,"+
+		"Classer / c,t / name\n1,1,0,2,1,3,alice\n2,0,1,1,,,\n", buf.String())
+
+	buf.Reset()
+
+	err = res.WriteCountsCSV(buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "QuestionID,Type,Question,Choice,Position,Count\n")
+	require.Contains(t, buf.String(), "s,select,Pick,yes,,1\n")
+	require.Contains(t, buf.String(), "r,rank,Classer,b,1,1\n")
+}
+
+func TestResults_WriteJSON(t *testing.T) {
+	res := NewResults("abcd", configuration, nil)
+
+	buf := new(bytes.Buffer)
+
+	err := res.WriteJSON(buf)
+	require.NoError(t, err)
+
+	var decoded struct {
+		FormID  string
+		Ballots [][]string
+		Counts  []Count
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	require.Equal(t, "abcd", decoded.FormID)
+	require.NotNil(t, decoded.Ballots)
+	require.Len(t, decoded.Counts, 12)
+}
+
+func TestResults_WriteBLT(t *testing.T) {
+	res := NewResults("abcd", configuration, ballots)
+
+	buf := new(bytes.Buffer)
+
+	err := res.WriteBLT(buf, "r", 2)
+	require.NoError(t, err)
+	require.Equal(t, "3 2\n1 2 1 3 0\n1 1 0\n0\n\"a\"\n\"b\"\n\"c\"\n\"Classer\"\n",
+		buf.String())
+
+	err = res.WriteBLT(buf, "s", 1)
+	require.EqualError(t, err, "no rank question with ID \"s\"")
+
+	err = res.WriteBLT(buf, "r", 0)
+	require.EqualError(t, err, "invalid number of seats: 0")
+}
+
+func TestResults_WriteBLT_TieAndNames(t *testing.T) {
+	config := types.Configuration{
+		Scaffold: []types.Subject{{
+			ID:    "subject",
+			Order: []types.ID{"r"},
+			Ranks: []types.Rank{{
+				ID:    "r",
+				Title: types.Title{En: `Élire le "comité"`},
+				Choices: []types.Choice{{Choice: "Zoë"}, {Choice: "Łukasz"},
+					{Choice: "李"}},
+			}},
+		}},
+	}
+
+	// the last two choices are tied on the second rank
+	res := NewResults("abcd", config, []types.Ballot{{
+		RankResultIDs: []types.ID{"r"},
+		RankResult:    [][]int8{{0, 1, 1}},
+	}})
+
+	buf := new(bytes.Buffer)
+
+	err := res.WriteBLT(buf, "r", 1)
+	require.NoError(t, err)
+	require.Equal(t, "3 1\n1 1 2=3 0\n0\n\"Zoë\"\n\"Łukasz\"\n\"李\"\n"+
+		"\"Élire le \\\"comité\\\"\"\n", buf.String())
+}
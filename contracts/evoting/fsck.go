@@ -0,0 +1,505 @@
+package evoting
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/core/execution"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// Problem is an inconsistency of the store found by Fsck.
+type Problem struct {
+	// FormID is the hex-encoded ID of the form concerned.
+	FormID string
+	// Description says what is wrong.
+	Description string
+	// Repairable is true when the REPAIR_FORM command fixes the problem.
+	Repairable bool
+}
+
+// String implements fmt.Stringer.
+func (p Problem) String() string {
+	if p.Repairable {
+		return fmt.Sprintf("%s: %s (repairable)", p.FormID, p.Description)
+	}
+
+	return fmt.Sprintf("%s: %s", p.FormID, p.Description)
+}
+
+// allStatuses are the statuses that have an index.
+var allStatuses = []types.Status{types.Initial, types.Open, types.Closed,
+	types.ShuffledBallots, types.PubSharesSubmitted, types.ResultAvailable, types.Canceled}
+
+// Fsck checks the integrity of the store of the contract, which must be read
+// with the prefix of the contract. As the keys of the store can't be listed,
+// it starts from the forms listed in the metadata and in the status indexes,
+// and checks every form, the batches of ballots it references and its
+// indexes.
+func Fsck(ctx serde.Context, formFac serde.Factory, rd store.Readable) ([]Problem, error) {
+	metadata, err := formsMetadataFromStore(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	adminListID := sha256.Sum256([]byte(AdminListId))
+
+	checked := map[string]bool{hex.EncodeToString(adminListID[:]): true}
+	problems := []Problem{}
+
+	for _, formID := range metadata.FormsIDs {
+		if checked[formID] {
+			continue
+		}
+
+		checked[formID] = true
+
+		found, err := checkForm(ctx, formFac, rd, formID, true)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to check form %s: %v", formID, err)
+		}
+
+		problems = append(problems, found...)
+	}
+
+	for _, status := range allStatuses {
+		ids, err := types.FormIndexFromStore(rd, types.StatusIndexKey(status))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get status index: %v", err)
+		}
+
+		for _, formID := range ids {
+			if checked[formID] {
+				continue
+			}
+
+			checked[formID] = true
+
+			found, err := checkForm(ctx, formFac, rd, formID, false)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to check form %s: %v", formID, err)
+			}
+
+			problems = append(problems, found...)
+		}
+	}
+
+	return problems, nil
+}
+
+// checkForm checks a form that is listed in the metadata or only in an index.
+// It returns an error only if the store can't be read.
+func checkForm(ctx serde.Context, formFac serde.Factory, rd store.Readable,
+	formID string, listed bool) ([]Problem, error) {
+
+	problem := func(repairable bool, format string, args ...interface{}) Problem {
+		return Problem{
+			FormID:      formID,
+			Description: fmt.Sprintf(format, args...),
+			Repairable:  repairable,
+		}
+	}
+
+	formIDBuf, err := hex.DecodeString(formID)
+	if err != nil {
+		return []Problem{problem(true, "invalid form ID")}, nil
+	}
+
+	buf, err := rd.Get(formIDBuf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get form: %v", err)
+	}
+
+	if len(buf) == 0 {
+		if listed {
+			return []Problem{problem(true, "listed but not stored")}, nil
+		}
+
+		return []Problem{problem(true, "indexed but not stored")}, nil
+	}
+
+	msg, err := formFac.Deserialize(ctx, buf)
+	if err != nil {
+		return []Problem{problem(false, "can't be decoded: %v", err)}, nil
+	}
+
+	form, ok := msg.(types.Form)
+	if !ok {
+		return []Problem{problem(false, "wrong message type: %T", msg)}, nil
+	}
+
+	if form.FormID != formID {
+		return []Problem{problem(false, "stored with the ID %s", form.FormID)}, nil
+	}
+
+	problems := []Problem{}
+
+	if !listed {
+		problems = append(problems, problem(true, "stored but not listed"))
+	}
+
+	for _, description := range checkBallots(ctx, rd, form) {
+		problems = append(problems, problem(description.repairable, description.text))
+	}
+
+	for _, text := range checkStatus(ctx, rd, form) {
+		problems = append(problems, problem(false, text))
+	}
+
+	for _, text := range checkIndexes(rd, form) {
+		problems = append(problems, problem(true, text))
+	}
+
+	return problems, nil
+}
+
+type finding struct {
+	text       string
+	repairable bool
+}
+
+// checkBallots checks the batches of ballots of the form, their keys and the
+// number of ballots cast.
+func checkBallots(ctx serde.Context, rd store.Readable, form types.Form) []finding {
+	if len(form.SuffragiaHashes) != len(form.SuffragiaStoreKeys) {
+		return []finding{{text: fmt.Sprintf("%d hashes for %d ballots batches",
+			len(form.SuffragiaHashes), len(form.SuffragiaStoreKeys))}}
+	}
+
+	findings := []finding{}
+
+	for i, key := range form.SuffragiaStoreKeys {
+		expected, err := form.SuffragiaBatchKey(i)
+		if err != nil || !bytes.Equal(expected, key) {
+			findings = append(findings, finding{
+				text: fmt.Sprintf("ballots batch %d is not at its key", i),
+			})
+		}
+	}
+
+	next, err := orphanBatch(rd, form)
+	if err == nil && next != nil {
+		findings = append(findings, finding{
+			text: fmt.Sprintf("ballots batch %d is stored but not referenced",
+				len(form.SuffragiaStoreKeys)),
+			repairable: true,
+		})
+	}
+
+	suff, err := form.Suffragia(ctx, rd)
+	if err != nil {
+		return append(findings, finding{text: err.Error()})
+	}
+
+	// The batches of the forms created before the tree of ballots have no
+	// digests, only the last ballot of each voter per batch.
+	if len(suff.Digests) == 0 {
+		if len(suff.VoterIDs) > int(form.BallotCount) {
+			findings = append(findings, finding{
+				text: fmt.Sprintf("%d voters for a ballot count of %d",
+					len(suff.VoterIDs), form.BallotCount),
+			})
+		}
+
+		return findings
+	}
+
+	if len(suff.Digests) != int(form.BallotCount) {
+		findings = append(findings, finding{
+			text: fmt.Sprintf("%d ballots stored for a ballot count of %d",
+				len(suff.Digests), form.BallotCount),
+			repairable: true,
+		})
+	}
+
+	if len(suff.Digests) != int(form.BallotsTree.Size) {
+		findings = append(findings, finding{
+			text: fmt.Sprintf("%d ballots stored for a tree of size %d",
+				len(suff.Digests), form.BallotsTree.Size),
+		})
+	}
+
+	return findings
+}
+
+// orphanBatch returns the key of the batch of ballots that follows the last
+// one referenced by the form, if it is stored.
+func orphanBatch(rd store.Readable, form types.Form) ([]byte, error) {
+	key, err := form.SuffragiaBatchKey(len(form.SuffragiaStoreKeys))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	return key, nil
+}
+
+// checkStatus checks that the fields set along the life of a form match its
+// status.
+func checkStatus(ctx serde.Context, rd store.Readable, form types.Form) []string {
+	problems := []string{}
+
+	active := form.Status != types.Canceled
+
+	if active && form.Status >= types.Open && form.Pubkey == nil {
+		problems = append(problems, "opened without a public key")
+	}
+
+	if form.Status <= types.Open {
+		if form.BallotsTree.Root != nil {
+			problems = append(problems, "ballots tree closed before the form")
+		}
+
+		if form.ShuffleCount() > 0 {
+			problems = append(problems, "shuffled before being closed")
+		}
+	}
+
+	if active && form.Status >= types.Closed && form.BallotsTree.Size > 0 &&
+		form.BallotsTree.Root == nil {
+		problems = append(problems, "closed with an open ballots tree")
+	}
+
+	if active && form.Status >= types.ShuffledBallots {
+		if form.ShuffleCount() < form.ShuffleThreshold {
+			problems = append(problems, fmt.Sprintf("%d shuffles for a threshold of %d",
+				form.ShuffleCount(), form.ShuffleThreshold))
+		}
+
+		for round := 0; round < form.ShuffleCount(); round++ {
+			_, err := form.Shuffle(ctx, rd, round)
+			if err != nil {
+				problems = append(problems, err.Error())
+				break
+			}
+		}
+	}
+
+	if form.Status != types.ResultAvailable && len(form.ResultsStoreKeys) > 0 {
+		problems = append(problems, "results stored before the decryption")
+	}
+
+	if form.Status == types.ResultAvailable {
+		results, err := form.Results(ctx, rd, 0, 0)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if len(results) != int(form.ResultsCount) {
+			problems = append(problems, fmt.Sprintf("%d results stored for a count of %d",
+				len(results), form.ResultsCount))
+		}
+	}
+
+	_, err := form.VoterIDs(ctx, rd, 0, 0)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
+}
+
+// checkIndexes checks the summary of the form and the indexes by status and
+// by owner.
+func checkIndexes(rd store.Readable, form types.Form) []string {
+	problems := []string{}
+
+	summary, found, err := types.FormSummaryFromStore(rd, form.FormID)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !found {
+		problems = append(problems, "no summary")
+	} else if summary.Status != form.Status || !sameInts(summary.Owners, form.Owners) {
+		problems = append(problems, "summary out of date")
+	}
+
+	for _, status := range allStatuses {
+		ids, err := types.FormIndexFromStore(rd, types.StatusIndexKey(status))
+		if err != nil {
+			return append(problems, err.Error())
+		}
+
+		indexed := ids.Contains(form.FormID) >= 0
+
+		if status == form.Status && !indexed {
+			problems = append(problems, "missing from the index of its status")
+		}
+
+		if status != form.Status && indexed {
+			problems = append(problems, fmt.Sprintf("in the index of status %d", status))
+		}
+	}
+
+	for _, owner := range form.Owners {
+		ids, err := types.FormIndexFromStore(rd, types.OwnerIndexKey(owner))
+		if err != nil {
+			return append(problems, err.Error())
+		}
+
+		if ids.Contains(form.FormID) < 0 {
+			problems = append(problems, fmt.Sprintf("missing from the index of owner %d", owner))
+		}
+	}
+
+	return problems
+}
+
+// repairForm implements commands. It performs the REPAIR_FORM command, which
+// fixes the problems found by Fsck that are marked as repairable.
+func (e evotingCommand) repairForm(snap store.Snapshot, step execution.Step) error {
+	msg, err := e.getTransaction(step.Current)
+	if err != nil {
+		return xerrors.Errorf(errGetTransaction, err)
+	}
+
+	tx, ok := msg.(types.RepairForm)
+	if !ok {
+		return xerrors.Errorf(errWrongTx, msg)
+	}
+
+	isAdmin, _, err := e.fetchAdmin(snap, tx.UserID)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return types.NewRejection(types.RejectForbidden, "The performing user is not an admin.")
+	}
+
+	err = removeFormIndexes(snap, tx.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to remove the indexes: %v", err)
+	}
+
+	for _, status := range allStatuses {
+		err = removeFromIndex(snap, types.StatusIndexKey(status), tx.FormID)
+		if err != nil {
+			return xerrors.Errorf("failed to update status index: %v", err)
+		}
+	}
+
+	formIDBuf, err := hex.DecodeString(tx.FormID)
+
+	var buf []byte
+	if err == nil {
+		buf, err = snap.Get(formIDBuf)
+		if err != nil {
+			return xerrors.Errorf("failed to get key %q: %v", formIDBuf, err)
+		}
+	}
+
+	// A form that doesn't exist is removed from the list of the forms.
+	if len(buf) == 0 {
+		err = removeFormMetadataStore(snap, tx.FormID)
+		if err != nil {
+			return xerrors.Errorf("failed to update the metadata in the store: %v", err)
+		}
+
+		return nil
+	}
+
+	form, _, err := e.getForm(tx.FormID, snap)
+	if err != nil {
+		return xerrors.Errorf(errGetForm, err)
+	}
+
+	metadata, err := formsMetadataFromStore(snap)
+	if err != nil {
+		return err
+	}
+
+	if metadata.FormsIDs.Contains(form.FormID) < 0 {
+		err = updateFormMetadataStore(snap, form.FormID)
+		if err != nil {
+			return xerrors.Errorf("failed to update the metadata in the store: %v", err)
+		}
+	}
+
+	orphan, err := orphanBatch(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to get the next ballots batch: %v", err)
+	}
+
+	if orphan != nil {
+		err = snap.Delete(orphan)
+		if err != nil {
+			return xerrors.Errorf("failed to delete ballots batch: %v", err)
+		}
+	}
+
+	suff, err := form.Suffragia(e.context, snap)
+	if err != nil {
+		return xerrors.Errorf("failed to get ballots: %v", err)
+	}
+
+	if len(suff.Digests) > 0 {
+		form.BallotCount = uint32(len(suff.Digests))
+	}
+
+	formBuf, err := form.Serialize(e.context)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal Form : %v", err)
+	}
+
+	err = snap.Set(formIDBuf, formBuf)
+	if err != nil {
+		return xerrors.Errorf("failed to set value: %v", err)
+	}
+
+	err = updateFormIndexes(snap, form)
+	if err != nil {
+		return xerrors.Errorf("failed to update the indexes: %v", err)
+	}
+
+	return nil
+}
+
+// formsMetadataFromStore returns the list of the forms, which is empty if no
+// form was created yet.
+func formsMetadataFromStore(rd store.Readable) (types.FormsMetadata, error) {
+	var metadata types.FormsMetadata
+
+	buf, err := rd.Get([]byte(FormsMetadataKey))
+	if err != nil {
+		return metadata, xerrors.Errorf("failed to get key '%s': %v", FormsMetadataKey, err)
+	}
+
+	if len(buf) == 0 {
+		return metadata, nil
+	}
+
+	err = json.Unmarshal(buf, &metadata)
+	if err != nil {
+		return metadata, xerrors.Errorf("failed to unmarshal FormsMetadata: %v", err)
+	}
+
+	return metadata, nil
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,192 @@
+package evoting
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/store"
+)
+
+func TestFsck_Clean(t *testing.T) {
+	snap, _ := initFsckStore(t)
+
+	problems, err := Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	problems, err = Fsck(ctx, formFac, fake.NewSnapshot())
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
+
+func TestFsck_Problems(t *testing.T) {
+	snap, form := initFsckStore(t)
+
+	err := updateFormMetadataStore(snap, "beef")
+	require.NoError(t, err)
+
+	// the form counts a ballot that isn't stored
+	form.BallotCount++
+	storeFsckForm(t, snap, form)
+
+	err = removeFromIndex(snap, types.StatusIndexKey(types.Open), fakeFormID)
+	require.NoError(t, err)
+
+	orphan, err := form.SuffragiaBatchKey(len(form.SuffragiaStoreKeys))
+	require.NoError(t, err)
+
+	err = snap.Set(orphan, []byte("ballots"))
+	require.NoError(t, err)
+
+	problems, err := Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Equal(t, []Problem{
+		{FormID: fakeFormID, Description: "ballots batch 1 is stored but not referenced", Repairable: true},
+		{FormID: fakeFormID, Description: "2 ballots stored for a ballot count of 3", Repairable: true},
+		{FormID: fakeFormID, Description: "missing from the index of its status", Repairable: true},
+		{FormID: "beef", Description: "listed but not stored", Repairable: true},
+	}, problems)
+
+	require.Equal(t, "beef: listed but not stored (repairable)", problems[3].String())
+
+	form.Pubkey = nil
+	form.ResultsStoreKeys = [][]byte{[]byte("results")}
+	storeFsckForm(t, snap, form)
+
+	problems, err = Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Contains(t, problems, Problem{FormID: fakeFormID, Description: "opened without a public key"})
+	require.Contains(t, problems, Problem{FormID: fakeFormID, Description: "results stored before the decryption"})
+
+	err = snap.Set(dummyFormIDBuff, []byte("dummy"))
+	require.NoError(t, err)
+
+	problems, err = Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Len(t, problems, 2)
+	require.Contains(t, problems[0].Description, "can't be decoded")
+	require.False(t, problems[0].Repairable)
+
+	err = snap.Set([]byte(FormsMetadataKey), []byte("dummy"))
+	require.NoError(t, err)
+
+	_, err = Fsck(ctx, formFac, snap)
+	require.ErrorContains(t, err, "failed to unmarshal FormsMetadata")
+}
+
+func TestFsck_NotListed(t *testing.T) {
+	snap, _ := initFsckStore(t)
+
+	err := removeFormMetadataStore(snap, fakeFormID)
+	require.NoError(t, err)
+
+	problems, err := Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Equal(t, []Problem{
+		{FormID: fakeFormID, Description: "stored but not listed", Repairable: true},
+	}, problems)
+}
+
+func TestCommand_RepairForm(t *testing.T) {
+	_, contract := initFormAndContract(123456)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	repair := types.RepairForm{FormID: fakeFormID, UserID: dummyUserAdminID}
+
+	err := cmd.repairForm(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.repairForm(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.repairForm(fake.NewSnapshot(), makeStep(t, FormArg, string(mustSerialize(t, repair))))
+	require.EqualError(t, err, "failed to get the AdminList: No list found")
+
+	snap, form := initFsckStore(t)
+
+	notAdmin := types.RepairForm{FormID: fakeFormID, UserID: "654321"}
+	err = cmd.repairForm(snap, makeStep(t, FormArg, string(mustSerialize(t, notAdmin))))
+	require.EqualError(t, err, "[forbidden] The performing user is not an admin.")
+
+	err = removeFormMetadataStore(snap, fakeFormID)
+	require.NoError(t, err)
+
+	form.BallotCount++
+	form.Status = types.Closed
+	storeFsckForm(t, snap, form)
+
+	orphan, err := form.SuffragiaBatchKey(len(form.SuffragiaStoreKeys))
+	require.NoError(t, err)
+
+	err = snap.Set(orphan, []byte("ballots"))
+	require.NoError(t, err)
+
+	err = updateFormMetadataStore(snap, "beef")
+	require.NoError(t, err)
+
+	err = cmd.repairForm(snap, makeStep(t, FormArg, string(mustSerialize(t, repair))))
+	require.NoError(t, err)
+
+	missing := types.RepairForm{FormID: "beef", UserID: dummyUserAdminID}
+	err = cmd.repairForm(snap, makeStep(t, FormArg, string(mustSerialize(t, missing))))
+	require.NoError(t, err)
+
+	problems, err := Fsck(ctx, formFac, snap)
+	require.NoError(t, err)
+	require.Equal(t, []Problem{
+		{FormID: fakeFormID, Description: "closed with an open ballots tree"},
+	}, problems)
+
+	repaired, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), repaired.BallotCount)
+
+	ids, err := types.FormIndexFromStore(snap, types.StatusIndexKey(types.Open))
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+// initFsckStore returns a store with the admin list and an open form with two
+// ballots, indexed as the contract does.
+func initFsckStore(t *testing.T) (store.Snapshot, types.Form) {
+	form, _ := initFormAndContract(123456)
+	form.Status = types.Open
+	form.Pubkey = suite.Point()
+
+	snap := fake.NewSnapshot()
+
+	err := initializeAdminList(snap, 123456, ctx)
+	require.NoError(t, err)
+
+	Ks, Cs, _ := fakeKCPoints(2)
+
+	for i, voter := range []string{"user1", "user2"} {
+		err = form.CastVote(ctx, snap, voter, types.Ciphervote{{K: Ks[i], C: Cs[i]}})
+		require.NoError(t, err)
+	}
+
+	err = updateFormMetadataStore(snap, form.FormID)
+	require.NoError(t, err)
+
+	storeFsckForm(t, snap, form)
+
+	return snap, form
+}
+
+// storeFsckForm stores the form and updates its indexes.
+func storeFsckForm(t *testing.T, snap store.Snapshot, form types.Form) {
+	formIDBuf, err := hex.DecodeString(form.FormID)
+	require.NoError(t, err)
+
+	err = snap.Set(formIDBuf, mustSerialize(t, form))
+	require.NoError(t, err)
+
+	err = updateFormIndexes(snap, form)
+	require.NoError(t, err)
+}
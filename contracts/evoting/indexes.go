@@ -0,0 +1,161 @@
+package evoting
+
+import (
+	"encoding/json"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/core/store"
+	"golang.org/x/xerrors"
+)
+
+// updateFormIndexes stores the summary of the form and moves the form between
+// the indexes by status and by owner according to the changes since the last
+// summary. It must be called every time the title, the status, the public key
+// or the owners of a form change.
+func updateFormIndexes(snap store.Snapshot, form types.Form) error {
+	previous, found, err := types.FormSummaryFromStore(snap, form.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to get summary: %v", err)
+	}
+
+	summary, err := types.NewFormSummary(form)
+	if err != nil {
+		return xerrors.Errorf("failed to create summary: %v", err)
+	}
+
+	if !found || previous.Status != summary.Status {
+		if found {
+			err = removeFromIndex(snap, types.StatusIndexKey(previous.Status), form.FormID)
+			if err != nil {
+				return xerrors.Errorf("failed to update status index: %v", err)
+			}
+		}
+
+		err = addToIndex(snap, types.StatusIndexKey(summary.Status), form.FormID)
+		if err != nil {
+			return xerrors.Errorf("failed to update status index: %v", err)
+		}
+	}
+
+	for _, owner := range previous.Owners {
+		if !containsInt(summary.Owners, owner) {
+			err = removeFromIndex(snap, types.OwnerIndexKey(owner), form.FormID)
+			if err != nil {
+				return xerrors.Errorf("failed to update owner index: %v", err)
+			}
+		}
+	}
+
+	for _, owner := range summary.Owners {
+		if !containsInt(previous.Owners, owner) {
+			err = addToIndex(snap, types.OwnerIndexKey(owner), form.FormID)
+			if err != nil {
+				return xerrors.Errorf("failed to update owner index: %v", err)
+			}
+		}
+	}
+
+	key, err := types.FormSummaryKey(form.FormID)
+	if err != nil {
+		return xerrors.Errorf("failed to get summary key: %v", err)
+	}
+
+	buf, err := json.Marshal(summary)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal summary: %v", err)
+	}
+
+	err = snap.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("failed to set summary: %v", err)
+	}
+
+	return nil
+}
+
+// removeFormIndexes removes the form from the indexes and deletes its
+// summary.
+func removeFormIndexes(snap store.Snapshot, formID string) error {
+	summary, found, err := types.FormSummaryFromStore(snap, formID)
+	if err != nil {
+		return xerrors.Errorf("failed to get summary: %v", err)
+	}
+
+	if !found {
+		return nil
+	}
+
+	err = removeFromIndex(snap, types.StatusIndexKey(summary.Status), formID)
+	if err != nil {
+		return xerrors.Errorf("failed to update status index: %v", err)
+	}
+
+	for _, owner := range summary.Owners {
+		err = removeFromIndex(snap, types.OwnerIndexKey(owner), formID)
+		if err != nil {
+			return xerrors.Errorf("failed to update owner index: %v", err)
+		}
+	}
+
+	key, err := types.FormSummaryKey(formID)
+	if err != nil {
+		return xerrors.Errorf("failed to get summary key: %v", err)
+	}
+
+	err = snap.Delete(key)
+	if err != nil {
+		return xerrors.Errorf("failed to delete summary: %v", err)
+	}
+
+	return nil
+}
+
+func addToIndex(snap store.Snapshot, key []byte, formID string) error {
+	ids, err := types.FormIndexFromStore(snap, key)
+	if err != nil {
+		return err
+	}
+
+	if ids.Contains(formID) >= 0 {
+		return nil
+	}
+
+	ids = append(ids, formID)
+
+	return setIndex(snap, key, ids)
+}
+
+func removeFromIndex(snap store.Snapshot, key []byte, formID string) error {
+	ids, err := types.FormIndexFromStore(snap, key)
+	if err != nil {
+		return err
+	}
+
+	ids.Remove(formID)
+
+	return setIndex(snap, key, ids)
+}
+
+func setIndex(snap store.Snapshot, key []byte, ids types.FormIDs) error {
+	buf, err := json.Marshal(ids)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal index: %v", err)
+	}
+
+	err = snap.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("failed to set index: %v", err)
+	}
+
+	return nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
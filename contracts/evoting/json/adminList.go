@@ -15,6 +15,7 @@ func (adminListFormat) Encode(ctx serde.Context, message serde.Message) ([]byte,
 	}
 
 	adminListJSON := AdminListJSON{
+		Version:   types.SchemaVersion,
 		AdminList: adminList.AdminList,
 	}
 
@@ -29,9 +30,10 @@ func (adminListFormat) Encode(ctx serde.Context, message serde.Message) ([]byte,
 func (adminListFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
 	var adminListJSON AdminListJSON
 
-	err := ctx.Unmarshal(data, &adminListJSON)
+	err := adminListSchema.decode(ctx, data, &adminListJSON,
+		func() int { return adminListJSON.Version })
 	if err != nil {
-		return nil, xerrors.Errorf("failed to unmarshal form: %v", err)
+		return nil, xerrors.Errorf("failed to decode admin list: %v", err)
 	}
 
 	return types.AdminList{
@@ -40,6 +42,9 @@ func (adminListFormat) Decode(ctx serde.Context, data []byte) (serde.Message, er
 }
 
 type AdminListJSON struct {
+	// Version is the schema version of the record, see types.SchemaVersion.
+	Version int
+
 	// List of SCIPER with admin rights
 	AdminList []int
 }
@@ -2,7 +2,6 @@ package json
 
 import (
 	"encoding/hex"
-	"encoding/json"
 
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
@@ -46,9 +45,14 @@ func (formFormat) Encode(ctx serde.Context, message serde.Message) ([]byte, erro
 			suffragiaHashes[i] = hex.EncodeToString(sufH)
 		}
 
-		shuffleInstances, err := encodeShuffleInstances(ctx, m.ShuffleInstances)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to encode shuffle instances: %v", err)
+		shuffles := make([]string, len(m.ShuffleStoreKeys))
+		for i, key := range m.ShuffleStoreKeys {
+			shuffles[i] = hex.EncodeToString(key)
+		}
+
+		shuffleHashes := make([]string, len(m.ShuffleHashes))
+		for i, hash := range m.ShuffleHashes {
+			shuffleHashes[i] = hex.EncodeToString(hash)
 		}
 
 		rosterBuf, err := m.Roster.Serialize(ctx)
@@ -62,22 +66,48 @@ func (formFormat) Encode(ctx serde.Context, message serde.Message) ([]byte, erro
 				err)
 		}
 
+		results := make([]string, len(m.ResultsStoreKeys))
+		for i, key := range m.ResultsStoreKeys {
+			results[i] = hex.EncodeToString(key)
+		}
+
+		resultsHashes := make([]string, len(m.ResultsHashes))
+		for i, hash := range m.ResultsHashes {
+			resultsHashes[i] = hex.EncodeToString(hash)
+		}
+
+		legacy, err := encodeLegacyForm(ctx, m.Legacy)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to encode legacy form: %v", err)
+		}
+
 		formJSON := FormJSON{
-			Configuration:    m.Configuration,
-			FormID:           m.FormID,
-			Status:           uint16(m.Status),
-			Pubkey:           pubkey,
-			BallotSize:       m.BallotSize,
-			Suffragias:       suffragias,
-			SuffragiaHashes:  suffragiaHashes,
-			BallotCount:      m.BallotCount,
-			ShuffleInstances: shuffleInstances,
-			ShuffleThreshold: m.ShuffleThreshold,
-			PubsharesUnits:   pubsharesUnits,
-			DecryptedBallots: m.DecryptedBallots,
-			RosterBuf:        rosterBuf,
-			Owners:           m.Owners,
-			Voters:           m.Voters,
+			Version:            types.SchemaVersion,
+			Configuration:      m.Configuration,
+			FormID:             m.FormID,
+			Status:             uint16(m.Status),
+			Pubkey:             pubkey,
+			BallotSize:         m.BallotSize,
+			Suffragias:         suffragias,
+			SuffragiaHashes:    suffragiaHashes,
+			BallotCount:        m.BallotCount,
+			VoterCount:         m.VoterCount,
+			BallotsTree:        BallotsTreeJSON(m.BallotsTree),
+			Shuffles:           shuffles,
+			ShuffleHashes:      shuffleHashes,
+			ShufflerPublicKeys: m.ShufflerPublicKeys,
+			ShuffleThreshold:   m.ShuffleThreshold,
+			PubsharesUnits:     pubsharesUnits,
+			Results:            results,
+			ResultsHashes:      resultsHashes,
+			ResultsCount:       m.ResultsCount,
+			ResultsPerBatch:    m.ResultsPerBatch,
+			Certificate:        m.Certificate,
+			RosterBuf:          rosterBuf,
+			Owners:             m.Owners,
+			Voters:             m.Voters,
+			Imported:           m.Imported,
+			Legacy:             legacy,
 		}
 
 		buff, err := ctx.Marshal(&formJSON)
@@ -95,9 +125,9 @@ func (formFormat) Encode(ctx serde.Context, message serde.Message) ([]byte, erro
 func (formFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
 	var formJSON FormJSON
 
-	err := ctx.Unmarshal(data, &formJSON)
+	err := formSchema.decode(ctx, data, &formJSON, func() int { return formJSON.Version })
 	if err != nil {
-		return nil, xerrors.Errorf("failed to unmarshal form: %v", err)
+		return nil, xerrors.Errorf("failed to decode form: %v", err)
 	}
 
 	var pubKey kyber.Point
@@ -126,9 +156,36 @@ func (formFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		}
 	}
 
-	shuffleInstances, err := decodeShuffleInstances(ctx, formJSON.ShuffleInstances)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to decode shuffle instances: %v", err)
+	results := make([][]byte, len(formJSON.Results))
+	for i, key := range formJSON.Results {
+		results[i], err = hex.DecodeString(key)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode results-address: %v", err)
+		}
+	}
+
+	resultsHashes := make([][]byte, len(formJSON.ResultsHashes))
+	for i, hash := range formJSON.ResultsHashes {
+		resultsHashes[i], err = hex.DecodeString(hash)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode results-hash: %v", err)
+		}
+	}
+
+	shuffles := make([][]byte, len(formJSON.Shuffles))
+	for i, key := range formJSON.Shuffles {
+		shuffles[i], err = hex.DecodeString(key)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode shuffle-address: %v", err)
+		}
+	}
+
+	shuffleHashes := make([][]byte, len(formJSON.ShuffleHashes))
+	for i, hash := range formJSON.ShuffleHashes {
+		shuffleHashes[i], err = hex.DecodeString(hash)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode shuffle-hash: %v", err)
+		}
 	}
 
 	fac := ctx.GetFactory(ctypes.RosterKey{})
@@ -147,6 +204,11 @@ func (formFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		return nil, xerrors.Errorf("failed to decode pubShares submissions: %v", err)
 	}
 
+	legacy, err := decodeLegacyForm(ctx, formJSON.Legacy)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode legacy form: %v", err)
+	}
+
 	return types.Form{
 		Configuration:      formJSON.Configuration,
 		FormID:             formJSON.FormID,
@@ -156,27 +218,39 @@ func (formFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error)
 		SuffragiaStoreKeys: suffragias,
 		SuffragiaHashes:    suffragiaHashes,
 		BallotCount:        formJSON.BallotCount,
-		ShuffleInstances:   shuffleInstances,
+		VoterCount:         formJSON.VoterCount,
+		BallotsTree:        types.BallotsTree(formJSON.BallotsTree),
+		ShuffleStoreKeys:   shuffles,
+		ShuffleHashes:      shuffleHashes,
+		ShufflerPublicKeys: formJSON.ShufflerPublicKeys,
 		ShuffleThreshold:   formJSON.ShuffleThreshold,
 		PubsharesUnits:     pubSharesSubmissions,
-		DecryptedBallots:   formJSON.DecryptedBallots,
+		ResultsStoreKeys:   results,
+		ResultsHashes:      resultsHashes,
+		ResultsCount:       formJSON.ResultsCount,
+		ResultsPerBatch:    formJSON.ResultsPerBatch,
+		Certificate:        formJSON.Certificate,
 		Roster:             roster,
 		Owners:             formJSON.Owners,
 		Voters:             formJSON.Voters,
+		Imported:           formJSON.Imported,
+		Legacy:             legacy,
 	}, nil
 }
 
 // FormJSON defines the Form in the JSON format
 type FormJSON struct {
+	// Version is the schema version of the record, see types.SchemaVersion.
+	Version int
+
 	Configuration types.Configuration
 
 	// FormID is the hex-encoded SHA256 of the transaction ID that creates
 	// the form
 	FormID string
 
-	AdminID string
-	Status  uint16
-	Pubkey  []byte `json:"Pubkey,omitempty"`
+	Status uint16
+	Pubkey []byte `json:"Pubkey,omitempty"`
 
 	// BallotSize represents the total size in bytes of one ballot. It is used
 	// to pad smaller ballots such that all  ballots cast have the same size
@@ -188,13 +262,24 @@ type FormJSON struct {
 	// BallotCount represents the total number of ballots cast.
 	BallotCount uint32
 
+	// VoterCount is the number of users who cast at least one ballot.
+	VoterCount uint32
+
 	// SuffragiaHashes are the hex-encoded sha256-hashes of the ballots
 	// in every Suffragia.
 	SuffragiaHashes []string
 
-	// ShuffleInstances is all the shuffles, along with their proof and identity
-	// of shuffler.
-	ShuffleInstances []ShuffleInstanceJSON
+	// BallotsTree is the Merkle tree over the digests of the ballots cast.
+	BallotsTree BallotsTreeJSON
+
+	// Shuffles are the hex-encoded addresses of the shuffles, one per round.
+	Shuffles []string
+
+	// ShuffleHashes are the hex-encoded hashes of the shuffles.
+	ShuffleHashes []string
+
+	// ShufflerPublicKeys are the keys of the nodes who made the shuffles.
+	ShufflerPublicKeys [][]byte
 
 	// ShuffleThreshold is set based on the roster. We save it so we do not have
 	// to compute it based on the roster each time we need it.
@@ -202,7 +287,22 @@ type FormJSON struct {
 
 	PubsharesUnits PubsharesUnitsJSON
 
-	DecryptedBallots []types.Ballot
+	// Results are the hex-encoded addresses of the batches of decrypted
+	// ballots.
+	Results []string
+
+	// ResultsHashes are the hex-encoded sha256-hashes of the batches of
+	// decrypted ballots.
+	ResultsHashes []string
+
+	// ResultsCount is the total number of decrypted ballots.
+	ResultsCount uint32
+
+	// ResultsPerBatch is the number of ballots in every batch but the last.
+	ResultsPerBatch uint32
+
+	// Certificate is the collective signature of the results by the roster.
+	Certificate []byte `json:",omitempty"`
 
 	// roster is set when the form is created based on the current
 	// roster of the node stored in the global state. The roster will not change
@@ -216,112 +316,72 @@ type FormJSON struct {
 
 	// Store the list of SCIPER of user that are Voters on the form.
 	Voters []int
-}
 
-// ShuffleInstanceJSON defines the JSON representation of a shuffle instance
-type ShuffleInstanceJSON struct {
-	// ShuffledBallots contains the list of shuffled ciphertext for this round
-	ShuffledBallots []json.RawMessage
+	// Imported is set when the form was copied from another chain.
+	Imported bool `json:",omitempty"`
 
-	// ShuffleProofs is the proof of the shuffle for this round
-	ShuffleProofs []byte
-
-	// ShufflerPublicKey is the key of the node who made the given shuffle.
-	ShufflerPublicKey []byte
+	// Legacy holds the fields of a form of version 0 that are now stored
+	// outside of the form, until the form is migrated in the store.
+	Legacy *LegacyFormJSON `json:",omitempty"`
 }
 
-func encodeShuffleInstances(ctx serde.Context,
-	shuffleInstances []types.ShuffleInstance) ([]ShuffleInstanceJSON, error) {
-
-	res := make([]ShuffleInstanceJSON, len(shuffleInstances))
-
-	for i, shuffleInstance := range shuffleInstances {
-		shuffleInstanceJSON, err := encodeShuffleInstance(ctx, shuffleInstance)
-		if err != nil {
-			return nil, xerrors.Errorf("failed to encode shuffle instance: %v", err)
-		}
-
-		res[i] = shuffleInstanceJSON
-	}
-
-	return res, nil
+// LegacyFormJSON defines the JSON representation of types.LegacyForm. The
+// fields keep their names of version 0.
+type LegacyFormJSON struct {
+	ShuffleInstances []ShuffleInstanceJSON
+	DecryptedBallots []types.Ballot
 }
 
-func encodeShuffleInstance(ctx serde.Context,
-	shuffleInstance types.ShuffleInstance) (ShuffleInstanceJSON, error) {
-
-	var res ShuffleInstanceJSON
-	shuffledBallots := make([]json.RawMessage, len(shuffleInstance.ShuffledBallots))
-
-	for i, shuffledBallot := range shuffleInstance.ShuffledBallots {
-		buff, err := shuffledBallot.Serialize(ctx)
-		if err != nil {
-			return res, xerrors.Errorf("failed to serialize ciphervote: %v", err)
-		}
-
-		shuffledBallots[i] = buff
-	}
-
-	res = ShuffleInstanceJSON{
-		ShuffledBallots:   shuffledBallots,
-		ShuffleProofs:     shuffleInstance.ShuffleProofs,
-		ShufflerPublicKey: shuffleInstance.ShufflerPublicKey,
-	}
-
-	return res, nil
+// BallotsTreeJSON defines the JSON representation of the tree of the ballots
+// cast.
+type BallotsTreeJSON struct {
+	Size     uint32
+	Frontier [][]byte
+	Root     []byte `json:",omitempty"`
 }
 
-func decodeShuffleInstances(ctx serde.Context,
-	shuffleInstancesJSON []ShuffleInstanceJSON) ([]types.ShuffleInstance, error) {
+func encodeLegacyForm(ctx serde.Context, legacy *types.LegacyForm) (*LegacyFormJSON, error) {
+	if legacy == nil {
+		return nil, nil
+	}
 
-	res := make([]types.ShuffleInstance, len(shuffleInstancesJSON))
+	shuffles := make([]ShuffleInstanceJSON, len(legacy.ShuffleInstances))
 
-	for i, shuffleInstanceJSON := range shuffleInstancesJSON {
-		shuffleInstance, err := decodeShuffleInstance(ctx, shuffleInstanceJSON)
+	for i, shuffle := range legacy.ShuffleInstances {
+		shuffleJSON, err := encodeShuffleInstance(ctx, shuffle)
 		if err != nil {
-			return nil, xerrors.Errorf("failed to decode shuffle instance: %v", err)
+			return nil, xerrors.Errorf("failed to encode shuffle: %v", err)
 		}
 
-		res[i] = shuffleInstance
+		shuffles[i] = shuffleJSON
 	}
 
-	return res, nil
+	return &LegacyFormJSON{
+		ShuffleInstances: shuffles,
+		DecryptedBallots: legacy.DecryptedBallots,
+	}, nil
 }
 
-func decodeShuffleInstance(ctx serde.Context,
-	shuffleInstanceJSON ShuffleInstanceJSON) (types.ShuffleInstance, error) {
-
-	var res types.ShuffleInstance
-	fac := ctx.GetFactory(types.CiphervoteKey{})
-
-	factory, ok := fac.(types.CiphervoteFactory)
-	if !ok {
-		return res, xerrors.Errorf("invalid ciphervote factory: '%T'", fac)
+func decodeLegacyForm(ctx serde.Context, legacyJSON *LegacyFormJSON) (*types.LegacyForm, error) {
+	if legacyJSON == nil {
+		return nil, nil
 	}
 
-	shuffledBallots := make([]types.Ciphervote, len(shuffleInstanceJSON.ShuffledBallots))
+	shuffles := make([]types.ShuffleInstance, len(legacyJSON.ShuffleInstances))
 
-	for i, ciphervoteJSON := range shuffleInstanceJSON.ShuffledBallots {
-		msg, err := factory.Deserialize(ctx, ciphervoteJSON)
+	for i, shuffleJSON := range legacyJSON.ShuffleInstances {
+		shuffle, err := decodeShuffleInstance(ctx, shuffleJSON)
 		if err != nil {
-			return res, xerrors.Errorf("failed to deserialize shuffle instance json: %v", err)
-		}
-
-		ciphervote, ok := msg.(types.Ciphervote)
-		if !ok {
-			return res, xerrors.Errorf("wrong type: '%T'", msg)
+			return nil, xerrors.Errorf("failed to decode shuffle: %v", err)
 		}
 
-		shuffledBallots[i] = ciphervote
-	}
-
-	res = types.ShuffleInstance{
-		ShuffledBallots:   shuffledBallots,
-		ShuffleProofs:     shuffleInstanceJSON.ShuffleProofs,
-		ShufflerPublicKey: shuffleInstanceJSON.ShufflerPublicKey,
+		shuffles[i] = shuffle
 	}
 
-	return res, nil
+	return &types.LegacyForm{
+		ShuffleInstances: shuffles,
+		DecryptedBallots: legacyJSON.DecryptedBallots,
+	}, nil
 }
 
 // PubsharesUnitJSON is the JSON representation of a submission of pubShares by
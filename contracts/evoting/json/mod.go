@@ -10,6 +10,9 @@ import (
 func init() {
 	types.RegisterFormFormat(serde.FormatJSON, formFormat{})
 	types.RegisterSuffragiaFormat(serde.FormatJSON, suffragiaFormat{})
+	types.RegisterResultsFormat(serde.FormatJSON, resultsFormat{})
+	types.RegisterVotersFormat(serde.FormatJSON, votersFormat{})
+	types.RegisterShuffleFormat(serde.FormatJSON, shuffleFormat{})
 	types.RegisterCiphervoteFormat(serde.FormatJSON, ciphervoteFormat{})
 	types.RegisterTransactionFormat(serde.FormatJSON, transactionFormat{})
 	types.RegisterAdminListFormat(serde.FormatJSON, adminListFormat{})
@@ -0,0 +1,52 @@
+package json
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// resultsFormat defines how the batches of decrypted ballots are
+// encoded/decoded using the JSON format.
+//
+// - implements serde.FormatEngine
+type resultsFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (resultsFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	switch m := msg.(type) {
+	case types.ResultsBatch:
+		rJSON := ResultsBatchJSON{
+			Ballots: m.Ballots,
+		}
+
+		buff, err := ctx.Marshal(&rJSON)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal results batch: %v", err)
+		}
+
+		return buff, nil
+	default:
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+}
+
+// Decode implements serde.FormatEngine
+func (resultsFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	var rJSON ResultsBatchJSON
+
+	err := ctx.Unmarshal(data, &rJSON)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal results batch: %v", err)
+	}
+
+	return types.ResultsBatch{
+		Ballots: rJSON.Ballots,
+	}, nil
+}
+
+// ResultsBatchJSON defines the JSON representation of a batch of decrypted
+// ballots.
+type ResultsBatchJSON struct {
+	Ballots []types.Ballot
+}
@@ -0,0 +1,166 @@
+package json
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// migration upgrades the fields of a stored record by one version.
+type migration func(fields map[string]json.RawMessage) error
+
+// schema defines the migrations of a stored record. The migration at index i
+// upgrades a record from version i to version i+1, so there must be exactly
+// types.SchemaVersion migrations.
+type schema struct {
+	name       string
+	migrations []migration
+}
+
+// formSchema is the schema of the stored forms.
+var formSchema = schema{
+	name: "form",
+	migrations: []migration{
+		// Version 1 drops the AdminID, which was never set, and moves the
+		// shuffles and the results, which are stored outside of the form
+		// since then, to Legacy until the form is migrated in the store.
+		func(fields map[string]json.RawMessage) error {
+			delete(fields, "AdminID")
+
+			err := countResults(fields)
+			if err != nil {
+				return err
+			}
+
+			return moveToLegacy(fields, "ShuffleInstances", "DecryptedBallots")
+		},
+		// Version 2 adds Imported, which is false when it is missing.
+		noMigration,
+	},
+}
+
+// adminListSchema is the schema of the stored admin list.
+var adminListSchema = schema{
+	name: "admin list",
+	migrations: []migration{
+		// Versions 1 and 2 only stamp the version.
+		noMigration,
+		noMigration,
+	},
+}
+
+// suffragiaSchema is the schema of the stored batches of ballots.
+var suffragiaSchema = schema{
+	name: "suffragia",
+	migrations: []migration{
+		// Versions 1 and 2 only stamp the version.
+		noMigration,
+		noMigration,
+	},
+}
+
+func noMigration(map[string]json.RawMessage) error {
+	return nil
+}
+
+// countResults sets the ResultsCount of a form of version 0 from its
+// DecryptedBallots.
+func countResults(fields map[string]json.RawMessage) error {
+	var ballots []json.RawMessage
+
+	buf, found := fields["DecryptedBallots"]
+	if found {
+		err := json.Unmarshal(buf, &ballots)
+		if err != nil {
+			return xerrors.Errorf("failed to unmarshal decrypted ballots: %v", err)
+		}
+	}
+
+	fields["ResultsCount"] = json.RawMessage(strconv.Itoa(len(ballots)))
+
+	return nil
+}
+
+// moveToLegacy moves the given fields of a form of version 0 to its Legacy
+// field, see types.LegacyForm. Legacy is set even if none of the fields is
+// present, since it marks the forms that must be migrated in the store.
+func moveToLegacy(fields map[string]json.RawMessage, names ...string) error {
+	legacy := make(map[string]json.RawMessage)
+
+	for _, name := range names {
+		value, found := fields[name]
+		if found {
+			legacy[name] = value
+			delete(fields, name)
+		}
+	}
+
+	buf, err := json.Marshal(legacy)
+	if err != nil {
+		return xerrors.Errorf("failed to marshal legacy fields: %v", err)
+	}
+
+	fields["Legacy"] = buf
+
+	return nil
+}
+
+// upgrade returns the record, at the given version, upgraded to the current
+// version.
+func (s schema) upgrade(data []byte, version int) ([]byte, error) {
+	if version < 0 || version > types.SchemaVersion {
+		return nil, xerrors.Errorf("unsupported %s version: %d", s.name, version)
+	}
+
+	var fields map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal %s: %v", s.name, err)
+	}
+
+	for v := version; v < types.SchemaVersion; v++ {
+		err = s.migrations[v](fields)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to migrate %s from version %d: %v",
+				s.name, v, err)
+		}
+	}
+
+	fields["Version"] = json.RawMessage(strconv.Itoa(types.SchemaVersion))
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal %s: %v", s.name, err)
+	}
+
+	return data, nil
+}
+
+// decode unmarshals the record into v, after upgrading it when its version,
+// read by the given function, isn't the current one.
+func (s schema) decode(ctx serde.Context, data []byte, v interface{}, version func() int) error {
+	err := ctx.Unmarshal(data, v)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal %s: %v", s.name, err)
+	}
+
+	if version() == types.SchemaVersion {
+		return nil
+	}
+
+	data, err = s.upgrade(data, version())
+	if err != nil {
+		return err
+	}
+
+	err = ctx.Unmarshal(data, v)
+	if err != nil {
+		return xerrors.Errorf("failed to unmarshal %s: %v", s.name, err)
+	}
+
+	return nil
+}
@@ -32,9 +32,9 @@ func (suffragiaFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, err
 func (suffragiaFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
 	var sJson SuffragiaJSON
 
-	err := ctx.Unmarshal(data, &sJson)
+	err := suffragiaSchema.decode(ctx, data, &sJson, func() int { return sJson.Version })
 	if err != nil {
-		return nil, xerrors.Errorf("failed to unmarshal form: %v", err)
+		return nil, xerrors.Errorf("failed to decode suffragia: %v", err)
 	}
 
 	return decodeSuffragia(ctx, sJson)
@@ -42,8 +42,12 @@ func (suffragiaFormat) Decode(ctx serde.Context, data []byte) (serde.Message, er
 
 // SuffragiaJSON defines the JSON representation of a suffragia.
 type SuffragiaJSON struct {
+	// Version is the schema version of the record, see types.SchemaVersion.
+	Version int
+
 	VoterIDs    []string
 	Ciphervotes []json.RawMessage
+	Digests     [][]byte `json:",omitempty"`
 }
 
 func encodeSuffragia(ctx serde.Context, suffragia types.Suffragia) (SuffragiaJSON, error) {
@@ -58,8 +62,10 @@ func encodeSuffragia(ctx serde.Context, suffragia types.Suffragia) (SuffragiaJSO
 		ciphervotes[i] = buff
 	}
 	return SuffragiaJSON{
+		Version:     types.SchemaVersion,
 		VoterIDs:    suffragia.VoterIDs,
 		Ciphervotes: ciphervotes,
+		Digests:     suffragia.Digests,
 	}, nil
 }
 
@@ -91,6 +97,7 @@ func decodeSuffragia(ctx serde.Context, suffragiaJSON SuffragiaJSON) (types.Suff
 	res = types.Suffragia{
 		VoterIDs:    suffragiaJSON.VoterIDs,
 		Ciphervotes: ciphervotes,
+		Digests:     suffragiaJSON.Digests,
 	}
 
 	return res, nil
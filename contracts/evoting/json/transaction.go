@@ -42,9 +42,29 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 			FormID:     t.FormID,
 			VoterID:    t.VoterID,
 			Ciphervote: ballot,
+			Signature:  t.Signature,
+			Nonce:      t.Nonce,
 		}
 
 		m = TransactionJSON{CastVote: &cv}
+	case types.CastVotes:
+		votes := make([]BatchVoteJSON, len(t.Votes))
+
+		for i, vote := range t.Votes {
+			ballot, err := vote.Ballot.Serialize(ctx)
+			if err != nil {
+				return nil, xerrors.Errorf("failed to serialize ballot %d: %v", i, err)
+			}
+
+			votes[i] = BatchVoteJSON{
+				VoterID:    vote.VoterID,
+				Ciphervote: ballot,
+				Signature:  vote.Signature,
+				Nonce:      vote.Nonce,
+			}
+		}
+
+		m = TransactionJSON{CastVotes: &CastVotesJSON{FormID: t.FormID, Votes: votes}}
 	case types.CloseForm:
 		ce := CloseFormJSON{
 			FormID: t.FormID,
@@ -107,6 +127,13 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 		}
 
 		m = TransactionJSON{CombineShares: &db}
+	case types.SubmitCertificate:
+		sc := SubmitCertificateJSON{
+			FormID:      t.FormID,
+			Certificate: t.Certificate,
+		}
+
+		m = TransactionJSON{SubmitCertificate: &sc}
 	case types.CancelForm:
 		ce := CancelFormJSON{
 			FormID: t.FormID,
@@ -117,6 +144,7 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 	case types.DeleteForm:
 		de := DeleteFormJSON{
 			FormID: t.FormID,
+			UserID: t.UserID,
 		}
 
 		m = TransactionJSON{DeleteForm: &de}
@@ -166,6 +194,37 @@ func (transactionFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, e
 		}
 
 		m = TransactionJSON{RemoveVoter: &removeVoter}
+	case types.Migrate:
+		migrate := MigrateJSON{
+			UserID: t.UserID,
+		}
+
+		m = TransactionJSON{Migrate: &migrate}
+	case types.ImportForm:
+		importForm := ImportFormJSON{
+			Form:    t.Form,
+			Batches: t.Batches,
+			UserID:  t.UserID,
+		}
+
+		m = TransactionJSON{ImportForm: &importForm}
+	case types.RepairForm:
+		repairForm := RepairFormJSON{
+			FormID: t.FormID,
+			UserID: t.UserID,
+		}
+
+		m = TransactionJSON{RepairForm: &repairForm}
+	case types.RegisterVoterKey:
+		registerVoterKey := RegisterVoterKeyJSON{
+			FormID:      t.FormID,
+			VoterID:     t.VoterID,
+			PublicKey:   t.PublicKey,
+			Signature:   t.Signature,
+			Endorsement: t.Endorsement,
+		}
+
+		m = TransactionJSON{RegisterVoterKey: &registerVoterKey}
 	default:
 		return nil, xerrors.Errorf("unknown type: '%T", msg)
 	}
@@ -204,6 +263,13 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 			return nil, xerrors.Errorf("failed to decode cast vote: %v", err)
 		}
 
+		return msg, nil
+	case m.CastVotes != nil:
+		msg, err := decodeCastVotes(ctx, *m.CastVotes)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode cast votes: %v", err)
+		}
+
 		return msg, nil
 	case m.CloseForm != nil:
 		return types.CloseForm{
@@ -229,6 +295,11 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 			FormID: m.CombineShares.FormID,
 			UserID: m.CombineShares.UserID,
 		}, nil
+	case m.SubmitCertificate != nil:
+		return types.SubmitCertificate{
+			FormID:      m.SubmitCertificate.FormID,
+			Certificate: m.SubmitCertificate.Certificate,
+		}, nil
 	case m.CancelForm != nil:
 		return types.CancelForm{
 			FormID: m.CancelForm.FormID,
@@ -237,6 +308,7 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 	case m.DeleteForm != nil:
 		return types.DeleteForm{
 			FormID: m.DeleteForm.FormID,
+			UserID: m.DeleteForm.UserID,
 		}, nil
 	case m.AddAdmin != nil:
 		return types.AddAdmin{
@@ -272,6 +344,29 @@ func (transactionFormat) Decode(ctx serde.Context, data []byte) (serde.Message,
 			TargetUserID:     m.RemoveVoter.TargetUserID,
 			PerformingUserID: m.RemoveVoter.PerformingUserID,
 		}, nil
+	case m.Migrate != nil:
+		return types.Migrate{
+			UserID: m.Migrate.UserID,
+		}, nil
+	case m.ImportForm != nil:
+		return types.ImportForm{
+			Form:    m.ImportForm.Form,
+			Batches: m.ImportForm.Batches,
+			UserID:  m.ImportForm.UserID,
+		}, nil
+	case m.RepairForm != nil:
+		return types.RepairForm{
+			FormID: m.RepairForm.FormID,
+			UserID: m.RepairForm.UserID,
+		}, nil
+	case m.RegisterVoterKey != nil:
+		return types.RegisterVoterKey{
+			FormID:      m.RegisterVoterKey.FormID,
+			VoterID:     m.RegisterVoterKey.VoterID,
+			PublicKey:   m.RegisterVoterKey.PublicKey,
+			Signature:   m.RegisterVoterKey.Signature,
+			Endorsement: m.RegisterVoterKey.Endorsement,
+		}, nil
 	}
 
 	return nil, xerrors.Errorf("empty type: %s", data)
@@ -287,6 +382,7 @@ type TransactionJSON struct {
 	ShuffleBallots    *ShuffleBallotsJSON    `json:",omitempty"`
 	RegisterPubShares *RegisterPubSharesJSON `json:",omitempty"`
 	CombineShares     *CombineSharesJSON     `json:",omitempty"`
+	SubmitCertificate *SubmitCertificateJSON `json:",omitempty"`
 	CancelForm        *CancelFormJSON        `json:",omitempty"`
 	DeleteForm        *DeleteFormJSON        `json:",omitempty"`
 	AddAdmin          *AddAdminJSON          `json:",omitempty"`
@@ -295,6 +391,11 @@ type TransactionJSON struct {
 	RemoveOwner       *RemoveOwnerJSON       `json:",omitempty"`
 	AddVoter          *AddVoterJSON          `json:",omitempty"`
 	RemoveVoter       *RemoveVoterJSON       `json:",omitempty"`
+	Migrate           *MigrateJSON           `json:",omitempty"`
+	ImportForm        *ImportFormJSON        `json:",omitempty"`
+	RepairForm        *RepairFormJSON        `json:",omitempty"`
+	RegisterVoterKey  *RegisterVoterKeyJSON  `json:",omitempty"`
+	CastVotes         *CastVotesJSON         `json:",omitempty"`
 }
 
 // CreateFormJSON is the JSON representation of a CreateForm transaction
@@ -314,6 +415,23 @@ type CastVoteJSON struct {
 	FormID     string
 	VoterID    string
 	Ciphervote json.RawMessage
+	Signature  []byte `json:",omitempty"`
+	Nonce      uint64 `json:",omitempty"`
+}
+
+// CastVotesJSON is the JSON representation of a CastVotes transaction
+type CastVotesJSON struct {
+	FormID string
+	Votes  []BatchVoteJSON
+}
+
+// BatchVoteJSON is the JSON representation of a vote of a CastVotes
+// transaction
+type BatchVoteJSON struct {
+	VoterID    string
+	Ciphervote json.RawMessage
+	Signature  []byte `json:",omitempty"`
+	Nonce      uint64 `json:",omitempty"`
 }
 
 // CloseFormJSON is the JSON representation of a CloseForm transaction
@@ -348,6 +466,13 @@ type CombineSharesJSON struct {
 	UserID string
 }
 
+// SubmitCertificateJSON is the JSON representation of a SubmitCertificate
+// transaction
+type SubmitCertificateJSON struct {
+	FormID      string
+	Certificate []byte
+}
+
 // CancelFormJSON is the JSON representation of a CancelForm transaction
 type CancelFormJSON struct {
 	FormID string
@@ -357,6 +482,7 @@ type CancelFormJSON struct {
 // DeleteFormJSON is the JSON representation of a DeleteForm transaction
 type DeleteFormJSON struct {
 	FormID string
+	UserID string
 }
 
 // AdminList
@@ -405,6 +531,34 @@ type RemoveVoterJSON struct {
 	PerformingUserID string
 }
 
+// MigrateJSON is the JSON representation of a Migrate transaction
+type MigrateJSON struct {
+	UserID string
+}
+
+// ImportFormJSON is the JSON representation of an ImportForm transaction
+type ImportFormJSON struct {
+	Form    []byte
+	Batches [][]byte
+	UserID  string
+}
+
+// RepairFormJSON is the JSON representation of a RepairForm transaction
+type RepairFormJSON struct {
+	FormID string
+	UserID string
+}
+
+// RegisterVoterKeyJSON is the JSON representation of a RegisterVoterKey
+// transaction
+type RegisterVoterKeyJSON struct {
+	FormID      string
+	VoterID     string
+	PublicKey   []byte
+	Signature   []byte
+	Endorsement []byte
+}
+
 func decodeCastVote(ctx serde.Context, m CastVoteJSON) (serde.Message, error) {
 	factory := ctx.GetFactory(types.CiphervoteKey{})
 	if factory == nil {
@@ -422,9 +576,44 @@ func decodeCastVote(ctx serde.Context, m CastVoteJSON) (serde.Message, error) {
 	}
 
 	return types.CastVote{
-		FormID:  m.FormID,
-		VoterID: m.VoterID,
-		Ballot:  ciphervote,
+		FormID:    m.FormID,
+		VoterID:   m.VoterID,
+		Ballot:    ciphervote,
+		Signature: m.Signature,
+		Nonce:     m.Nonce,
+	}, nil
+}
+
+func decodeCastVotes(ctx serde.Context, m CastVotesJSON) (serde.Message, error) {
+	factory := ctx.GetFactory(types.CiphervoteKey{})
+	if factory == nil {
+		return nil, xerrors.Errorf("missing ciphervote factory")
+	}
+
+	votes := make([]types.BatchVote, len(m.Votes))
+
+	for i, vote := range m.Votes {
+		msg, err := factory.Deserialize(ctx, vote.Ciphervote)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to deserialize ciphervote %d: %v", i, err)
+		}
+
+		ciphervote, ok := msg.(types.Ciphervote)
+		if !ok {
+			return nil, xerrors.Errorf("invalid ciphervote: '%T'", msg)
+		}
+
+		votes[i] = types.BatchVote{
+			VoterID:   vote.VoterID,
+			Ballot:    ciphervote,
+			Signature: vote.Signature,
+			Nonce:     vote.Nonce,
+		}
+	}
+
+	return types.CastVotes{
+		FormID: m.FormID,
+		Votes:  votes,
 	}, nil
 }
 
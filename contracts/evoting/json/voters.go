@@ -0,0 +1,51 @@
+package json
+
+import (
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// votersFormat defines how the batches of voters are encoded/decoded using
+// the JSON format.
+//
+// - implements serde.FormatEngine
+type votersFormat struct{}
+
+// Encode implements serde.FormatEngine
+func (votersFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	switch m := msg.(type) {
+	case types.VotersBatch:
+		vJSON := VotersBatchJSON{
+			VoterIDs: m.VoterIDs,
+		}
+
+		buff, err := ctx.Marshal(&vJSON)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to marshal voters batch: %v", err)
+		}
+
+		return buff, nil
+	default:
+		return nil, xerrors.Errorf("Unknown format: %T", msg)
+	}
+}
+
+// Decode implements serde.FormatEngine
+func (votersFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	var vJSON VotersBatchJSON
+
+	err := ctx.Unmarshal(data, &vJSON)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal voters batch: %v", err)
+	}
+
+	return types.VotersBatch{
+		VoterIDs: vJSON.VoterIDs,
+	}, nil
+}
+
+// VotersBatchJSON defines the JSON representation of a batch of voters.
+type VotersBatchJSON struct {
+	VoterIDs []string
+}
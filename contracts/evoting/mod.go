@@ -12,7 +12,7 @@ import (
 	"go.dedis.ch/dela/core/store"
 	"go.dedis.ch/dela/core/store/prefixed"
 	"go.dedis.ch/dela/serde"
-	"go.dedis.ch/dela/serde/json"
+	sjson "go.dedis.ch/dela/serde/json"
 
 	"go.dedis.ch/kyber/v3/proof"
 	"go.dedis.ch/kyber/v3/suites"
@@ -20,6 +20,8 @@ import (
 
 	// Register the JSON format for the form
 	_ "github.com/dedis/d-voting/contracts/evoting/json"
+
+	"github.com/dedis/d-voting/contracts/evoting/binary"
 )
 
 var (
@@ -63,6 +65,10 @@ const (
 	// FormsMetadataKey is the key at which form metadata are saved in
 	// the storage.
 	FormsMetadataKey = "FormsMetadataKey"
+
+	// StateFormatKey is the key at which the contract records the serde
+	// format of the state the first time it writes to it.
+	StateFormatKey = "StateFormatKey"
 )
 
 var suite = suites.MustFind("Ed25519")
@@ -82,6 +88,11 @@ const (
 	// transaction. The content is defined by the type of command.
 	FormArg = "evoting:arg"
 
+	// ProxyKeyArg is the key at which the proxy stores the name of the web
+	// backend key that signed the request of the transaction. The contract
+	// ignores it.
+	ProxyKeyArg = "evoting:proxykey"
+
 	// credentialAllCommand defines the credential command that is allowed to
 	// perform all commands.
 	credentialAllCommand = "all"
@@ -99,10 +110,16 @@ type commands interface {
 	shuffleBallots(snap store.Snapshot, step execution.Step) error
 	registerPubshares(snap store.Snapshot, step execution.Step) error
 	combineShares(snap store.Snapshot, step execution.Step) error
+	submitCertificate(snap store.Snapshot, step execution.Step) error
 	cancelForm(snap store.Snapshot, step execution.Step) error
 	deleteForm(snap store.Snapshot, step execution.Step) error
 	manageAdminList(snap store.Snapshot, step execution.Step) error
 	manageOwnersVotersForm(snap store.Snapshot, step execution.Step) error
+	migrate(snap store.Snapshot, step execution.Step) error
+	importForm(snap store.Snapshot, step execution.Step) error
+	repairForm(snap store.Snapshot, step execution.Step) error
+	registerVoterKey(snap store.Snapshot, step execution.Step) error
+	castVotes(snap store.Snapshot, step execution.Step) error
 }
 
 // Command defines a type of command for the value contract
@@ -125,6 +142,9 @@ const (
 
 	// CmdCombineShares is the command to decrypt ballots
 	CmdCombineShares Command = "COMBINE_SHARES"
+	// CmdSubmitCertificate is the command to store the collective signature
+	// of the results
+	CmdSubmitCertificate Command = "SUBMIT_CERTIFICATE"
 	// CmdCancelForm is the command to cancel a form
 	CmdCancelForm Command = "CANCEL_FORM"
 
@@ -145,6 +165,24 @@ const (
 	CmdAddVoterForm Command = "ADD_VOTER"
 	// CmdRemoveVoterForm is the command to remove an Voter to a form
 	CmdRemoveVoterForm Command = "REMOVE_VOTER"
+
+	// CmdMigrate is the command to store again the records at the current
+	// schema version
+	CmdMigrate Command = "MIGRATE"
+
+	// CmdImportForm is the command to copy a finished form of another chain
+	CmdImportForm Command = "IMPORT_FORM"
+
+	// CmdRepairForm is the command to fix the repairable problems of a form
+	// found by Fsck
+	CmdRepairForm Command = "REPAIR_FORM"
+
+	// CmdRegisterVoterKey is the command to register the public key of a
+	// voter on a form with signed ballots
+	CmdRegisterVoterKey Command = "REGISTER_VOTER_KEY"
+
+	// CmdCastVotes is the command to cast a batch of votes
+	CmdCastVotes Command = "CAST_VOTES"
 )
 
 // NewCreds creates new credentials for a evoting contract execution. We might
@@ -178,11 +216,10 @@ type Contract struct {
 	transactionFac serde.Factory
 }
 
-// NewContract creates a new Value contract
+// NewContract creates a new Value contract. The context defines the format of
+// the forms and the transactions, it must be the same on all the nodes.
 func NewContract(srvc access.Service,
-	pedersen dkg.DKG, rosterFac authority.Factory) Contract {
-
-	ctx := json.NewContext()
+	pedersen dkg.DKG, rosterFac authority.Factory, ctx serde.Context) Contract {
 
 	ciphervoteFac := types.CiphervoteFactory{}
 	formFac := types.NewFormFactory(ciphervoteFac, rosterFac)
@@ -206,6 +243,73 @@ func NewContract(srvc access.Service,
 	return contract
 }
 
+// NewSerdeContext returns the context of the given format, JSON or BINARY.
+// The forms and the transactions are stored in this format, so all the nodes
+// of a chain must use the same one.
+func NewSerdeContext(format string) (serde.Context, error) {
+	switch serde.Format(format) {
+	case serde.FormatJSON:
+		return sjson.NewContext(), nil
+	case binary.Format:
+		return binary.NewContext(), nil
+	default:
+		return serde.Context{}, xerrors.Errorf("unknown serde format: %q", format)
+	}
+}
+
+// CheckStateFormat returns an error if the state of the contract in the store
+// is not in the format of the context, so that a node doesn't start with
+// another format than the one of its chain. The state written before the
+// format was recorded is checked by reading its admin list.
+func CheckStateFormat(rd store.Readable, ctx serde.Context) error {
+	rd = prefixed.NewReadable(ContractUID, rd)
+
+	format, err := rd.Get([]byte(StateFormatKey))
+	if err != nil {
+		return xerrors.Errorf("failed to get the format: %v", err)
+	}
+
+	if len(format) > 0 {
+		if serde.Format(format) != ctx.GetFormat() {
+			return xerrors.Errorf("the state is in the %s format, not %s", format, ctx.GetFormat())
+		}
+
+		return nil
+	}
+
+	_, err = types.AdminListFromStore(ctx, types.AdminListFactory{}, rd, AdminListId)
+	if err != nil && err.Error() != "No list found" {
+		return xerrors.Errorf("the admin list is not in the %s format: %v", ctx.GetFormat(), err)
+	}
+
+	return nil
+}
+
+// recordFormat records the format of the context in the state if it is the
+// first write, and otherwise checks that the state is in this format.
+func (c Contract) recordFormat(snap store.Snapshot) error {
+	format, err := snap.Get([]byte(StateFormatKey))
+	if err != nil {
+		return xerrors.Errorf("failed to get the format: %v", err)
+	}
+
+	if len(format) > 0 {
+		if serde.Format(format) != c.context.GetFormat() {
+			return xerrors.Errorf("the state is in the %s format, not %s",
+				format, c.context.GetFormat())
+		}
+
+		return nil
+	}
+
+	err = snap.Set([]byte(StateFormatKey), []byte(c.context.GetFormat()))
+	if err != nil {
+		return xerrors.Errorf("failed to set the format: %v", err)
+	}
+
+	return nil
+}
+
 // Execute implements native.Contract
 func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 	creds := NewCreds()
@@ -223,6 +327,11 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 
 	snap = prefixed.NewSnapshot(ContractUID, snap)
 
+	err = c.recordFormat(snap)
+	if err != nil {
+		return xerrors.Errorf("failed to check the format of the state: %v", err)
+	}
+
 	switch Command(cmd) {
 	case CmdCreateForm:
 		err = c.cmd.createForm(snap, step)
@@ -259,6 +368,11 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 		if err != nil {
 			return xerrors.Errorf("failed to decrypt ballots: %v", err)
 		}
+	case CmdSubmitCertificate:
+		err := c.cmd.submitCertificate(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to submit certificate: %v", err)
+		}
 	case CmdCancelForm:
 		err := c.cmd.cancelForm(snap, step)
 		if err != nil {
@@ -299,6 +413,31 @@ func (c Contract) Execute(snap store.Snapshot, step execution.Step) error {
 		if err != nil {
 			return xerrors.Errorf("failed to remove voter: %v", err)
 		}
+	case CmdMigrate:
+		err := c.cmd.migrate(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to migrate: %v", err)
+		}
+	case CmdImportForm:
+		err := c.cmd.importForm(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to import form: %v", err)
+		}
+	case CmdRepairForm:
+		err := c.cmd.repairForm(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to repair form: %v", err)
+		}
+	case CmdRegisterVoterKey:
+		err := c.cmd.registerVoterKey(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to register voter key: %v", err)
+		}
+	case CmdCastVotes:
+		err := c.cmd.castVotes(snap, step)
+		if err != nil {
+			return xerrors.Errorf("failed to cast votes: %v", err)
+		}
 	default:
 		return xerrors.Errorf("unknown command: %s", cmd)
 	}
@@ -3,7 +3,9 @@ package evoting
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
 	"testing"
 
@@ -18,6 +20,7 @@ import (
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/core/store/prefixed"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/crypto"
@@ -69,14 +72,14 @@ func TestExecute(t *testing.T) {
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(service, fakeDkg, rosterFac)
+	contract := NewContract(service, fakeDkg, rosterFac, ctx)
 
 	err := contract.Execute(fakeStore{}, makeStep(t))
 	require.EqualError(t, err, "identity not authorized: fake.PublicKey ("+fake.GetError().Error()+")")
 
 	service = fakeAccess{}
 
-	contract = NewContract(service, fakeDkg, rosterFac)
+	contract = NewContract(service, fakeDkg, rosterFac, ctx)
 	err = contract.Execute(fakeStore{}, makeStep(t))
 	require.EqualError(t, err, "\"evoting:command\" not found in tx arg")
 
@@ -97,6 +100,9 @@ func TestExecute(t *testing.T) {
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCombineShares)))
 	require.EqualError(t, err, fake.Err("failed to decrypt ballots"))
 
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdSubmitCertificate)))
+	require.EqualError(t, err, fake.Err("failed to submit certificate"))
+
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCancelForm)))
 	require.EqualError(t, err, fake.Err("failed to cancel form"))
 
@@ -106,6 +112,21 @@ func TestExecute(t *testing.T) {
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRemoveAdmin)))
 	require.EqualError(t, err, fake.Err("failed to remove admin"))
 
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdMigrate)))
+	require.EqualError(t, err, fake.Err("failed to migrate"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdImportForm)))
+	require.EqualError(t, err, fake.Err("failed to import form"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRepairForm)))
+	require.EqualError(t, err, fake.Err("failed to repair form"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdRegisterVoterKey)))
+	require.EqualError(t, err, fake.Err("failed to register voter key"))
+
+	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, string(CmdCastVotes)))
+	require.EqualError(t, err, fake.Err("failed to cast votes"))
+
 	err = contract.Execute(fakeStore{}, makeStep(t, CmdArg, "fake"))
 	require.EqualError(t, err, "unknown command: fake")
 
@@ -115,6 +136,49 @@ func TestExecute(t *testing.T) {
 
 }
 
+func TestExecute_StateFormat(t *testing.T) {
+	binaryCtx, err := NewSerdeContext("BINARY")
+	require.NoError(t, err)
+
+	snap := fake.NewSnapshot()
+
+	err = CheckStateFormat(snap, binaryCtx)
+	require.NoError(t, err)
+
+	contract := NewContract(fakeAccess{}, fakeDKG{}, fakeAuthorityFactory{}, ctx)
+	contract.cmd = fakeCmd{}
+
+	// the first transaction records the format of the state
+	err = contract.Execute(snap, makeStep(t, CmdArg, string(CmdCreateForm)))
+	require.NoError(t, err)
+
+	err = CheckStateFormat(snap, ctx)
+	require.NoError(t, err)
+
+	err = CheckStateFormat(snap, binaryCtx)
+	require.EqualError(t, err, "the state is in the JSON format, not BINARY")
+
+	contract = NewContract(fakeAccess{}, fakeDKG{}, fakeAuthorityFactory{}, binaryCtx)
+	contract.cmd = fakeCmd{}
+
+	err = contract.Execute(snap, makeStep(t, CmdArg, string(CmdCreateForm)))
+	require.EqualError(t, err, "failed to check the format of the state: "+
+		"the state is in the JSON format, not BINARY")
+
+	// a state written before the format was recorded is checked with its
+	// admin list
+	snap = fake.NewSnapshot()
+
+	err = initializeAdminList(prefixed.NewSnapshot(ContractUID, snap), 123456, ctx)
+	require.NoError(t, err)
+
+	err = CheckStateFormat(snap, ctx)
+	require.NoError(t, err)
+
+	err = CheckStateFormat(snap, binaryCtx)
+	require.ErrorContains(t, err, "the admin list is not in the BINARY format")
+}
+
 func TestCommand_CreateForm(t *testing.T) {
 	initMetrics()
 
@@ -142,7 +206,7 @@ func TestCommand_CreateForm(t *testing.T) {
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(service, fakeDkg, rosterFac)
+	contract := NewContract(service, fakeDkg, rosterFac, ctx)
 
 	cmd := evotingCommand{
 		Contract: &contract,
@@ -236,7 +300,8 @@ func TestCommand_CastVote(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.castVote(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the form is not open, current status: %d", types.Initial))
+	require.EqualError(t, err, fmt.Sprintf("[invalid_status] the form is not open, "+
+		"current status: %d", types.Initial))
 
 	dummyForm.Status = types.Open
 	dummyForm.BallotSize = 0
@@ -248,7 +313,8 @@ func TestCommand_CastVote(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.castVote(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, "The user 123456 doesn't have the Voter permission on the form.")
+	require.EqualError(t, err, "[forbidden] The user 123456 doesn't have the Voter "+
+		"permission on the form.")
 
 	addVoter := types.AddVoter{FormID: fakeFormID, TargetUserID: dummyUserAdminID, PerformingUserID: dummyUserAdminID}
 	dataAddVoter, err := addVoter.Serialize(ctx)
@@ -257,7 +323,7 @@ func TestCommand_CastVote(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.castVote(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, "the ballot has unexpected length: 1 != 0")
+	require.EqualError(t, err, "[invalid_ballot] the ballot has unexpected length: 1 != 0")
 
 	dummyForm.BallotSize = 29
 
@@ -334,6 +400,345 @@ func TestCommand_CastVote(t *testing.T) {
 
 	require.Equal(t, castVote.VoterID, suff.VoterIDs[0])
 	require.Equal(t, float64(form.BallotCount), testutil.ToFloat64(PromFormBallots))
+
+	hash, err := suff.Hash(ctx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{hash}, form.SuffragiaHashes)
+
+	digest, err := castVote.Ballot.Digest()
+	require.NoError(t, err)
+
+	digests, err := form.BallotDigests(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{digest}, digests)
+
+	form.SuffragiaHashes[0] = []byte("bad hash")
+	_, err = form.Suffragia(ctx, snap)
+	require.EqualError(t, err, "hash of ballots batch 0 doesn't match")
+
+	form.SuffragiaHashes = nil
+	_, err = form.Suffragia(ctx, snap)
+	require.EqualError(t, err, "0 hashes for 1 ballots batches")
+}
+
+func TestCommand_RegisterVoterKey(t *testing.T) {
+	form, contract := initFormAndContract(123456)
+	form.Configuration.SignedBallots = true
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	authority := suite.Scalar().Pick(suite.RandomStream())
+
+	form.Configuration.VoterKeysAuthority, _ = suite.Point().Mul(authority, nil).MarshalBinary()
+
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	register, err := types.NewRegisterVoterKey(secret, fakeFormID, "234567")
+	require.NoError(t, err)
+
+	// the key the backend registers for a voter isn't endorsed by the
+	// authority
+	unendorsed, err := types.EndorseVoterKey(secret, register)
+	require.NoError(t, err)
+
+	register, err = types.EndorseVoterKey(authority, register)
+	require.NoError(t, err)
+
+	data := string(mustSerialize(t, register))
+
+	err = cmd.registerVoterKey(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.registerVoterKey(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	snap := fake.NewSnapshot()
+
+	form.Configuration.SignedBallots = false
+	storeForm(t, snap, form)
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, data))
+	require.EqualError(t, err, "the form doesn't use signed ballots")
+
+	form.Configuration.SignedBallots = true
+	form.Status = types.Open
+	storeForm(t, snap, form)
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, data))
+	require.EqualError(t, err, "[invalid_status] the form is not in its initial state, "+
+		"current status: 1")
+
+	form.Status = types.Initial
+	storeForm(t, snap, form)
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, data))
+	require.EqualError(t, err, "[forbidden] The user 234567 doesn't have the Voter "+
+		"permission on the form.")
+
+	err = form.AddVoter("234567")
+	require.NoError(t, err)
+	storeForm(t, snap, form)
+
+	forged := register
+	forged.Signature = []byte("signature")
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, string(mustSerialize(t, forged))))
+	require.ErrorContains(t, err, "failed to verify the key: invalid signature")
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, string(mustSerialize(t, unendorsed))))
+	require.ErrorContains(t, err, "failed to verify the key: invalid endorsement")
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, data))
+	require.NoError(t, err)
+
+	key, err := form.VoterKey(snap, "234567")
+	require.NoError(t, err)
+	require.True(t, key.Equal(suite.Point().Mul(secret, nil)))
+
+	err = cmd.registerVoterKey(snap, makeStep(t, FormArg, data))
+	require.EqualError(t, err, "voter 234567 already registered a key")
+}
+
+func TestCommand_CastSignedVote(t *testing.T) {
+	initMetrics()
+
+	form, contract := initFormAndContract(123456)
+	form.Configuration.SignedBallots = true
+	form.Status = types.Open
+	form.BallotSize = 29
+
+	err := form.AddVoter("234567")
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+	storeForm(t, snap, form)
+
+	Ks, Cs, _ := fakeKCPoints(1)
+	ballot := types.Ciphervote{{K: Ks[0], C: Cs[0]}}
+
+	castVote := types.CastVote{FormID: fakeFormID, VoterID: "234567", Ballot: ballot}
+
+	err = cmd.castVote(snap, makeStep(t, FormArg, string(mustSerialize(t, castVote))))
+	require.EqualError(t, err, "[invalid_ballot] failed to verify ballot: voter 234567 "+
+		"has no registered key")
+
+	secret := suite.Scalar().Pick(suite.RandomStream())
+
+	err = form.SetVoterKey(snap, "234567", suite.Point().Mul(secret, nil))
+	require.NoError(t, err)
+
+	// a signature of the ballot on another form is rejected
+	castVote.Nonce = 1
+	castVote.Signature, err = types.SignVote(secret, "beef", 1, ballot)
+	require.NoError(t, err)
+
+	err = cmd.castVote(snap, makeStep(t, FormArg, string(mustSerialize(t, castVote))))
+	require.ErrorContains(t, err, "[invalid_ballot] failed to verify ballot: invalid ballot signature")
+
+	castVote.Signature, err = types.SignVote(secret, fakeFormID, 1, ballot)
+	require.NoError(t, err)
+
+	first := string(mustSerialize(t, castVote))
+
+	err = cmd.castVote(snap, makeStep(t, FormArg, first))
+	require.NoError(t, err)
+
+	stored, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), stored.BallotCount)
+
+	// the nonce is signed with the ballot
+	castVote.Nonce = 2
+
+	err = cmd.castVote(snap, makeStep(t, FormArg, string(mustSerialize(t, castVote))))
+	require.ErrorContains(t, err, "[invalid_ballot] failed to verify ballot: invalid ballot signature")
+
+	castVote.Signature, err = types.SignVote(secret, fakeFormID, 2, ballot)
+	require.NoError(t, err)
+
+	err = cmd.castVote(snap, makeStep(t, FormArg, string(mustSerialize(t, castVote))))
+	require.NoError(t, err)
+
+	// the first ballot can't be cast again to replace the second one
+	err = cmd.castVote(snap, makeStep(t, FormArg, first))
+	require.EqualError(t, err, "[invalid_ballot] failed to verify ballot: nonce 1 "+
+		"is not greater than the last one 2")
+
+	stored, err = types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), stored.BallotCount)
+
+	// the votes of a batch are checked the same way
+	signature, err := types.SignVote(secret, fakeFormID, 3, ballot)
+	require.NoError(t, err)
+
+	castVotes := types.CastVotes{FormID: fakeFormID, Votes: []types.BatchVote{
+		{VoterID: "234567", Ballot: ballot, Signature: signature, Nonce: 3},
+	}}
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.NoError(t, err)
+
+	nonce, err := stored.VoteNonce(snap, "234567")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.ErrorContains(t, err, "nonce 3 is not greater than the last one 3")
+}
+
+func TestCommand_CastVotes(t *testing.T) {
+	initMetrics()
+
+	form, contract := initFormAndContract(123456)
+	form.Status = types.Open
+	form.BallotSize = 29
+
+	err := form.AddVoter("234567")
+	require.NoError(t, err)
+
+	err = form.AddVoter("345678")
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+	storeForm(t, snap, form)
+
+	Ks, Cs, _ := fakeKCPoints(2)
+	ballot := types.Ciphervote{{K: Ks[0], C: Cs[0]}}
+
+	castVotes := types.CastVotes{FormID: fakeFormID}
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.EqualError(t, err, "the batch has 0 votes, expected between 1 and 100")
+
+	castVotes.Votes = []types.BatchVote{
+		{VoterID: "234567", Ballot: ballot},
+		{VoterID: "111111", Ballot: ballot},
+		{VoterID: "234567", Ballot: ballot},
+		{VoterID: "345678", Ballot: types.Ciphervote{{K: Ks[0], C: Cs[0]}, {K: Ks[1], C: Cs[1]}}},
+	}
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.Error(t, err)
+
+	// the errors are found back in the reason of the rejection
+	errs, found := types.ParseBallotErrors("failed to cast votes: " + err.Error())
+	require.True(t, found)
+	require.Equal(t, types.BallotErrors{
+		1: "[forbidden] The user 111111 doesn't have the Voter permission on the form.",
+		2: "voter 234567 has another ballot in the batch",
+		3: "[invalid_ballot] the ballot has unexpected length: 2 != 1",
+	}, errs)
+
+	_, found = types.ParseBallotErrors("the form is not open")
+	require.False(t, found)
+
+	// so are their kinds
+	kind, found := types.ParseRejection(errs[1])
+	require.True(t, found)
+	require.Equal(t, types.RejectForbidden, kind)
+
+	_, found = types.ParseRejection(errs[2])
+	require.False(t, found)
+
+	// no vote of the batch is cast
+	stored, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), stored.BallotCount)
+
+	castVotes.Votes = []types.BatchVote{
+		{VoterID: "234567", Ballot: ballot},
+		{VoterID: "345678", Ballot: types.Ciphervote{{K: Ks[1], C: Cs[1]}}},
+	}
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.NoError(t, err)
+
+	stored, err = types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), stored.BallotCount)
+	require.Equal(t, uint32(2), stored.VoterCount)
+
+	form.Status = types.Closed
+	storeForm(t, snap, form)
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.EqualError(t, err, fmt.Sprintf("[invalid_status] the form is not open, "+
+		"current status: %d", types.Closed))
+}
+
+func TestCommand_CastVotes_LaterVoteFails(t *testing.T) {
+	initMetrics()
+
+	form, contract := initFormAndContract(123456)
+	form.Status = types.Open
+	form.BallotSize = 29
+
+	err := form.AddVoter("234567")
+	require.NoError(t, err)
+
+	err = form.AddVoter("345678")
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	formID, err := hex.DecodeString(fakeFormID)
+	require.NoError(t, err)
+
+	// the second vote fails when it looks for the previous ballot of its voter,
+	// at H( formID | len | "voter" | len | voterID )
+	h := sha256.New()
+	h.Write(formID)
+	h.Write([]byte{5, 0, 0, 0})
+	h.Write([]byte("voter"))
+	h.Write([]byte{6, 0, 0, 0})
+	h.Write([]byte("345678"))
+
+	snap := &badKeySnapshot{
+		InMemorySnapshot: fake.NewSnapshot(),
+		key:              h.Sum(nil),
+	}
+	storeForm(t, snap, form)
+
+	Ks, Cs, _ := fakeKCPoints(2)
+
+	castVotes := types.CastVotes{
+		FormID: fakeFormID,
+		Votes: []types.BatchVote{
+			{VoterID: "234567", Ballot: types.Ciphervote{{K: Ks[0], C: Cs[0]}}},
+			{VoterID: "345678", Ballot: types.Ciphervote{{K: Ks[1], C: Cs[1]}}},
+		},
+	}
+
+	err = cmd.castVotes(snap, makeStep(t, FormArg, string(mustSerialize(t, castVotes))))
+	require.EqualError(t, err, fake.Err("couldn't cast vote 1: couldn't add voter: "+
+		"couldn't get voter"))
+
+	// the first vote isn't written either
+	stored, err := types.FormFromStore(ctx, formFac, fakeFormID, snap.InMemorySnapshot)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), stored.BallotCount)
+	require.Equal(t, uint32(0), stored.VoterCount)
+
+	voted, err := stored.HasVoted(snap.InMemorySnapshot, "234567")
+	require.NoError(t, err)
+	require.False(t, voted)
+
+	suffragia, err := stored.Suffragia(ctx, snap.InMemorySnapshot)
+	require.NoError(t, err)
+	require.Empty(t, suffragia.Ciphervotes)
 }
 
 func TestCommand_CloseForm(t *testing.T) {
@@ -380,7 +785,7 @@ func TestCommand_CloseForm(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.closeForm(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the form is not open, "+
+	require.EqualError(t, err, fmt.Sprintf("[invalid_status] the form is not open, "+
 		"current status: %d", types.Initial))
 	require.Equal(t, 0, testutil.CollectAndCount(PromFormStatus))
 
@@ -419,6 +824,7 @@ func TestCommand_CloseForm(t *testing.T) {
 
 	require.Equal(t, types.Closed, form.Status)
 	require.Equal(t, float64(types.Closed), testutil.ToFloat64(PromFormStatus))
+	require.Equal(t, form.BallotsTree.CurrentRoot(), form.BallotsTree.Root)
 }
 
 func TestCommand_ShuffleBallotsCannotShuffleTwice(t *testing.T) {
@@ -434,8 +840,10 @@ func TestCommand_ShuffleBallotsCannotShuffleTwice(t *testing.T) {
 	// Attempts to shuffle twice :
 	shuffleBallots.Round = 1
 
-	form.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	form.ShuffleInstances[0].ShuffledBallots = make([]types.Ciphervote, 3)
+	shuffle := types.ShuffleInstance{
+		ShuffledBallots:   make([]types.Ciphervote, 3),
+		ShufflerPublicKey: shuffleBallots.PublicKey,
+	}
 
 	Ks, Cs, _ := fakeKCPoints(k)
 	for i := 0; i < k; i++ {
@@ -443,10 +851,11 @@ func TestCommand_ShuffleBallotsCannotShuffleTwice(t *testing.T) {
 			K: Ks[i],
 			C: Cs[i],
 		}}
-		form.ShuffleInstances[0].ShuffledBallots[i] = ballot
+		shuffle.ShuffledBallots[i] = ballot
 	}
 
-	form.ShuffleInstances[0].ShufflerPublicKey = shuffleBallots.PublicKey
+	err := form.StoreShuffle(ctx, snap, shuffle)
+	require.NoError(t, err)
 
 	formBuff, err := form.Serialize(ctx)
 	require.NoError(t, err)
@@ -492,13 +901,12 @@ func TestCommand_ShuffleBallotsValidScenarios(t *testing.T) {
 
 	// Valid Shuffle is over :
 	shuffleBallots.Round = k
-	form.ShuffleInstances = make([]types.ShuffleInstance, k)
 
 	data, err = shuffleBallots.Serialize(ctx)
 	require.NoError(t, err)
 
-	for i := 1; i <= k-1; i++ {
-		form.ShuffleInstances[i].ShuffledBallots = make([]types.Ciphervote, 3)
+	shuffle := types.ShuffleInstance{
+		ShuffledBallots: make([]types.Ciphervote, 3),
 	}
 
 	Ks, Cs, _ := fakeKCPoints(k)
@@ -507,7 +915,12 @@ func TestCommand_ShuffleBallotsValidScenarios(t *testing.T) {
 			K: Ks[i],
 			C: Cs[i],
 		}}
-		form.ShuffleInstances[k-1].ShuffledBallots[i] = ballot
+		shuffle.ShuffledBallots[i] = ballot
+	}
+
+	for i := 0; i < k; i++ {
+		err = form.StoreShuffle(ctx, snap, shuffle)
+		require.NoError(t, err)
 	}
 
 	formBuf, err = form.Serialize(ctx)
@@ -576,7 +989,8 @@ func TestCommand_ShuffleBallotsFormatErrors(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.shuffleBallots(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, "the form is not in state closed (current: 0 != closed: 2)")
+	require.EqualError(t, err, "[invalid_status] the form is not in state closed "+
+		"(current: 0 != closed: 2)")
 
 	// Wrong round :
 	form.Status = types.Closed
@@ -593,9 +1007,11 @@ func TestCommand_ShuffleBallotsFormatErrors(t *testing.T) {
 
 	// Missing public key of shuffler:
 	shuffleBallots.Round = 1
-	form.ShuffleInstances = make([]types.ShuffleInstance, 1)
 	shuffleBallots.PublicKey = []byte("wrong Key")
 
+	err = form.StoreShuffle(ctx, snap, types.ShuffleInstance{})
+	require.NoError(t, err)
+
 	data, err = shuffleBallots.Serialize(ctx)
 	require.NoError(t, err)
 
@@ -723,7 +1139,9 @@ func TestCommand_ShuffleBallotsFormatErrors(t *testing.T) {
 
 	form.Pubkey = pubKey
 	shuffleBallots.Round = 0
-	form.ShuffleInstances = make([]types.ShuffleInstance, 0)
+	form.ShuffleStoreKeys = nil
+	form.ShuffleHashes = nil
+	form.ShufflerPublicKeys = nil
 
 	data, err = shuffleBallots.Serialize(ctx)
 	require.NoError(t, err)
@@ -798,7 +1216,7 @@ func TestCommand_RegisterPubShares(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.registerPubshares(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, "the ballots have not been shuffled")
+	require.EqualError(t, err, "[invalid_status] the ballots have not been shuffled")
 
 	// Requirements:
 	form.Status = types.ShuffledBallots
@@ -807,14 +1225,14 @@ func TestCommand_RegisterPubShares(t *testing.T) {
 		PubKeys:   make([][]byte, 0),
 		Indexes:   make([]int, 0),
 	}
-	form.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	form.ShuffleInstances[0] = types.ShuffleInstance{
-		ShuffledBallots: make([]types.Ciphervote, 1),
-	}
-	form.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{types.EGPair{
-		K: suite.Point(),
-		C: suite.Point(),
-	}}
+
+	err = form.StoreShuffle(ctx, snap, types.ShuffleInstance{
+		ShuffledBallots: []types.Ciphervote{{types.EGPair{
+			K: suite.Point(),
+			C: suite.Point(),
+		}}},
+	})
+	require.NoError(t, err)
 
 	formBuf, err = form.Serialize(ctx)
 	require.NoError(t, err)
@@ -1001,20 +1419,16 @@ func TestCommand_DecryptBallots(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.combineShares(snap, makeStep(t, FormArg, string(data)))
-	require.EqualError(t, err, fmt.Sprintf("the public shares have not"+
+	require.EqualError(t, err, fmt.Sprintf("[invalid_status] the public shares have not"+
 		" been submitted, current status: %d", types.Initial))
 
 	dummyForm.Status = types.PubSharesSubmitted
 
 	// Avoid panic (will always be the case in practice):
-	dummyForm.ShuffleInstances = make([]types.ShuffleInstance, 1)
-	dummyForm.ShuffleInstances[0] = types.ShuffleInstance{
-		ShuffledBallots:   make([]types.Ciphervote, 1),
-		ShuffleProofs:     nil,
-		ShufflerPublicKey: nil,
-	}
-
-	dummyForm.ShuffleInstances[0].ShuffledBallots[0] = types.Ciphervote{}
+	err = dummyForm.StoreShuffle(ctx, snap, types.ShuffleInstance{
+		ShuffledBallots: []types.Ciphervote{{}},
+	})
+	require.NoError(t, err)
 
 	formBuf, err = dummyForm.Serialize(ctx)
 	require.NoError(t, err)
@@ -1026,11 +1440,17 @@ func TestCommand_DecryptBallots(t *testing.T) {
 	err = cmd.combineShares(snap, makeStep(t, FormArg, string(data)))
 	require.NoError(t, err)
 
-	dummyForm.ShuffleInstances[0].ShuffledBallots[0] = make([]types.EGPair, 1)
-	dummyForm.ShuffleInstances[0].ShuffledBallots[0][0] = types.EGPair{
-		K: suite.Point(),
-		C: suite.Point(),
-	}
+	dummyForm.ShuffleStoreKeys = nil
+	dummyForm.ShuffleHashes = nil
+	dummyForm.ShufflerPublicKeys = nil
+
+	err = dummyForm.StoreShuffle(ctx, snap, types.ShuffleInstance{
+		ShuffledBallots: []types.Ciphervote{{types.EGPair{
+			K: suite.Point(),
+			C: suite.Point(),
+		}}},
+	})
+	require.NoError(t, err)
 
 	formBuf, err = dummyForm.Serialize(ctx)
 	require.NoError(t, err)
@@ -1051,63 +1471,330 @@ func TestCommand_DecryptBallots(t *testing.T) {
 	form, ok := message.(types.Form)
 	require.True(t, ok)
 
-	require.Equal(t, types.Ballot{}, form.DecryptedBallots[0])
+	require.Equal(t, uint32(1), form.ResultsCount)
+	require.Len(t, form.ResultsStoreKeys, 1)
+	require.Len(t, form.ResultsHashes, 1)
+
+	ballots, err := form.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []types.Ballot{{}}, ballots)
 	require.Equal(t, types.ResultAvailable, form.Status)
 	require.Equal(t, float64(types.ResultAvailable), testutil.ToFloat64(PromFormStatus))
 }
 
-func TestCommand_CancelForm(t *testing.T) {
-	cancelForm := types.CancelForm{
-		FormID: fakeFormID,
-		UserID: dummyUserAdminID,
+func TestForm_Results(t *testing.T) {
+	oldBatch := types.BallotsPerBatch
+	types.BallotsPerBatch = 2
+	defer func() {
+		types.BallotsPerBatch = oldBatch
+	}()
+
+	snap := fake.NewSnapshot()
+	form := types.Form{FormID: fakeFormID}
+
+	ballots := make([]types.Ballot, 5)
+	for i := range ballots {
+		ballots[i] = types.Ballot{TextResultIDs: []types.ID{types.ID(strconv.Itoa(i))}}
 	}
 
-	data, err := cancelForm.Serialize(ctx)
+	err := form.StoreResults(ctx, snap, ballots)
 	require.NoError(t, err)
+	require.Equal(t, uint32(5), form.ResultsCount)
+	require.Len(t, form.ResultsStoreKeys, 3)
+	require.Len(t, form.ResultsHashes, 3)
 
-	dummyForm, contract := initFormAndContract(123456)
-	dummyForm.FormID = fakeFormID
+	res, err := form.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, ballots, res)
 
-	formBuf, err := dummyForm.Serialize(ctx)
+	res, err = form.Results(ctx, snap, 1, 3)
 	require.NoError(t, err)
+	require.Equal(t, ballots[1:4], res)
 
-	cmd := evotingCommand{
-		Contract: &contract,
-	}
+	res, err = form.Results(ctx, snap, 4, 10)
+	require.NoError(t, err)
+	require.Equal(t, ballots[4:], res)
 
-	err = cmd.cancelForm(fake.NewSnapshot(), makeStep(t))
-	require.EqualError(t, err, getTransactionErr)
+	res, err = form.Results(ctx, snap, 5, 10)
+	require.NoError(t, err)
+	require.Empty(t, res)
 
-	err = cmd.cancelForm(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
-	require.EqualError(t, err, unmarshalTransactionErr)
+	_, err = form.Results(ctx, snap, -1, 10)
+	require.EqualError(t, err, "invalid offset: -1")
 
-	err = cmd.cancelForm(fake.NewBadSnapshot(), makeStep(t, FormArg, string(data)))
-	require.ErrorContains(t, err, "failed to get key")
+	buf, err := snap.Get(form.ResultsStoreKeys[0])
+	require.NoError(t, err)
+
+	hash := sha256.Sum256(buf)
+	require.Equal(t, hash[:], form.ResultsHashes[0])
+}
+
+func TestForm_VoterIndex(t *testing.T) {
+	oldBatch := types.BallotsPerBatch
+	types.BallotsPerBatch = 2
+	defer func() {
+		types.BallotsPerBatch = oldBatch
+	}()
 
 	snap := fake.NewSnapshot()
-	err = snap.Set(dummyFormIDBuff, invalidForm)
-	require.NoError(t, err)
+	form := types.Form{FormID: fakeFormID}
 
-	err = cmd.cancelForm(snap, makeStep(t, FormArg, string(data)))
-	require.ErrorContains(t, err, deserializeErr)
+	Ks, Cs, _ := fakeKCPoints(5)
+	voterIDs := []string{"a", "b", "a", "c", "d"}
 
-	err = snap.Set(dummyFormIDBuff, formBuf)
+	for i, voterID := range voterIDs {
+		err := form.CastVote(ctx, snap, voterID, types.Ciphervote{{K: Ks[i], C: Cs[i]}})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, uint32(5), form.BallotCount)
+	require.Equal(t, uint32(4), form.VoterCount)
+
+	voters, err := form.VoterIDs(ctx, snap, 0, 0)
 	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c", "d"}, voters)
 
-	data, err = cancelForm.Serialize(ctx)
+	// only the batches of the page are read
+	page, err := form.VoterIDs(ctx, snap, 1, 2)
 	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, page)
 
-	err = cmd.cancelForm(snap, makeStep(t, FormArg, string(data)))
+	page, err = form.VoterIDs(ctx, snap, 3, 10)
 	require.NoError(t, err)
+	require.Equal(t, []string{"d"}, page)
 
-	res, err := snap.Get(dummyFormIDBuff)
+	page, err = form.VoterIDs(ctx, snap, 4, 10)
 	require.NoError(t, err)
+	require.Empty(t, page)
 
-	message, err := formFac.Deserialize(ctx, res)
+	_, err = form.VoterIDs(ctx, snap, -1, 10)
+	require.EqualError(t, err, "invalid offset: -1")
+
+	voted, err := form.HasVoted(snap, "c")
 	require.NoError(t, err)
+	require.True(t, voted)
 
-	form, ok := message.(types.Form)
-	require.True(t, ok)
+	voted, err = form.HasVoted(snap, "e")
+	require.NoError(t, err)
+	require.False(t, voted)
+
+	// the second ballot of "a", in another batch, replaces the first one
+	suff, err := form.Suffragia(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, voters, suff.VoterIDs)
+	require.True(t, suff.Ciphervotes[0][0].C.Equal(Cs[2]))
+	require.Len(t, suff.Digests, 5)
+
+	form.VoterCount = 6
+
+	_, err = form.VoterIDs(ctx, snap, 0, 0)
+	require.ErrorContains(t, err, "couldn't unmarshal voters batch")
+}
+
+func TestForm_Shuffles(t *testing.T) {
+	snap := fake.NewSnapshot()
+	form := types.Form{FormID: fakeFormID, Roster: fake.Authority{}}
+
+	_, err := form.LastShuffle(ctx, snap)
+	require.EqualError(t, err, "form has no shuffles")
+
+	Ks, Cs, _ := fakeKCPoints(2)
+
+	for i := 0; i < 2; i++ {
+		err = form.StoreShuffle(ctx, snap, types.ShuffleInstance{
+			ShuffledBallots:   []types.Ciphervote{{{K: Ks[i], C: Cs[i]}}},
+			ShuffleProofs:     []byte{byte(i)},
+			ShufflerPublicKey: []byte{byte(i)},
+		})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 2, form.ShuffleCount())
+	require.Equal(t, [][]byte{{0}, {1}}, form.ShufflerPublicKeys)
+
+	// the form only keeps references to the shuffles
+	formBuf, err := form.Serialize(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, string(formBuf), "ShuffledBallots")
+
+	shuffle, err := form.LastShuffle(ctx, snap)
+	require.NoError(t, err)
+	require.True(t, shuffle.ShuffledBallots[0][0].C.Equal(Cs[1]))
+	require.Equal(t, []byte{1}, shuffle.ShuffleProofs)
+
+	_, err = form.Shuffle(ctx, snap, 2)
+	require.EqualError(t, err, "no shuffle for round 2")
+
+	form.ShuffleHashes[0] = []byte("bad hash")
+
+	_, err = form.Shuffle(ctx, snap, 0)
+	require.EqualError(t, err, "hash of shuffle 0 doesn't match")
+}
+
+func TestFormIndexes(t *testing.T) {
+	snap := fake.NewSnapshot()
+
+	form := types.Form{
+		FormID:        fakeFormID,
+		Configuration: types.Configuration{Title: types.Title{En: "title"}},
+		Status:        types.Initial,
+		Owners:        []int{123456},
+	}
+
+	err := updateFormIndexes(snap, form)
+	require.NoError(t, err)
+
+	summary, found, err := types.FormSummaryFromStore(snap, fakeFormID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "title", summary.Title.En)
+	require.Equal(t, types.Initial, summary.Status)
+
+	requireIndex(t, snap, types.StatusIndexKey(types.Initial), fakeFormID)
+	requireIndex(t, snap, types.OwnerIndexKey(123456), fakeFormID)
+
+	form.Status = types.Open
+	form.Owners = []int{654321}
+
+	err = updateFormIndexes(snap, form)
+	require.NoError(t, err)
+
+	requireIndex(t, snap, types.StatusIndexKey(types.Initial))
+	requireIndex(t, snap, types.StatusIndexKey(types.Open), fakeFormID)
+	requireIndex(t, snap, types.OwnerIndexKey(123456))
+	requireIndex(t, snap, types.OwnerIndexKey(654321), fakeFormID)
+
+	err = removeFormIndexes(snap, fakeFormID)
+	require.NoError(t, err)
+
+	requireIndex(t, snap, types.StatusIndexKey(types.Open))
+	requireIndex(t, snap, types.OwnerIndexKey(654321))
+
+	_, found, err = types.FormSummaryFromStore(snap, fakeFormID)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	err = updateFormMetadataStore(snap, fakeFormID)
+	require.NoError(t, err)
+
+	err = removeFormMetadataStore(snap, fakeFormID)
+	require.NoError(t, err)
+
+	buf, err := snap.Get([]byte(FormsMetadataKey))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"FormsIDs": []}`, string(buf))
+
+	err = updateFormIndexes(fake.NewBadSnapshot(), form)
+	require.EqualError(t, err, fake.Err("failed to get summary: failed to get summary"))
+}
+
+func TestCommand_SubmitCertificate(t *testing.T) {
+	submitCertificate := types.SubmitCertificate{
+		FormID:      fakeFormID,
+		Certificate: []byte("certificate"),
+	}
+
+	data, err := submitCertificate.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyForm, contract := initFormAndContract(123456)
+
+	formBuf, err := dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.submitCertificate(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.submitCertificate(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	snap := fake.NewSnapshot()
+	err = snap.Set(dummyFormIDBuff, formBuf)
+	require.NoError(t, err)
+
+	err = cmd.submitCertificate(snap, makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, fmt.Sprintf("[invalid_status] the results are not available, "+
+		"current status: %d", types.Initial))
+
+	dummyForm.Status = types.ResultAvailable
+
+	formBuf, err = dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyFormIDBuff, formBuf)
+	require.NoError(t, err)
+
+	err = cmd.submitCertificate(snap, makeStep(t, FormArg, string(data)))
+	require.ErrorContains(t, err, "invalid certificate: failed to verify certificate")
+
+	dummyForm.Certificate = []byte("certificate")
+
+	formBuf, err = dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyFormIDBuff, formBuf)
+	require.NoError(t, err)
+
+	err = cmd.submitCertificate(snap, makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "[invalid_status] the results are already certified")
+}
+
+func TestCommand_CancelForm(t *testing.T) {
+	cancelForm := types.CancelForm{
+		FormID: fakeFormID,
+		UserID: dummyUserAdminID,
+	}
+
+	data, err := cancelForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	dummyForm, contract := initFormAndContract(123456)
+	dummyForm.FormID = fakeFormID
+
+	formBuf, err := dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	err = cmd.cancelForm(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.cancelForm(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.cancelForm(fake.NewBadSnapshot(), makeStep(t, FormArg, string(data)))
+	require.ErrorContains(t, err, "failed to get key")
+
+	snap := fake.NewSnapshot()
+	err = snap.Set(dummyFormIDBuff, invalidForm)
+	require.NoError(t, err)
+
+	err = cmd.cancelForm(snap, makeStep(t, FormArg, string(data)))
+	require.ErrorContains(t, err, deserializeErr)
+
+	err = snap.Set(dummyFormIDBuff, formBuf)
+	require.NoError(t, err)
+
+	data, err = cancelForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.cancelForm(snap, makeStep(t, FormArg, string(data)))
+	require.NoError(t, err)
+
+	res, err := snap.Get(dummyFormIDBuff)
+	require.NoError(t, err)
+
+	message, err := formFac.Deserialize(ctx, res)
+	require.NoError(t, err)
+
+	form, ok := message.(types.Form)
+	require.True(t, ok)
 
 	require.Equal(t, types.Canceled, form.Status)
 	require.Equal(t, float64(types.Canceled), testutil.ToFloat64(PromFormStatus))
@@ -1179,7 +1866,7 @@ func TestCommand_AdminList(t *testing.T) {
 	require.NoError(t, err)
 
 	_, err = adminListFac.Deserialize(ctx, res)
-	require.ErrorContains(t, err, "failed to unmarshal form")
+	require.ErrorContains(t, err, "failed to unmarshal admin list")
 
 	// Now Let's add our admin
 	data, err = addAdmin.Serialize(ctx)
@@ -1210,7 +1897,7 @@ func TestCommand_AdminList(t *testing.T) {
 	require.NoError(t, err)
 
 	err = cmd.manageAdminList(snap, makeStep(t, FormArg, string(data2)))
-	require.ErrorContains(t, err, "The performing user is not an admin")
+	require.ErrorContains(t, err, "[forbidden] The performing user is not an admin")
 
 	// Now we add another admin but with a performing user that is already admin
 	addAdmin2 = types.AddAdmin{dummyUID2, dummyUID}
@@ -1530,9 +2217,612 @@ func TestCommand_VoterForm(t *testing.T) {
 	require.True(t, dummyUserVoterIndex == -1)
 }
 
+func TestCommand_DeleteForm(t *testing.T) {
+	form, contract := initFormAndContract(123456)
+	form.Configuration.Title = types.Title{En: "title"}
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+	storeForm(t, snap, form)
+
+	otherFormID := hex.EncodeToString([]byte("other"))
+
+	for _, formID := range []string{otherFormID, fakeFormID} {
+		err := updateFormMetadataStore(snap, formID)
+		require.NoError(t, err)
+	}
+
+	err := updateFormIndexes(snap, form)
+	require.NoError(t, err)
+
+	// the keys that stay once the form is deleted
+	kept := map[string]bool{
+		FormsMetadataKey: true,
+		string(types.StatusIndexKey(types.Initial)): true,
+		string(types.OwnerIndexKey(123456)):         true,
+	}
+
+	// the data of the form stored next to it
+	form.Voters = []int{234567, 345678}
+
+	for _, voterID := range []string{"345678", "456789"} {
+		err = form.CastVote(ctx, snap, voterID, types.Ciphervote{})
+		require.NoError(t, err)
+
+		err = form.SetVoteNonce(snap, voterID, 1)
+		require.NoError(t, err)
+	}
+
+	// a voter with a key who didn't vote
+	err = form.SetVoterKey(snap, "234567", suite.Point().Pick(suite.RandomStream()))
+	require.NoError(t, err)
+
+	err = form.StoreShuffle(ctx, snap, types.ShuffleInstance{})
+	require.NoError(t, err)
+
+	err = form.StoreResults(ctx, snap, []types.Ballot{{}})
+	require.NoError(t, err)
+
+	storeForm(t, snap, form)
+
+	summaryKey, err := types.FormSummaryKey(fakeFormID)
+	require.NoError(t, err)
+
+	buf, err := snap.Get(summaryKey)
+	require.NoError(t, err)
+	require.NotNil(t, buf)
+
+	deleteForm := types.DeleteForm{FormID: fakeFormID, UserID: "654321"}
+
+	err = cmd.deleteForm(snap, makeStep(t, FormArg, string(mustSerialize(t, deleteForm))))
+	require.EqualError(t, err, "[forbidden] The user 654321 doesn't have the Owner "+
+		"permission on the form.")
+
+	deleteForm.UserID = "123456"
+
+	err = cmd.deleteForm(snap, makeStep(t, FormArg, string(mustSerialize(t, deleteForm))))
+	require.NoError(t, err)
+
+	buf, err = snap.Get(dummyFormIDBuff)
+	require.NoError(t, err)
+	require.Nil(t, buf)
+
+	// nothing of the form stays in the store
+	for _, key := range snap.Keys() {
+		require.True(t, kept[string(key)], "key %x was not deleted", key)
+	}
+
+	// the form is removed from the metadata instead of being added again
+	buf, err = snap.Get([]byte(FormsMetadataKey))
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{"FormsIDs": [%q]}`, otherFormID), string(buf))
+
+	requireIndex(t, snap, types.StatusIndexKey(types.Initial))
+	requireIndex(t, snap, types.OwnerIndexKey(123456))
+
+	err = cmd.deleteForm(snap, makeStep(t, FormArg, string(mustSerialize(t, deleteForm))))
+	require.ErrorContains(t, err, "[form_not_found] no form found")
+}
+
+func TestCommand_Migrate(t *testing.T) {
+	dummyForm, contract := initFormAndContract(123456)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	migrate := types.Migrate{UserID: dummyUserAdminID}
+	data, err := migrate.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.migrate(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.migrate(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.migrate(fake.NewSnapshot(), makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "failed to get the AdminList: No list found")
+
+	snap := fake.NewSnapshot()
+
+	err = initializeAdminList(snap, 654321, ctx)
+	require.NoError(t, err)
+
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "[forbidden] The performing user is not an admin.")
+
+	// The records are written as they were before the schema was versioned.
+	h := sha256.New()
+	h.Write([]byte(AdminListId))
+	adminListIDBuf := h.Sum(nil)
+
+	err = snap.Set(adminListIDBuf, []byte(`{"AdminList":[123456]}`))
+	require.NoError(t, err)
+
+	Ks, Cs, _ := fakeKCPoints(1)
+
+	err = dummyForm.CastVote(ctx, snap, "user1", types.Ciphervote{{K: Ks[0], C: Cs[0]}})
+	require.NoError(t, err)
+
+	formBuf, err := dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	// the batches were not hashed
+	legacy := downgradeRecord(t, formBuf, map[string]string{
+		"AdminID":         `"admin"`,
+		"SuffragiaHashes": `[""]`,
+	})
+
+	err = snap.Set(dummyFormIDBuff, legacy)
+	require.NoError(t, err)
+
+	err = snap.Set(dummyForm.SuffragiaStoreKeys[0],
+		downgradeRecord(t, mustGet(t, snap, dummyForm.SuffragiaStoreKeys[0]), nil))
+	require.NoError(t, err)
+
+	err = updateFormMetadataStore(snap, dummyForm.FormID)
+	require.NoError(t, err)
+
+	// Legacy records are migrated on read.
+	msg, err := formFac.Deserialize(ctx, legacy)
+	require.NoError(t, err)
+	require.Equal(t, dummyForm.FormID, msg.(types.Form).FormID)
+
+	legacyForm := msg.(types.Form)
+	require.Equal(t, [][]byte{{}}, legacyForm.SuffragiaHashes)
+
+	_, err = legacyForm.Suffragia(ctx, snap)
+	require.NoError(t, err)
+
+	_, err = formFac.Deserialize(ctx, downgradeRecord(t, formBuf, map[string]string{"Version": "99"}))
+	require.EqualError(t, err, "failed to decode: failed to decode form: unsupported form version: 99")
+
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(data)))
+	require.NoError(t, err)
+
+	for _, key := range [][]byte{adminListIDBuf, dummyFormIDBuff, dummyForm.SuffragiaStoreKeys[0]} {
+		var fields map[string]json.RawMessage
+
+		err = json.Unmarshal(mustGet(t, snap, key), &fields)
+		require.NoError(t, err)
+		require.Equal(t, json.RawMessage(strconv.Itoa(types.SchemaVersion)), fields["Version"])
+		require.NotContains(t, fields, "AdminID")
+	}
+
+	form, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+
+	suff, err := form.Suffragia(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1"}, suff.VoterIDs)
+	require.Equal(t, dummyForm.SuffragiaHashes, form.SuffragiaHashes)
+
+	// the form created before the indexes is added to them
+	requireIndex(t, snap, types.StatusIndexKey(types.Initial), fakeFormID)
+	requireIndex(t, snap, types.OwnerIndexKey(123456), fakeFormID)
+
+	// and the migration can be run again
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(data)))
+	require.NoError(t, err)
+
+	requireIndex(t, snap, types.StatusIndexKey(types.Initial), fakeFormID)
+}
+
+func TestCommand_Migrate_LegacyShuffles(t *testing.T) {
+	dummyForm, contract := initFormAndContract(123456)
+	dummyForm.Status = types.ShuffledBallots
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+
+	err := initializeAdminList(snap, 123456, ctx)
+	require.NoError(t, err)
+
+	// the forms of version 0 kept their shuffles
+	Ks, Cs, _ := fakeKCPoints(1)
+
+	shuffle := types.ShuffleInstance{
+		ShuffledBallots:   []types.Ciphervote{{{K: Ks[0], C: Cs[0]}}},
+		ShuffleProofs:     []byte("proof"),
+		ShufflerPublicKey: []byte("shuffler"),
+	}
+
+	shuffleBuf, err := shuffle.Serialize(ctx)
+	require.NoError(t, err)
+
+	legacy := downgradeRecord(t, mustSerialize(t, dummyForm), map[string]string{
+		"ShuffleInstances": "[" + string(shuffleBuf) + "]",
+	})
+
+	err = snap.Set(dummyFormIDBuff, legacy)
+	require.NoError(t, err)
+
+	err = updateFormMetadataStore(snap, dummyForm.FormID)
+	require.NoError(t, err)
+
+	// they are read from the form until it is migrated
+	form, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.NotNil(t, form.Legacy)
+	require.Equal(t, 1, form.ShuffleCount())
+
+	last, err := form.LastShuffle(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, shuffle.ShuffleProofs, last.ShuffleProofs)
+
+	migrate := types.Migrate{UserID: dummyUserAdminID}
+
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(mustSerialize(t, migrate))))
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+
+	err = json.Unmarshal(mustGet(t, snap, dummyFormIDBuff), &fields)
+	require.NoError(t, err)
+	require.NotContains(t, fields, "Legacy")
+	require.NotContains(t, fields, "ShuffleInstances")
+
+	form, err = types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Nil(t, form.Legacy)
+	require.Len(t, form.ShuffleStoreKeys, 1)
+	require.Equal(t, [][]byte{shuffle.ShufflerPublicKey}, form.ShufflerPublicKeys)
+
+	last, err = form.LastShuffle(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, shuffle.ShuffleProofs, last.ShuffleProofs)
+	require.True(t, shuffle.ShuffledBallots[0][0].K.Equal(last.ShuffledBallots[0][0].K))
+}
+
+func TestCommand_Migrate_LegacyResults(t *testing.T) {
+	dummyForm, contract := initFormAndContract(123456)
+	dummyForm.Status = types.ResultAvailable
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+
+	err := initializeAdminList(snap, 123456, ctx)
+	require.NoError(t, err)
+
+	// the forms of version 0 kept their results
+	ballots := []types.Ballot{
+		{SelectResultIDs: []types.ID{"q1"}, SelectResult: [][]bool{{true, false}}},
+		{SelectResultIDs: []types.ID{"q1"}, SelectResult: [][]bool{{false, true}}},
+	}
+
+	ballotsBuf, err := json.Marshal(ballots)
+	require.NoError(t, err)
+
+	legacy := downgradeRecord(t, mustSerialize(t, dummyForm), map[string]string{
+		"DecryptedBallots": string(ballotsBuf),
+	})
+
+	err = snap.Set(dummyFormIDBuff, legacy)
+	require.NoError(t, err)
+
+	err = updateFormMetadataStore(snap, dummyForm.FormID)
+	require.NoError(t, err)
+
+	// they are read from the form until it is migrated
+	form, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), form.ResultsCount)
+
+	results, err := form.Results(ctx, snap, 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, ballots[1:], results)
+
+	migrate := types.Migrate{UserID: dummyUserAdminID}
+
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(mustSerialize(t, migrate))))
+	require.NoError(t, err)
+
+	form, err = types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.Nil(t, form.Legacy)
+	require.Len(t, form.ResultsStoreKeys, 1)
+	require.Equal(t, uint32(2), form.ResultsCount)
+
+	results, err = form.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, ballots, results)
+}
+
+// The records in testdata were stored by the version 0 of the contract: user1
+// voted twice in the same batch, so its first ballot was replaced.
+func TestCommand_Migrate_Version0(t *testing.T) {
+	_, contract := initFormAndContract(123456)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	snap := fake.NewSnapshot()
+
+	err := initializeAdminList(snap, 123456, ctx)
+	require.NoError(t, err)
+
+	formBuf, err := os.ReadFile("testdata/form_v0.json")
+	require.NoError(t, err)
+
+	form, err := formFac.Deserialize(ctx, formBuf)
+	require.NoError(t, err)
+
+	legacy := form.(types.Form)
+	require.NotNil(t, legacy.Legacy)
+	require.Len(t, legacy.SuffragiaStoreKeys, 1)
+
+	formID, err := hex.DecodeString(legacy.FormID)
+	require.NoError(t, err)
+
+	err = snap.Set(formID, formBuf)
+	require.NoError(t, err)
+
+	batchBuf, err := os.ReadFile("testdata/suffragia_v0.json")
+	require.NoError(t, err)
+
+	err = snap.Set(legacy.SuffragiaStoreKeys[0], batchBuf)
+	require.NoError(t, err)
+
+	err = updateFormMetadataStore(snap, legacy.FormID)
+	require.NoError(t, err)
+
+	// the form can be read before it is migrated
+	require.Equal(t, 1, legacy.ShuffleCount())
+
+	shuffle, err := legacy.Shuffle(ctx, snap, 0)
+	require.NoError(t, err)
+	require.Len(t, shuffle.ShuffledBallots, 2)
+
+	results, err := legacy.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	suff, err := legacy.Suffragia(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1", "user2"}, suff.VoterIDs)
+
+	migrate := types.Migrate{UserID: dummyUserAdminID}
+
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(mustSerialize(t, migrate))))
+	require.NoError(t, err)
+
+	migrated, err := types.FormFromStore(ctx, formFac, legacy.FormID, snap)
+	require.NoError(t, err)
+	require.Nil(t, migrated.Legacy)
+	require.Len(t, migrated.ShuffleStoreKeys, 1)
+	require.Len(t, migrated.ResultsStoreKeys, 1)
+	require.Equal(t, uint32(2), migrated.BallotCount)
+
+	shuffle, err = migrated.Shuffle(ctx, snap, 0)
+	require.NoError(t, err)
+	require.Len(t, shuffle.ShuffledBallots, 2)
+
+	results, err = migrated.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// the batch is hashed and has the digests of its ballots
+	suff, err = migrated.Suffragia(ctx, snap)
+	require.NoError(t, err)
+	require.Len(t, suff.Digests, 2)
+
+	for i, ciphervote := range suff.Ciphervotes {
+		digest, err := ciphervote.Digest()
+		require.NoError(t, err)
+		require.Equal(t, digest, suff.Digests[i])
+	}
+
+	// the tree is closed, since the form is
+	require.Equal(t, uint32(2), migrated.BallotsTree.Size)
+	require.NotNil(t, migrated.BallotsTree.Root)
+
+	digests, err := migrated.BallotDigests(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, suff.Digests, digests)
+
+	require.Equal(t, uint32(2), migrated.VoterCount)
+
+	voterIDs, err := migrated.VoterIDs(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1", "user2"}, voterIDs)
+
+	voted, err := migrated.HasVoted(snap, "user2")
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	// the migration of a migrated form changes nothing
+	err = cmd.migrate(snap, makeStep(t, FormArg, string(mustSerialize(t, migrate))))
+	require.NoError(t, err)
+
+	again, err := types.FormFromStore(ctx, formFac, legacy.FormID, snap)
+	require.NoError(t, err)
+	require.Equal(t, migrated, again)
+}
+
+func TestCommand_ImportForm(t *testing.T) {
+	dummyForm, contract := initFormAndContract(123456)
+
+	cmd := evotingCommand{
+		Contract: &contract,
+	}
+
+	// the form is finished on the other chain
+	other := fake.NewSnapshot()
+
+	Ks, Cs, _ := fakeKCPoints(2)
+
+	for i, voter := range []string{"user1", "user2"} {
+		err := dummyForm.CastVote(ctx, other, voter, types.Ciphervote{{K: Ks[i], C: Cs[i]}})
+		require.NoError(t, err)
+	}
+
+	dummyForm.BallotsTree.Close()
+	dummyForm.Status = types.ResultAvailable
+
+	err := dummyForm.StoreResults(ctx, other, []types.Ballot{{}, {}})
+	require.NoError(t, err)
+
+	formBuf, err := dummyForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	batches := make([][]byte, 0)
+	for _, key := range dummyForm.BatchKeys() {
+		batches = append(batches, mustGet(t, other, key))
+	}
+
+	importForm := types.ImportForm{Form: formBuf, Batches: batches, UserID: dummyUserAdminID}
+	data, err := importForm.Serialize(ctx)
+	require.NoError(t, err)
+
+	err = cmd.importForm(fake.NewSnapshot(), makeStep(t))
+	require.EqualError(t, err, getTransactionErr)
+
+	err = cmd.importForm(fake.NewSnapshot(), makeStep(t, FormArg, "dummy"))
+	require.EqualError(t, err, unmarshalTransactionErr)
+
+	err = cmd.importForm(fake.NewSnapshot(), makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "failed to get the AdminList: No list found")
+
+	snap := fake.NewSnapshot()
+
+	err = initializeAdminList(snap, 654321, ctx)
+	require.NoError(t, err)
+
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "[forbidden] The performing user is not an admin.")
+
+	h := sha256.New()
+	h.Write([]byte(AdminListId))
+
+	err = snap.Set(h.Sum(nil), mustSerialize(t, types.AdminList{AdminList: []int{654321, 123456}}))
+	require.NoError(t, err)
+
+	bad := importForm
+	bad.Form = []byte("dummy")
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.ErrorContains(t, err, "failed to deserialize Form")
+
+	open := dummyForm
+	open.Status = types.Open
+	bad.Form, err = open.Serialize(ctx)
+	require.NoError(t, err)
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.EqualError(t, err, "[invalid_status] the form is not finished: status 1")
+
+	bad = importForm
+	bad.Batches = [][]byte{batches[1], batches[0]}
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.ErrorContains(t, err, "failed to import batches: invalid ballots")
+
+	// a forged key would overwrite the admin list, which isn't written
+	forged := dummyForm
+	forged.SuffragiaStoreKeys = [][]byte{h.Sum(nil)}
+	bad = importForm
+	bad.Form, err = forged.Serialize(ctx)
+	require.NoError(t, err)
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.EqualError(t, err, "failed to import batches: batch key 0 doesn't match the form")
+
+	isAdmin, _, err := cmd.fetchAdmin(snap, dummyUserAdminID)
+	require.NoError(t, err)
+	require.True(t, isAdmin)
+
+	forged = dummyForm
+	forged.ResultsCount = 3
+	bad.Form, err = forged.Serialize(ctx)
+	require.NoError(t, err)
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.EqualError(t, err, "failed to import batches: 2 results for a count of 3")
+
+	garbage := sha256.Sum256([]byte("dummy"))
+	forged = dummyForm
+	forged.ResultsHashes = [][]byte{garbage[:]}
+	bad.Form, err = forged.Serialize(ctx)
+	require.NoError(t, err)
+	bad.Batches = append(append([][]byte{}, batches[:len(batches)-1]...), []byte("dummy"))
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(mustSerialize(t, bad))))
+	require.ErrorContains(t, err, "failed to import batches: couldn't unmarshal results batch")
+
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(data)))
+	require.NoError(t, err)
+
+	form, err := types.FormFromStore(ctx, formFac, fakeFormID, snap)
+	require.NoError(t, err)
+	require.True(t, form.Imported)
+	require.Equal(t, dummyForm.Certificate, form.Certificate)
+	require.Equal(t, uint32(2), form.VoterCount)
+
+	voted, err := form.HasVoted(snap, "user2")
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	results, err := form.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	summary, found, err := types.FormSummaryFromStore(snap, fakeFormID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, types.ResultAvailable, summary.Status)
+
+	err = cmd.importForm(snap, makeStep(t, FormArg, string(data)))
+	require.EqualError(t, err, "form "+fakeFormID+" already exists")
+}
+
 // -----------------------------------------------------------------------------
 // Utility functions
 
+func mustSerialize(t *testing.T, msg serde.Message) []byte {
+	data, err := msg.Serialize(ctx)
+	require.NoError(t, err)
+
+	return data
+}
+
+// storeForm stores the form at its ID.
+func storeForm(t *testing.T, snap store.Snapshot, form types.Form) {
+	err := snap.Set(dummyFormIDBuff, mustSerialize(t, form))
+	require.NoError(t, err)
+}
+
+// downgradeRecord removes the version of a JSON record and sets the given
+// fields, to write it as an older version would have.
+func downgradeRecord(t *testing.T, data []byte, fields map[string]string) []byte {
+	var record map[string]json.RawMessage
+
+	err := json.Unmarshal(data, &record)
+	require.NoError(t, err)
+
+	delete(record, "Version")
+
+	for k, v := range fields {
+		record[k] = json.RawMessage(v)
+	}
+
+	data, err = json.Marshal(record)
+	require.NoError(t, err)
+
+	return data
+}
+
+func mustGet(t *testing.T, snap store.Snapshot, key []byte) []byte {
+	data, err := snap.Get(key)
+	require.NoError(t, err)
+
+	return data
+}
+
 func initMetrics() {
 	PromFormStatus.Reset()
 	PromFormBallots.Reset()
@@ -1550,8 +2840,6 @@ func initFormAndContract(initialOwner int) (types.Form, Contract) {
 		FormID:           fakeFormID,
 		Status:           0,
 		Pubkey:           nil,
-		ShuffleInstances: make([]types.ShuffleInstance, 0),
-		DecryptedBallots: nil,
 		ShuffleThreshold: 0,
 		Roster:           fake.Authority{},
 		Owners:           []int{initialOwner},
@@ -1560,7 +2848,7 @@ func initFormAndContract(initialOwner int) (types.Form, Contract) {
 	service := fakeAccess{err: fake.GetError()}
 	rosterFac := fakeAuthorityFactory{}
 
-	contract := NewContract(service, fakeDkg, rosterFac)
+	contract := NewContract(service, fakeDkg, rosterFac, ctx)
 
 	return dummyForm, contract
 }
@@ -1586,7 +2874,6 @@ func initGoodShuffleBallot(t *testing.T, k int) (store.Snapshot, types.Form, typ
 	// Encrypted ballots:
 	form.Pubkey = pubKey
 	shuffleBallots.Round = 0
-	form.ShuffleInstances = make([]types.ShuffleInstance, 0)
 
 	snap := fake.NewSnapshot()
 	for i := 0; i < k; i++ {
@@ -1800,6 +3087,10 @@ func (c fakeCmd) combineShares(snap store.Snapshot, step execution.Step) error {
 	return c.err
 }
 
+func (c fakeCmd) submitCertificate(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
 func (c fakeCmd) cancelForm(snap store.Snapshot, step execution.Step) error {
 	return c.err
 }
@@ -1812,6 +3103,26 @@ func (c fakeCmd) registerPubshares(snap store.Snapshot, step execution.Step) err
 	return c.err
 }
 
+func (c fakeCmd) migrate(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) importForm(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) repairForm(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) registerVoterKey(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
+func (c fakeCmd) castVotes(snap store.Snapshot, step execution.Step) error {
+	return c.err
+}
+
 type fakeAuthorityFactory struct {
 	serde.Factory
 }
@@ -1851,3 +3162,24 @@ func (f fakeAuthority) PublicKeyIterator() crypto.PublicKeyIterator {
 func (f fakeAuthority) Len() int {
 	return 0
 }
+
+func requireIndex(t *testing.T, snap store.Readable, key []byte, formIDs ...string) {
+	ids, err := types.FormIndexFromStore(snap, key)
+	require.NoError(t, err)
+	require.Equal(t, types.FormIDs(append([]string{}, formIDs...)), ids)
+}
+
+// badKeySnapshot is a snapshot that fails to read a given key.
+type badKeySnapshot struct {
+	*fake.InMemorySnapshot
+
+	key []byte
+}
+
+func (s *badKeySnapshot) Get(key []byte) ([]byte, error) {
+	if string(key) == string(s.key) {
+		return nil, fake.GetError()
+	}
+
+	return s.InMemorySnapshot.Get(key)
+}
@@ -0,0 +1,172 @@
+// Package record defines the election record: a copy of a form and of the
+// batches it references in the store, so that an election can be verified
+// without access to the nodes.
+package record
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino/minogrpc/session"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+
+	// register the JSON formats of the form and of the roster
+	_ "github.com/dedis/d-voting/contracts/evoting/json"
+	_ "go.dedis.ch/dela/core/ordering/cosipbft/json"
+)
+
+const (
+	// Type identifies an election record.
+	Type = "d-voting/election-record"
+	// Version is the version of the record format written by this package.
+	Version = 1
+)
+
+// Record is an election record. The form and the batches are kept as they are
+// serialized in the store, so that their hashes can be checked against the
+// ones kept by the form.
+type Record struct {
+	Type    string
+	Version int
+	// Format is the serde format of the form and of the batches, for example
+	// "JSON".
+	Format string
+	FormID string
+	// Form is the serialized form
+	Form []byte
+	// Batches contains the Suffragia, the shuffles and the decrypted ballots
+	// referenced by the form, indexed by their hex-encoded store key.
+	Batches map[string][]byte
+}
+
+// NewFormFactory returns the factory of the forms of the d-voting nodes, whose
+// roster uses gRPC addresses and BLS keys.
+func NewFormFactory() serde.Factory {
+	rosterFac := authority.NewFactory(session.AddressFactory{}, bls.NewPublicKeyFactory())
+
+	return types.NewFormFactory(types.CiphervoteFactory{}, rosterFac)
+}
+
+// New creates the record of a form by reading the batches it references.
+func New(ctx serde.Context, form types.Form, rd store.Readable) (Record, error) {
+	formBuf, err := form.Serialize(ctx)
+	if err != nil {
+		return Record{}, xerrors.Errorf("failed to serialize form: %v", err)
+	}
+
+	record := Record{
+		Type:    Type,
+		Version: Version,
+		Format:  string(ctx.GetFormat()),
+		FormID:  form.FormID,
+		Form:    formBuf,
+		Batches: make(map[string][]byte),
+	}
+
+	for _, key := range form.BatchKeys() {
+		buf, err := rd.Get(key)
+		if err != nil {
+			return Record{}, xerrors.Errorf("failed to get batch %x: %v", key, err)
+		}
+
+		record.Batches[hex.EncodeToString(key)] = buf
+	}
+
+	return record, nil
+}
+
+// Read reads a record and checks its type and version.
+func Read(r io.Reader) (Record, error) {
+	var record Record
+
+	err := json.NewDecoder(r).Decode(&record)
+	if err != nil {
+		return record, xerrors.Errorf("failed to decode record: %v", err)
+	}
+
+	if record.Type != Type {
+		return record, xerrors.Errorf("not an election record: %q", record.Type)
+	}
+
+	if record.Version != Version {
+		return record, xerrors.Errorf("unsupported record version: %d", record.Version)
+	}
+
+	return record, nil
+}
+
+// ReadFile reads the record stored at path.
+func ReadFile(path string) (Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Record{}, xerrors.Errorf("failed to open record: %v", err)
+	}
+
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Write writes the record in JSON.
+func (r Record) Write(w io.Writer) error {
+	err := json.NewEncoder(w).Encode(r)
+	if err != nil {
+		return xerrors.Errorf("failed to encode record: %v", err)
+	}
+
+	return nil
+}
+
+// GetForm returns the form of the record. The context must use the format of
+// the record.
+func (r Record) GetForm(ctx serde.Context, formFac serde.Factory) (types.Form, error) {
+	if string(ctx.GetFormat()) != r.Format {
+		return types.Form{}, xerrors.Errorf("record format %q does not match "+
+			"the context format %q", r.Format, ctx.GetFormat())
+	}
+
+	message, err := formFac.Deserialize(ctx, r.Form)
+	if err != nil {
+		return types.Form{}, xerrors.Errorf("failed to deserialize form: %v", err)
+	}
+
+	form, ok := message.(types.Form)
+	if !ok {
+		return types.Form{}, xerrors.Errorf("wrong message type: %T", message)
+	}
+
+	if form.FormID != r.FormID {
+		return types.Form{}, xerrors.Errorf("form ID mismatch: %s != %s",
+			form.FormID, r.FormID)
+	}
+
+	return form, nil
+}
+
+// Store returns the batches of the record as a read-only store, so that the
+// ballots can be read with the functions of the form.
+func (r Record) Store() store.Readable {
+	return batches(r.Batches)
+}
+
+// batches is a read-only store over the batches of a record.
+//
+// - implements store.Readable
+type batches map[string][]byte
+
+// Get implements store.Readable.
+func (b batches) Get(key []byte) ([]byte, error) {
+	buf, ok := b[hex.EncodeToString(key)]
+	if !ok {
+		return nil, xerrors.Errorf("batch %x is not in the record", key)
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,87 @@
+package record
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sjson "go.dedis.ch/dela/serde/json"
+)
+
+func TestRecord_WriteRead(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	form, snap, formFac := makeElection(t, ctx)
+
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+	require.Equal(t, Type, r.Type)
+	require.Equal(t, Version, r.Version)
+	require.Equal(t, "JSON", r.Format)
+	require.Len(t, r.Batches, len(form.SuffragiaStoreKeys)+len(form.ShuffleStoreKeys)+
+		len(form.ResultsStoreKeys))
+
+	buf := new(bytes.Buffer)
+
+	err = r.Write(buf)
+	require.NoError(t, err)
+
+	read, err := Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, r, read)
+
+	readForm, err := read.GetForm(ctx, formFac)
+	require.NoError(t, err)
+	require.Equal(t, form.FormID, readForm.FormID)
+	require.Equal(t, form.ResultsHashes, readForm.ResultsHashes)
+
+	_, err = read.Store().Get([]byte("unknown"))
+	require.EqualError(t, err, "batch 756e6b6e6f776e is not in the record")
+}
+
+func TestRead(t *testing.T) {
+	_, err := Read(strings.NewReader("{"))
+	require.ErrorContains(t, err, "failed to decode record")
+
+	_, err = Read(strings.NewReader(`{"Type": "ballots", "Version": 1}`))
+	require.EqualError(t, err, "not an election record: \"ballots\"")
+
+	_, err = Read(strings.NewReader(`{"Type": "d-voting/election-record", "Version": 2}`))
+	require.EqualError(t, err, "unsupported record version: 2")
+}
+
+func TestReadFile(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	form, snap, _ := makeElection(t, ctx)
+
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "record.json")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	err = r.Write(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	read, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, r, read)
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "unknown.json"))
+	require.ErrorContains(t, err, "failed to open record")
+}
+
+func TestRecord_GetForm(t *testing.T) {
+	r := Record{Format: "PROTOBUF"}
+
+	_, err := r.GetForm(sjson.NewContext(), nil)
+	require.EqualError(t, err, "record format \"PROTOBUF\" does not match the "+
+		"context format \"JSON\"")
+}
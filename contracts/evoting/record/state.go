@@ -0,0 +1,92 @@
+package record
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// StateType identifies a state file.
+	StateType = "d-voting/state"
+	// StateVersion is the version of the state format written by this
+	// package.
+	StateVersion = 1
+)
+
+// State is a copy of the state of the evoting contract of a chain, to replay
+// it on another chain. It holds the admin list, the IDs of all the forms and a
+// record of each finished form.
+type State struct {
+	Type    string
+	Version int
+	// Format is the serde format of the records, for example "JSON".
+	Format string
+	// AdminList is the list of the SCIPERs of the admins.
+	AdminList []int
+	// FormsIDs are the hex-encoded IDs of all the forms of the chain, including
+	// the ones that are not finished and have no record.
+	FormsIDs []string
+	Records  []Record
+}
+
+// ReadState reads a state and checks its type and version.
+func ReadState(r io.Reader) (State, error) {
+	var state State
+
+	err := json.NewDecoder(r).Decode(&state)
+	if err != nil {
+		return state, xerrors.Errorf("failed to decode state: %v", err)
+	}
+
+	if state.Type != StateType {
+		return state, xerrors.Errorf("not a state: %q", state.Type)
+	}
+
+	if state.Version != StateVersion {
+		return state, xerrors.Errorf("unsupported state version: %d", state.Version)
+	}
+
+	return state, nil
+}
+
+// ReadStateFile reads the state stored at path.
+func ReadStateFile(path string) (State, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return State{}, xerrors.Errorf("failed to open state: %v", err)
+	}
+
+	defer f.Close()
+
+	return ReadState(f)
+}
+
+// Write writes the state in JSON.
+func (s State) Write(w io.Writer) error {
+	err := json.NewEncoder(w).Encode(s)
+	if err != nil {
+		return xerrors.Errorf("failed to encode state: %v", err)
+	}
+
+	return nil
+}
+
+// BatchList returns the batches of the record in the order of the keys of the
+// form, as expected by the IMPORT_FORM command.
+func (r Record) BatchList(keys [][]byte) ([][]byte, error) {
+	batches := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		buf, err := r.Store().Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		batches[i] = buf
+	}
+
+	return batches, nil
+}
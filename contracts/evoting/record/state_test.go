@@ -0,0 +1,103 @@
+package record
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	sjson "go.dedis.ch/dela/serde/json"
+)
+
+func TestState_WriteRead(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	form, snap, formFac := makeElection(t, ctx)
+
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+
+	state := State{
+		Type:      StateType,
+		Version:   StateVersion,
+		Format:    "JSON",
+		AdminList: []int{123456},
+		FormsIDs:  []string{form.FormID, "beefdead"},
+		Records:   []Record{r},
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = state.Write(buf)
+	require.NoError(t, err)
+
+	read, err := ReadState(buf)
+	require.NoError(t, err)
+	require.Equal(t, state, read)
+
+	readForm, err := read.Records[0].GetForm(ctx, formFac)
+	require.NoError(t, err)
+
+	batches, err := read.Records[0].BatchList(readForm.BatchKeys())
+	require.NoError(t, err)
+
+	// the batches are imported in a new store
+	imported := fake.NewSnapshot()
+
+	err = readForm.ImportBatches(ctx, imported, batches)
+	require.NoError(t, err)
+	require.Equal(t, form.VoterCount, readForm.VoterCount)
+
+	voted, err := readForm.HasVoted(imported, "a")
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	results, err := readForm.Results(ctx, imported, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	err = readForm.ImportBatches(ctx, fake.NewSnapshot(), batches[1:])
+	require.EqualError(t, err, "2 batches for 3 keys")
+
+	batches[len(batches)-1] = []byte("{}")
+
+	err = readForm.ImportBatches(ctx, fake.NewSnapshot(), batches)
+	require.EqualError(t, err, "hash of results batch 0 doesn't match")
+
+	_, err = read.Records[0].BatchList([][]byte{[]byte("unknown")})
+	require.EqualError(t, err, "batch 756e6b6e6f776e is not in the record")
+}
+
+func TestReadState(t *testing.T) {
+	_, err := ReadState(strings.NewReader("{"))
+	require.ErrorContains(t, err, "failed to decode state")
+
+	_, err = ReadState(strings.NewReader(`{"Type": "d-voting/election-record", "Version": 1}`))
+	require.EqualError(t, err, "not a state: \"d-voting/election-record\"")
+
+	_, err = ReadState(strings.NewReader(`{"Type": "d-voting/state", "Version": 2}`))
+	require.EqualError(t, err, "unsupported state version: 2")
+}
+
+func TestReadStateFile(t *testing.T) {
+	state := State{Type: StateType, Version: StateVersion, Format: "JSON"}
+
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	err = state.Write(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	read, err := ReadStateFile(path)
+	require.NoError(t, err)
+	require.Equal(t, state, read)
+
+	_, err = ReadStateFile(filepath.Join(t.TempDir(), "unknown.json"))
+	require.ErrorContains(t, err, "failed to open state")
+}
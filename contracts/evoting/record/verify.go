@@ -0,0 +1,273 @@
+package record
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// StepError is returned by Verify. It names the first step of the
+// verification that failed.
+type StepError struct {
+	Step string
+	Err  error
+}
+
+// Error implements error.
+func (e StepError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Step, e.Err)
+}
+
+// Verify re-checks the election of the record independently of the nodes:
+// the proof of every shuffle with its random vector, the decryption of the
+// last shuffle with the public shares, and that the published results are the
+// decrypted ballots. If the results are certified, the certificate is checked
+// too. Each successful step is reported on w.
+func Verify(ctx serde.Context, r Record, formFac serde.Factory, w io.Writer) error {
+	form, err := r.GetForm(ctx, formFac)
+	if err != nil {
+		return StepError{Step: "form", Err: err}
+	}
+
+	if form.Status != types.ResultAvailable {
+		return StepError{Step: "form", Err: xerrors.Errorf("the results are not "+
+			"available, current status: %d", form.Status)}
+	}
+
+	fmt.Fprintf(w, "form %s: ok\n", form.FormID)
+
+	suff, err := form.Suffragia(ctx, r.Store())
+	if err != nil {
+		return StepError{Step: "suffragia", Err: err}
+	}
+
+	fmt.Fprintf(w, "suffragia: ok, %d ballots\n", len(suff.Ciphervotes))
+
+	err = verifyBallotsTree(ctx, form, r, suff.Ciphervotes)
+	if err != nil {
+		return StepError{Step: "ballots tree", Err: err}
+	}
+
+	fmt.Fprintf(w, "ballots tree: ok, %d ballots cast\n", form.BallotsTree.Size)
+
+	shuffledBallots, err := verifyShuffles(ctx, form, r, suff.Ciphervotes, w)
+	if err != nil {
+		return err
+	}
+
+	ballots, err := evoting.DecryptBallots(form, shuffledBallots)
+	if err != nil {
+		return StepError{Step: "decryption", Err: err}
+	}
+
+	fmt.Fprintf(w, "decryption: ok, %d ballots\n", len(ballots))
+
+	err = verifyResults(ctx, form, r, ballots)
+	if err != nil {
+		return StepError{Step: "results", Err: err}
+	}
+
+	fmt.Fprintf(w, "results: ok, %d ballots\n", form.ResultsCount)
+
+	if len(form.Certificate) == 0 {
+		fmt.Fprintln(w, "certificate: none")
+		return nil
+	}
+
+	err = form.VerifyCertificate()
+	if err != nil {
+		return StepError{Step: "certificate", Err: err}
+	}
+
+	fmt.Fprintln(w, "certificate: ok")
+
+	return nil
+}
+
+// verifyShuffles checks the shuffles in order, the first one taking the cast
+// ballots as input. It does the same checks as the smart contract except for
+// the signature of the shuffler, which is not kept in the form. It returns the
+// ballots of the last shuffle.
+func verifyShuffles(ctx serde.Context, form types.Form, r Record,
+	ciphervotes []types.Ciphervote, w io.Writer) ([]types.Ciphervote, error) {
+
+	if form.ShuffleCount() < form.ShuffleThreshold {
+		return nil, StepError{Step: "shuffle", Err: xerrors.Errorf("not enough "+
+			"shuffles: %d < %d", form.ShuffleCount(), form.ShuffleThreshold)}
+	}
+
+	shufflers := make(map[string]struct{})
+
+	for round := 0; round < form.ShuffleCount(); round++ {
+		step := fmt.Sprintf("shuffle %d", round)
+
+		instance, err := form.Shuffle(ctx, r.Store(), round)
+		if err != nil {
+			return nil, StepError{Step: step, Err: err}
+		}
+
+		shuffler := hex.EncodeToString(instance.ShufflerPublicKey)
+
+		_, found := shufflers[shuffler]
+		if found {
+			return nil, StepError{Step: step, Err: xerrors.Errorf("node %s already "+
+				"shuffled", shuffler)}
+		}
+
+		shufflers[shuffler] = struct{}{}
+
+		err = isMemberOf(form, instance.ShufflerPublicKey)
+		if err != nil {
+			return nil, StepError{Step: step, Err: err}
+		}
+
+		if len(ciphervotes) < 2 {
+			return nil, StepError{Step: step, Err: xerrors.Errorf("not enough votes: "+
+				"%d < 2", len(ciphervotes))}
+		}
+
+		randomVector, err := shuffleRandomVector(form, instance)
+		if err != nil {
+			return nil, StepError{Step: step, Err: err}
+		}
+
+		err = evoting.VerifyShuffle(form.Pubkey, ciphervotes, instance.ShuffledBallots,
+			randomVector, instance.ShuffleProofs)
+		if err != nil {
+			return nil, StepError{Step: step, Err: xerrors.Errorf("proof verification "+
+				"failed: %v", err)}
+		}
+
+		fmt.Fprintf(w, "%s: ok\n", step)
+
+		ciphervotes = instance.ShuffledBallots
+	}
+
+	return ciphervotes, nil
+}
+
+// verifyBallotsTree checks that the tree of the ballots cast is closed, that
+// its leaves match its root, and that it contains every counted ballot.
+func verifyBallotsTree(ctx serde.Context, form types.Form, r Record,
+	ciphervotes []types.Ciphervote) error {
+
+	if form.BallotsTree.Root == nil {
+		return xerrors.Errorf("the tree is not closed")
+	}
+
+	digests, err := form.BallotDigests(ctx, r.Store())
+	if err != nil {
+		return xerrors.Errorf("failed to get digests: %v", err)
+	}
+
+	leaves := make(map[string]struct{}, len(digests))
+	for _, digest := range digests {
+		leaves[string(digest)] = struct{}{}
+	}
+
+	for i, ciphervote := range ciphervotes {
+		digest, err := ciphervote.Digest()
+		if err != nil {
+			return xerrors.Errorf("failed to get digest of ballot %d: %v", i, err)
+		}
+
+		_, found := leaves[string(digest)]
+		if !found {
+			return xerrors.Errorf("ballot %d is not in the tree", i)
+		}
+	}
+
+	return nil
+}
+
+// shuffleRandomVector derives the random vector of a shuffle from the hash of
+// its transaction, which only depends on the form ID and the shuffled ballots.
+func shuffleRandomVector(form types.Form, instance types.ShuffleInstance) (
+	[]kyber.Scalar, error) {
+
+	tx := types.ShuffleBallots{
+		FormID:          form.FormID,
+		ShuffledBallots: instance.ShuffledBallots,
+	}
+
+	h := sha256.New()
+
+	err := tx.Fingerprint(h)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get fingerprint: %v", err)
+	}
+
+	randomVector, err := evoting.DeriveRandomVector(h.Sum(nil), form.ChunksPerBallot())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to derive random vector: %v", err)
+	}
+
+	return randomVector, nil
+}
+
+// verifyResults checks the batches of the results against the hashes kept by
+// the form, and that they contain the decrypted ballots in the same order.
+func verifyResults(ctx serde.Context, form types.Form, r Record, ballots []types.Ballot) error {
+	if len(form.ResultsHashes) != len(form.ResultsStoreKeys) {
+		return xerrors.Errorf("%d results hashes for %d batches",
+			len(form.ResultsHashes), len(form.ResultsStoreKeys))
+	}
+
+	for i, key := range form.ResultsStoreKeys {
+		buf, err := r.Store().Get(key)
+		if err != nil {
+			return xerrors.Errorf("failed to get batch %d: %v", i, err)
+		}
+
+		hash := sha256.Sum256(buf)
+		if !bytes.Equal(hash[:], form.ResultsHashes[i]) {
+			return xerrors.Errorf("hash of batch %d does not match", i)
+		}
+	}
+
+	if int(form.ResultsCount) != len(ballots) {
+		return xerrors.Errorf("%d published ballots for %d decrypted ballots",
+			form.ResultsCount, len(ballots))
+	}
+
+	published, err := form.Results(ctx, r.Store(), 0, 0)
+	if err != nil {
+		return xerrors.Errorf("failed to get results: %v", err)
+	}
+
+	for i, ballot := range published {
+		if !ballot.Equal(ballots[i]) {
+			return xerrors.Errorf("published ballot %d does not match the "+
+				"decrypted ballot", i)
+		}
+	}
+
+	return nil
+}
+
+// isMemberOf checks that the public key belongs to the roster of the form.
+func isMemberOf(form types.Form, publicKey []byte) error {
+	iter := form.Roster.PublicKeyIterator()
+
+	for iter.HasNext() {
+		key, err := iter.GetNext().MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal public key: %v", err)
+		}
+
+		if bytes.Equal(key, publicKey) {
+			return nil
+		}
+	}
+
+	return xerrors.Errorf("public key not associated to a member of the roster: %x",
+		publicKey)
+}
@@ -0,0 +1,269 @@
+package record
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+	dfake "go.dedis.ch/dela/testing/fake"
+	"go.dedis.ch/kyber/v3/proof"
+	"go.dedis.ch/kyber/v3/shuffle"
+	"go.dedis.ch/kyber/v3/suites"
+	"go.dedis.ch/kyber/v3/util/random"
+)
+
+var suite = suites.MustFind("Ed25519")
+
+func TestVerify(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	form, snap, formFac := makeElection(t, ctx)
+
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+
+	err = Verify(ctx, r, formFac, out)
+	require.NoError(t, err)
+	require.Equal(t, "form deadbeef: ok\n"+
+		"suffragia: ok, 3 ballots\n"+
+		"ballots tree: ok, 3 ballots cast\n"+
+		"shuffle 0: ok\n"+
+		"decryption: ok, 3 ballots\n"+
+		"results: ok, 3 ballots\n"+
+		"certificate: none\n", out.String())
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	form, snap, formFac := makeElection(t, ctx)
+
+	instance, err := form.Shuffle(ctx, snap, 0)
+	require.NoError(t, err)
+
+	// the shuffle proof does not match
+	badInstance := instance
+	badInstance.ShuffleProofs = []byte("bad proof")
+
+	err = verifyForm(t, ctx, storeShuffle(t, ctx, form, snap, badInstance), snap, formFac)
+	require.IsType(t, StepError{}, err)
+	require.Equal(t, "shuffle 0", err.(StepError).Step)
+	require.ErrorContains(t, err, "proof verification failed")
+
+	// the shuffle is modified in the record
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+
+	err = Verify(ctx, r, formFac, new(bytes.Buffer))
+	require.EqualError(t, err, "shuffle 0: hash of shuffle 0 doesn't match")
+
+	// the shuffler is not in the roster
+	badInstance = instance
+	badInstance.ShufflerPublicKey = []byte("not a member")
+
+	err = verifyForm(t, ctx, storeShuffle(t, ctx, form, snap, badInstance), snap, formFac)
+	require.ErrorContains(t, err, "shuffle 0: public key not associated to a member of the roster")
+
+	storeShuffle(t, ctx, form, snap, instance)
+
+	// not enough shuffles
+	tampered := form
+	tampered.ShuffleThreshold = 2
+
+	err = verifyForm(t, ctx, tampered, snap, formFac)
+	require.EqualError(t, err, "shuffle: not enough shuffles: 1 < 2")
+
+	// the published results are not the decrypted ballots
+	tampered = form
+
+	ballots, err := tampered.Results(ctx, snap, 0, 0)
+	require.NoError(t, err)
+
+	ballots[0] = types.Ballot{}
+
+	err = tampered.StoreResults(ctx, snap, ballots)
+	require.NoError(t, err)
+
+	err = verifyForm(t, ctx, tampered, snap, formFac)
+	require.EqualError(t, err, "results: published ballot 0 does not match the decrypted ballot")
+
+	// a batch of the results is modified in the record
+	r, err = New(ctx, form, snap)
+	require.NoError(t, err)
+
+	for _, key := range form.ResultsStoreKeys {
+		r.Batches[hex.EncodeToString(key)] = append(r.Batches[hex.EncodeToString(key)], ' ')
+	}
+
+	err = Verify(ctx, r, formFac, new(bytes.Buffer))
+	require.EqualError(t, err, "results: hash of batch 0 does not match")
+
+	// a ballot is added to the suffragia in the record
+	r, err = New(ctx, form, snap)
+	require.NoError(t, err)
+
+	key := hex.EncodeToString(form.SuffragiaStoreKeys[0])
+	r.Batches[key] = bytes.Replace(r.Batches[key], []byte(`"a"`), []byte(`"z"`), 1)
+
+	err = Verify(ctx, r, formFac, new(bytes.Buffer))
+	require.EqualError(t, err, "suffragia: hash of ballots batch 0 doesn't match")
+
+	// the root of the tree of the ballots cast is not the one of the leaves
+	tampered = form
+	tampered.BallotsTree.Root = []byte("bad root")
+
+	err = verifyForm(t, ctx, tampered, snap, formFac)
+	require.EqualError(t, err, "ballots tree: failed to get digests: ballots "+
+		"digests don't match the root of the tree")
+
+	// the results are not available
+	tampered = form
+	tampered.Status = types.PubSharesSubmitted
+
+	err = verifyForm(t, ctx, tampered, snap, formFac)
+	require.EqualError(t, err, "form: the results are not available, current status: 4")
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+func verifyForm(t *testing.T, ctx serde.Context, form types.Form, snap *fake.InMemorySnapshot,
+	formFac serde.Factory) error {
+
+	r, err := New(ctx, form, snap)
+	require.NoError(t, err)
+
+	return Verify(ctx, r, formFac, new(bytes.Buffer))
+}
+
+// storeShuffle replaces the shuffle of the form in the store and returns a copy
+// of the form that references it.
+func storeShuffle(t *testing.T, ctx serde.Context, form types.Form, snap *fake.InMemorySnapshot,
+	instance types.ShuffleInstance) types.Form {
+
+	form.ShuffleStoreKeys = nil
+	form.ShuffleHashes = nil
+	form.ShufflerPublicKeys = nil
+
+	err := form.StoreShuffle(ctx, snap, instance)
+	require.NoError(t, err)
+
+	return form
+}
+
+// makeElection runs an election with three voters on a form whose results are
+// available: the ballots are shuffled once and decrypted with the public shares
+// of a single node.
+func makeElection(t *testing.T, ctx serde.Context) (types.Form, *fake.InMemorySnapshot, serde.Factory) {
+	secret := suite.Scalar().Pick(random.New())
+	pubkey := suite.Point().Mul(secret, nil)
+
+	signer := bls.NewSigner()
+	shufflerKey, err := signer.GetPublicKey().MarshalBinary()
+	require.NoError(t, err)
+
+	roster := authority.New([]mino.Address{dfake.NewAddress(0)},
+		[]crypto.PublicKey{signer.GetPublicKey()})
+
+	configuration := types.Configuration{
+		Title: types.Title{En: "title"},
+		Scaffold: []types.Subject{{
+			ID: "s",
+			Selects: []types.Select{{
+				ID:      "Q1",
+				MaxN:    1,
+				Choices: make([]types.Choice, 2),
+			}},
+		}},
+	}
+
+	form := types.Form{
+		FormID:           "deadbeef",
+		Configuration:    configuration,
+		Status:           types.ResultAvailable,
+		Pubkey:           pubkey,
+		BallotSize:       configuration.MaxBallotSize(),
+		Roster:           roster,
+		ShuffleThreshold: 1,
+	}
+
+	snap := fake.NewSnapshot()
+
+	questionID := base64.StdEncoding.EncodeToString([]byte("Q1"))
+	plaintexts := []string{"1,0", "0,1", "1,0"}
+
+	for i, plaintext := range plaintexts {
+		M := suite.Point().Embed([]byte("select:"+questionID+":"+plaintext+"\n"), random.New())
+		k := suite.Scalar().Pick(random.New())
+		K := suite.Point().Mul(k, nil)
+		C := suite.Point().Add(suite.Point().Mul(k, pubkey), M)
+
+		err = form.CastVote(ctx, snap, string(rune('a'+i)), types.Ciphervote{{K: K, C: C}})
+		require.NoError(t, err)
+	}
+
+	form.BallotsTree.Close()
+
+	suff, err := form.Suffragia(ctx, snap)
+	require.NoError(t, err)
+
+	X, Y := types.CiphervotesToPairs(suff.Ciphervotes)
+
+	XX, YY, getProver := shuffle.SequencesShuffle(suite, nil, pubkey, X, Y, suite.RandomStream())
+
+	shuffledBallots, err := types.CiphervotesFromPairs(XX, YY)
+	require.NoError(t, err)
+
+	instance := types.ShuffleInstance{
+		ShuffledBallots:   shuffledBallots,
+		ShufflerPublicKey: shufflerKey,
+	}
+
+	randomVector, err := shuffleRandomVector(form, instance)
+	require.NoError(t, err)
+
+	prover, err := getProver(randomVector)
+	require.NoError(t, err)
+
+	instance.ShuffleProofs, err = proof.HashProve(suite, "PairShuffle", prover)
+	require.NoError(t, err)
+
+	err = form.StoreShuffle(ctx, snap, instance)
+	require.NoError(t, err)
+
+	// with a single node, the public share of a pair is C - secret*K
+	unit := make(types.PubsharesUnit, len(shuffledBallots))
+
+	for i, ciphervote := range shuffledBallots {
+		unit[i] = []types.Pubshare{
+			suite.Point().Sub(ciphervote[0].C, suite.Point().Mul(secret, ciphervote[0].K)),
+		}
+	}
+
+	form.PubsharesUnits = types.PubsharesUnits{
+		Pubshares: []types.PubsharesUnit{unit},
+		PubKeys:   [][]byte{shufflerKey},
+		Indexes:   []int{0},
+	}
+
+	ballots, err := evoting.DecryptBallots(form, shuffledBallots)
+	require.NoError(t, err)
+
+	err = form.StoreResults(ctx, snap, ballots)
+	require.NoError(t, err)
+
+	return form, snap, types.NewFormFactory(types.CiphervoteFactory{}, fake.NewRosterFac(roster))
+}
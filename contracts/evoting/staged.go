@@ -0,0 +1,94 @@
+package evoting
+
+import (
+	"go.dedis.ch/dela/core/store"
+	"golang.org/x/xerrors"
+)
+
+// stagedSnapshot keeps the writes made on a snapshot aside until they are
+// committed. The writes of a transaction that fails are not rolled back, so a
+// command that writes several keys stages them and commits only once all of
+// them are known to succeed.
+//
+// - implements store.Snapshot
+type stagedSnapshot struct {
+	store.Snapshot
+
+	values  map[string][]byte
+	deleted map[string]bool
+
+	// keys keeps the order of the first write of each key.
+	keys []string
+}
+
+func newStagedSnapshot(snap store.Snapshot) *stagedSnapshot {
+	return &stagedSnapshot{
+		Snapshot: snap,
+		values:   make(map[string][]byte),
+		deleted:  make(map[string]bool),
+	}
+}
+
+// Get implements store.Readable. It returns the staged value if any, or the
+// one of the snapshot.
+func (s *stagedSnapshot) Get(key []byte) ([]byte, error) {
+	if s.deleted[string(key)] {
+		return nil, nil
+	}
+
+	value, found := s.values[string(key)]
+	if found {
+		return value, nil
+	}
+
+	return s.Snapshot.Get(key)
+}
+
+// Set implements store.Writable. The value is written to the snapshot only on
+// commit.
+func (s *stagedSnapshot) Set(key []byte, value []byte) error {
+	s.stage(key)
+
+	delete(s.deleted, string(key))
+	s.values[string(key)] = value
+
+	return nil
+}
+
+// Delete implements store.Writable. The key is deleted from the snapshot only
+// on commit.
+func (s *stagedSnapshot) Delete(key []byte) error {
+	s.stage(key)
+
+	delete(s.values, string(key))
+	s.deleted[string(key)] = true
+
+	return nil
+}
+
+// commit applies the staged writes to the snapshot in the order of the first
+// write of each key.
+func (s *stagedSnapshot) commit() error {
+	for _, key := range s.keys {
+		var err error
+
+		if s.deleted[key] {
+			err = s.Snapshot.Delete([]byte(key))
+		} else {
+			err = s.Snapshot.Set([]byte(key), s.values[key])
+		}
+
+		if err != nil {
+			return xerrors.Errorf("failed to write key %x: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *stagedSnapshot) stage(key []byte) {
+	_, found := s.values[string(key)]
+	if !found && !s.deleted[string(key)] {
+		s.keys = append(s.keys, string(key))
+	}
+}
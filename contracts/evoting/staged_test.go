@@ -0,0 +1,63 @@
+package evoting
+
+import (
+	"testing"
+
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagedSnapshot(t *testing.T) {
+	snap := fake.NewSnapshot()
+	require.NoError(t, snap.Set([]byte("a"), []byte("1")))
+	require.NoError(t, snap.Set([]byte("b"), []byte("2")))
+
+	stage := newStagedSnapshot(snap)
+
+	require.NoError(t, stage.Set([]byte("a"), []byte("3")))
+	require.NoError(t, stage.Delete([]byte("b")))
+	require.NoError(t, stage.Set([]byte("c"), []byte("4")))
+
+	// the stage sees its own writes
+	value, err := stage.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+
+	value, err = stage.Get([]byte("b"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// the snapshot doesn't see them before the commit
+	value, err = snap.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), value)
+
+	value, err = snap.Get([]byte("c"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.NoError(t, stage.commit())
+
+	value, err = snap.Get([]byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+
+	value, err = snap.Get([]byte("b"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	value, err = snap.Get([]byte("c"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("4"), value)
+
+	snap.ErrWrite = fake.GetError()
+
+	err = newStagedSnapshot(snap).commit()
+	require.NoError(t, err)
+
+	stage = newStagedSnapshot(snap)
+	require.NoError(t, stage.Set([]byte("a"), []byte("5")))
+
+	err = stage.commit()
+	require.EqualError(t, err, fake.Err("failed to write key 61"))
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/xerrors"
 )
@@ -61,91 +63,166 @@ func (b *Ballot) Unmarshal(marshalledBallot string, form Form) error {
 			break
 		}
 
-		question := strings.Split(line, ":")
-
-		if len(question) != 3 {
+		_, err := b.unmarshalLine(line, form.Configuration, false)
+		if err != nil {
 			b.invalidate()
-			return xerrors.Errorf("a line in the ballot has length != 3: %s", line)
+			return err
 		}
+	}
 
-		questionID, err := base64.StdEncoding.DecodeString(question[1])
-		if err != nil {
-			return xerrors.Errorf("could not decode question ID: %v", err)
+	return nil
+}
+
+// BallotError describes why a part of a marshalled ballot is not acceptable.
+// QuestionID is empty when the error cannot be attributed to a question.
+type BallotError struct {
+	QuestionID ID
+	Message    string
+}
+
+// Validate runs the checks of Unmarshal on a marshalled ballot without
+// stopping at the first error. It additionally enforces the text constraints
+// (MaxLength and Regex) of the configuration, checks that every question
+// requiring an answer has one, and that the ballot fits in ballotSize bytes.
+// It returns one error per faulty question, or nil if the ballot is valid.
+func (b *Ballot) Validate(marshalledBallot string, configuration Configuration,
+	ballotSize int) []BallotError {
+
+	var errs []BallotError
+
+	if len(marshalledBallot) > ballotSize {
+		errs = append(errs, BallotError{
+			Message: fmt.Sprintf("ballot is too long: %d > %d",
+				len(marshalledBallot), ballotSize),
+		})
+	}
+
+	answered := make(map[ID]bool)
+
+	for _, line := range strings.Split(marshalledBallot, "\n") {
+		if line == "" {
+			break
 		}
 
-		q := form.Configuration.GetQuestion(ID(questionID))
+		questionID, err := b.unmarshalLine(line, configuration, true)
 
-		if q == nil {
-			b.invalidate()
-			return fmt.Errorf("wrong question ID: the question doesn't exist")
+		if questionID != "" && answered[questionID] {
+			errs = append(errs, BallotError{
+				QuestionID: questionID,
+				Message:    "question answered more than once",
+			})
 		}
 
-		switch question[0] {
+		if questionID != "" {
+			answered[questionID] = true
+		}
 
-		case selectID:
-			selections := strings.Split(question[2], ",")
+		if err != nil {
+			errs = append(errs, BallotError{QuestionID: questionID, Message: err.Error()})
+		}
+	}
 
-			selectQ := Select{
-				ID:      ID(questionID),
-				MaxN:    q.GetMaxN(),
-				MinN:    q.GetMinN(),
-				Choices: make([]Choice, q.GetChoicesLength()),
-			}
+	configuration.ForEachQuestion(func(id ID, q Question) {
+		if !answered[id] && q.GetMinN() > 0 {
+			errs = append(errs, BallotError{
+				QuestionID: id,
+				Message:    "question has not been answered",
+			})
+		}
+	})
 
-			results, err := selectQ.unmarshalAnswers(selections)
-			if err != nil {
-				b.invalidate()
-				return fmt.Errorf("could not unmarshal select answers: %v", err)
-			}
+	return errs
+}
 
-			b.SelectResultIDs = append(b.SelectResultIDs, ID(questionID))
-			b.SelectResult = append(b.SelectResult, results)
+// unmarshalLine decodes one line of a marshalled ballot and appends the
+// result to the ballot. It returns the decoded question ID, when it could be
+// decoded. In strict mode the text constraints of the question are enforced.
+func (b *Ballot) unmarshalLine(line string, configuration Configuration,
+	strict bool) (ID, error) {
 
-		case rankID:
-			ranks := strings.Split(question[2], ",")
+	question := strings.Split(line, ":")
 
-			rankQ := Rank{
-				ID:      ID(questionID),
-				MaxN:    q.GetMaxN(),
-				MinN:    q.GetMinN(),
-				Choices: make([]Choice, q.GetChoicesLength()),
-			}
+	if len(question) != 3 {
+		return "", xerrors.Errorf("a line in the ballot has length != 3: %s", line)
+	}
 
-			results, err := rankQ.unmarshalAnswers(ranks)
-			if err != nil {
-				b.invalidate()
-				return fmt.Errorf("could not unmarshal rank answers: %v", err)
-			}
-			b.RankResultIDs = append(b.RankResultIDs, ID(questionID))
-			b.RankResult = append(b.RankResult, results)
-
-		case textID:
-			texts := strings.Split(question[2], ",")
-
-			textQ := Text{
-				ID:        ID(questionID),
-				MaxN:      q.GetMaxN(),
-				MinN:      q.GetMinN(),
-				MaxLength: 0, // TODO: Should the length check be also done at decryption?
-				Choices:   make([]Choice, q.GetChoicesLength()),
-			}
+	questionID, err := base64.StdEncoding.DecodeString(question[1])
+	if err != nil {
+		return "", xerrors.Errorf("could not decode question ID: %v", err)
+	}
 
-			results, err := textQ.unmarshalAnswers(texts)
-			if err != nil {
-				b.invalidate()
-				return fmt.Errorf("could not unmarshal text answers: %v", err)
-			}
-			b.TextResultIDs = append(b.TextResultIDs, ID(questionID))
-			b.TextResult = append(b.TextResult, results)
+	q := configuration.GetQuestion(ID(questionID))
 
-		default:
-			b.invalidate()
-			return fmt.Errorf("question type is unknown")
+	if q == nil {
+		return ID(questionID), fmt.Errorf("wrong question ID: the question doesn't exist")
+	}
+
+	switch question[0] {
+
+	case selectID:
+		selections := strings.Split(question[2], ",")
+
+		selectQ := Select{
+			ID:      ID(questionID),
+			MaxN:    q.GetMaxN(),
+			MinN:    q.GetMinN(),
+			Choices: make([]Choice, q.GetChoicesLength()),
+		}
+
+		results, err := selectQ.unmarshalAnswers(selections)
+		if err != nil {
+			return ID(questionID), fmt.Errorf("could not unmarshal select answers: %v", err)
 		}
 
+		b.SelectResultIDs = append(b.SelectResultIDs, ID(questionID))
+		b.SelectResult = append(b.SelectResult, results)
+
+	case rankID:
+		ranks := strings.Split(question[2], ",")
+
+		rankQ := Rank{
+			ID:      ID(questionID),
+			MaxN:    q.GetMaxN(),
+			MinN:    q.GetMinN(),
+			Choices: make([]Choice, q.GetChoicesLength()),
+		}
+
+		results, err := rankQ.unmarshalAnswers(ranks)
+		if err != nil {
+			return ID(questionID), fmt.Errorf("could not unmarshal rank answers: %v", err)
+		}
+		b.RankResultIDs = append(b.RankResultIDs, ID(questionID))
+		b.RankResult = append(b.RankResult, results)
+
+	case textID:
+		texts := strings.Split(question[2], ",")
+
+		textQ := Text{
+			ID:        ID(questionID),
+			MaxN:      q.GetMaxN(),
+			MinN:      q.GetMinN(),
+			MaxLength: 0, // TODO: Should the length check be also done at decryption?
+			Choices:   make([]Choice, q.GetChoicesLength()),
+		}
+
+		text, isText := q.(Text)
+		if strict && isText {
+			textQ.MaxLength = text.MaxLength
+			textQ.Regex = text.Regex
+		}
+
+		results, err := textQ.unmarshalAnswers(texts)
+		if err != nil {
+			return ID(questionID), fmt.Errorf("could not unmarshal text answers: %v", err)
+		}
+		b.TextResultIDs = append(b.TextResultIDs, ID(questionID))
+		b.TextResult = append(b.TextResult, results)
+
+	default:
+		return ID(questionID), fmt.Errorf("question type is unknown")
 	}
 
-	return nil
+	return ID(questionID), nil
 }
 
 // checkNumberOfAnswers checks if the given amount of answers is in the accepted
@@ -322,6 +399,53 @@ func (s *Subject) GetQuestion(ID ID) Question {
 	return nil
 }
 
+// forEachQuestion calls fn on every question of the subject and of its
+// sub-subjects. Elements listed in Order are visited first, in that order,
+// followed by the ones missing from it.
+func (s *Subject) forEachQuestion(fn func(ID, Question)) {
+	visited := make(map[ID]bool)
+
+	visit := func(id ID) {
+		if visited[id] {
+			return
+		}
+
+		for _, subject := range s.Subjects {
+			if subject.ID == id {
+				visited[id] = true
+				subject.forEachQuestion(fn)
+				return
+			}
+		}
+
+		question := s.GetQuestion(id)
+		if question != nil {
+			visited[id] = true
+			fn(id, question)
+		}
+	}
+
+	for _, id := range s.Order {
+		visit(id)
+	}
+
+	for _, subject := range s.Subjects {
+		visit(subject.ID)
+	}
+
+	for _, selects := range s.Selects {
+		visit(selects.ID)
+	}
+
+	for _, rank := range s.Ranks {
+		visit(rank.ID)
+	}
+
+	for _, text := range s.Texts {
+		visit(text.ID)
+	}
+}
+
 // MaxEncodedSize returns the maximum amount of bytes taken to store the
 // questions in this subject once encoded in a ballot
 func (s *Subject) MaxEncodedSize() int {
@@ -631,6 +755,17 @@ func (t Text) unmarshalAnswers(texts []string) ([]string, error) {
 	var selected uint = 0
 	results := make([]string, 0)
 
+	// the whole text must match the regex, not only a part of it
+	var regex *regexp.Regexp
+	if t.Regex != "" {
+		var err error
+
+		regex, err = regexp.Compile("^(?:" + t.Regex + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for Q.%s: %v", t.ID, err)
+		}
+	}
+
 	for _, text := range texts {
 		if len(text) > 0 {
 			selected++
@@ -641,6 +776,15 @@ func (t Text) unmarshalAnswers(texts []string) ([]string, error) {
 			return nil, fmt.Errorf("could not decode text for Q.%s: %v", t.ID, err)
 		}
 
+		if t.MaxLength > 0 && uint(utf8.RuneCount(textValue)) > t.MaxLength {
+			return nil, fmt.Errorf("text for Q.%s is longer than %d characters",
+				t.ID, t.MaxLength)
+		}
+
+		if regex != nil && len(textValue) > 0 && !regex.Match(textValue) {
+			return nil, fmt.Errorf("text for Q.%s does not match %q", t.ID, t.Regex)
+		}
+
 		results = append(results, string(textValue))
 	}
 
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/base64"
+	"fmt"
 	"strconv"
 	"testing"
 
@@ -301,6 +302,126 @@ func TestBallot_Unmarshal(t *testing.T) {
 	require.EqualError(t, err, "question type is unknown")
 }
 
+func TestBallot_Validate(t *testing.T) {
+	configuration := Configuration{Scaffold: []Subject{{
+		Selects: []Select{{
+			ID:      decodedQuestionID(1),
+			MaxN:    2,
+			MinN:    1,
+			Choices: make([]Choice, 3),
+		}},
+		Ranks: []Rank{{
+			ID:      decodedQuestionID(2),
+			MaxN:    3,
+			MinN:    0,
+			Choices: make([]Choice, 3),
+		}},
+		Texts: []Text{{
+			ID:        decodedQuestionID(3),
+			MaxN:      1,
+			MinN:      1,
+			MaxLength: 5,
+			Regex:     "^[a-z]+$",
+			Choices:   make([]Choice, 1),
+		}},
+	}}}
+
+	b := Ballot{}
+
+	valid := string(selectIDTest + encodedQuestionID(1) + ":1,0,1\n" +
+		rankIDTest + encodedQuestionID(2) + ":0,,1\n" +
+		textIDTest + encodedQuestionID(3) + ":" +
+		ID(base64.StdEncoding.EncodeToString([]byte("abc"))) + "\n\n")
+
+	errs := b.Validate(valid, configuration, len(valid))
+	require.Nil(t, errs)
+
+	errs = b.Validate(valid, configuration, 1)
+	require.Len(t, errs, 1)
+	require.Equal(t, ID(""), errs[0].QuestionID)
+	require.Equal(t, fmt.Sprintf("ballot is too long: %d > 1", len(valid)), errs[0].Message)
+
+	// every faulty question is reported, not only the first one
+	invalid := string(selectIDTest + encodedQuestionID(1) + ":1,1,1\n" +
+		rankIDTest + encodedQuestionID(2) + ":0,3,\n" +
+		textIDTest + encodedQuestionID(3) + ":" +
+		ID(base64.StdEncoding.EncodeToString([]byte("abcdef"))) + "\n\n")
+
+	errs = b.Validate(invalid, configuration, len(invalid))
+	require.Len(t, errs, 3)
+	require.Equal(t, decodedQuestionID(1), errs[0].QuestionID)
+	require.Equal(t, "could not unmarshal select answers: failed to check number "+
+		"of answers: question Q1 has too many selected answers", errs[0].Message)
+	require.Equal(t, decodedQuestionID(2), errs[1].QuestionID)
+	require.Equal(t, "could not unmarshal rank answers: invalid rank not in "+
+		"range [0, MaxN[: 3", errs[1].Message)
+	require.Equal(t, decodedQuestionID(3), errs[2].QuestionID)
+	require.Equal(t, "could not unmarshal text answers: text for Q.Q3 is "+
+		"longer than 5 characters", errs[2].Message)
+
+	// regex mismatch and missing mandatory question
+	invalid = string(textIDTest + encodedQuestionID(3) + ":" +
+		ID(base64.StdEncoding.EncodeToString([]byte("AB"))) + "\n\n")
+
+	errs = b.Validate(invalid, configuration, len(invalid))
+	require.Len(t, errs, 2)
+	require.Equal(t, decodedQuestionID(3), errs[0].QuestionID)
+	require.Equal(t, "could not unmarshal text answers: text for Q.Q3 does "+
+		"not match \"^[a-z]+$\"", errs[0].Message)
+	require.Equal(t, BallotError{
+		QuestionID: decodedQuestionID(1),
+		Message:    "question has not been answered",
+	}, errs[1])
+
+	// question answered twice and unknown question
+	invalid = string(selectIDTest + encodedQuestionID(1) + ":1,0,0\n" +
+		selectIDTest + encodedQuestionID(1) + ":1,0,0\n" +
+		selectIDTest + encodedQuestionID(9) + ":1,0,0\n" +
+		textIDTest + encodedQuestionID(3) + ":" +
+		ID(base64.StdEncoding.EncodeToString([]byte("a"))) + "\n\n")
+
+	errs = b.Validate(invalid, configuration, len(invalid))
+	require.Equal(t, []BallotError{{
+		QuestionID: decodedQuestionID(1),
+		Message:    "question answered more than once",
+	}, {
+		QuestionID: decodedQuestionID(9),
+		Message:    "wrong question ID: the question doesn't exist",
+	}}, errs)
+}
+
+func TestText_Regex(t *testing.T) {
+	text := Text{
+		ID:      decodedQuestionID(1),
+		MaxN:    1,
+		Regex:   "[0-9]+",
+		Choices: make([]Choice, 1),
+	}
+
+	encode := func(s string) []string {
+		return []string{base64.StdEncoding.EncodeToString([]byte(s))}
+	}
+
+	res, err := text.unmarshalAnswers(encode("123"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"123"}, res)
+
+	// the regex must match the whole text
+	_, err = text.unmarshalAnswers(encode("abc1"))
+	require.EqualError(t, err, "text for Q.Q1 does not match \"[0-9]+\"")
+
+	// an alternation is anchored as a whole
+	text.Regex = "a|b"
+
+	_, err = text.unmarshalAnswers(encode("ab"))
+	require.Error(t, err)
+
+	text.Regex = "("
+
+	_, err = text.unmarshalAnswers(encode("1"))
+	require.ErrorContains(t, err, "invalid regex for Q.Q1")
+}
+
 func TestSubject_MaxEncodedSize(t *testing.T) {
 	subject := Subject{
 		Subjects: []Subject{{
@@ -493,6 +614,20 @@ func TestSubject_IsValid(t *testing.T) {
 
 	valid = configuration.IsValid()
 	require.False(t, valid)
+
+	// with signed ballots but no authority to endorse the voter keys
+
+	mainSubject.Order = []ID{}
+	configuration.Scaffold = []Subject{*mainSubject}
+	configuration.SignedBallots = true
+
+	valid = configuration.IsValid()
+	require.False(t, valid)
+
+	configuration.VoterKeysAuthority, _ = suite.Point().Pick(suite.RandomStream()).MarshalBinary()
+
+	valid = configuration.IsValid()
+	require.True(t, valid)
 }
 
 func TestBallot_Equal(t *testing.T) {
@@ -0,0 +1,189 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/bits"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// BallotsTree is an append-only Merkle tree over the digests of the ballots,
+// in the order they are cast. A ballot cast again by the same voter is added
+// as a new leaf: the tree records every ballot cast, the last one of a voter
+// being the one counted. Leaves and nodes are hashed as in RFC 9162, so that
+// the tree does not need to be balanced.
+//
+// Only the roots of the perfect subtrees are kept, which is enough to append a
+// leaf and to compute the root of the tree. The digests of the leaves are kept
+// in the Suffragia.
+type BallotsTree struct {
+	// Size is the number of leaves
+	Size uint32
+
+	// Frontier holds the roots of the perfect subtrees of the tree, from the
+	// largest to the smallest. There is one per bit set in Size.
+	Frontier [][]byte
+
+	// Root is set when the form is closed. Once it is, no leaf can be
+	// appended.
+	Root []byte
+}
+
+// Append adds the digest of a ballot to the tree.
+func (t *BallotsTree) Append(digest []byte) error {
+	if t.Root != nil {
+		return xerrors.Errorf("the ballots tree is closed")
+	}
+
+	node := leafHash(digest)
+
+	for size := t.Size; size&1 == 1; size >>= 1 {
+		node = nodeHash(t.Frontier[len(t.Frontier)-1], node)
+		t.Frontier = t.Frontier[:len(t.Frontier)-1]
+	}
+
+	t.Frontier = append(t.Frontier, node)
+	t.Size++
+
+	return nil
+}
+
+// CurrentRoot returns the root of the tree, which is Root once the tree is
+// closed.
+func (t BallotsTree) CurrentRoot() []byte {
+	if t.Root != nil {
+		return t.Root
+	}
+
+	if len(t.Frontier) == 0 {
+		return emptyRoot()
+	}
+
+	root := t.Frontier[len(t.Frontier)-1]
+
+	for i := len(t.Frontier) - 2; i >= 0; i-- {
+		root = nodeHash(t.Frontier[i], root)
+	}
+
+	return root
+}
+
+// Close sets the root of the tree.
+func (t *BallotsTree) Close() {
+	t.Root = t.CurrentRoot()
+}
+
+// MerkleRoot returns the root of the tree over the digests of the ballots.
+func MerkleRoot(digests [][]byte) []byte {
+	if len(digests) == 0 {
+		return emptyRoot()
+	}
+
+	if len(digests) == 1 {
+		return leafHash(digests[0])
+	}
+
+	k := splitPoint(len(digests))
+
+	return nodeHash(MerkleRoot(digests[:k]), MerkleRoot(digests[k:]))
+}
+
+// InclusionProof returns the audit path of the leaf at index in the tree over
+// the digests of the ballots, from the leaf to the root, as defined by RFC
+// 9162. It contains one hash per level of the tree.
+func InclusionProof(digests [][]byte, index int) ([][]byte, error) {
+	if index < 0 || index >= len(digests) {
+		return nil, xerrors.Errorf("index out of range: %d not in [0, %d)",
+			index, len(digests))
+	}
+
+	return inclusionProof(digests, index), nil
+}
+
+func inclusionProof(digests [][]byte, index int) [][]byte {
+	if len(digests) == 1 {
+		return nil
+	}
+
+	k := splitPoint(len(digests))
+
+	if index < k {
+		return append(inclusionProof(digests[:k], index), MerkleRoot(digests[k:]))
+	}
+
+	return append(inclusionProof(digests[k:], index-k), MerkleRoot(digests[:k]))
+}
+
+// VerifyInclusion checks that the digest is the leaf at index of the tree of
+// the given size and root, as defined by RFC 9162.
+func VerifyInclusion(root, digest []byte, index, size uint32, proof [][]byte) error {
+	if index >= size {
+		return xerrors.Errorf("index out of range: %d not in [0, %d)", index, size)
+	}
+
+	fn, sn := index, size-1
+	node := leafHash(digest)
+
+	for _, hash := range proof {
+		if sn == 0 {
+			return xerrors.Errorf("proof is too long")
+		}
+
+		if fn&1 == 1 || fn == sn {
+			node = nodeHash(hash, node)
+
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			node = nodeHash(node, hash)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 {
+		return xerrors.Errorf("proof is too short")
+	}
+
+	if !bytes.Equal(node, root) {
+		return xerrors.Errorf("root mismatch")
+	}
+
+	return nil
+}
+
+// splitPoint returns the largest power of two smaller than n, n > 1.
+func splitPoint(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+func leafHash(digest []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(digest)
+
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+
+	return h.Sum(nil)
+}
+
+func emptyRoot() []byte {
+	h := sha256.Sum256(nil)
+	return h[:]
+}
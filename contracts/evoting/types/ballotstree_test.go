@@ -0,0 +1,82 @@
+package types
+
+import (
+	"crypto/sha256"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBallotsTree_Append(t *testing.T) {
+	var tree BallotsTree
+	var digests [][]byte
+
+	require.Equal(t, MerkleRoot(nil), tree.CurrentRoot())
+
+	for i := 0; i < 20; i++ {
+		digest := sha256.Sum256([]byte(strconv.Itoa(i)))
+		digests = append(digests, digest[:])
+
+		err := tree.Append(digest[:])
+		require.NoError(t, err)
+		require.Equal(t, uint32(i+1), tree.Size)
+		require.Equal(t, MerkleRoot(digests), tree.CurrentRoot())
+	}
+
+	require.Len(t, tree.Frontier, 2)
+
+	tree.Close()
+	require.Equal(t, MerkleRoot(digests), tree.Root)
+
+	err := tree.Append(digests[0])
+	require.EqualError(t, err, "the ballots tree is closed")
+}
+
+func TestInclusionProof(t *testing.T) {
+	var digests [][]byte
+
+	for n := 1; n <= 17; n++ {
+		digest := sha256.Sum256([]byte(strconv.Itoa(n)))
+		digests = append(digests, digest[:])
+
+		root := MerkleRoot(digests)
+
+		for i := range digests {
+			proof, err := InclusionProof(digests, i)
+			require.NoError(t, err)
+
+			err = VerifyInclusion(root, digests[i], uint32(i), uint32(n), proof)
+			require.NoError(t, err, "leaf %d of %d", i, n)
+		}
+	}
+
+	_, err := InclusionProof(digests, len(digests))
+	require.EqualError(t, err, "index out of range: 17 not in [0, 17)")
+}
+
+func TestVerifyInclusion(t *testing.T) {
+	var digests [][]byte
+
+	for i := 0; i < 5; i++ {
+		digest := sha256.Sum256([]byte(strconv.Itoa(i)))
+		digests = append(digests, digest[:])
+	}
+
+	root := MerkleRoot(digests)
+
+	proof, err := InclusionProof(digests, 2)
+	require.NoError(t, err)
+
+	err = VerifyInclusion(root, digests[2], 5, 5, proof)
+	require.EqualError(t, err, "index out of range: 5 not in [0, 5)")
+
+	err = VerifyInclusion(root, digests[3], 2, 5, proof)
+	require.EqualError(t, err, "root mismatch")
+
+	err = VerifyInclusion(root, digests[2], 2, 5, proof[:1])
+	require.EqualError(t, err, "proof is too short")
+
+	err = VerifyInclusion(root, digests[2], 2, 5, append(proof, root))
+	require.EqualError(t, err, "proof is too long")
+}
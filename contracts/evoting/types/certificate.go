@@ -0,0 +1,152 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+
+	"go.dedis.ch/dela/cosi/threshold"
+	ttypes "go.dedis.ch/dela/cosi/threshold/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	sjson "go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+
+	// register the JSON format of the collective signatures
+	_ "go.dedis.ch/dela/cosi/threshold/json"
+)
+
+// ResultsDigest returns the canonical hash certified by the roster once the
+// results are available. It covers the form ID, the configuration, the last
+// shuffle through its hash, the public shares and the decrypted ballots through
+// the hashes of their batches. Points are hashed in their binary form so that
+// the digest does not depend on the serialization format of the form.
+func (form *Form) ResultsDigest() ([]byte, error) {
+	h := sha256.New()
+
+	formID, err := hex.DecodeString(form.FormID)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't decode formID: %v", err)
+	}
+
+	writeBytes(h, formID)
+
+	configuration, err := json.Marshal(form.Configuration)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal configuration: %v", err)
+	}
+
+	writeBytes(h, configuration)
+
+	var lastShuffle []byte
+	if len(form.ShuffleHashes) > 0 {
+		lastShuffle = form.ShuffleHashes[len(form.ShuffleHashes)-1]
+	}
+
+	writeBytes(h, lastShuffle)
+
+	writeUint32(h, uint32(len(form.PubsharesUnits.Pubshares)))
+
+	for i, unit := range form.PubsharesUnits.Pubshares {
+		if i < len(form.PubsharesUnits.Indexes) {
+			writeUint32(h, uint32(form.PubsharesUnits.Indexes[i]))
+		}
+
+		err = unit.Fingerprint(h)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to hash pubshares: %v", err)
+		}
+	}
+
+	writeUint32(h, form.ResultsCount)
+	writeUint32(h, uint32(len(form.ResultsHashes)))
+
+	for _, resultsHash := range form.ResultsHashes {
+		writeBytes(h, resultsHash)
+	}
+
+	return h.Sum(nil), nil
+}
+
+// VerifyCertificate checks that the certificate is a collective signature of
+// the digest by at least a Byzantine threshold of the roster. The certificate
+// is a serialized threshold signature over BLS, as produced by the nodes. Like
+// every dela message, it is always serialized in JSON, whatever the format of
+// the form.
+func VerifyCertificate(roster crypto.CollectiveAuthority,
+	digest []byte, certificate []byte) error {
+
+	if len(certificate) == 0 {
+		return xerrors.Errorf("certificate is empty")
+	}
+
+	if roster.Len() == 0 {
+		return xerrors.Errorf("roster is empty")
+	}
+
+	sigFac := ttypes.NewSignatureFactory(bls.NewSignatureFactory())
+
+	signature, err := sigFac.SignatureOf(sjson.NewContext(), certificate)
+	if err != nil {
+		return xerrors.Errorf("failed to deserialize certificate: %v", err)
+	}
+
+	indices := signature.(*ttypes.Signature).GetIndices()
+
+	for _, index := range indices {
+		if index >= roster.Len() {
+			return xerrors.Errorf("signer %d is not in the roster", index)
+		}
+	}
+
+	minSigners := threshold.ByzantineThreshold(roster.Len())
+	if len(indices) < minSigners {
+		return xerrors.Errorf("not enough signers: %d < %d", len(indices), minSigners)
+	}
+
+	// the BLS verifier factory is only exposed through the signer
+	verifierFac := ttypes.NewThresholdVerifierFactory(bls.Signer{}.GetVerifierFactory())
+
+	verifier, err := verifierFac.FromAuthority(roster)
+	if err != nil {
+		return xerrors.Errorf("failed to create verifier: %v", err)
+	}
+
+	err = verifier.Verify(digest, signature)
+	if err != nil {
+		return xerrors.Errorf("invalid certificate: %v", err)
+	}
+
+	return nil
+}
+
+// VerifyCertificate checks the certificate of the form against the public keys
+// of its roster and the digest of its results.
+func (form *Form) VerifyCertificate() error {
+	digest, err := form.ResultsDigest()
+	if err != nil {
+		return xerrors.Errorf("failed to compute digest: %v", err)
+	}
+
+	err = VerifyCertificate(form.Roster, digest, form.Certificate)
+	if err != nil {
+		return xerrors.Errorf("failed to verify certificate: %v", err)
+	}
+
+	return nil
+}
+
+// writeBytes writes a length-prefixed buffer so that two consecutive fields
+// cannot be confused.
+func writeBytes(h hash.Hash, buf []byte) {
+	writeUint32(h, uint32(len(buf)))
+	h.Write(buf)
+}
+
+func writeUint32(h hash.Hash, n uint32) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, n)
+	h.Write(buf)
+}
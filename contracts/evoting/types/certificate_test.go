@@ -0,0 +1,90 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	ttypes "go.dedis.ch/dela/cosi/threshold/types"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/mino"
+	sjson "go.dedis.ch/dela/serde/json"
+	"go.dedis.ch/dela/testing/fake"
+)
+
+func TestForm_ResultsDigest(t *testing.T) {
+	form := Form{FormID: "abcd"}
+
+	digest, err := form.ResultsDigest()
+	require.NoError(t, err)
+	require.Len(t, digest, 32)
+
+	form.ResultsHashes = [][]byte{{1}}
+
+	other, err := form.ResultsDigest()
+	require.NoError(t, err)
+	require.NotEqual(t, digest, other)
+
+	form.FormID = "not hex"
+
+	_, err = form.ResultsDigest()
+	require.ErrorContains(t, err, "couldn't decode formID")
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	signers := make([]crypto.AggregateSigner, 4)
+	addrs := make([]mino.Address, 4)
+	pubkeys := make([]crypto.PublicKey, 4)
+
+	for i := range signers {
+		signers[i] = bls.NewSigner()
+		addrs[i] = fake.NewAddress(i)
+		pubkeys[i] = signers[i].GetPublicKey()
+	}
+
+	form := Form{FormID: "abcd", Roster: authority.New(addrs, pubkeys)}
+
+	digest, err := form.ResultsDigest()
+	require.NoError(t, err)
+
+	// 3 out of 4 is the Byzantine threshold
+	form.Certificate = makeCertificate(t, digest, signers, 0, 1, 3)
+
+	err = form.VerifyCertificate()
+	require.NoError(t, err)
+
+	form.Certificate = makeCertificate(t, digest, signers, 0, 1)
+
+	err = form.VerifyCertificate()
+	require.EqualError(t, err, "failed to verify certificate: not enough signers: 2 < 3")
+
+	form.Certificate = makeCertificate(t, []byte("other"), signers, 0, 1, 2)
+
+	err = form.VerifyCertificate()
+	require.ErrorContains(t, err, "invalid certificate")
+
+	form.Certificate = nil
+
+	err = form.VerifyCertificate()
+	require.EqualError(t, err, "failed to verify certificate: certificate is empty")
+}
+
+func makeCertificate(t *testing.T, digest []byte, signers []crypto.AggregateSigner,
+	indices ...int) []byte {
+
+	signature := new(ttypes.Signature)
+
+	for _, index := range indices {
+		sig, err := signers[index].Sign(digest)
+		require.NoError(t, err)
+
+		err = signature.Merge(signers[index], index, sig)
+		require.NoError(t, err)
+	}
+
+	buf, err := signature.Serialize(sjson.NewContext())
+	require.NoError(t, err)
+
+	return buf
+}
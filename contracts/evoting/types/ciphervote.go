@@ -1,6 +1,7 @@
 package types
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"io"
 
@@ -66,6 +67,20 @@ func (c Ciphervote) FingerPrint(writer io.Writer) error {
 	return nil
 }
 
+// Digest returns the sha256 of the fingerprint of the ciphervote, that is of
+// the binary K and C of every pair. It identifies a cast ballot in the
+// BallotsTree of the form.
+func (c Ciphervote) Digest() ([]byte, error) {
+	h := sha256.New()
+
+	err := c.FingerPrint(h)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to fingerprint ciphervote: %v", err)
+	}
+
+	return h.Sum(nil), nil
+}
+
 // GetElGPairs returns corresponding kyber.Points from the ciphertexts
 func (c Ciphervote) GetElGPairs() (ks []kyber.Point, cs []kyber.Point) {
 	ks = make([]kyber.Point, len(c))
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
@@ -87,14 +88,33 @@ type Form struct {
 	// ballots.
 	BallotCount uint32
 
+	// VoterCount is the number of users who cast at least one ballot. The
+	// users themselves are in the voter index, see VotersBatch.
+	VoterCount uint32
+
 	// SuffragiaHashes holds a slice of hashes to all SuffragiaStoreKeys.
 	// In case a Form has also to be proven to be correct outside the nodes,
-	// the hashes are needed to prove the Suffragia are correct.
+	// the hashes are needed to prove the Suffragia are correct. They are
+	// updated on every cast vote with Suffragia.Hash and checked when the
+	// Suffragia are read.
 	SuffragiaHashes [][]byte
 
-	// ShuffleInstances is all the shuffles, along with their proof and identity
-	// of shuffler.
-	ShuffleInstances []ShuffleInstance
+	// BallotsTree is the Merkle tree over the digests of the ballots cast. Its
+	// root is set when the form is closed.
+	BallotsTree BallotsTree
+
+	// ShuffleStoreKeys holds the storage-keys of the shuffles, one per round,
+	// see ShuffleInstance. The shuffles are not kept in the form since each
+	// of them holds a copy of all the ballots.
+	ShuffleStoreKeys [][]byte
+
+	// ShuffleHashes holds the hashes of the shuffles, see
+	// ShuffleInstance.Hash.
+	ShuffleHashes [][]byte
+
+	// ShufflerPublicKeys holds the public key of the node who made each
+	// shuffle, so that a node can't shuffle twice.
+	ShufflerPublicKeys [][]byte
 
 	// ShuffleThreshold is set based on the roster. We save it so we do not have
 	// to compute it based on the roster each time we need it.
@@ -104,7 +124,26 @@ type Form struct {
 	// Each node submits its share to its personal index from the DKG service.
 	PubsharesUnits PubsharesUnits
 
-	DecryptedBallots []Ballot
+	// ResultsStoreKeys holds the storage-keys of the batches of decrypted
+	// ballots, see ResultsBatch. The ballots are not kept in the form so that
+	// reading it stays cheap once the results are available.
+	ResultsStoreKeys [][]byte
+
+	// ResultsHashes holds the sha256-hashes of the serialized batches stored
+	// at ResultsStoreKeys.
+	ResultsHashes [][]byte
+
+	// ResultsCount is the total number of decrypted ballots.
+	ResultsCount uint32
+
+	// ResultsPerBatch is the number of ballots in every batch but the last
+	// one.
+	ResultsPerBatch uint32
+
+	// Certificate is the collective signature of the roster over
+	// ResultsDigest, set once the results are available. See
+	// VerifyCertificate.
+	Certificate []byte
 
 	// roster is set when the form is created based on the current
 	// roster of the node stored in the global state. The roster will not change
@@ -118,6 +157,16 @@ type Form struct {
 
 	// Store the list of SCIPER of user that are Voters on the form.
 	Voters []int
+
+	// Imported is set when the form was copied from another chain with the
+	// IMPORT_FORM command. Its ballots, shuffles and results are the ones of
+	// the other chain.
+	Imported bool
+
+	// Legacy is set when the form is read from a record of version 0. It
+	// holds what such a form kept that is now stored outside of it, until
+	// MigrateLegacy moves it.
+	Legacy *LegacyForm
 }
 
 // Serialize implements serde.Message
@@ -181,7 +230,7 @@ func FormFromStore(ctx serde.Context, formFac serde.Factory, formIDHex string,
 		return form, xerrors.Errorf("while getting data for form: %v", err)
 	}
 	if len(formBuff) == 0 {
-		return form, xerrors.Errorf("no form found")
+		return form, NewRejection(RejectFormNotFound, "no form found")
 	}
 
 	message, err := formFac.Deserialize(ctx, formBuff)
@@ -213,17 +262,11 @@ func (form *Form) CastVote(ctx serde.Context, st store.Snapshot, userID string,
 	var batchID []byte
 
 	if form.BallotCount%BallotsPerBatch == 0 {
-		// Need to create a random ID for storing the ballots.
-		// H( formID | ballotcount )
-		// should be random enough, even if it's previsible.
-		id, err := hex.DecodeString(form.FormID)
+		var err error
+		batchID, err = form.SuffragiaBatchKey(len(form.SuffragiaStoreKeys))
 		if err != nil {
-			return xerrors.Errorf("couldn't decode formID: %v", err)
+			return xerrors.Errorf("couldn't get ballots batch key: %v", err)
 		}
-		h := sha256.New()
-		h.Write(id)
-		binary.LittleEndian.PutUint32(id, form.BallotCount)
-		batchID = h.Sum(id[0:4])[:32]
 
 		err = st.Set(batchID, []byte{})
 		if err != nil {
@@ -246,50 +289,155 @@ func (form *Form) CastVote(ctx serde.Context, st store.Snapshot, userID string,
 		suff = msg.(Suffragia)
 	}
 
+	digest, err := ciphervote.Digest()
+	if err != nil {
+		return xerrors.Errorf("couldn't get ballot digest: %v", err)
+	}
+	casts := uint32(1)
+	err = form.addVoter(ctx, st, userID)
+	if err != nil {
+		return xerrors.Errorf("couldn't add voter: %v", err)
+	}
 	suff.CastVote(userID, ciphervote)
 	if TestCastBallots {
 		for i := uint32(1); i < BallotsPerBatch; i++ {
-			suff.CastVote(fmt.Sprintf("%s-%d", userID, i), ciphervote)
+			voterID := fmt.Sprintf("%s-%d", userID, i)
+			err = form.addVoter(ctx, st, voterID)
+			if err != nil {
+				return xerrors.Errorf("couldn't add voter: %v", err)
+			}
+			suff.CastVote(voterID, ciphervote)
 		}
+		casts = BallotsPerBatch
 		form.BallotCount += BallotsPerBatch - 1
 	}
+	for i := uint32(0); i < casts; i++ {
+		suff.Digests = append(suff.Digests, digest)
+		err = form.BallotsTree.Append(digest)
+		if err != nil {
+			return xerrors.Errorf("couldn't add ballot to the tree: %v", err)
+		}
+	}
 	buf, err := suff.Serialize(ctx)
 	if err != nil {
 		return xerrors.Errorf("couldn't marshal ballots batch: %v", err)
 	}
 	err = st.Set(batchID, buf)
 	if err != nil {
-		xerrors.Errorf("couldn't set new ballots batch: %v", err)
+		return xerrors.Errorf("couldn't set new ballots batch: %v", err)
+	}
+	hash, err := suff.Hash(ctx)
+	if err != nil {
+		return xerrors.Errorf("couldn't hash ballots batch: %v", err)
 	}
+	form.SuffragiaHashes[len(form.SuffragiaHashes)-1] = hash
 	form.BallotCount += 1
 	return nil
 }
 
+// SuffragiaBatchKey returns the store key of the i-th batch of ballots, which
+// is ( ballotcount | H( formID ) ) truncated to 32 bytes, with the number of
+// ballots cast before the batch. It should be random enough, even if it's
+// previsible.
+func (form *Form) SuffragiaBatchKey(i int) ([]byte, error) {
+	id, err := hex.DecodeString(form.FormID)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't decode formID: %v", err)
+	}
+
+	h := sha256.New()
+	h.Write(id)
+
+	count := make([]byte, 4)
+	binary.LittleEndian.PutUint32(count, uint32(i)*BallotsPerBatch)
+
+	return h.Sum(count)[:32], nil
+}
+
 // Suffragia returns all ballots from the storage. This should only
 // be called rarely, as it might take a long time.
 // It overwrites ballots cast by the same user and keeps only
-// the latest ballot.
+// the latest ballot. The digests of all the ballots cast are kept.
 func (form *Form) Suffragia(ctx serde.Context, rd store.Readable) (Suffragia, error) {
 	var suff Suffragia
-	for _, id := range form.SuffragiaStoreKeys {
-		buf, err := rd.Get(id)
-		if err != nil {
-			return suff, xerrors.Errorf("couldn't get ballot batch: %v", err)
-		}
-		format := suffragiaFormat.Get(ctx.GetFormat())
-		ctx = serde.WithFactory(ctx, CiphervoteKey{}, CiphervoteFactory{})
-		msg, err := format.Decode(ctx, buf)
+	if len(form.SuffragiaHashes) != len(form.SuffragiaStoreKeys) {
+		return suff, xerrors.Errorf("%d hashes for %d ballots batches",
+			len(form.SuffragiaHashes), len(form.SuffragiaStoreKeys))
+	}
+	positions := make(map[string]int)
+	for i := range form.SuffragiaStoreKeys {
+		suffTmp, err := form.suffragiaBatch(ctx, rd, i)
 		if err != nil {
-			return suff, xerrors.Errorf("couldn't unmarshal ballots batch in cast: %v", err)
+			return suff, err
 		}
-		suffTmp := msg.(Suffragia)
-		for i, uid := range suffTmp.VoterIDs {
-			suff.CastVote(uid, suffTmp.Ciphervotes[i])
+		for j, uid := range suffTmp.VoterIDs {
+			position, found := positions[uid]
+			if found {
+				suff.Ciphervotes[position] = suffTmp.Ciphervotes[j]
+				continue
+			}
+			positions[uid] = len(suff.VoterIDs)
+			suff.VoterIDs = append(suff.VoterIDs, uid)
+			suff.Ciphervotes = append(suff.Ciphervotes, suffTmp.Ciphervotes[j])
 		}
+		suff.Digests = append(suff.Digests, suffTmp.Digests...)
+	}
+	return suff, nil
+}
+
+// BallotDigests returns the digests of all the ballots cast, in order, which
+// are the leaves of the BallotsTree. It checks that they match the root of the
+// tree.
+func (form *Form) BallotDigests(ctx serde.Context, rd store.Readable) ([][]byte, error) {
+	suff, err := form.Suffragia(ctx, rd)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't get ballots: %v", err)
+	}
+	if len(suff.Digests) != int(form.BallotsTree.Size) {
+		return nil, xerrors.Errorf("%d ballots digests for a tree of size %d",
+			len(suff.Digests), form.BallotsTree.Size)
+	}
+	if !bytes.Equal(MerkleRoot(suff.Digests), form.BallotsTree.CurrentRoot()) {
+		return nil, xerrors.Errorf("ballots digests don't match the root of the tree")
+	}
+	return suff.Digests, nil
+}
+
+// suffragiaBatch reads the i-th batch of ballots and checks its hash. The
+// batches of a form of version 0 are not hashed until it is migrated.
+func (form *Form) suffragiaBatch(ctx serde.Context, rd store.Readable, i int) (Suffragia, error) {
+	suff, err := form.readSuffragiaBatch(ctx, rd, i)
+	if err != nil {
+		return Suffragia{}, err
+	}
+	if form.Legacy != nil {
+		return suff, nil
+	}
+	hash, err := suff.Hash(ctx)
+	if err != nil {
+		return Suffragia{}, xerrors.Errorf("couldn't hash ballots batch: %v", err)
+	}
+	if !bytes.Equal(hash, form.SuffragiaHashes[i]) {
+		return Suffragia{}, xerrors.Errorf("hash of ballots batch %d doesn't match", i)
 	}
 	return suff, nil
 }
 
+// readSuffragiaBatch reads the i-th batch of ballots without checking it.
+func (form *Form) readSuffragiaBatch(ctx serde.Context, rd store.Readable, i int) (Suffragia, error) {
+	buf, err := rd.Get(form.SuffragiaStoreKeys[i])
+	if err != nil {
+		return Suffragia{}, xerrors.Errorf("couldn't get ballot batch: %v", err)
+	}
+	format := suffragiaFormat.Get(ctx.GetFormat())
+	ctx = serde.WithFactory(ctx, CiphervoteKey{}, CiphervoteFactory{})
+	msg, err := format.Decode(ctx, buf)
+	if err != nil {
+		return Suffragia{}, xerrors.Errorf("couldn't unmarshal ballots batch in cast: %v", err)
+	}
+	return msg.(Suffragia), nil
+}
+
 // RandomVector is a slice of kyber.Scalar (encoded) which is used to prove
 // and verify the proof of a shuffle
 type RandomVector [][]byte
@@ -326,24 +474,19 @@ func (randomVector *RandomVector) LoadFromScalars(e []kyber.Scalar) error {
 	return nil
 }
 
-// ShuffleInstance is an instance of a shuffle, it contains the shuffled
-// ballots, the proofs and the identity of the shuffler.
-type ShuffleInstance struct {
-	// ShuffledBallots contains the list of shuffled ciphertext for this round
-	ShuffledBallots []Ciphervote
-
-	// ShuffleProofs is the proof of the shuffle for this round
-	ShuffleProofs []byte
-
-	// ShufflerPublicKey is the key of the node who made the given shuffle.
-	ShufflerPublicKey []byte
-}
-
 // Configuration contains the configuration of a new poll.
 type Configuration struct {
 	Title          Title
 	Scaffold       []Subject
 	AdditionalInfo string
+	// SignedBallots is set when the voters register a public key on the form
+	// and sign their ballots with it, see RegisterVoterKey. The identity of a
+	// voter is then not only asserted by the web backend.
+	SignedBallots bool `json:",omitempty"`
+	// VoterKeysAuthority is the binary Ed25519 public key of the authority
+	// that endorses the keys of the voters, required with SignedBallots. Its
+	// secret must not be held by the web backend.
+	VoterKeysAuthority []byte `json:",omitempty"`
 }
 
 // MaxBallotSize returns the maximum number of bytes required to store a ballot
@@ -369,6 +512,14 @@ func (configuration *Configuration) GetQuestion(ID ID) Question {
 	return nil
 }
 
+// ForEachQuestion calls fn on every question of the configuration, in the
+// display order defined by the subjects.
+func (configuration *Configuration) ForEachQuestion(fn func(ID, Question)) {
+	for _, subject := range configuration.Scaffold {
+		subject.forEachQuestion(fn)
+	}
+}
+
 // IsValid returns true if and only if the whole configuration is coherent and
 // valid.
 func (configuration *Configuration) IsValid() bool {
@@ -381,6 +532,13 @@ func (configuration *Configuration) IsValid() bool {
 		}
 	}
 
+	if configuration.SignedBallots {
+		err := suite.Point().UnmarshalBinary(configuration.VoterKeysAuthority)
+		if err != nil {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -510,6 +668,59 @@ func (form *Form) RemoveOwner(userID string) error {
 	return nil
 }
 
+// DeleteStore deletes what the form keeps in the store next to it: the
+// batches of ballots, the voter index, the voter keys and nonces, the shuffles
+// and the results, with the keys and the counts the form records. The keys of
+// the voters are the ones of the voter index and of the voters of the form.
+// The summary is deleted with the indexes of the form.
+func (form *Form) DeleteStore(ctx serde.Context, st store.Snapshot) error {
+	voterIDs, err := form.VoterIDs(ctx, st, 0, 0)
+	if err != nil {
+		return xerrors.Errorf("couldn't get voters: %v", err)
+	}
+
+	for _, voter := range form.Voters {
+		voterIDs = append(voterIDs, strconv.Itoa(voter))
+	}
+
+	keys := [][]byte{}
+
+	for _, voterID := range voterIDs {
+		for _, key := range []func(string) ([]byte, error){
+			form.voterKey, form.voterKeyKey, form.voteNonceKey,
+		} {
+			k, err := key(voterID)
+			if err != nil {
+				return xerrors.Errorf("couldn't get voter key: %v", err)
+			}
+
+			keys = append(keys, k)
+		}
+	}
+
+	for i := uint32(0); i*BallotsPerBatch < form.VoterCount; i++ {
+		key, err := form.votersBatchKey(i)
+		if err != nil {
+			return xerrors.Errorf("couldn't get voters batch key: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	keys = append(keys, form.SuffragiaStoreKeys...)
+	keys = append(keys, form.ShuffleStoreKeys...)
+	keys = append(keys, form.ResultsStoreKeys...)
+
+	for _, key := range keys {
+		err = st.Delete(key)
+		if err != nil {
+			return xerrors.Errorf("couldn't delete key %x: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
 func SciperToInt(userID string) (int, error) {
 	sciperInt, err := strconv.Atoi(userID)
 	if err != nil {
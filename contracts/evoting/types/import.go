@@ -0,0 +1,162 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// BatchKeys returns the store keys of the batches of ballots, of the shuffles
+// and of the decrypted ballots referenced by the form, in this order.
+func (form *Form) BatchKeys() [][]byte {
+	keys := append([][]byte{}, form.SuffragiaStoreKeys...)
+	keys = append(keys, form.ShuffleStoreKeys...)
+	keys = append(keys, form.ResultsStoreKeys...)
+
+	return keys
+}
+
+// ImportBatches stores the batches of a form copied from another chain, in the
+// order of BatchKeys, and checks them against the hashes and the tree of
+// ballots kept by the form. The keys of the form are checked against the ones
+// computed from its ID before anything is stored, so that an imported form
+// can't overwrite the keys of another one. The voter index is built again from
+// the ballots.
+func (form *Form) ImportBatches(ctx serde.Context, st store.Snapshot, batches [][]byte) error {
+	keys := form.BatchKeys()
+
+	if len(batches) != len(keys) {
+		return xerrors.Errorf("%d batches for %d keys", len(batches), len(keys))
+	}
+
+	expected, err := form.expectedBatchKeys()
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if !bytes.Equal(key, expected[i]) {
+			return xerrors.Errorf("batch key %d doesn't match the form", i)
+		}
+	}
+
+	for i, key := range keys {
+		err := st.Set(key, batches[i])
+		if err != nil {
+			return xerrors.Errorf("couldn't store batch: %v", err)
+		}
+	}
+
+	suff, err := form.Suffragia(ctx, st)
+	if err != nil {
+		return xerrors.Errorf("invalid ballots: %v", err)
+	}
+
+	if form.BallotsTree.Size > 0 {
+		_, err = form.BallotDigests(ctx, st)
+		if err != nil {
+			return xerrors.Errorf("invalid ballots: %v", err)
+		}
+	}
+
+	for round := 0; round < form.ShuffleCount(); round++ {
+		_, err = form.Shuffle(ctx, st, round)
+		if err != nil {
+			return xerrors.Errorf("invalid shuffle: %v", err)
+		}
+	}
+
+	err = form.checkResults(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	err = form.indexVoters(ctx, st, suff.VoterIDs)
+	if err != nil {
+		return xerrors.Errorf("couldn't index voters: %v", err)
+	}
+
+	return nil
+}
+
+// expectedBatchKeys returns the keys that BatchKeys must return, computed from
+// the ID of the form and the number of batches.
+func (form *Form) expectedBatchKeys() ([][]byte, error) {
+	keys := make([][]byte, 0, len(form.BatchKeys()))
+
+	for i := range form.SuffragiaStoreKeys {
+		key, err := form.SuffragiaBatchKey(i)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't get ballots batch key: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	for round := range form.ShuffleStoreKeys {
+		key, err := form.shuffleKey(round)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't get shuffle key: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	for i := range form.ResultsStoreKeys {
+		key, err := form.resultsBatchKey(i)
+		if err != nil {
+			return nil, xerrors.Errorf("couldn't get results batch key: %v", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// checkResults checks the hash of every batch of results and that the batches
+// hold ResultsCount ballots, ResultsPerBatch in each but the last one.
+func (form *Form) checkResults(ctx serde.Context, rd store.Readable) error {
+	if len(form.ResultsHashes) != len(form.ResultsStoreKeys) {
+		return xerrors.Errorf("%d hashes for %d results batches",
+			len(form.ResultsHashes), len(form.ResultsStoreKeys))
+	}
+
+	count := 0
+
+	for i, key := range form.ResultsStoreKeys {
+		buf, err := rd.Get(key)
+		if err != nil {
+			return xerrors.Errorf("couldn't get results batch: %v", err)
+		}
+
+		hash := sha256.Sum256(buf)
+		if !bytes.Equal(hash[:], form.ResultsHashes[i]) {
+			return xerrors.Errorf("hash of results batch %d doesn't match", i)
+		}
+
+		batch, err := form.resultsBatch(ctx, rd, i)
+		if err != nil {
+			return err
+		}
+
+		last := i == len(form.ResultsStoreKeys)-1
+
+		if len(batch.Ballots) == 0 || len(batch.Ballots) > int(form.ResultsPerBatch) ||
+			!last && len(batch.Ballots) != int(form.ResultsPerBatch) {
+			return xerrors.Errorf("%d ballots in results batch %d of size %d",
+				len(batch.Ballots), i, form.ResultsPerBatch)
+		}
+
+		count += len(batch.Ballots)
+	}
+
+	if count != int(form.ResultsCount) {
+		return xerrors.Errorf("%d results for a count of %d", count, form.ResultsCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// LegacyForm holds the parts of a form of version 0 that are stored outside of
+// the form since version 1. The form can be read as it is, but it must be
+// migrated with MigrateLegacy before it is updated.
+type LegacyForm struct {
+	// ShuffleInstances are the shuffles, which were kept in the form.
+	ShuffleInstances []ShuffleInstance
+
+	// DecryptedBallots are the results, which were kept in the form.
+	DecryptedBallots []Ballot
+}
+
+// MigrateLegacy moves the parts of a form of version 0 to the layout of the
+// current version: the digests of the ballots are added to their batches and
+// to the BallotsTree, the batches are hashed, the voter index is built, the
+// shuffles are stored with StoreShuffle and the results with StoreResults. It
+// does nothing if the form is not of version 0. The keys it writes only depend
+// on the form, so that it can be run again on the same form if it isn't stored
+// afterwards.
+//
+// A form of version 0 replaced the ballot of a voter who voted again in the
+// same batch, so only the last ballot of such a voter is in the tree and
+// BallotCount becomes the number of ballots stored.
+func (form *Form) MigrateLegacy(ctx serde.Context, st store.Snapshot) error {
+	legacy := form.Legacy
+	if legacy == nil {
+		return nil
+	}
+
+	// the form is read and stored with the current layout from now on
+	form.Legacy = nil
+
+	if len(form.SuffragiaHashes) != len(form.SuffragiaStoreKeys) {
+		return xerrors.Errorf("%d hashes for %d ballots batches",
+			len(form.SuffragiaHashes), len(form.SuffragiaStoreKeys))
+	}
+
+	form.BallotsTree = BallotsTree{}
+	voterIDs := []string{}
+	voted := make(map[string]bool)
+
+	// the forms of version 0 didn't keep the digests of the ballots nor hash
+	// their batches
+	for i, key := range form.SuffragiaStoreKeys {
+		suff, err := form.readSuffragiaBatch(ctx, st, i)
+		if err != nil {
+			return err
+		}
+
+		err = suff.addDigests(ctx, st, key)
+		if err != nil {
+			return xerrors.Errorf("couldn't add digests to ballots batch %d: %v", i, err)
+		}
+
+		for _, digest := range suff.Digests {
+			err = form.BallotsTree.Append(digest)
+			if err != nil {
+				return xerrors.Errorf("couldn't add ballot to the tree: %v", err)
+			}
+		}
+
+		form.SuffragiaHashes[i], err = suff.Hash(ctx)
+		if err != nil {
+			return xerrors.Errorf("couldn't hash ballots batch: %v", err)
+		}
+
+		for _, voterID := range suff.VoterIDs {
+			if !voted[voterID] {
+				voted[voterID] = true
+				voterIDs = append(voterIDs, voterID)
+			}
+		}
+	}
+
+	form.BallotCount = form.BallotsTree.Size
+
+	if form.Status >= Closed && form.Status != Canceled {
+		form.BallotsTree.Close()
+	}
+
+	err := form.indexVoters(ctx, st, voterIDs)
+	if err != nil {
+		return xerrors.Errorf("couldn't index voters: %v", err)
+	}
+
+	for _, shuffle := range legacy.ShuffleInstances {
+		err := form.StoreShuffle(ctx, st, shuffle)
+		if err != nil {
+			return xerrors.Errorf("couldn't move shuffle: %v", err)
+		}
+	}
+
+	if form.Status == ResultAvailable {
+		err := form.StoreResults(ctx, st, legacy.DecryptedBallots)
+		if err != nil {
+			return xerrors.Errorf("couldn't move results: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// addDigests sets the digests of a batch of ballots of version 0, which didn't
+// have any, and stores the batch at the given key. A batch that already has
+// them is left as it is.
+func (s *Suffragia) addDigests(ctx serde.Context, st store.Snapshot, key []byte) error {
+	if len(s.Digests) > 0 {
+		return nil
+	}
+
+	for _, ciphervote := range s.Ciphervotes {
+		digest, err := ciphervote.Digest()
+		if err != nil {
+			return xerrors.Errorf("couldn't get ballot digest: %v", err)
+		}
+
+		s.Digests = append(s.Digests, digest)
+	}
+
+	buf, err := s.Serialize(ctx)
+	if err != nil {
+		return xerrors.Errorf("couldn't marshal ballots batch: %v", err)
+	}
+
+	err = st.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("couldn't store ballots batch: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+// resultsFormat contains the supported formats for the batches of decrypted
+// ballots. Right now only JSON is supported.
+var resultsFormat = registry.NewSimpleRegistry()
+
+// RegisterResultsFormat registers the engine for the provided format
+func RegisterResultsFormat(format serde.Format, engine serde.FormatEngine) {
+	resultsFormat.Register(format, engine)
+}
+
+// ResultsBatch is a batch of at most BallotsPerBatch decrypted ballots. The
+// batches are stored outside of the form, so that reading a form does not
+// deserialize all its results.
+//
+// - implements serde.Message
+type ResultsBatch struct {
+	Ballots []Ballot
+}
+
+// Serialize implements serde.Message
+func (r ResultsBatch) Serialize(ctx serde.Context) ([]byte, error) {
+	format := resultsFormat.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode results batch: %v", err)
+	}
+
+	return data, nil
+}
+
+// StoreResults splits the decrypted ballots in batches and stores them. The
+// form keeps the keys and the hashes of the batches. The batch i is stored at
+// H( formID | "results" | i ).
+func (form *Form) StoreResults(ctx serde.Context, st store.Snapshot, ballots []Ballot) error {
+	form.ResultsStoreKeys = [][]byte{}
+	form.ResultsHashes = [][]byte{}
+	form.ResultsCount = uint32(len(ballots))
+	form.ResultsPerBatch = BallotsPerBatch
+
+	for start := 0; start < len(ballots); start += int(BallotsPerBatch) {
+		end := start + int(BallotsPerBatch)
+		if end > len(ballots) {
+			end = len(ballots)
+		}
+
+		batchID, err := form.resultsBatchKey(len(form.ResultsStoreKeys))
+		if err != nil {
+			return xerrors.Errorf("couldn't get results batch key: %v", err)
+		}
+
+		buf, err := ResultsBatch{Ballots: ballots[start:end]}.Serialize(ctx)
+		if err != nil {
+			return xerrors.Errorf("couldn't marshal results batch: %v", err)
+		}
+
+		err = st.Set(batchID, buf)
+		if err != nil {
+			return xerrors.Errorf("couldn't store results batch: %v", err)
+		}
+
+		hash := sha256.Sum256(buf)
+
+		form.ResultsStoreKeys = append(form.ResultsStoreKeys, batchID)
+		form.ResultsHashes = append(form.ResultsHashes, hash[:])
+	}
+
+	return nil
+}
+
+// Results returns the decrypted ballots from offset, at most limit of them. A
+// limit of 0 or less returns all the ballots from offset. Only the batches
+// containing the requested ballots are read.
+func (form *Form) Results(ctx serde.Context, rd store.Readable, offset, limit int) ([]Ballot, error) {
+	total := int(form.ResultsCount)
+	if form.Legacy != nil {
+		total = len(form.Legacy.DecryptedBallots)
+	}
+
+	if offset < 0 {
+		return nil, xerrors.Errorf("invalid offset: %d", offset)
+	}
+
+	if offset >= total {
+		return []Ballot{}, nil
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	// the results of a form of version 0 are in the form
+	if form.Legacy != nil {
+		return form.Legacy.DecryptedBallots[offset:end], nil
+	}
+
+	perBatch := int(form.ResultsPerBatch)
+	if perBatch == 0 {
+		return nil, xerrors.Errorf("invalid results batch size: 0")
+	}
+
+	ballots := make([]Ballot, 0, end-offset)
+
+	for i := offset / perBatch; i*perBatch < end; i++ {
+		if i >= len(form.ResultsStoreKeys) {
+			return nil, xerrors.Errorf("missing results batch %d", i)
+		}
+
+		batch, err := form.resultsBatch(ctx, rd, i)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, ballot := range batch.Ballots {
+			position := i*perBatch + j
+			if position >= offset && position < end {
+				ballots = append(ballots, ballot)
+			}
+		}
+	}
+
+	return ballots, nil
+}
+
+func (form *Form) resultsBatch(ctx serde.Context, rd store.Readable, i int) (ResultsBatch, error) {
+	buf, err := rd.Get(form.ResultsStoreKeys[i])
+	if err != nil {
+		return ResultsBatch{}, xerrors.Errorf("couldn't get results batch: %v", err)
+	}
+
+	msg, err := resultsFormat.Get(ctx.GetFormat()).Decode(ctx, buf)
+	if err != nil {
+		return ResultsBatch{}, xerrors.Errorf("couldn't unmarshal results batch: %v", err)
+	}
+
+	batch, ok := msg.(ResultsBatch)
+	if !ok {
+		return ResultsBatch{}, xerrors.Errorf("wrong message type: %T", msg)
+	}
+
+	return batch, nil
+}
+
+// resultsBatchKey returns the store key of the i-th batch of results.
+func (form *Form) resultsBatchKey(i int) ([]byte, error) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(i))
+
+	return form.storeKey("results", buf)
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// SchemaVersion is the version of the layout of the forms, the admin list and
+// the batches of ballots in the store. The formats stamp it in every record
+// they write and migrate the records of an older version when they read them,
+// so that a record is upgraded the next time it is stored.
+//
+// Records written before the layout was versioned are of version 0. Version 2
+// adds Imported to the forms.
+const SchemaVersion = 2
+
+// RewriteSuffragia stores again every batch of ballots of the form, so that
+// they are written at the current schema version. The hashes of the batches
+// don't depend on the version and are left unchanged.
+func (form *Form) RewriteSuffragia(ctx serde.Context, st store.Snapshot) error {
+	if len(form.SuffragiaHashes) != len(form.SuffragiaStoreKeys) {
+		return xerrors.Errorf("%d hashes for %d ballots batches",
+			len(form.SuffragiaHashes), len(form.SuffragiaStoreKeys))
+	}
+
+	for i, key := range form.SuffragiaStoreKeys {
+		suff, err := form.suffragiaBatch(ctx, st, i)
+		if err != nil {
+			return err
+		}
+
+		buf, err := suff.Serialize(ctx)
+		if err != nil {
+			return xerrors.Errorf("couldn't marshal ballots batch: %v", err)
+		}
+
+		err = st.Set(key, buf)
+		if err != nil {
+			return xerrors.Errorf("couldn't set ballots batch: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+// shuffleFormat contains the supported formats for the shuffles. Right now
+// only JSON is supported.
+var shuffleFormat = registry.NewSimpleRegistry()
+
+// RegisterShuffleFormat registers the engine for the provided format
+func RegisterShuffleFormat(format serde.Format, engine serde.FormatEngine) {
+	shuffleFormat.Register(format, engine)
+}
+
+// ShuffleInstance is an instance of a shuffle, it contains the shuffled
+// ballots, the proofs and the identity of the shuffler. The round i of a form
+// is stored at H( formID | "shuffle" | i ), outside of the form.
+//
+// - implements serde.Message
+type ShuffleInstance struct {
+	// ShuffledBallots contains the list of shuffled ciphertext for this round
+	ShuffledBallots []Ciphervote
+
+	// ShuffleProofs is the proof of the shuffle for this round
+	ShuffleProofs []byte
+
+	// ShufflerPublicKey is the key of the node who made the given shuffle.
+	ShufflerPublicKey []byte
+}
+
+// Serialize implements serde.Message
+func (s ShuffleInstance) Serialize(ctx serde.Context) ([]byte, error) {
+	format := shuffleFormat.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, s)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode shuffle: %v", err)
+	}
+
+	return data, nil
+}
+
+// Hash returns the hash of the shuffle. Points are hashed in their binary form
+// so that the hash does not depend on the serialization format.
+func (s ShuffleInstance) Hash() ([]byte, error) {
+	h := sha256.New()
+
+	writeUint32(h, uint32(len(s.ShuffledBallots)))
+
+	for _, ciphervote := range s.ShuffledBallots {
+		err := ciphervote.FingerPrint(h)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to hash shuffled ballot: %v", err)
+		}
+	}
+
+	writeBytes(h, s.ShuffleProofs)
+	writeBytes(h, s.ShufflerPublicKey)
+
+	return h.Sum(nil), nil
+}
+
+// ShuffleCount returns the number of shuffles made on the ballots.
+func (form *Form) ShuffleCount() int {
+	if form.Legacy != nil {
+		return len(form.Legacy.ShuffleInstances)
+	}
+
+	return len(form.ShuffleStoreKeys)
+}
+
+// StoreShuffle stores the shuffle as the next round. The form keeps its key,
+// its hash and the public key of the shuffler.
+func (form *Form) StoreShuffle(ctx serde.Context, st store.Snapshot, shuffle ShuffleInstance) error {
+	key, err := form.shuffleKey(form.ShuffleCount())
+	if err != nil {
+		return xerrors.Errorf("couldn't get shuffle key: %v", err)
+	}
+
+	buf, err := shuffle.Serialize(ctx)
+	if err != nil {
+		return xerrors.Errorf("couldn't marshal shuffle: %v", err)
+	}
+
+	hash, err := shuffle.Hash()
+	if err != nil {
+		return xerrors.Errorf("couldn't hash shuffle: %v", err)
+	}
+
+	err = st.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("couldn't store shuffle: %v", err)
+	}
+
+	form.ShuffleStoreKeys = append(form.ShuffleStoreKeys, key)
+	form.ShuffleHashes = append(form.ShuffleHashes, hash)
+	form.ShufflerPublicKeys = append(form.ShufflerPublicKeys, shuffle.ShufflerPublicKey)
+
+	return nil
+}
+
+// shuffleKey returns the store key of the shuffle of the given round.
+func (form *Form) shuffleKey(round int) ([]byte, error) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(round))
+
+	return form.storeKey("shuffle", buf)
+}
+
+// Shuffle returns the shuffle of the given round and checks its hash.
+func (form *Form) Shuffle(ctx serde.Context, rd store.Readable, round int) (ShuffleInstance, error) {
+	if round < 0 || round >= form.ShuffleCount() {
+		return ShuffleInstance{}, xerrors.Errorf("no shuffle for round %d", round)
+	}
+
+	// the shuffles of a form of version 0 are in the form
+	if form.Legacy != nil {
+		return form.Legacy.ShuffleInstances[round], nil
+	}
+
+	if len(form.ShuffleHashes) != form.ShuffleCount() {
+		return ShuffleInstance{}, xerrors.Errorf("%d hashes for %d shuffles",
+			len(form.ShuffleHashes), form.ShuffleCount())
+	}
+
+	buf, err := rd.Get(form.ShuffleStoreKeys[round])
+	if err != nil {
+		return ShuffleInstance{}, xerrors.Errorf("couldn't get shuffle: %v", err)
+	}
+
+	format := shuffleFormat.Get(ctx.GetFormat())
+	ctx = serde.WithFactory(ctx, CiphervoteKey{}, CiphervoteFactory{})
+
+	msg, err := format.Decode(ctx, buf)
+	if err != nil {
+		return ShuffleInstance{}, xerrors.Errorf("couldn't unmarshal shuffle: %v", err)
+	}
+
+	shuffle, ok := msg.(ShuffleInstance)
+	if !ok {
+		return ShuffleInstance{}, xerrors.Errorf("wrong message type: %T", msg)
+	}
+
+	hash, err := shuffle.Hash()
+	if err != nil {
+		return ShuffleInstance{}, xerrors.Errorf("couldn't hash shuffle: %v", err)
+	}
+
+	if !bytes.Equal(hash, form.ShuffleHashes[round]) {
+		return ShuffleInstance{}, xerrors.Errorf("hash of shuffle %d doesn't match", round)
+	}
+
+	return shuffle, nil
+}
+
+// LastShuffle returns the shuffle of the last round.
+func (form *Form) LastShuffle(ctx serde.Context, rd store.Readable) (ShuffleInstance, error) {
+	if form.ShuffleCount() == 0 {
+		return ShuffleInstance{}, xerrors.Errorf("form has no shuffles")
+	}
+
+	return form.Shuffle(ctx, rd, form.ShuffleCount()-1)
+}
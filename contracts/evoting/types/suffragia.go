@@ -21,6 +21,11 @@ func RegisterSuffragiaFormat(format serde.Format, engine serde.FormatEngine) {
 type Suffragia struct {
 	VoterIDs    []string
 	Ciphervotes []Ciphervote
+
+	// Digests are the digests of all the ballots cast in this batch, in
+	// order, including the ones that have been replaced. They are the leaves
+	// of the BallotsTree of the form.
+	Digests [][]byte
 }
 
 // Serialize implements the serde.Message
@@ -48,16 +53,23 @@ func (s *Suffragia) CastVote(voterID string, ciphervote Ciphervote) {
 	s.Ciphervotes = append(s.Ciphervotes, ciphervote.Copy())
 }
 
-// Hash returns the hash of this list of ballots.
+// Hash returns the hash of this list of ballots. The lists and their elements
+// are prefixed with their length, so that two different lists can't have the
+// same hash.
 func (s *Suffragia) Hash(ctx serde.Context) ([]byte, error) {
 	h := sha256.New()
+	writeUint32(h, uint32(len(s.VoterIDs)))
 	for i, u := range s.VoterIDs {
-		h.Write([]byte(u))
+		writeBytes(h, []byte(u))
 		buf, err := s.Ciphervotes[i].Serialize(ctx)
 		if err != nil {
 			return nil, xerrors.Errorf("couldn't serialize ciphervote: %v", err)
 		}
-		h.Write(buf)
+		writeBytes(h, buf)
+	}
+	writeUint32(h, uint32(len(s.Digests)))
+	for _, digest := range s.Digests {
+		writeBytes(h, digest)
 	}
 	return h.Sum(nil), nil
 }
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sjson "go.dedis.ch/dela/serde/json"
+)
+
+func TestSuffragia_Hash(t *testing.T) {
+	ctx := sjson.NewContext()
+
+	one := Suffragia{Digests: [][]byte{[]byte("ab")}}
+	two := Suffragia{Digests: [][]byte{[]byte("a"), []byte("b")}}
+
+	hashOne, err := one.Hash(ctx)
+	require.NoError(t, err)
+
+	hashTwo, err := two.Hash(ctx)
+	require.NoError(t, err)
+
+	// the digests are not simply concatenated
+	require.NotEqual(t, hashOne, hashTwo)
+
+	hashEmpty, err := (&Suffragia{}).Hash(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, hashEmpty, hashOne)
+}
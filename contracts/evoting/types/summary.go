@@ -0,0 +1,119 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"strconv"
+
+	"go.dedis.ch/dela/core/store"
+	"golang.org/x/xerrors"
+)
+
+// FormSummary is the light version of a form maintained by the smart contract
+// next to the form, so that listing the forms does not deserialize them. It is
+// stored in JSON at H( formID | "summary" ).
+type FormSummary struct {
+	FormID string
+	Title  Title
+	Status Status
+	Pubkey []byte
+	Owners []int
+}
+
+// NewFormSummary returns the summary of the form.
+func NewFormSummary(form Form) (FormSummary, error) {
+	summary := FormSummary{
+		FormID: form.FormID,
+		Title:  form.Configuration.Title,
+		Status: form.Status,
+		Owners: append([]int{}, form.Owners...),
+	}
+
+	if form.Pubkey != nil {
+		pubkey, err := form.Pubkey.MarshalBinary()
+		if err != nil {
+			return summary, xerrors.Errorf("failed to marshal pubkey: %v", err)
+		}
+
+		summary.Pubkey = pubkey
+	}
+
+	return summary, nil
+}
+
+// FormSummaryKey returns the store key of the summary of a form.
+func FormSummaryKey(formID string) ([]byte, error) {
+	form := Form{FormID: formID}
+	return form.storeKey("summary", nil)
+}
+
+// FormSummaryFromStore returns the summary of a form. The boolean is false if
+// the form has no summary.
+func FormSummaryFromStore(rd store.Readable, formID string) (FormSummary, bool, error) {
+	var summary FormSummary
+
+	key, err := FormSummaryKey(formID)
+	if err != nil {
+		return summary, false, xerrors.Errorf("failed to get summary key: %v", err)
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return summary, false, xerrors.Errorf("failed to get summary: %v", err)
+	}
+
+	if len(buf) == 0 {
+		return summary, false, nil
+	}
+
+	err = json.Unmarshal(buf, &summary)
+	if err != nil {
+		return summary, false, xerrors.Errorf("failed to unmarshal summary: %v", err)
+	}
+
+	return summary, true, nil
+}
+
+// StatusIndexKey returns the store key of the IDs of the forms with the given
+// status.
+func StatusIndexKey(status Status) []byte {
+	return indexKey("status", strconv.Itoa(int(status)))
+}
+
+// OwnerIndexKey returns the store key of the IDs of the forms owned by the
+// given user.
+func OwnerIndexKey(owner int) []byte {
+	return indexKey("owner", strconv.Itoa(owner))
+}
+
+// FormIndexFromStore returns the IDs of the forms stored at the key of an
+// index, in the order they were added.
+func FormIndexFromStore(rd store.Readable, key []byte) (FormIDs, error) {
+	buf, err := rd.Get(key)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get index: %v", err)
+	}
+
+	ids := FormIDs{}
+
+	if len(buf) == 0 {
+		return ids, nil
+	}
+
+	err = json.Unmarshal(buf, &ids)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal index: %v", err)
+	}
+
+	return ids, nil
+}
+
+// indexKey returns H( "forms" | name | value ).
+func indexKey(name, value string) []byte {
+	h := sha256.New()
+	h.Write([]byte("forms"))
+	h.Write([]byte(name))
+	h.Write([]byte(value))
+
+	return h.Sum(nil)
+}
@@ -3,8 +3,12 @@ package types
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"go.dedis.ch/dela/serde"
 	"go.dedis.ch/dela/serde/registry"
@@ -138,6 +142,13 @@ type CastVote struct {
 	FormID  string
 	VoterID string
 	Ballot  Ciphervote
+	// Signature is the signature of the voter on the VoteDigest, with the key
+	// registered on the form. It is only set on the forms with SignedBallots.
+	Signature []byte
+	// Nonce is signed with the ballot and must be greater than the one of the
+	// previous ballot of the voter, so that a ballot can't be cast again. It
+	// is only set on the forms with SignedBallots.
+	Nonce uint64
 }
 
 // Serialize implements serde.Message
@@ -152,6 +163,137 @@ func (castVote CastVote) Serialize(ctx serde.Context) ([]byte, error) {
 	return data, nil
 }
 
+// MaxBatchVotes is the maximum number of votes of a CastVotes transaction.
+const MaxBatchVotes = 100
+
+// CastVotes defines the transaction to cast a batch of votes at once. The
+// votes are all cast, or none of them if one is invalid.
+//
+// - implements serde.Message
+type CastVotes struct {
+	// FormID is hex-encoded
+	FormID string
+	Votes  []BatchVote
+}
+
+// BatchVote is a vote of a CastVotes transaction, as in a CastVote
+// transaction.
+type BatchVote struct {
+	VoterID   string
+	Ballot    Ciphervote
+	Signature []byte
+	Nonce     uint64
+}
+
+// Serialize implements serde.Message
+func (castVotes CastVotes) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, castVotes)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode cast votes: %v", err)
+	}
+
+	return data, nil
+}
+
+// ballotErrorsPrefix starts the message of the BallotErrors.
+const ballotErrorsPrefix = "invalid ballots: "
+
+// BallotErrors is the error of a CastVotes transaction with invalid votes. It
+// maps the index of each invalid vote to the reason.
+type BallotErrors map[int]string
+
+// Error implements error. The reasons are encoded in JSON, so that they can be
+// found back in the reason of the rejection of the transaction.
+func (e BallotErrors) Error() string {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return ballotErrorsPrefix + err.Error()
+	}
+
+	return ballotErrorsPrefix + string(buf)
+}
+
+// ParseBallotErrors returns the BallotErrors found in the reason of the
+// rejection of a transaction, or false if there are none.
+func ParseBallotErrors(reason string) (BallotErrors, bool) {
+	i := strings.Index(reason, ballotErrorsPrefix)
+	if i < 0 {
+		return nil, false
+	}
+
+	var errs BallotErrors
+
+	// the reason might be wrapped, so only the first value is decoded
+	decoder := json.NewDecoder(strings.NewReader(reason[i+len(ballotErrorsPrefix):]))
+
+	err := decoder.Decode(&errs)
+	if err != nil {
+		return nil, false
+	}
+
+	return errs, true
+}
+
+// RejectionKind is the kind of a Rejection.
+type RejectionKind string
+
+const (
+	// RejectForbidden is the kind of a transaction whose user doesn't have the
+	// permission to perform it.
+	RejectForbidden RejectionKind = "forbidden"
+	// RejectInvalidStatus is the kind of a transaction that the status of the
+	// form doesn't allow.
+	RejectInvalidStatus RejectionKind = "invalid_status"
+	// RejectInvalidBallot is the kind of a ballot that can't be cast.
+	RejectInvalidBallot RejectionKind = "invalid_ballot"
+	// RejectFormNotFound is the kind of a transaction on an unknown form.
+	RejectFormNotFound RejectionKind = "form_not_found"
+)
+
+// rejectionRegex finds the kind of a Rejection in the reason of the rejection
+// of a transaction.
+var rejectionRegex = regexp.MustCompile(`\[(` + strings.Join([]string{
+	string(RejectForbidden),
+	string(RejectInvalidStatus),
+	string(RejectInvalidBallot),
+	string(RejectFormNotFound),
+}, "|") + `)\] `)
+
+// Rejection is an error of the contract whose kind can be found back in the
+// reason of the rejection of the transaction, as its message starts with the
+// kind between brackets.
+type Rejection struct {
+	Kind    RejectionKind
+	Message string
+}
+
+// NewRejection returns the rejection of the given kind with the formatted
+// message.
+func NewRejection(kind RejectionKind, format string, args ...interface{}) Rejection {
+	return Rejection{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Error implements error.
+func (r Rejection) Error() string {
+	return "[" + string(r.Kind) + "] " + r.Message
+}
+
+// ParseRejection returns the kind of the first Rejection found in the reason
+// of the rejection of a transaction, or false if there are none.
+func ParseRejection(reason string) (RejectionKind, bool) {
+	match := rejectionRegex.FindStringSubmatch(reason)
+	if match == nil {
+		return "", false
+	}
+
+	return RejectionKind(match[1]), true
+}
+
 // CloseForm defines the transaction to close a form
 //
 // - implements serde.Message
@@ -265,6 +407,29 @@ func (combineShares CombineShares) Serialize(ctx serde.Context) ([]byte, error)
 	return data, nil
 }
 
+// SubmitCertificate defines the transaction to store the collective signature
+// of the results by the roster.
+//
+// - implements serde.Message
+type SubmitCertificate struct {
+	// FormID is hex-encoded
+	FormID string
+	// Certificate is the serialized collective signature of the results digest
+	Certificate []byte
+}
+
+// Serialize implements serde.Message
+func (submitCertificate SubmitCertificate) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, submitCertificate)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode submit certificate: %v", err)
+	}
+
+	return data, nil
+}
+
 // CancelForm defines the transaction to cancel the form
 //
 // - implements serde.Message
@@ -485,3 +650,104 @@ func (removeOwner RemoveOwner) Serialize(ctx serde.Context) ([]byte, error) {
 
 	return data, nil
 }
+
+// Migrate defines the transaction that stores again all the forms, their
+// ballots and the admin list at the current schema version.
+//
+// - implements serde.Message
+type Migrate struct {
+	// UserID of the admin that is performing the action
+	UserID string
+}
+
+// Serialize implements serde.Message
+func (migrate Migrate) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, migrate)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode migrate: %v", err)
+	}
+
+	return data, nil
+}
+
+// ImportForm defines the transaction that copies a finished form of another
+// chain, with its batches of ballots, shuffles and results.
+//
+// - implements serde.Message
+type ImportForm struct {
+	// Form is the form serialized as it is stored on the other chain
+	Form []byte
+	// Batches are the batches referenced by the form, in the order of
+	// Form.BatchKeys
+	Batches [][]byte
+	// UserID of the admin that is performing the action
+	UserID string
+}
+
+// Serialize implements serde.Message
+func (importForm ImportForm) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, importForm)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode import form: %v", err)
+	}
+
+	return data, nil
+}
+
+// RepairForm defines the transaction that fixes the inconsistencies of a form
+// found by the integrity check of the store.
+//
+// - implements serde.Message
+type RepairForm struct {
+	// FormID is hex-encoded
+	FormID string
+	// UserID of the admin that is performing the action
+	UserID string
+}
+
+// Serialize implements serde.Message
+func (repairForm RepairForm) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, repairForm)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode repair form: %v", err)
+	}
+
+	return data, nil
+}
+
+// RegisterVoterKey defines the transaction that registers the public key of a
+// voter on a form with SignedBallots. The signature proves that the voter owns
+// the key, and the endorsement that the VoterKeysAuthority of the form
+// authenticated the voter.
+//
+// - implements serde.Message
+type RegisterVoterKey struct {
+	// FormID is hex-encoded
+	FormID  string
+	VoterID string
+	// PublicKey is the binary Ed25519 public key of the voter
+	PublicKey []byte
+	// Signature is the signature on the Digest with the key
+	Signature []byte
+	// Endorsement is the signature on the Digest of the VoterKeysAuthority of
+	// the form
+	Endorsement []byte
+}
+
+// Serialize implements serde.Message
+func (registerVoterKey RegisterVoterKey) Serialize(ctx serde.Context) ([]byte, error) {
+	format := transactionFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, registerVoterKey)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode register voter key: %v", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,284 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/sign/schnorr"
+	"golang.org/x/xerrors"
+)
+
+// NewRegisterVoterKey returns the transaction that registers the public key of
+// the secret for the voter on the form.
+func NewRegisterVoterKey(secret kyber.Scalar, formID, voterID string) (RegisterVoterKey, error) {
+	publicKey, err := suite.Point().Mul(secret, nil).MarshalBinary()
+	if err != nil {
+		return RegisterVoterKey{}, xerrors.Errorf("failed to marshal public key: %v", err)
+	}
+
+	tx := RegisterVoterKey{
+		FormID:    formID,
+		VoterID:   voterID,
+		PublicKey: publicKey,
+	}
+
+	digest, err := tx.Digest()
+	if err != nil {
+		return RegisterVoterKey{}, xerrors.Errorf("failed to get digest: %v", err)
+	}
+
+	tx.Signature, err = schnorr.Sign(suite, secret, digest)
+	if err != nil {
+		return RegisterVoterKey{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return tx, nil
+}
+
+// EndorseVoterKey returns the registration endorsed with the secret of the
+// VoterKeysAuthority of the form. The authority endorses a key once it
+// authenticated the voter.
+func EndorseVoterKey(secret kyber.Scalar, tx RegisterVoterKey) (RegisterVoterKey, error) {
+	digest, err := tx.Digest()
+	if err != nil {
+		return RegisterVoterKey{}, xerrors.Errorf("failed to get digest: %v", err)
+	}
+
+	tx.Endorsement, err = schnorr.Sign(suite, secret, digest)
+	if err != nil {
+		return RegisterVoterKey{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return tx, nil
+}
+
+// Digest returns the hash signed with the registered key and by the
+// authority: sha256( formID | "voter key" | voterID | publicKey ).
+func (registerVoterKey RegisterVoterKey) Digest() ([]byte, error) {
+	formID, err := hex.DecodeString(registerVoterKey.FormID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode formID: %v", err)
+	}
+
+	h := sha256.New()
+	h.Write(formID)
+	h.Write([]byte("voter key"))
+	h.Write([]byte(registerVoterKey.VoterID))
+	h.Write(registerVoterKey.PublicKey)
+
+	return h.Sum(nil), nil
+}
+
+// Verify returns the public key of the transaction if the signature and the
+// endorsement of the given authority on the Digest are valid.
+func (registerVoterKey RegisterVoterKey) Verify(authority []byte) (kyber.Point, error) {
+	authorityKey := suite.Point()
+
+	err := authorityKey.UnmarshalBinary(authority)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal authority key: %v", err)
+	}
+
+	publicKey := suite.Point()
+
+	err = publicKey.UnmarshalBinary(registerVoterKey.PublicKey)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal public key: %v", err)
+	}
+
+	digest, err := registerVoterKey.Digest()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get digest: %v", err)
+	}
+
+	err = schnorr.Verify(suite, publicKey, digest, registerVoterKey.Signature)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid signature: %v", err)
+	}
+
+	err = schnorr.Verify(suite, authorityKey, digest, registerVoterKey.Endorsement)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid endorsement: %v", err)
+	}
+
+	return publicKey, nil
+}
+
+// VoteDigest returns the hash a voter signs to cast the ballot on the form:
+// sha256( formID | "vote" | nonce | ballot digest ), with the nonce in little
+// endian on 8 bytes.
+func VoteDigest(formID string, nonce uint64, ballot Ciphervote) ([]byte, error) {
+	formIDBuf, err := hex.DecodeString(formID)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode formID: %v", err)
+	}
+
+	digest, err := ballot.Digest()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get ballot digest: %v", err)
+	}
+
+	nonceBuf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonceBuf, nonce)
+
+	h := sha256.New()
+	h.Write(formIDBuf)
+	h.Write([]byte("vote"))
+	h.Write(nonceBuf)
+	h.Write(digest)
+
+	return h.Sum(nil), nil
+}
+
+// SignVote returns the signature of the voter on the VoteDigest of the ballot
+// and the nonce.
+func SignVote(secret kyber.Scalar, formID string, nonce uint64, ballot Ciphervote) ([]byte, error) {
+	digest, err := VoteDigest(formID, nonce, ballot)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get vote digest: %v", err)
+	}
+
+	signature, err := schnorr.Sign(suite, secret, digest)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	return signature, nil
+}
+
+// VerifyVote checks the signature of the voter on the ballot with the key the
+// voter registered on the form, and that the nonce is greater than the one of
+// the previous ballot of the voter.
+func (form *Form) VerifyVote(rd store.Readable, voterID string, nonce uint64,
+	ballot Ciphervote, signature []byte) error {
+
+	publicKey, err := form.VoterKey(rd, voterID)
+	if err != nil {
+		return xerrors.Errorf("couldn't get voter key: %v", err)
+	}
+
+	if publicKey == nil {
+		return xerrors.Errorf("voter %s has no registered key", voterID)
+	}
+
+	digest, err := VoteDigest(form.FormID, nonce, ballot)
+	if err != nil {
+		return xerrors.Errorf("couldn't get vote digest: %v", err)
+	}
+
+	err = schnorr.Verify(suite, publicKey, digest, signature)
+	if err != nil {
+		return xerrors.Errorf("invalid ballot signature: %v", err)
+	}
+
+	last, err := form.VoteNonce(rd, voterID)
+	if err != nil {
+		return xerrors.Errorf("couldn't get vote nonce: %v", err)
+	}
+
+	if nonce <= last {
+		return xerrors.Errorf("nonce %d is not greater than the last one %d", nonce, last)
+	}
+
+	return nil
+}
+
+// VoteNonce returns the nonce of the last ballot of the voter, or 0.
+func (form *Form) VoteNonce(rd store.Readable, voterID string) (uint64, error) {
+	key, err := form.voteNonceKey(voterID)
+	if err != nil {
+		return 0, xerrors.Errorf("couldn't get vote nonce key: %v", err)
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return 0, xerrors.Errorf("couldn't get vote nonce: %v", err)
+	}
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	if len(buf) != 8 {
+		return 0, xerrors.Errorf("invalid vote nonce of %d bytes", len(buf))
+	}
+
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+// SetVoteNonce stores the nonce of the last ballot of the voter. It is stored
+// outside of the form at H( formID | "votenonce" | voterID ).
+func (form *Form) SetVoteNonce(st store.Snapshot, voterID string, nonce uint64) error {
+	key, err := form.voteNonceKey(voterID)
+	if err != nil {
+		return xerrors.Errorf("couldn't get vote nonce key: %v", err)
+	}
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, nonce)
+
+	err = st.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("couldn't store vote nonce: %v", err)
+	}
+
+	return nil
+}
+
+// VoterKey returns the key the voter registered on the form, or nil.
+func (form *Form) VoterKey(rd store.Readable, voterID string) (kyber.Point, error) {
+	key, err := form.voterKeyKey(voterID)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't get voter key key: %v", err)
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't get voter key: %v", err)
+	}
+
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	publicKey := suite.Point()
+
+	err = publicKey.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't unmarshal voter key: %v", err)
+	}
+
+	return publicKey, nil
+}
+
+// SetVoterKey stores the key of the voter. It is stored outside of the form at
+// H( formID | "voterkey" | voterID ).
+func (form *Form) SetVoterKey(st store.Snapshot, voterID string, publicKey kyber.Point) error {
+	key, err := form.voterKeyKey(voterID)
+	if err != nil {
+		return xerrors.Errorf("couldn't get voter key key: %v", err)
+	}
+
+	buf, err := publicKey.MarshalBinary()
+	if err != nil {
+		return xerrors.Errorf("couldn't marshal voter key: %v", err)
+	}
+
+	err = st.Set(key, buf)
+	if err != nil {
+		return xerrors.Errorf("couldn't store voter key: %v", err)
+	}
+
+	return nil
+}
+
+func (form *Form) voterKeyKey(voterID string) ([]byte, error) {
+	return form.storeKey("voterkey", []byte(voterID))
+}
+
+func (form *Form) voteNonceKey(voterID string) ([]byte, error) {
+	return form.storeKey("votenonce", []byte(voterID))
+}
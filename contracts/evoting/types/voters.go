@@ -0,0 +1,263 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"go.dedis.ch/dela/core/store"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+// votersFormat contains the supported formats for the batches of voters.
+// Right now only JSON is supported.
+var votersFormat = registry.NewSimpleRegistry()
+
+// RegisterVotersFormat registers the engine for the provided format
+func RegisterVotersFormat(format serde.Format, engine serde.FormatEngine) {
+	votersFormat.Register(format, engine)
+}
+
+// VotersBatch is a batch of at most BallotsPerBatch voters, in the order of
+// their first ballot. Together with one entry per voter, the batches form the
+// voter index of a form: it tells whether a user already voted and lists the
+// voters without reading the ballots. The batch i is stored at
+// H( formID | "voters" | i ), and the entry of a voter at
+// H( formID | "voter" | voterID ).
+//
+// - implements serde.Message
+type VotersBatch struct {
+	VoterIDs []string
+}
+
+// Serialize implements serde.Message
+func (v VotersBatch) Serialize(ctx serde.Context) ([]byte, error) {
+	format := votersFormat.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, v)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode voters batch: %v", err)
+	}
+
+	return data, nil
+}
+
+// HasVoted returns true if the voter cast at least one ballot.
+func (form *Form) HasVoted(rd store.Readable, voterID string) (bool, error) {
+	key, err := form.voterKey(voterID)
+	if err != nil {
+		return false, xerrors.Errorf("couldn't get voter key: %v", err)
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return false, xerrors.Errorf("couldn't get voter: %v", err)
+	}
+
+	return len(buf) > 0, nil
+}
+
+// addVoter adds the voter to the index if it is its first ballot and updates
+// VoterCount.
+func (form *Form) addVoter(ctx serde.Context, st store.Snapshot, voterID string) error {
+	voted, err := form.HasVoted(st, voterID)
+	if err != nil {
+		return err
+	}
+
+	if voted {
+		return nil
+	}
+
+	index := form.VoterCount / BallotsPerBatch
+
+	batchKey, err := form.votersBatchKey(index)
+	if err != nil {
+		return xerrors.Errorf("couldn't get voters batch key: %v", err)
+	}
+
+	batch := VotersBatch{}
+
+	if form.VoterCount%BallotsPerBatch != 0 {
+		batch, err = form.votersBatch(ctx, st, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	batch.VoterIDs = append(batch.VoterIDs, voterID)
+
+	buf, err := batch.Serialize(ctx)
+	if err != nil {
+		return xerrors.Errorf("couldn't marshal voters batch: %v", err)
+	}
+
+	err = st.Set(batchKey, buf)
+	if err != nil {
+		return xerrors.Errorf("couldn't store voters batch: %v", err)
+	}
+
+	voterKey, err := form.voterKey(voterID)
+	if err != nil {
+		return xerrors.Errorf("couldn't get voter key: %v", err)
+	}
+
+	position := make([]byte, 4)
+	binary.LittleEndian.PutUint32(position, form.VoterCount)
+
+	err = st.Set(voterKey, position)
+	if err != nil {
+		return xerrors.Errorf("couldn't store voter: %v", err)
+	}
+
+	form.VoterCount++
+
+	return nil
+}
+
+// indexVoters writes the voter index of the given voters, in order, and sets
+// VoterCount. Unlike addVoter, it doesn't read the index, so that it can be
+// run again on the same voters.
+func (form *Form) indexVoters(ctx serde.Context, st store.Snapshot, voterIDs []string) error {
+	form.VoterCount = 0
+
+	for start := 0; start < len(voterIDs); start += int(BallotsPerBatch) {
+		end := start + int(BallotsPerBatch)
+		if end > len(voterIDs) {
+			end = len(voterIDs)
+		}
+
+		batchKey, err := form.votersBatchKey(uint32(start) / BallotsPerBatch)
+		if err != nil {
+			return xerrors.Errorf("couldn't get voters batch key: %v", err)
+		}
+
+		buf, err := VotersBatch{VoterIDs: voterIDs[start:end]}.Serialize(ctx)
+		if err != nil {
+			return xerrors.Errorf("couldn't marshal voters batch: %v", err)
+		}
+
+		err = st.Set(batchKey, buf)
+		if err != nil {
+			return xerrors.Errorf("couldn't store voters batch: %v", err)
+		}
+
+		for _, voterID := range voterIDs[start:end] {
+			voterKey, err := form.voterKey(voterID)
+			if err != nil {
+				return xerrors.Errorf("couldn't get voter key: %v", err)
+			}
+
+			position := make([]byte, 4)
+			binary.LittleEndian.PutUint32(position, form.VoterCount)
+
+			err = st.Set(voterKey, position)
+			if err != nil {
+				return xerrors.Errorf("couldn't store voter: %v", err)
+			}
+
+			form.VoterCount++
+		}
+	}
+
+	return nil
+}
+
+// VoterIDs returns the users who cast a ballot, in the order of their first
+// ballot, from offset and at most limit of them. A limit of 0 or less returns
+// all the voters from offset. Only the batches of the voter index containing
+// the requested voters are read, not the ballots.
+func (form *Form) VoterIDs(ctx serde.Context, rd store.Readable, offset, limit int) ([]string, error) {
+	total := int(form.VoterCount)
+
+	if offset < 0 {
+		return nil, xerrors.Errorf("invalid offset: %d", offset)
+	}
+
+	if offset >= total {
+		return []string{}, nil
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	perBatch := int(BallotsPerBatch)
+	voterIDs := make([]string, 0, end-offset)
+
+	for i := offset / perBatch; i*perBatch < end; i++ {
+		batch, err := form.votersBatch(ctx, rd, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(batch.VoterIDs) == 0 {
+			return nil, xerrors.Errorf("voters batch %d is empty", i)
+		}
+
+		for j, voterID := range batch.VoterIDs {
+			position := i*perBatch + j
+			if position >= offset && position < end {
+				voterIDs = append(voterIDs, voterID)
+			}
+		}
+	}
+
+	return voterIDs, nil
+}
+
+func (form *Form) votersBatch(ctx serde.Context, rd store.Readable, index uint32) (VotersBatch, error) {
+	key, err := form.votersBatchKey(index)
+	if err != nil {
+		return VotersBatch{}, xerrors.Errorf("couldn't get voters batch key: %v", err)
+	}
+
+	buf, err := rd.Get(key)
+	if err != nil {
+		return VotersBatch{}, xerrors.Errorf("couldn't get voters batch: %v", err)
+	}
+
+	msg, err := votersFormat.Get(ctx.GetFormat()).Decode(ctx, buf)
+	if err != nil {
+		return VotersBatch{}, xerrors.Errorf("couldn't unmarshal voters batch: %v", err)
+	}
+
+	batch, ok := msg.(VotersBatch)
+	if !ok {
+		return VotersBatch{}, xerrors.Errorf("wrong message type: %T", msg)
+	}
+
+	return batch, nil
+}
+
+func (form *Form) votersBatchKey(index uint32) ([]byte, error) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, index)
+
+	return form.storeKey("voters", buf)
+}
+
+func (form *Form) voterKey(voterID string) ([]byte, error) {
+	return form.storeKey("voter", []byte(voterID))
+}
+
+// storeKey returns H( formID | prefix | suffix ). The prefix and the suffix
+// are prefixed with their length, so that a key of one kind can't be the key
+// of another kind, like the one of the voter "key1" and the one of the voter
+// key of "1".
+func (form *Form) storeKey(prefix string, suffix []byte) ([]byte, error) {
+	formID, err := hex.DecodeString(form.FormID)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't decode formID: %v", err)
+	}
+
+	h := sha256.New()
+	h.Write(formID)
+	writeBytes(h, []byte(prefix))
+	writeBytes(h, suffix)
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm_StoreKey(t *testing.T) {
+	form := Form{FormID: "deadbeef"}
+
+	voter, err := form.storeKey("voter", []byte("key1"))
+	require.NoError(t, err)
+
+	voterKey, err := form.storeKey("voterkey", []byte("1"))
+	require.NoError(t, err)
+
+	require.NotEqual(t, voter, voterKey)
+
+	voter, err = form.voterKey("s\x00\x00\x00\x00")
+	require.NoError(t, err)
+
+	batch, err := form.votersBatchKey(0)
+	require.NoError(t, err)
+
+	require.NotEqual(t, voter, batch)
+}
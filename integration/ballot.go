@@ -427,7 +427,7 @@ func castVotesLoad(numVotesPerSec, numSec, BallotSize, chunksPerBallot int, form
 		t.Logf("Waiting... included votes %d", atomic.LoadUint64(&includedVoteCount))
 		infos := getFormInfo(proxyArray[0], formID, t)
 		// check that our counter is synchronized with the blockchain
-		t.Logf("Voters count: %v", len(infos.Voters))
+		t.Logf("Voters count: %v", infos.VoterCount)
 		// check every 10 seconds
 		time.Sleep(time.Second * 10)
 	}
@@ -441,7 +441,7 @@ func cast(idx int, castVoteRequest ptypes.CastVoteRequest, contentType, randompr
 
 	t.Logf("cast ballot to proxy %v", randomproxy)
 
-	signed, err := createSignedRequest(secret, castVoteRequest)
+	signed, err := createSignedRequest(secret, "POST "+controller.FormPathSlash+formID+"/vote", castVoteRequest)
 	require.NoError(t, err)
 
 	resp, err := http.Post(randomproxy+controller.FormPathSlash+formID+"/vote", contentType, bytes.NewBuffer(signed))
@@ -509,7 +509,7 @@ func castVotesScenario(numVotes, BallotSize, chunksPerBallot int, formID, conten
 		randomproxy := proxyArray[rand.Intn(len(proxyArray))]
 		t.Logf("cast ballot to proxy %v", randomproxy)
 
-		signed, err := createSignedRequest(secret, castVoteRequest)
+		signed, err := createSignedRequest(secret, "POST "+controller.FormPathSlash+formID+"/vote", castVoteRequest)
 		require.NoError(t, err)
 
 		resp, err := http.Post(randomproxy+"/evoting/forms/"+formID+"/vote", contentType, bytes.NewBuffer(signed))
@@ -55,7 +55,7 @@ func startDKGScenario(numNodes int, timeTable []float64, formID string, secret k
 	msg := ptypes.UpdateDKG{
 		Action: "setup",
 	}
-	signed, err := createSignedRequest(secret, msg)
+	signed, err := createSignedRequest(secret, "PUT /evoting/services/dkg/actors/"+formID, msg)
 	require.NoError(t, err)
 
 	req, err := http.NewRequest(http.MethodPut, proxyArray[0]+"/evoting/services/dkg/actors/"+formID, bytes.NewBuffer(signed))
@@ -77,7 +77,7 @@ func initDKG(secret kyber.Scalar, proxyAddr, formIDHex string, t *testing.T) err
 		FormID: formIDHex,
 	}
 
-	signed, err := createSignedRequest(secret, setupDKG)
+	signed, err := createSignedRequest(secret, "POST /evoting/services/dkg/actors", setupDKG)
 	require.NoError(t, err)
 
 	resp, err := http.Post(proxyAddr+"/evoting/services/dkg/actors", "application/json", bytes.NewBuffer(signed))
@@ -95,7 +95,7 @@ func updateDKG(secret kyber.Scalar, proxyAddr, formIDHex, action string, t *test
 		Action: action,
 	}
 
-	signed, err := createSignedRequest(secret, msg)
+	signed, err := createSignedRequest(secret, "PUT /evoting/services/dkg/actors/"+formIDHex, msg)
 	require.NoError(t, err)
 
 	req, err := http.NewRequest(http.MethodPut, proxyAddr+"/evoting/services/dkg/actors/"+formIDHex, bytes.NewBuffer(signed))
@@ -241,11 +241,13 @@ func newDVotingNode(t require.TestingT, path string, randSource rand.Source) dVo
 
 	formFac := etypes.NewFormFactory(etypes.CiphervoteFactory{}, rosterFac)
 
-	dkg := pedersen.NewPedersen(onet, srvc, db, pool, formFac, signer)
+	serdeCtx := json.NewContext()
 
-	evoting.RegisterContract(exec, evoting.NewContract(accessService, dkg, rosterFac))
+	dkg := pedersen.NewPedersen(onet, srvc, db, pool, serdeCtx, formFac, signer)
 
-	neffShuffle := neff.NewNeffShuffle(onet, srvc, pool, blocks, formFac, signer)
+	evoting.RegisterContract(exec, evoting.NewContract(accessService, dkg, rosterFac, serdeCtx))
+
+	neffShuffle := neff.NewNeffShuffle(onet, srvc, pool, blocks, serdeCtx, formFac, signer)
 
 	// Neff shuffle signer
 	l := loader.NewFileLoader(filepath.Join(path, "private_neff.key"))
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -23,7 +24,6 @@ import (
 	"go.dedis.ch/dela/serde"
 	jsonDela "go.dedis.ch/dela/serde/json"
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"golang.org/x/xerrors"
 )
 
@@ -101,7 +101,7 @@ func createFormScenario(contentType, proxy string, secret kyber.Scalar, t *testi
 		UserID:        "adminId",
 	}
 
-	signed, err := createSignedRequest(secret, createSimpleFormRequest)
+	signed, err := createSignedRequest(secret, "POST /evoting/forms", createSimpleFormRequest)
 	require.NoError(t, err)
 
 	resp, err := http.Post(proxy+"/evoting/forms", contentType, bytes.NewBuffer(signed))
@@ -164,6 +164,11 @@ func getForm(formFac serde.Factory, formID []byte,
 	return types.FormFromStore(serdecontext, formFac, hex.EncodeToString(formID), service.GetStore())
 }
 
+// getDecryptedBallots returns all the decrypted ballots of the form
+func getDecryptedBallots(form types.Form, service ordering.Service) ([]types.Ballot, error) {
+	return form.Results(serdecontext, service.GetStore(), 0, 0)
+}
+
 // for integration tests
 func closeForm(m txManager, formID []byte, admin string) error {
 	closeForm := &types.CloseForm{
@@ -216,7 +221,7 @@ func updateForm(secret kyber.Scalar, proxyAddr, formIDHex, action string, t *tes
 		Action: action,
 	}
 
-	signed, err := createSignedRequest(secret, msg)
+	signed, err := createSignedRequest(secret, "PUT /evoting/forms/"+formIDHex, msg)
 	require.NoError(t, err)
 
 	req, err := http.NewRequest(http.MethodPut, proxyAddr+"/evoting/forms/"+formIDHex, bytes.NewBuffer(signed))
@@ -265,27 +270,36 @@ func getFormInfo(proxyAddr, formID string, t *testing.T) ptypes.GetFormResponse
 
 }
 
-func createSignedRequest(secret kyber.Scalar, msg interface{}) ([]byte, error) {
-	jsonMsg, err := json.Marshal(msg)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to marshal json: %v", err)
-	}
+// for Scenario
+func getFormResults(proxyAddr, formID string, t *testing.T) []types.Ballot {
+	var ballots []types.Ballot
 
-	payload := base64.URLEncoding.EncodeToString(jsonMsg)
+	for {
+		url := fmt.Sprintf("%s/evoting/forms/%s/results?offset=%d", proxyAddr, formID, len(ballots))
 
-	hash := sha256.New()
+		resp, err := http.Get(url)
+		require.NoError(t, err)
 
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
+		var page ptypes.GetResultsResponse
+		decoder := json.NewDecoder(resp.Body)
 
-	signature, err := schnorr.Sign(suite, secret, md)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to sign: %v", err)
+		err = decoder.Decode(&page)
+		require.NoError(t, err)
+
+		resp.Body.Close()
+
+		ballots = append(ballots, page.Ballots...)
+
+		if len(page.Ballots) == 0 || len(ballots) >= page.Total {
+			return ballots
+		}
 	}
+}
 
-	signed := ptypes.SignedRequest{
-		Payload:   payload,
-		Signature: hex.EncodeToString(signature),
+func createSignedRequest(secret kyber.Scalar, route string, msg interface{}) ([]byte, error) {
+	signed, err := ptypes.SignRequest(secret, route, msg)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign request: %v", err)
 	}
 
 	signedJSON, err := json.Marshal(signed)
@@ -142,14 +142,17 @@ func getIntegrationTest(numNodes, numVotes int) func(*testing.T) {
 		form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 		require.NoError(t, err)
 
+		decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+		require.NoError(t, err)
+
 		fmt.Println("Title of the form : " + form.Configuration.Title.En)
 		fmt.Println("ID of the form : " + string(form.FormID))
 		fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 
-		require.Len(t, form.DecryptedBallots, len(castedVotes))
+		require.Len(t, decryptedBallots, len(castedVotes))
 
-		for _, b := range form.DecryptedBallots {
+		for _, b := range decryptedBallots {
 			ok := false
 			for i, casted := range castedVotes {
 				if b.Equal(casted) {
@@ -310,12 +313,15 @@ func getIntegrationTestCrash(numNodes, numVotes, failingNodes int) func(*testing
 		form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 		require.NoError(t, err)
 
+		decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+		require.NoError(t, err)
+
 		fmt.Println("Title of the form : " + form.Configuration.Title.En)
 		fmt.Println("ID of the form : " + string(form.FormID))
 		fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 
-		checkBallots(form.DecryptedBallots, castedVotes, t)
+		checkBallots(decryptedBallots, castedVotes, t)
 
 		fmt.Println("closing nodes")
 
@@ -433,14 +439,17 @@ func getIntegrationBenchmark(numNodes, numVotes int) func(*testing.B) {
 		form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 		require.NoError(b, err)
 
+		decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+		require.NoError(b, err)
+
 		fmt.Println("Title of the form : " + form.Configuration.Title.En)
 		fmt.Println("ID of the form : " + string(form.FormID))
 		fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 
-		require.Len(b, form.DecryptedBallots, len(castedVotes))
+		require.Len(b, decryptedBallots, len(castedVotes))
 
-		for _, ballot := range form.DecryptedBallots {
+		for _, ballot := range decryptedBallots {
 			ok := false
 			for _, casted := range castedVotes {
 				if ballot.Equal(casted) {
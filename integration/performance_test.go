@@ -13,11 +13,14 @@ import (
 
 	"github.com/dedis/d-voting/contracts/evoting"
 	"github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
 	"github.com/dedis/d-voting/services/dkg"
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering/cosipbft"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
 	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/serde"
 	"golang.org/x/xerrors"
 )
 
@@ -158,10 +161,13 @@ func customVotesScenario(b *testing.B, stuffing bool) {
 	form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 	require.NoError(b, err)
 
+	decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+	require.NoError(b, err)
+
 	fmt.Println("Title of the form : " + form.Configuration.Title.En)
 	fmt.Println("ID of the form : " + string(form.FormID))
 	fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-	fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+	fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 	fmt.Println("Chunks per ballot : " + strconv.Itoa(form.ChunksPerBallot()))
 
 	b.Logf("Casting %d votes took %v", numVotes, durationCasting)
@@ -169,13 +175,13 @@ func customVotesScenario(b *testing.B, stuffing bool) {
 	b.Logf("Submitting shares took: %v", durationPubShares)
 	b.Logf("Decryption took: %v", durationDecrypt)
 
-	require.Len(b, form.DecryptedBallots, len(castedVotes)*int(types.BallotsPerBatch))
+	require.Len(b, decryptedBallots, len(castedVotes)*int(types.BallotsPerBatch))
 
 	// There will be a lot of supplementary ballots, but at least the ones that were
 	// cast by the test should be present.
 	for _, casted := range castedVotes {
 		ok := false
-		for _, ballot := range form.DecryptedBallots {
+		for _, ballot := range decryptedBallots {
 			if ballot.Equal(casted) {
 				ok = true
 				break
@@ -335,3 +341,125 @@ func closeNodesBench(b *testing.B, nodes []dVotingCosiDela) {
 	wait.Wait()
 	close(done)
 }
+
+// Compare the size and the speed of the JSON and BINARY formats on a form with
+// the public shares of 3 nodes over 200 ballots.
+func BenchmarkSerde_Form(b *testing.B) {
+	numNodes := 3
+	numVotes := 200
+	numChunksPerBallot := 3
+
+	units := types.PubsharesUnits{}
+
+	for i := 0; i < numNodes; i++ {
+		unit := make(types.PubsharesUnit, numVotes)
+		for j := range unit {
+			unit[j] = make([]types.Pubshare, numChunksPerBallot)
+			for k := range unit[j] {
+				unit[j][k] = suite.Point().Pick(suite.RandomStream())
+			}
+		}
+
+		units.Pubshares = append(units.Pubshares, unit)
+		units.PubKeys = append(units.PubKeys, []byte("public key"))
+		units.Indexes = append(units.Indexes, i)
+	}
+
+	form := types.Form{
+		FormID:             "deadbeef",
+		Status:             types.PubSharesSubmitted,
+		Pubkey:             suite.Point().Pick(suite.RandomStream()),
+		BallotSize:         numChunksPerBallot * 29,
+		SuffragiaStoreKeys: [][]byte{make([]byte, 32)},
+		SuffragiaHashes:    [][]byte{make([]byte, 32)},
+		BallotCount:        uint32(numVotes),
+		ShuffleThreshold:   numNodes,
+		PubsharesUnits:     units,
+		Roster:             fake.Authority{},
+	}
+
+	formFac := types.NewFormFactory(types.CiphervoteFactory{}, fake.NewRosterFac(authority.Roster{}))
+
+	benchmarkSerde(b, form, formFac)
+}
+
+// Compare the size and the speed of the JSON and BINARY formats on the
+// transaction that casts a ballot of 3 chunks.
+func BenchmarkSerde_CastVote(b *testing.B) {
+	tx := types.CastVote{
+		FormID:  "deadbeef",
+		VoterID: "123456",
+		Ballot:  makeBenchCiphervote(3),
+	}
+
+	benchmarkSerde(b, tx, types.NewTransactionFactory(types.CiphervoteFactory{}))
+}
+
+// Compare the size and the speed of the JSON and BINARY formats on the
+// transaction that shuffles 200 ballots of 3 chunks.
+func BenchmarkSerde_ShuffleBallots(b *testing.B) {
+	numVotes := 200
+
+	tx := types.ShuffleBallots{
+		FormID:          "deadbeef",
+		Round:           1,
+		ShuffledBallots: make([]types.Ciphervote, numVotes),
+		RandomVector:    make(types.RandomVector, 3),
+		Proof:           make([]byte, 10000),
+		Signature:       make([]byte, 48),
+		PublicKey:       make([]byte, 96),
+	}
+
+	for i := range tx.ShuffledBallots {
+		tx.ShuffledBallots[i] = makeBenchCiphervote(3)
+	}
+
+	for i := range tx.RandomVector {
+		tx.RandomVector[i] = make([]byte, 32)
+	}
+
+	benchmarkSerde(b, tx, types.NewTransactionFactory(types.CiphervoteFactory{}))
+}
+
+// benchmarkSerde serializes and deserializes the message in both formats. The
+// size of the encoded message is reported as the "bytes" metric.
+func benchmarkSerde(b *testing.B, msg serde.Message, fac serde.Factory) {
+	for _, format := range []string{"JSON", "BINARY"} {
+		ctx, err := evoting.NewSerdeContext(format)
+		require.NoError(b, err)
+
+		b.Run(format, func(b *testing.B) {
+			data, err := msg.Serialize(ctx)
+			require.NoError(b, err)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				data, err := msg.Serialize(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				_, err = fac.Deserialize(ctx, data)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(len(data)), "bytes")
+		})
+	}
+}
+
+func makeBenchCiphervote(numChunks int) types.Ciphervote {
+	ciphervote := make(types.Ciphervote, numChunks)
+
+	for i := range ciphervote {
+		ciphervote[i] = types.EGPair{
+			K: suite.Point().Pick(suite.RandomStream()),
+			C: suite.Point().Pick(suite.RandomStream()),
+		}
+	}
+
+	return ciphervote
+}
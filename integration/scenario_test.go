@@ -226,7 +226,7 @@ func startFormProcess(wg *sync.WaitGroup, numNodes, numVotes, numSec int, proxyA
 		Action: "shuffle",
 	}
 
-	signed, err := createSignedRequest(secret, shuffleBallotsRequest)
+	signed, err := createSignedRequest(secret, "PUT /evoting/services/shuffle/"+formID, shuffleBallotsRequest)
 	require.NoError(t, err)
 
 	randomproxy = proxyArray[rand.Intn(len(proxyArray))]
@@ -317,7 +317,7 @@ func startFormProcess(wg *sync.WaitGroup, numNodes, numVotes, numSec int, proxyA
 
 	//#################################### VALIDATE FORM RESULT ##############
 
-	tmpBallots := getFormResponse.Result
+	tmpBallots := getFormResults(proxyAddr1, formID, t)
 	var tmpCount bool
 
 	for _, ballotIntem := range tmpBallots {
@@ -138,19 +138,22 @@ func getIntegrationTestBadVote(numNodes, numVotes, numBadVotes int) func(*testin
 		form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 		require.NoError(t, err)
 
+		decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+		require.NoError(t, err)
+
 		fmt.Println("Title of the form : " + form.Configuration.Title.En)
 		fmt.Println("ID of the form : " + string(form.FormID))
 		fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 
 		// should contains numBadVotes empty ballots
 		count := 0
-		for _, ballot := range form.DecryptedBallots {
+		for _, ballot := range decryptedBallots {
 			if ballotIsNull(ballot) {
 				count++
 			}
 		}
-		fmt.Println(form.DecryptedBallots)
+		fmt.Println(decryptedBallots)
 
 		require.Equal(t, numBadVotes, count)
 
@@ -276,12 +279,15 @@ func getIntegrationTestRevote(numNodes, numVotes, numRevotes int) func(*testing.
 		form, err = getForm(formFac, formID, nodes[0].GetOrdering())
 		require.NoError(t, err)
 
+		decryptedBallots, err := getDecryptedBallots(form, nodes[0].GetOrdering())
+		require.NoError(t, err)
+
 		fmt.Println("Title of the form : " + form.Configuration.Title.En)
 		fmt.Println("ID of the form : " + string(form.FormID))
 		fmt.Println("Status of the form : " + strconv.Itoa(int(form.Status)))
-		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(form.DecryptedBallots)))
+		fmt.Println("Number of decrypted ballots : " + strconv.Itoa(len(decryptedBallots)))
 
-		checkBallots(form.DecryptedBallots, castedVotes, t)
+		checkBallots(decryptedBallots, castedVotes, t)
 
 		fmt.Println("closing nodes")
 
@@ -31,8 +31,6 @@ func NewForm(ctx serde.Context, snapshot store.Snapshot, formID string) (types.F
 		FormID:           formID,
 		Status:           types.Closed,
 		Pubkey:           pubKey,
-		ShuffleInstances: []types.ShuffleInstance{},
-		DecryptedBallots: nil,
 		ShuffleThreshold: 1,
 	}
 
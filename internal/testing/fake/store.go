@@ -53,6 +53,16 @@ func (snap *InMemorySnapshot) Delete(key []byte) error {
 	return snap.ErrDelete
 }
 
+// Keys returns the keys of the values of the snapshot, in no particular order.
+func (snap *InMemorySnapshot) Keys() [][]byte {
+	keys := make([][]byte, 0, len(snap.values))
+	for key := range snap.values {
+		keys = append(keys, []byte(key))
+	}
+
+	return keys
+}
+
 // InMemoryDB is a fake implementation of a key/value storage.
 //
 // - implements kv.DB
@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"encoding/hex"
+	"net/http"
+
+	"github.com/dedis/d-voting/proxy/types"
+	certificateSrv "github.com/dedis/d-voting/services/certificate"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+// NewCertificate returns a new initialized certificate
+func NewCertificate(actor certificateSrv.Actor, keys *types.KeyRing) Certificate {
+	return certificate{
+		actor: actor,
+		keys:  keys,
+	}
+}
+
+// certificate defines the proxy handlers for the certificate service
+//
+// - implements proxy.Certificate
+type certificate struct {
+	// actor is the certificate actor
+	actor certificateSrv.Actor
+	// pk is the public key of the proxy
+	keys *types.KeyRing
+}
+
+// EditCertificate implements proxy.Certificate
+func (c certificate) EditCertificate(w http.ResponseWriter, r *http.Request) {
+	var req types.UpdateCertificate
+
+	// Read the request
+	signed, err := types.NewSignedRequest(r.Body)
+	if err != nil {
+		BadRequestError(w, r, newSignedErr(err), nil)
+		return
+	}
+
+	// Verify the signature and get the request
+	r, err = getAndVerify(signed, c.keys, r, &req)
+	if err != nil {
+		signedRequestError(w, r, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	// check if the formID is present
+	if vars == nil || vars["formID"] == "" {
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	formIDBuf, err := hex.DecodeString(formID)
+	if err != nil {
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("failed to decode formID: %v", err), nil)
+		return
+	}
+
+	switch req.Action {
+	// collectively sign the results
+	case "certify":
+		err = c.actor.Certify(formIDBuf)
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to certify: %v", err), nil)
+			return
+		}
+	default:
+		BadRequestError(w, r, xerrors.Errorf("invalid action: %s", req.Action), nil)
+		return
+	}
+}
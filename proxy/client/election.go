@@ -0,0 +1,354 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dedis/d-voting/proxy/txnmanager"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	formsPath = "/evoting/forms"
+	adminPath = "/evoting"
+)
+
+// FormsQuery holds the optional parameters of GET /evoting/forms.
+type FormsQuery struct {
+	// Status filters the forms by status, if set
+	Status *uint16
+	// Owner filters the forms by owner, if not empty
+	Owner string
+	// Cursor is the cursor of the page, as returned in NextCursor
+	Cursor string
+	// Limit is the maximum number of forms of the page, zero for the default
+	Limit int
+}
+
+// ResultsQuery holds the optional parameters of GET
+// /evoting/forms/{formID}/results.
+type ResultsQuery struct {
+	Offset int
+	// Limit is the maximum number of ballots of the page, zero for the
+	// default
+	Limit int
+}
+
+// VotersQuery holds the optional parameters of GET
+// /evoting/forms/{formID}/voters.
+type VotersQuery struct {
+	Offset int
+	// Limit is the maximum number of voters of the page, zero for the default
+	Limit int
+}
+
+// CreateForm creates a form. The form exists once the transaction of the token
+// is included.
+func (c *Client) CreateForm(ctx context.Context, req ptypes.CreateFormRequest) (ptypes.CreateFormResponse, error) {
+	var res ptypes.CreateFormResponse
+
+	err := c.sendSigned(ctx, http.MethodPost, formsPath, req, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to create form: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetForms returns a page of the forms.
+func (c *Client) GetForms(ctx context.Context, q FormsQuery) (ptypes.GetFormsResponse, error) {
+	query := url.Values{}
+
+	if q.Status != nil {
+		query.Set("status", strconv.Itoa(int(*q.Status)))
+	}
+
+	if q.Owner != "" {
+		query.Set("owner", q.Owner)
+	}
+
+	if q.Cursor != "" {
+		query.Set("cursor", q.Cursor)
+	}
+
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	var res ptypes.GetFormsResponse
+
+	err := c.getJSON(ctx, formsPath, query, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get forms: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetForm returns the form. formID is hex-encoded.
+func (c *Client) GetForm(ctx context.Context, formID string) (ptypes.GetFormResponse, error) {
+	var res ptypes.GetFormResponse
+
+	err := c.getJSON(ctx, formPath(formID), nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get form: %w", err)
+	}
+
+	return res, nil
+}
+
+// WaitFormStatus polls the form until it has the status, and returns it. It
+// fails when the context is done.
+func (c *Client) WaitFormStatus(ctx context.Context, formID string, status uint16) (ptypes.GetFormResponse, error) {
+	for {
+		form, err := c.GetForm(ctx, formID)
+		if err != nil {
+			return form, err
+		}
+
+		if form.Status == status {
+			return form, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return form, xerrors.Errorf("form in status %d instead of %d: %v", form.Status, status, ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// UpdateForm performs the action on the form, which is one of open, close,
+// combineShares and cancel.
+func (c *Client) UpdateForm(ctx context.Context, formID string,
+	req ptypes.UpdateFormRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPut, formPath(formID), req, "update form")
+}
+
+// DeleteForm deletes the form.
+func (c *Client) DeleteForm(ctx context.Context, formID string,
+	req ptypes.DeleteFormRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodDelete, formPath(formID), req, "delete form")
+}
+
+// CastVote casts the ballot on the form.
+func (c *Client) CastVote(ctx context.Context, formID string,
+	req ptypes.CastVoteRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/vote", req, "cast vote")
+}
+
+// CastVotes casts the batch of ballots on the form in a single transaction.
+// The result of each ballot is in the Ballots of the transaction status.
+func (c *Client) CastVotes(ctx context.Context, formID string,
+	req ptypes.CastVotesRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/votes", req, "cast votes")
+}
+
+// RegisterVoterKey registers the key of a voter on a form with signed ballots.
+func (c *Client) RegisterVoterKey(ctx context.Context, formID string,
+	req ptypes.RegisterVoterKeyRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/voterkeys", req, "register voter key")
+}
+
+// ValidateBallot checks the plaintext ballot against the configuration of the
+// form.
+func (c *Client) ValidateBallot(ctx context.Context, formID string,
+	req ptypes.ValidateBallotRequest) (ptypes.ValidateBallotResponse, error) {
+
+	var res ptypes.ValidateBallotResponse
+
+	err := c.sendJSON(ctx, http.MethodPost, formPath(formID)+"/ballots/validate", req, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to validate ballot: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetResults returns a page of the decrypted ballots of the form.
+func (c *Client) GetResults(ctx context.Context, formID string, q ResultsQuery) (ptypes.GetResultsResponse, error) {
+	query := url.Values{}
+
+	if q.Offset != 0 {
+		query.Set("offset", strconv.Itoa(q.Offset))
+	}
+
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	var res ptypes.GetResultsResponse
+
+	err := c.getJSON(ctx, formPath(formID)+"/results", query, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get results: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetVoters returns a page of the users who cast a ballot on the form.
+func (c *Client) GetVoters(ctx context.Context, formID string, q VotersQuery) (ptypes.GetVotersResponse, error) {
+	query := url.Values{}
+
+	if q.Offset != 0 {
+		query.Set("offset", strconv.Itoa(q.Offset))
+	}
+
+	if q.Limit != 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	var res ptypes.GetVotersResponse
+
+	err := c.getJSON(ctx, formPath(formID)+"/voters", query, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get voters: %w", err)
+	}
+
+	return res, nil
+}
+
+// ExportResults returns the results of the form exported in the format, with
+// the other parameters of the export, like table, question and seats.
+func (c *Client) ExportResults(ctx context.Context, formID, format string, params url.Values) ([]byte, error) {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+
+	query.Set("format", format)
+
+	res, err := c.get(ctx, formPath(formID)+"/results", query)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to export results: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetCertificate returns the certificate of the results of the form.
+func (c *Client) GetCertificate(ctx context.Context, formID string) (ptypes.GetCertificateResponse, error) {
+	var res ptypes.GetCertificateResponse
+
+	err := c.getJSON(ctx, formPath(formID)+"/certificate", nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get certificate: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetRecord returns the JSON election record of the form.
+func (c *Client) GetRecord(ctx context.Context, formID string) ([]byte, error) {
+	res, err := c.get(ctx, formPath(formID)+"/record", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get record: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetBallotProof returns the proof that the ballot with the hex-encoded
+// digest is in the tree of the ballots of the form.
+func (c *Client) GetBallotProof(ctx context.Context, formID, digest string) (ptypes.GetBallotProofResponse, error) {
+	var res ptypes.GetBallotProofResponse
+
+	err := c.getJSON(ctx, formPath(formID)+"/ballots/"+digest+"/proof", nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get ballot proof: %w", err)
+	}
+
+	return res, nil
+}
+
+// AddOwner adds an owner to the form.
+func (c *Client) AddOwner(ctx context.Context, formID string,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/addowner", req, "add owner")
+}
+
+// RemoveOwner removes an owner from the form.
+func (c *Client) RemoveOwner(ctx context.Context, formID string,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/removeowner", req, "remove owner")
+}
+
+// AddVoter adds a voter to the form.
+func (c *Client) AddVoter(ctx context.Context, formID string,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/addvoter", req, "add voter")
+}
+
+// RemoveVoter removes a voter from the form.
+func (c *Client) RemoveVoter(ctx context.Context, formID string,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, formPath(formID)+"/removevoter", req, "remove voter")
+}
+
+// AddAdmin adds an admin to the admin list.
+func (c *Client) AddAdmin(ctx context.Context,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, adminPath+"/addadmin", req, "add admin")
+}
+
+// RemoveAdmin removes an admin from the admin list.
+func (c *Client) RemoveAdmin(ctx context.Context,
+	req ptypes.PermissionOperationRequest) (txnmanager.TransactionClientInfo, error) {
+
+	return c.submit(ctx, http.MethodPost, adminPath+"/removeadmin", req, "remove admin")
+}
+
+// GetAdmins returns the admin list.
+func (c *Client) GetAdmins(ctx context.Context) (ptypes.GetAdminsResponse, error) {
+	var res ptypes.GetAdminsResponse
+
+	err := c.getJSON(ctx, adminPath+"/adminlist", nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get admins: %w", err)
+	}
+
+	return res, nil
+}
+
+// GetOpenAPI returns the OpenAPI document of the proxy.
+func (c *Client) GetOpenAPI(ctx context.Context) ([]byte, error) {
+	res, err := c.get(ctx, "/openapi.json", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get the OpenAPI document: %w", err)
+	}
+
+	return res, nil
+}
+
+// submit sends the signed request of an operation that submits a transaction.
+func (c *Client) submit(ctx context.Context, method, path string, req interface{},
+	op string) (txnmanager.TransactionClientInfo, error) {
+
+	var res txnmanager.TransactionClientInfo
+
+	err := c.sendSigned(ctx, method, path, req, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to %s: %w", op, err)
+	}
+
+	return res, nil
+}
+
+func formPath(formID string) string {
+	return formsPath + "/" + formID
+}
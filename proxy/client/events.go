@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const eventsPath = "/evoting/events"
+
+// Event is a server-sent event of GET /evoting/events. Data is one of the
+// events of proxy/types, as given by Name.
+type Event struct {
+	Name string
+	Data json.RawMessage
+}
+
+// Decode decodes the data of the event in v.
+func (e Event) Decode(v interface{}) error {
+	err := json.Unmarshal(e.Data, v)
+	if err != nil {
+		return xerrors.Errorf("failed to decode event %s: %v", e.Name, err)
+	}
+
+	return nil
+}
+
+// Events streams the events of the form, or of all the forms if formID is
+// empty. The channel is closed when the stream ends or the context is done.
+func (c *Client) Events(ctx context.Context, formID string) (<-chan Event, error) {
+	path := eventsPath
+	if formID != "" {
+		path += "?" + url.Values{"formID": {formID}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.addr+path, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to send request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		buf, _ := io.ReadAll(resp.Body)
+
+		return nil, xerrors.Errorf("failed to stream events: %w", newError(resp.StatusCode, buf))
+	}
+
+	events := make(chan Event)
+
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+
+		var event Event
+
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			switch {
+			case line == "":
+				if event.Name == "" && event.Data == nil {
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+
+				event = Event{}
+			case strings.HasPrefix(line, ":"):
+				// a comment, as the keep-alive messages
+			case strings.HasPrefix(line, "event:"):
+				event.Name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				event.Data = append(event.Data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+			}
+		}
+	}()
+
+	return events, nil
+}
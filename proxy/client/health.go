@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"golang.org/x/xerrors"
+)
+
+// Health returns the health report of the node of the proxy.
+func (c *Client) Health(ctx context.Context) (ptypes.HealthResponse, error) {
+	var res ptypes.HealthResponse
+
+	err := c.getJSON(ctx, "/health", nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get health: %w", err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,317 @@
+// Package client implements a client of the HTTP API of the proxy, as
+// described in docs/api.md.
+//
+// The signed requests are signed with the secret key of the client, which must
+// be one of the keys trusted by the proxy. The operations that submit a
+// transaction return its TransactionClientInfo, and Wait polls the proxy until
+// the transaction is included or rejected.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultRetries      = 3
+	defaultRetryWait    = 500 * time.Millisecond
+	defaultPollInterval = 500 * time.Millisecond
+
+	contentType = "application/json"
+)
+
+// Option is the type of the options of the client.
+type Option func(*Client)
+
+// WithSecret sets the secret key that signs the requests. The signed requests
+// fail without it.
+func WithSecret(secret kyber.Scalar) Option {
+	return func(c *Client) {
+		c.secret = secret
+	}
+}
+
+// WithHTTPClient sets the HTTP client that sends the requests. The default is
+// http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.http = client
+	}
+}
+
+// WithRetry sets the number of times a request is retried and the time to
+// wait before the first retry, which is doubled after each retry.
+func WithRetry(retries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryWait = wait
+	}
+}
+
+// WithPollInterval sets the time between two polls of the status of a
+// transaction.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+// Client is a client of the proxy.
+//
+// A request is retried when the proxy is unavailable or receives too many
+// requests. A request without a body is also retried when it can't be sent,
+// but the others are not since they may have been received. A signed request
+// is signed again for each attempt, so that it is not rejected as replayed.
+type Client struct {
+	addr         string
+	secret       kyber.Scalar
+	http         *http.Client
+	retries      int
+	retryWait    time.Duration
+	pollInterval time.Duration
+}
+
+// NewClient returns a new client of the proxy at the address, as in
+// http://127.0.0.1:9080.
+func NewClient(addr string, opts ...Option) *Client {
+	c := &Client{
+		addr:         strings.TrimSuffix(addr, "/"),
+		http:         http.DefaultClient,
+		retries:      defaultRetries,
+		retryWait:    defaultRetryWait,
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Addr returns the address of the proxy.
+func (c *Client) Addr() string {
+	return c.addr
+}
+
+// Error is the error of a request that the proxy didn't accept.
+type Error struct {
+	// Status is the HTTP status of the response
+	Status int
+	// HTTPError is the error sent by the proxy. It is empty if the response
+	// is not a JSON error, in which case the body is in Message.
+	ptypes.HTTPError
+}
+
+// Error implements error. It returns the code and the message of the error.
+func (e *Error) Error() string {
+	if e.ErrorCode == "" {
+		return fmt.Sprintf("unexpected status %d: %s", e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("%s (%d): %v", e.ErrorCode, e.Status, e.Args["error"])
+}
+
+// RejectedError is the error of a transaction rejected by the contract.
+type RejectedError struct {
+	ErrorCode ptypes.ErrorCode
+	// Reason is the reason of the rejection given by the contract
+	Reason string
+}
+
+// Error implements error. It returns the code and the reason of the
+// rejection.
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("transaction rejected: %s: %s", e.ErrorCode, e.Reason)
+}
+
+// Code returns the code of the error returned by the client, or an empty code
+// if the error doesn't come from the proxy.
+func Code(err error) ptypes.ErrorCode {
+	var httpErr *Error
+	if xerrors.As(err, &httpErr) {
+		return httpErr.ErrorCode
+	}
+
+	var rejected *RejectedError
+	if xerrors.As(err, &rejected) {
+		return rejected.ErrorCode
+	}
+
+	return ""
+}
+
+// getJSON sends a GET request and decodes the JSON response in res.
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, res interface{}) error {
+	body, err := c.get(ctx, path, query)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, res)
+	if err != nil {
+		return xerrors.Errorf("failed to decode the response: %v", err)
+	}
+
+	return nil
+}
+
+// get sends a GET request and returns the body of the response.
+func (c *Client) get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	return c.do(ctx, http.MethodGet, path, func() ([]byte, error) {
+		return nil, nil
+	})
+}
+
+// sendJSON sends the message as the body of the request and decodes the JSON
+// response in res, if res is not nil.
+func (c *Client) sendJSON(ctx context.Context, method, path string, msg, res interface{}) error {
+	body, err := c.do(ctx, method, path, func() ([]byte, error) {
+		return json.Marshal(msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(body, res)
+}
+
+// sendSigned sends the message signed for the route of the request and
+// decodes the JSON response in res, if res is not nil.
+func (c *Client) sendSigned(ctx context.Context, method, path string, msg, res interface{}) error {
+	if c.secret == nil {
+		return xerrors.New("the client has no secret key to sign the request")
+	}
+
+	body, err := c.do(ctx, method, path, func() ([]byte, error) {
+		signed, err := ptypes.SignRequest(c.secret, ptypes.Route(method, path), msg)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to sign request: %v", err)
+		}
+
+		return json.Marshal(signed)
+	})
+	if err != nil {
+		return err
+	}
+
+	return decodeResponse(body, res)
+}
+
+func decodeResponse(body []byte, res interface{}) error {
+	if res == nil {
+		return nil
+	}
+
+	err := json.Unmarshal(body, res)
+	if err != nil {
+		return xerrors.Errorf("failed to decode the response: %v", err)
+	}
+
+	return nil
+}
+
+// do sends the request and returns the body of the response, retrying as
+// described in Client. The body of the request is created for each attempt.
+func (c *Client) do(ctx context.Context, method, path string, newBody func() ([]byte, error)) ([]byte, error) {
+	wait := c.retryWait
+
+	for i := 0; ; i++ {
+		body, retry, err := c.send(ctx, method, path, newBody)
+		if err == nil {
+			return body, nil
+		}
+
+		if !retry || i >= c.retries {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, xerrors.Errorf("%v: %v", ctx.Err(), err)
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+	}
+}
+
+// send sends the request once. It returns true if the request can be retried
+// when it fails.
+func (c *Client) send(ctx context.Context, method, path string,
+	newBody func() ([]byte, error)) ([]byte, bool, error) {
+
+	buf, err := newBody()
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to create the body: %v", err)
+	}
+
+	var body io.Reader = http.NoBody
+	if buf != nil {
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, body)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to create request: %v", err)
+	}
+
+	if buf != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, buf == nil && ctx.Err() == nil,
+			xerrors.Errorf("failed to send request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	res, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to read the response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, isRetryable(resp.StatusCode), newError(resp.StatusCode, res)
+	}
+
+	return res, false, nil
+}
+
+func isRetryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func newError(status int, body []byte) *Error {
+	e := &Error{Status: status}
+
+	err := json.Unmarshal(body, &e.HTTPError)
+	if err != nil || e.HTTPError.Code == 0 {
+		e.HTTPError = ptypes.HTTPError{Message: strings.TrimSpace(string(body))}
+	}
+
+	return e
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dedis/d-voting/proxy/txnmanager"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
+)
+
+var suite = suites.MustFind("Ed25519")
+
+func TestClient_Signed(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	public := suite.Point().Mul(secret, nil)
+
+	nonces := ptypes.NewNonceCache(10)
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		// the first attempt fails as if the proxy was overloaded
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		signed, err := ptypes.NewSignedRequest(r.Body)
+		require.NoError(t, err)
+
+		var req ptypes.CastVoteRequest
+
+		err = signed.GetAndVerify(public, &req)
+		require.NoError(t, err)
+
+		err = signed.Check(r, nonces, time.Now())
+		require.NoError(t, err)
+
+		require.Equal(t, "user1", req.VoterID)
+
+		json.NewEncoder(w).Encode(txnmanager.TransactionClientInfo{Token: "abcd"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithSecret(secret), WithRetry(2, time.Millisecond))
+
+	info, err := client.CastVote(context.Background(), "aa", ptypes.CastVoteRequest{VoterID: "user1"})
+	require.NoError(t, err)
+	require.Equal(t, "abcd", info.Token)
+	require.Equal(t, 2, attempts)
+
+	client = NewClient(server.URL)
+
+	_, err = client.CastVote(context.Background(), "aa", ptypes.CastVoteRequest{})
+	require.EqualError(t, err, "failed to cast vote: the client has no secret key to sign the request")
+}
+
+func TestClient_Error(t *testing.T) {
+	attempts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if r.URL.Path == "/evoting/adminlist" {
+			fmt.Fprint(w, "not json")
+			return
+		}
+
+		if r.URL.Path == "/evoting/forms/bb" {
+			http.Error(w, "oops", http.StatusBadGateway)
+			return
+		}
+
+		ptypes.NewHTTPError(r, ptypes.CodeFormNotFound, xerrors.New("no form"), nil).Write(w)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(1, time.Millisecond))
+
+	_, err := client.GetForm(context.Background(), "aa")
+	require.EqualError(t, err, "failed to get form: form_not_found (404): no form")
+	require.Equal(t, ptypes.CodeFormNotFound, Code(err))
+	require.Equal(t, 1, attempts)
+
+	var httpErr *Error
+	require.True(t, xerrors.As(err, &httpErr))
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+
+	attempts = 0
+
+	_, err = client.GetForm(context.Background(), "bb")
+	require.EqualError(t, err, "failed to get form: unexpected status 502: oops")
+	require.Equal(t, ptypes.ErrorCode(""), Code(err))
+	require.Equal(t, 2, attempts)
+
+	_, err = client.GetAdmins(context.Background())
+	require.ErrorContains(t, err, "failed to get admins: failed to decode the response")
+
+	require.Equal(t, ptypes.ErrorCode(""), Code(xerrors.New("oops")))
+}
+
+func TestClient_Wait(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var info txnmanager.TransactionClientInfo
+
+		switch r.URL.Path {
+		case transactionsPath + "pending":
+			info = txnmanager.TransactionClientInfo{Token: "included"}
+		case transactionsPath + "included":
+			info = txnmanager.TransactionClientInfo{Status: txnmanager.IncludedTransaction, Token: "x"}
+		case transactionsPath + "rejected":
+			info = txnmanager.TransactionClientInfo{
+				Status:    txnmanager.RejectedTransaction,
+				ErrorCode: ptypes.CodeInvalidStatus,
+				Reason:    "the form is not open",
+			}
+		default:
+			info = txnmanager.TransactionClientInfo{Token: r.URL.Path}
+		}
+
+		json.NewEncoder(w).Encode(info)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithPollInterval(time.Millisecond))
+
+	info, err := client.Wait(context.Background(), "pending")
+	require.NoError(t, err)
+	require.Equal(t, txnmanager.IncludedTransaction, info.Status)
+
+	_, err = client.Wait(context.Background(), "rejected")
+	require.EqualError(t, err, "transaction rejected: invalid_status: the form is not open")
+	require.Equal(t, ptypes.CodeInvalidStatus, Code(err))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Wait(ctx, "unknown")
+	require.Error(t, err)
+}
+
+func TestClient_Events(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "aa", r.URL.Query().Get("formID"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "event: ballots\ndata: {\"FormID\":\"aa\",\"Cast\":2}\n\n")
+		fmt.Fprint(w, "event: status\ndata: {\"FormID\":\"aa\",\"Status\":2}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	events, err := client.Events(context.Background(), "aa")
+	require.NoError(t, err)
+
+	event := <-events
+	require.Equal(t, ptypes.BallotsEventName, event.Name)
+
+	var ballots ptypes.BallotsEvent
+
+	err = event.Decode(&ballots)
+	require.NoError(t, err)
+	require.Equal(t, 2, ballots.Cast)
+
+	event = <-events
+	require.Equal(t, ptypes.StatusEventName, event.Name)
+
+	_, more := <-events
+	require.False(t, more)
+}
+
+func TestClient_WaitShuffle(t *testing.T) {
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		info := ptypes.GetShuffleInfo{Status: 1, Round: calls, Threshold: 2}
+
+		switch {
+		case r.URL.Path == shufflePath+"bb":
+			info.Status = 3
+			info.Error = &ptypes.HTTPError{Message: "oops"}
+		case calls == 2:
+			info.Status = 2
+		}
+
+		json.NewEncoder(w).Encode(info)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithPollInterval(time.Millisecond))
+
+	info, err := client.WaitShuffle(context.Background(), "aa")
+	require.NoError(t, err)
+	require.Equal(t, 2, info.Round)
+
+	_, err = client.WaitShuffle(context.Background(), "bb")
+	require.EqualError(t, err, "failed to shuffle: oops")
+}
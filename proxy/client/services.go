@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/dedis/d-voting/services/dkg"
+	"github.com/dedis/d-voting/services/shuffle"
+	"golang.org/x/xerrors"
+)
+
+const (
+	dkgPath         = "/evoting/services/dkg/actors"
+	shufflePath     = "/evoting/services/shuffle/"
+	certificatePath = "/evoting/services/certificate/"
+)
+
+// InitDKG creates the DKG actor of the form on the node of the proxy.
+func (c *Client) InitDKG(ctx context.Context, formID string) error {
+	err := c.sendSigned(ctx, http.MethodPost, dkgPath, ptypes.NewDKGRequest{FormID: formID}, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to init DKG: %w", err)
+	}
+
+	return nil
+}
+
+// GetDKGActor returns the status of the DKG actor of the form.
+func (c *Client) GetDKGActor(ctx context.Context, formID string) (ptypes.GetActorInfo, error) {
+	var res ptypes.GetActorInfo
+
+	err := c.getJSON(ctx, dkgPath+"/"+formID, nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get DKG actor: %w", err)
+	}
+
+	return res, nil
+}
+
+// UpdateDKG performs the action on the DKG actor of the form, which is one of
+// setup and computePubshares.
+func (c *Client) UpdateDKG(ctx context.Context, formID, action string) error {
+	err := c.sendSigned(ctx, http.MethodPut, dkgPath+"/"+formID, ptypes.UpdateDKG{Action: action}, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to update DKG: %w", err)
+	}
+
+	return nil
+}
+
+// WaitDKGSetup polls the DKG actor of the form until it is set up, which is
+// done asynchronously after UpdateDKG with setup. It fails if the setup fails
+// or the context is done.
+func (c *Client) WaitDKGSetup(ctx context.Context, formID string) error {
+	for {
+		info, err := c.GetDKGActor(ctx, formID)
+		if err != nil {
+			return err
+		}
+
+		switch dkg.StatusCode(info.Status) {
+		case dkg.Setup:
+			return nil
+		case dkg.Failed:
+			return xerrors.Errorf("failed to set up DKG: %v", info.Error.Args["error"])
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("DKG not set up: %v", ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// Shuffle starts the shuffle of the ballots of the form, which runs in the
+// background on the node of the proxy.
+func (c *Client) Shuffle(ctx context.Context, formID string) error {
+	err := c.sendSigned(ctx, http.MethodPut, shufflePath+formID, ptypes.UpdateShuffle{Action: "shuffle"}, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to shuffle: %w", err)
+	}
+
+	return nil
+}
+
+// GetShuffle returns the status of the shuffle of the form started on the node
+// of the proxy.
+func (c *Client) GetShuffle(ctx context.Context, formID string) (ptypes.GetShuffleInfo, error) {
+	var res ptypes.GetShuffleInfo
+
+	err := c.getJSON(ctx, shufflePath+formID, nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get shuffle: %w", err)
+	}
+
+	return res, nil
+}
+
+// WaitShuffle polls the status of the shuffle of the form until it is done. It
+// fails if the shuffle fails or the context is done.
+func (c *Client) WaitShuffle(ctx context.Context, formID string) (ptypes.GetShuffleInfo, error) {
+	for {
+		info, err := c.GetShuffle(ctx, formID)
+		if err != nil {
+			return info, err
+		}
+
+		switch shuffle.StatusCode(info.Status) {
+		case shuffle.Done:
+			return info, nil
+		case shuffle.Failed:
+			if info.Error == nil {
+				return info, xerrors.Errorf("failed to shuffle")
+			}
+
+			return info, xerrors.Errorf("failed to shuffle: %s", info.Error.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, xerrors.Errorf("shuffle not done after round %d: %v", info.Round, ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// Certify certifies the results of the form.
+func (c *Client) Certify(ctx context.Context, formID string) error {
+	err := c.sendSigned(ctx, http.MethodPut, certificatePath+formID,
+		ptypes.UpdateCertificate{Action: "certify"}, nil)
+	if err != nil {
+		return xerrors.Errorf("failed to certify: %w", err)
+	}
+
+	return nil
+}
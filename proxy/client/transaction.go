@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/dedis/d-voting/proxy/txnmanager"
+	"golang.org/x/xerrors"
+)
+
+const transactionsPath = "/evoting/transactions/"
+
+// GetTransaction returns the status of the transaction of the token, with the
+// token to use for the next check.
+func (c *Client) GetTransaction(ctx context.Context, token string) (txnmanager.TransactionClientInfo, error) {
+	var res txnmanager.TransactionClientInfo
+
+	err := c.getJSON(ctx, transactionsPath+token, nil, &res)
+	if err != nil {
+		return res, xerrors.Errorf("failed to get transaction: %w", err)
+	}
+
+	return res, nil
+}
+
+// Wait polls the status of the transaction of the token until it is included.
+// It returns a RejectedError if the contract rejects the transaction, and
+// fails when the context is done.
+func (c *Client) Wait(ctx context.Context, token string) (txnmanager.TransactionClientInfo, error) {
+	for {
+		info, err := c.GetTransaction(ctx, token)
+		if err != nil {
+			return info, err
+		}
+
+		switch info.Status {
+		case txnmanager.IncludedTransaction:
+			return info, nil
+		case txnmanager.RejectedTransaction:
+			return info, &RejectedError{ErrorCode: info.ErrorCode, Reason: info.Reason}
+		}
+
+		token = info.Token
+
+		select {
+		case <-ctx.Done():
+			return info, xerrors.Errorf("transaction not included: %v", ctx.Err())
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// SubmitAndWait waits for the transaction returned by submit, as in
+//
+//	client.SubmitAndWait(ctx, func() (txnmanager.TransactionClientInfo, error) {
+//		return client.CastVote(ctx, formID, req)
+//	})
+func (c *Client) SubmitAndWait(ctx context.Context,
+	submit func() (txnmanager.TransactionClientInfo, error)) (txnmanager.TransactionClientInfo, error) {
+
+	info, err := submit()
+	if err != nil {
+		return info, err
+	}
+
+	return c.Wait(ctx, info.Token)
+}
@@ -3,7 +3,6 @@ package proxy
 import (
 	"encoding/hex"
 	"encoding/json"
-	"fmt"
 
 	"net/http"
 
@@ -12,16 +11,15 @@ import (
 	"github.com/gorilla/mux"
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/txn"
-	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
 
 // NewDKG returns a new initialized DKG proxy
-func NewDKG(mngr txn.Manager, d dkgSrv.DKG, pk kyber.Point) DKG {
+func NewDKG(mngr txn.Manager, d dkgSrv.DKG, keys *types.KeyRing) DKG {
 	return dkg{
 		manager:    mngr,
 		dkgService: d,
-		pk:         pk,
+		keys:       keys,
 	}
 }
 
@@ -34,7 +32,7 @@ type dkg struct {
 	// dkgService is the DKG service
 	dkgService dkgSrv.DKG
 	// pk is the public key of the proxy
-	pk kyber.Point
+	keys *types.KeyRing
 }
 
 // NewDKGActor implements proxy.DKG
@@ -46,26 +44,25 @@ func (d dkg) NewDKGActor(w http.ResponseWriter, r *http.Request) {
 	// Read the request
 	signed, err := types.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// Verify the request
-	err = signed.GetAndVerify(d.pk, &req)
+	r, err = getAndVerify(signed, d.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
 	formIDBuf, err := hex.DecodeString(req.FormID)
 	if err != nil {
-		http.Error(w, "failed to decode formID: "+req.FormID,
-			http.StatusBadRequest)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("failed to decode formID: %v", err), nil)
 		return
 	}
 
 	if len(formIDBuf) == 0 {
-		http.Error(w, "formID is empty", http.StatusBadRequest)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.New("formID is empty"), nil)
 		return
 	}
 
@@ -77,8 +74,7 @@ func (d dkg) NewDKGActor(w http.ResponseWriter, r *http.Request) {
 	// subscribe to the DKG service
 	_, err = d.dkgService.Listen(formIDBuf, d.manager)
 	if err != nil {
-		http.Error(w, "failed to start actor: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to start actor: %v", err), nil)
 		return
 	}
 }
@@ -93,7 +89,7 @@ func (d dkg) Actor(w http.ResponseWriter, r *http.Request) {
 
 	// check if the formID is present
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -149,20 +145,20 @@ func (d dkg) EditDKGActor(w http.ResponseWriter, r *http.Request) {
 	// Read the request
 	signed, err := types.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// Verify the signature
-	err = signed.GetAndVerify(d.pk, &req)
+	r, err = getAndVerify(signed, d.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
 	vars := mux.Vars(r)
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -170,14 +166,14 @@ func (d dkg) EditDKGActor(w http.ResponseWriter, r *http.Request) {
 
 	formIDBuf, err := hex.DecodeString(formID)
 	if err != nil {
-		http.Error(w, "failed to decode formID: "+formID, http.StatusBadRequest)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("failed to decode formID: %v", err), nil)
 		return
 	}
 
 	// get the actor
 	a, exists := d.dkgService.GetActor(formIDBuf)
 	if !exists {
-		http.Error(w, "actor does not exist", http.StatusInternalServerError)
+		NotFoundErr(w, r, xerrors.New("actor does not exist"), nil)
 		return
 	}
 
@@ -196,7 +192,7 @@ func (d dkg) EditDKGActor(w http.ResponseWriter, r *http.Request) {
 	case "computePubshares":
 		err = a.ComputePubshares()
 		if err != nil {
-			http.Error(w, "failed to compute pubshares: "+err.Error(), http.StatusInternalServerError)
+			InternalError(w, r, xerrors.Errorf("failed to compute pubshares: %v", err), nil)
 			return
 		}
 	default:
@@ -1,8 +1,6 @@
 package proxy
 
 import (
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -18,7 +16,6 @@ import (
 	"go.dedis.ch/dela/cli/node"
 	"go.dedis.ch/dela/core/txn"
 	"go.dedis.ch/kyber/v3"
-	"go.dedis.ch/kyber/v3/sign/schnorr"
 	"golang.org/x/xerrors"
 )
 
@@ -33,18 +30,18 @@ func TestNewDkg(t *testing.T) {
 	ctx.Injector.Inject(&mngr)
 	var d dkgSrv.DKG
 	ctx.Injector.Inject(&d)
-	var pk kyber.Point
-	ctx.Injector.Inject(&pk)
+	keys := types.NewKeyRing()
+	ctx.Injector.Inject(keys)
 
-	dkgInterface := NewDKG(mngr, d, pk)
+	dkgInterface := NewDKG(mngr, d, keys)
 	//check that the dkg is not nil
 	require.NotNil(t, dkgInterface)
 	//the txn.Manager of the dkg should be the same as the one we injected$
 	require.Equal(t, mngr, dkgInterface.(dkg).manager)
 	//the dkg of the dkg should be the same as the one we injected
 	require.Equal(t, d, dkgInterface.(dkg).dkgService)
-	//the pk of the dkg should be the same as the one we injected
-	require.Equal(t, pk, dkgInterface.(dkg).pk)
+	//the keys of the dkg should be the same as the ones we injected
+	require.Equal(t, keys, dkgInterface.(dkg).keys)
 }
 
 // test that NewDKGActor is working properly
@@ -80,9 +77,9 @@ func TestNewDKGActor(t *testing.T) {
 		FormID: "abcd",
 	}
 
-	dkgInterface := NewDKG(mngr, mockDKGService{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGService{}, publicKeys(public))
 
-	requestt, e := createSignedRequest(secret, request)
+	requestt, e := createSignedRequest(secret, "POST /dkg", request)
 	require.NoError(t, e)
 
 	r, e := http.NewRequest("POST", "/dkg", strings.NewReader(string(requestt)))
@@ -94,6 +91,43 @@ func TestNewDKGActor(t *testing.T) {
 
 }
 
+// test that NewDKGActor rejects a replayed request and a request signed for
+// another route
+func TestNewDKGActorReplayed(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	public := suite.Point().Mul(secret, nil)
+
+	dkgInterface := NewDKG(nil, mockDKGService{}, publicKeys(public))
+
+	request := types.NewDKGRequest{
+		FormID: "abcd",
+	}
+
+	signed, err := createSignedRequest(secret, "POST /dkg", request)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/dkg", strings.NewReader(string(signed)))
+	dkgInterface.NewDKGActor(w, r)
+	require.Equal(t, 200, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/dkg", strings.NewReader(string(signed)))
+	dkgInterface.NewDKGActor(w, r)
+	require.Equal(t, 401, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), "already used")
+	require.Contains(t, w.Body.String(), `"ErrorCode": "rejected_request"`)
+
+	signed, err = createSignedRequest(secret, "POST /other", request)
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("POST", "/dkg", strings.NewReader(string(signed)))
+	dkgInterface.NewDKGActor(w, r)
+	require.Equal(t, 401, w.Result().StatusCode)
+	require.Contains(t, w.Body.String(), "request signed for")
+}
+
 // test that NewDKGActor is setting
 // the right status code when the request is not valid
 func TestNewDKGActorInvalidRequest(t *testing.T) {
@@ -121,7 +155,7 @@ func TestNewDKGActorInvalidRequest(t *testing.T) {
 	err = secret.UnmarshalBinary(secretkeyBuf)
 	require.NoError(t, err)
 
-	dkgInterface := NewDKG(mngr, mockDKGService{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGService{}, publicKeys(public))
 
 	r, e := http.NewRequest("POST", "/dkg", strings.NewReader("abcd"))
 	if e != nil {
@@ -130,7 +164,7 @@ func TestNewDKGActorInvalidRequest(t *testing.T) {
 
 	dkgInterface.NewDKGActor(w, r)
 
-	require.Equal(t, 500, w.(*httptest.ResponseRecorder).Result().StatusCode)
+	require.Equal(t, 400, w.(*httptest.ResponseRecorder).Result().StatusCode)
 
 }
 
@@ -167,9 +201,9 @@ func TestNewDKGActorInvalidSignature(t *testing.T) {
 		FormID: "abcd",
 	}
 
-	dkgInterface := NewDKG(mngr, mockDKGService{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGService{}, publicKeys(public))
 
-	requestt, err := createSignedRequest(secret, request)
+	requestt, err := createSignedRequest(secret, "POST /dkg", request)
 	require.NoError(t, err)
 
 	r, err := http.NewRequest("POST", "/dkg", strings.NewReader(string(requestt)))
@@ -177,7 +211,8 @@ func TestNewDKGActorInvalidSignature(t *testing.T) {
 
 	dkgInterface.NewDKGActor(w, r)
 
-	require.Equal(t, 500, w.(*httptest.ResponseRecorder).Result().StatusCode)
+	require.Equal(t, 401, w.(*httptest.ResponseRecorder).Result().StatusCode)
+	require.Contains(t, w.(*httptest.ResponseRecorder).Body.String(), `"ErrorCode": "invalid_signature"`)
 
 }
 
@@ -214,9 +249,9 @@ func TestNewDKGActorInvalidFormID(t *testing.T) {
 		FormID: "abcdefg",
 	}
 
-	dkgInterface := NewDKG(mngr, mockDKGService{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGService{}, publicKeys(public))
 
-	requestt, err := createSignedRequest(secret, request)
+	requestt, err := createSignedRequest(secret, "POST /dkg", request)
 
 	require.NoError(t, err)
 
@@ -263,9 +298,9 @@ func TestNewDKGActorFormDoesNotExist(t *testing.T) {
 		FormID: "abcd",
 	}
 
-	dkgInterface := NewDKG(mngr, mockDKGServiceError{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGServiceError{}, publicKeys(public))
 
-	requestt, err := createSignedRequest(secret, request)
+	requestt, err := createSignedRequest(secret, "POST /dkg", request)
 
 	require.NoError(t, err)
 
@@ -310,9 +345,9 @@ func TestActor(t *testing.T) {
 		FormID: "abcd",
 	}
 
-	dkgInterface := NewDKG(mngr, mockDKGService{}, public)
+	dkgInterface := NewDKG(mngr, mockDKGService{}, publicKeys(public))
 
-	requestt, err := createSignedRequest(secret, request)
+	requestt, err := createSignedRequest(secret, "GET /services/dkg/actors/1234", request)
 	require.NoError(t, err)
 
 	r, err := http.NewRequest("GET", "/services/dkg/actors/1234", strings.NewReader(string(requestt)))
@@ -352,27 +387,10 @@ func (m mockDKGServiceError) GetActor(formID []byte) (dkgSrv.Actor, bool) {
 	return nil, false
 }
 
-func createSignedRequest(secret kyber.Scalar, msg interface{}) ([]byte, error) {
-	jsonMsg, err := json.Marshal(msg)
+func createSignedRequest(secret kyber.Scalar, route string, msg interface{}) ([]byte, error) {
+	signed, err := types.SignRequest(secret, route, msg)
 	if err != nil {
-		return nil, xerrors.Errorf("failed to marshal json: %v", err)
-	}
-
-	payload := base64.URLEncoding.EncodeToString(jsonMsg)
-
-	hash := sha256.New()
-
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
-
-	signature, err := schnorr.Sign(suite, secret, md)
-	if err != nil {
-		return nil, xerrors.Errorf("failed to sign: %v", err)
-	}
-
-	signed := types.SignedRequest{
-		Payload:   payload,
-		Signature: hex.EncodeToString(signature),
+		return nil, xerrors.Errorf("failed to sign request: %v", err)
 	}
 
 	signedJSON, err := json.Marshal(signed)
@@ -382,3 +400,8 @@ func createSignedRequest(secret kyber.Scalar, msg interface{}) ([]byte, error) {
 
 	return signedJSON, nil
 }
+
+// publicKeys returns a key ring with the public key.
+func publicKeys(public kyber.Point) *types.KeyRing {
+	return types.NewKeyRing(types.ProxyKey{Name: "default", Key: public})
+}
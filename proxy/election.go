@@ -1,15 +1,19 @@
 package proxy
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"sync"
 
 	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/export"
+	"github.com/dedis/d-voting/contracts/evoting/record"
 	"github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/proxy/txnmanager"
 	ptypes "github.com/dedis/d-voting/proxy/types"
@@ -19,10 +23,26 @@ import (
 	"go.dedis.ch/dela/core/ordering"
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/serde"
-	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
 
+const (
+	// defaultResultsLimit is the number of decrypted ballots returned when no
+	// limit is given.
+	defaultResultsLimit = 100
+	// maxResultsLimit is the maximum number of decrypted ballots returned at
+	// once.
+	maxResultsLimit = 1000
+	// defaultVotersLimit is the number of voters returned when no limit is
+	// given.
+	defaultVotersLimit = 100
+	// maxVotersLimit is the maximum number of voters returned at once.
+	maxVotersLimit = 1000
+	// maxFormsLimit is the maximum number of forms returned at once when the
+	// listing is paginated.
+	maxFormsLimit = 1000
+)
+
 func newSignedErr(err error) error {
 	return xerrors.Errorf("failed to created signed request: %v", err)
 }
@@ -31,9 +51,22 @@ func getSignedErr(err error) error {
 	return xerrors.Errorf("failed to get and verify signed request: %v", err)
 }
 
+// signedRequestError sets the error of a signed request that can't be
+// verified, with the code of the failure.
+func signedRequestError(w http.ResponseWriter, r *http.Request, err error) {
+	code := ptypes.CodeInternal
+
+	var coded codedError
+	if xerrors.As(err, &coded) {
+		code = coded.code
+	}
+
+	ErrorResponse(w, r, code, getSignedErr(err), nil)
+}
+
 // NewForm returns a new initialized form proxy
 func NewForm(srv ordering.Service, p pool.Pool,
-	ctx serde.Context, fac serde.Factory, pk kyber.Point, txnManaxer txnmanager.Manager) Form {
+	ctx serde.Context, fac serde.Factory, keys *ptypes.KeyRing, txnManaxer txnmanager.Manager) Form {
 
 	logger := dela.Logger.With().Timestamp().Str("role", "evoting-proxy").Logger()
 
@@ -52,7 +85,7 @@ func NewForm(srv ordering.Service, p pool.Pool,
 		adminFac:    types.AdminListFactory{},
 		mngr:        txnManaxer,
 		pool:        p,
-		pk:          pk,
+		keys:        keys,
 		adminListID: adminListID,
 	}
 }
@@ -70,7 +103,7 @@ type form struct {
 	adminFac    serde.Factory
 	mngr        txnmanager.Manager
 	pool        pool.Pool
-	pk          kyber.Point
+	keys        *ptypes.KeyRing
 	adminListID string
 }
 
@@ -81,14 +114,14 @@ func (form *form) NewForm(w http.ResponseWriter, r *http.Request) {
 	// get the signed request
 	signed, err := ptypes.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// get the request and verify the signature
-	err = signed.GetAndVerify(form.pk, &req)
+	r, err = getAndVerify(signed, form.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
@@ -100,15 +133,14 @@ func (form *form) NewForm(w http.ResponseWriter, r *http.Request) {
 	// serialize the transaction
 	data, err := createForm.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal CreateFormTransaction: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal CreateFormTransaction: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
 	txnID, blockIdx, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCreateForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -120,7 +152,7 @@ func (form *form) NewForm(w http.ResponseWriter, r *http.Request) {
 	// create it to get the  token
 	transactionClientInfo, err := form.mngr.CreateTransactionResult(txnID, blockIdx, txnmanager.UnknownTransactionStatus)
 	if err != nil {
-		http.Error(w, "failed to create transaction info: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to create transaction info: %v", err), nil)
 		return
 	}
 
@@ -143,14 +175,14 @@ func (form *form) NewFormVote(w http.ResponseWriter, r *http.Request) {
 	// get the signed request
 	signed, err := ptypes.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// get the request and verify the signature
-	err = signed.GetAndVerify(form.pk, &req)
+	r, err = getAndVerify(signed, form.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
@@ -158,7 +190,7 @@ func (form *form) NewFormVote(w http.ResponseWriter, r *http.Request) {
 
 	// check if the formID is valid
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -166,34 +198,161 @@ func (form *form) NewFormVote(w http.ResponseWriter, r *http.Request) {
 
 	elecMD, err := form.getFormsMetadata()
 	if err != nil {
-		http.Error(w, "failed to get form metadata", http.StatusNotFound)
+		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
 		return
 	}
 
 	// check if the form exist
 	if elecMD.FormsIDs.Contains(formID) < 0 {
-		http.Error(w, "the form does not exist", http.StatusNotFound)
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("the form does not exist"), nil)
 		return
 	}
 
-	ciphervote := make(types.Ciphervote, len(req.Ballot))
+	ciphervote, err := decodeCiphervote(req.Ballot)
+	if err != nil {
+		ErrorResponse(w, r, ptypes.CodeInvalidBallot, xerrors.Errorf("invalid ballot: %v", err), nil)
+		return
+	}
 
-	// unmarshal the encrypted ballot
-	for i, egpair := range req.Ballot {
-		k := suite.Point()
+	castVote := types.CastVote{
+		FormID:    formID,
+		VoterID:   req.VoterID,
+		Ballot:    ciphervote,
+		Signature: req.Signature,
+		Nonce:     req.Nonce,
+	}
+
+	// serialize the vote
+	data, err := castVote.Serialize(form.context)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to marshal CastVoteTransaction: %v", err), nil)
+		return
+	}
+
+	// create the transaction and add it to the pool
+	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCastVote, evoting.FormArg, data)
+	if err != nil {
+		form.logger.Err(err).Msg("failed to submit txn")
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
+		return
+	}
+
+	// send the transaction's information
+	err = form.mngr.SendTransactionInfo(w, txnID, lastBlock, txnmanager.UnknownTransactionStatus)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("couldn't send transaction info: %v", err), nil)
+		return
+	}
+}
+
+// NewFormVotes implements proxy.Proxy. The votes are submitted in a single
+// transaction, and the result of each ballot is given with the status of the
+// transaction.
+func (form *form) NewFormVotes(w http.ResponseWriter, r *http.Request) {
+	var req ptypes.CastVotesRequest
+
+	// get the signed request
+	signed, err := ptypes.NewSignedRequest(r.Body)
+	if err != nil {
+		BadRequestError(w, r, newSignedErr(err), nil)
+		return
+	}
+
+	// get the request and verify the signature
+	r, err = getAndVerify(signed, form.keys, r, &req)
+	if err != nil {
+		signedRequestError(w, r, err)
+		return
+	}
+
+	if len(req.Votes) == 0 || len(req.Votes) > types.MaxBatchVotes {
+		BadRequestError(w, r, xerrors.Errorf("the batch has %d votes, expected between 1 and %d",
+			len(req.Votes), types.MaxBatchVotes), nil)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	// check if the formID is valid
+	if vars == nil || vars["formID"] == "" {
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	elecMD, err := form.getFormsMetadata()
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
+		return
+	}
 
-		err = k.UnmarshalBinary(egpair.K)
+	// check if the form exist
+	if elecMD.FormsIDs.Contains(formID) < 0 {
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("the form does not exist"), nil)
+		return
+	}
+
+	castVotes := types.CastVotes{
+		FormID: formID,
+		Votes:  make([]types.BatchVote, len(req.Votes)),
+	}
+
+	for i, vote := range req.Votes {
+		ciphervote, err := decodeCiphervote(vote.Ballot)
 		if err != nil {
-			http.Error(w, "failed to unmarshal K: "+err.Error(), http.StatusInternalServerError)
+			ErrorResponse(w, r, ptypes.CodeInvalidBallot, xerrors.Errorf("invalid ballot %d: %v", i, err), nil)
 			return
 		}
 
+		castVotes.Votes[i] = types.BatchVote{
+			VoterID:   vote.VoterID,
+			Ballot:    ciphervote,
+			Signature: vote.Signature,
+			Nonce:     vote.Nonce,
+		}
+	}
+
+	// serialize the votes
+	data, err := castVotes.Serialize(form.context)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to marshal CastVotesTransaction: %v", err), nil)
+		return
+	}
+
+	// create the transaction and add it to the pool
+	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCastVotes, evoting.FormArg, data)
+	if err != nil {
+		form.logger.Err(err).Msg("failed to submit txn")
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
+		return
+	}
+
+	// send the transaction's information
+	err = form.mngr.SendTransactionInfo(w, txnID, lastBlock, txnmanager.UnknownTransactionStatus)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("couldn't send transaction info: %v", err), nil)
+		return
+	}
+}
+
+// decodeCiphervote returns the encrypted ballot of its JSON representation.
+func decodeCiphervote(ballot ptypes.CiphervoteJSON) (types.Ciphervote, error) {
+	ciphervote := make(types.Ciphervote, len(ballot))
+
+	for i, egpair := range ballot {
+		k := suite.Point()
+
+		err := k.UnmarshalBinary(egpair.K)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to unmarshal K: %v", err)
+		}
+
 		c := suite.Point()
 
 		err = c.UnmarshalBinary(egpair.C)
 		if err != nil {
-			http.Error(w, "failed to unmarshal C: "+err.Error(), http.StatusInternalServerError)
-			return
+			return nil, xerrors.Errorf("failed to unmarshal C: %v", err)
 		}
 
 		ciphervote[i] = types.EGPair{
@@ -202,34 +361,100 @@ func (form *form) NewFormVote(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	castVote := types.CastVote{
-		FormID:  formID,
-		VoterID: req.VoterID,
-		Ballot:  ciphervote,
+	return ciphervote, nil
+}
+
+// RegisterVoterKey implements proxy.Proxy. The key is only checked by the
+// contract.
+func (form *form) RegisterVoterKey(w http.ResponseWriter, r *http.Request) {
+	var req ptypes.RegisterVoterKeyRequest
+
+	// get the signed request
+	signed, err := ptypes.NewSignedRequest(r.Body)
+	if err != nil {
+		BadRequestError(w, r, newSignedErr(err), nil)
+		return
 	}
 
-	// serialize the vote
-	data, err := castVote.Serialize(form.context)
+	// get the request and verify the signature
+	r, err = getAndVerify(signed, form.keys, r, &req)
+	if err != nil {
+		signedRequestError(w, r, err)
+		return
+	}
+
+	formID, hasFailed := form.extractAndRetrieveFormID(w, r)
+	if hasFailed {
+		return
+	}
+
+	registerVoterKey := types.RegisterVoterKey{
+		FormID:      formID,
+		VoterID:     req.VoterID,
+		PublicKey:   req.PublicKey,
+		Signature:   req.Signature,
+		Endorsement: req.Endorsement,
+	}
+
+	data, err := registerVoterKey.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal CastVoteTransaction: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal RegisterVoterKey: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
-	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCastVote, evoting.FormArg, data)
+	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdRegisterVoterKey, evoting.FormArg, data)
 	if err != nil {
-		form.logger.Err(err).Msg("failed to submit txn")
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
-	// send the transaction's information
-	err = form.mngr.SendTransactionInfo(w, txnID, lastBlock, txnmanager.UnknownTransactionStatus)
+	form.mngr.SendTransactionInfo(w, txnID, lastBlock, txnmanager.UnknownTransactionStatus)
+}
+
+// ValidateBallot implements proxy.Proxy. The request is not signed because
+// it never reaches the chain: the plaintext ballot is only checked against the
+// configuration of the stored form.
+func (form *form) ValidateBallot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" {
+		BadRequestError(w, r, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	var req ptypes.ValidateBallotRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to decode request: %v", err), nil)
+		return
+	}
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
 	if err != nil {
-		http.Error(w, "couldn't send transaction info: "+err.Error(), http.StatusInternalServerError)
+		NotFoundErr(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
 		return
 	}
+
+	var ballot types.Ballot
+
+	ballotErrors := ballot.Validate(req.Ballot, formFromStore.Configuration, formFromStore.BallotSize)
+	if ballotErrors == nil {
+		ballotErrors = []types.BallotError{}
+	}
+
+	response := ptypes.ValidateBallotResponse{
+		Valid:  len(ballotErrors) == 0,
+		Errors: ballotErrors,
+	}
+
+	txnmanager.SendResponse(w, response)
 }
 
 // EditForm implements proxy.Proxy
@@ -239,14 +464,14 @@ func (form *form) EditForm(w http.ResponseWriter, r *http.Request) {
 	// get the signed request
 	signed, err := ptypes.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// get the request and verify the signature
-	err = signed.GetAndVerify(form.pk, &req)
+	r, err = getAndVerify(signed, form.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
@@ -254,7 +479,7 @@ func (form *form) EditForm(w http.ResponseWriter, r *http.Request) {
 
 	// check if the formID is valid
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -262,13 +487,13 @@ func (form *form) EditForm(w http.ResponseWriter, r *http.Request) {
 
 	elecMD, err := form.getFormsMetadata()
 	if err != nil {
-		http.Error(w, "failed to get form metadata", http.StatusNotFound)
+		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
 		return
 	}
 
 	// check if the form exists
 	if elecMD.FormsIDs.Contains(formID) < 0 {
-		http.Error(w, "the form does not exist", http.StatusNotFound)
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("the form does not exist"), nil)
 		return
 	}
 
@@ -298,15 +523,14 @@ func (form *form) openForm(formID string, userID string, w http.ResponseWriter,
 	// serialize the transaction
 	data, err := openForm.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal OpenFormTransaction: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal OpenFormTransaction: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdOpenForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -325,15 +549,14 @@ func (form *form) closeForm(formIDHex string, userID string, w http.ResponseWrit
 	// serialize the transaction
 	data, err := closeForm.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal CloseFormTransaction: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal CloseFormTransaction: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCloseForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -347,13 +570,12 @@ func (form *form) combineShares(formIDHex string, userID string, w http.Response
 
 	formFromStore, err := types.FormFromStore(form.context, form.formFac, formIDHex, form.orderingSvc.GetStore())
 	if err != nil {
-		http.Error(w, "failed to get form: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
 		return
 	}
 	if formFromStore.Status != types.PubSharesSubmitted {
-		http.Error(w, "the submission of public shares must be over!",
-			http.StatusUnauthorized)
+		ErrorResponse(w, r, ptypes.CodeInvalidStatus,
+			xerrors.New("the submission of public shares must be over"), nil)
 		return
 	}
 
@@ -365,15 +587,14 @@ func (form *form) combineShares(formIDHex string, userID string, w http.Response
 	// serialize the transaction
 	data, err := decryptBallots.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal decryptBallots: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal decryptBallots: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCombineShares, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -392,15 +613,14 @@ func (form *form) cancelForm(formIDHex string, userID string, w http.ResponseWri
 	// serialize the transaction
 	data, err := cancelForm.Serialize(form.context)
 	if err != nil {
-		http.Error(w, "failed to marshal CancelForm: "+err.Error(),
-			http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to marshal CancelForm: %v", err), nil)
 		return
 	}
 
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdCancelForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -418,7 +638,7 @@ func (form *form) Form(w http.ResponseWriter, r *http.Request) {
 
 	// check if the form exists
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -427,7 +647,7 @@ func (form *form) Form(w http.ResponseWriter, r *http.Request) {
 	// get the form
 	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
 	if err != nil {
-		http.Error(w, xerrors.Errorf("failed to get form: %v", err).Error(), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("failed to get form: %v", err), nil)
 		return
 	}
 
@@ -437,8 +657,7 @@ func (form *form) Form(w http.ResponseWriter, r *http.Request) {
 	if formFromStore.Pubkey != nil {
 		pubkeyBuf, err = formFromStore.Pubkey.MarshalBinary()
 		if err != nil {
-			http.Error(w, "failed to marshal pubkey: "+err.Error(),
-				http.StatusInternalServerError)
+			InternalError(w, r, xerrors.Errorf("failed to marshal pubkey: %v", err), nil)
 			return
 		}
 	}
@@ -450,11 +669,9 @@ func (form *form) Form(w http.ResponseWriter, r *http.Request) {
 		roster = append(roster, iter.GetNext().String())
 	}
 
-	suff, err := formFromStore.Suffragia(form.context, form.orderingSvc.GetStore())
-	if err != nil {
-		http.Error(w, "couldn't get ballots: "+err.Error(),
-			http.StatusInternalServerError)
-		return
+	suffragiaHashes := make([]string, len(formFromStore.SuffragiaHashes))
+	for i, hash := range formFromStore.SuffragiaHashes {
+		suffragiaHashes[i] = hex.EncodeToString(hash)
 	}
 
 	response := ptypes.GetFormResponse{
@@ -462,76 +679,577 @@ func (form *form) Form(w http.ResponseWriter, r *http.Request) {
 		Configuration:   formFromStore.Configuration,
 		Status:          uint16(formFromStore.Status),
 		Pubkey:          hex.EncodeToString(pubkeyBuf),
-		Result:          formFromStore.DecryptedBallots,
 		Roster:          roster,
 		ChunksPerBallot: formFromStore.ChunksPerBallot(),
 		BallotSize:      formFromStore.BallotSize,
-		Voters:          suff.VoterIDs,
+		ResultsCount:    formFromStore.ResultsCount,
+		VoterCount:      formFromStore.VoterCount,
+		SuffragiaHashes: suffragiaHashes,
+		BallotsRoot:     hex.EncodeToString(formFromStore.BallotsTree.Root),
+		Imported:        formFromStore.Imported,
 	}
 
 	txnmanager.SendResponse(w, response)
 
 }
 
-// Forms implements proxy.Proxy. The request should not be signed because it
-// is fecthing public data.
-func (form *form) Forms(w http.ResponseWriter, r *http.Request) {
+// Results implements proxy.Proxy. Without the "format" query parameter it
+// returns a page of decrypted ballots given by "offset" and "limit". Otherwise
+// it exports all the decrypted ballots in the given format: csv, json or blt.
+// The request should not be signed because it is fetching public data.
+func (form *form) Results(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Headers", "*")
 
-	elecMD, err := form.getFormsMetadata()
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" {
+		BadRequestError(w, r, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
 	if err != nil {
-		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
+		NotFoundErr(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+		return
+	}
+
+	if formFromStore.Status != types.ResultAvailable {
+		BadRequestError(w, r, xerrors.Errorf("the results are not available, "+
+			"current status: %d", formFromStore.Status), nil)
 		return
 	}
 
-	allFormsInfo := make([]ptypes.LightForm, len(elecMD.FormsIDs))
+	query := r.URL.Query()
 
-	// get the forms
-	for i, id := range elecMD.FormsIDs {
-		if id != form.adminListID {
-			form, err := types.FormFromStore(form.context, form.formFac, id, form.orderingSvc.GetStore())
-			if err != nil {
-				InternalError(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+	format := query.Get("format")
+	if format == "" {
+		form.resultsPage(formFromStore, w, r)
+		return
+	}
+
+	ballots, err := formFromStore.Results(form.context, form.orderingSvc.GetStore(), 0, 0)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get results: %v", err), nil)
+		return
+	}
+
+	results := export.NewResults(formID, formFromStore.Configuration, ballots)
+
+	switch format {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = results.WriteJSON(w)
+	case "csv":
+		table := query.Get("table")
+		if table == "" {
+			table = "ballots"
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", formID+"-"+table+".csv"))
+
+		switch table {
+		case "ballots":
+			err = results.WriteBallotsCSV(w)
+		case "counts":
+			err = results.WriteCountsCSV(w)
+		default:
+			BadRequestError(w, r, xerrors.Errorf("unknown table: %s", table), nil)
+			return
+		}
+	case "blt":
+		questionID := types.ID(query.Get("question"))
+
+		if questionID == "" {
+			rankQuestions := results.RankQuestions()
+			if len(rankQuestions) != 1 {
+				BadRequestError(w, r, xerrors.Errorf("the question must be "+
+					"given when the form has %d rank questions", len(rankQuestions)), nil)
 				return
 			}
 
-			var pubkeyBuf []byte
+			questionID = rankQuestions[0]
+		}
 
-			if form.Pubkey != nil {
-				pubkeyBuf, err = form.Pubkey.MarshalBinary()
-				if err != nil {
-					InternalError(w, r, xerrors.Errorf("failed to marshal pubkey: %v", err), nil)
-					return
-				}
-			}
+		seats := 1
 
-			info := ptypes.LightForm{
-				FormID: string(form.FormID),
-				Title:  form.Configuration.Title,
-				Status: uint16(form.Status),
-				Pubkey: hex.EncodeToString(pubkeyBuf),
+		if query.Get("seats") != "" {
+			seats, err = strconv.Atoi(query.Get("seats"))
+			if err != nil || seats <= 0 {
+				BadRequestError(w, r, xerrors.Errorf("invalid seats: %s",
+					query.Get("seats")), nil)
+				return
 			}
+		}
+
+		// write in a buffer first so that a wrong question can still be
+		// reported with a proper status code.
+		buf := new(bytes.Buffer)
 
-			allFormsInfo[i] = info
+		err = results.WriteBLT(buf, questionID, seats)
+		if err != nil {
+			BadRequestError(w, r, xerrors.Errorf("failed to export: %v", err), nil)
+			return
 		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", formID+".blt"))
+
+		_, err = buf.WriteTo(w)
+	default:
+		BadRequestError(w, r, xerrors.Errorf("unknown format: %s", format), nil)
+		return
+	}
+
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to export results: %v", err), nil)
+		return
 	}
+}
 
-	response := ptypes.GetFormsResponse{Forms: allFormsInfo}
+// resultsPage sends the decrypted ballots of the form between the "offset"
+// and "limit" query parameters. Only the batches of the page are read.
+func (form *form) resultsPage(formFromStore types.Form, w http.ResponseWriter, r *http.Request) {
+	offset, limit, err := pageQuery(r, defaultResultsLimit, maxResultsLimit)
+	if err != nil {
+		BadRequestError(w, r, err, nil)
+		return
+	}
+
+	ballots, err := formFromStore.Results(form.context, form.orderingSvc.GetStore(), offset, limit)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get results: %v", err), nil)
+		return
+	}
+
+	response := ptypes.GetResultsResponse{
+		FormID:  formFromStore.FormID,
+		Offset:  offset,
+		Limit:   limit,
+		Total:   int(formFromStore.ResultsCount),
+		Ballots: ballots,
+	}
 
 	txnmanager.SendResponse(w, response)
+}
 
+// Voters implements proxy.Proxy. It returns a page of the users who cast a
+// ballot, in the order of their first ballot, given by "offset" and "limit".
+// The request should not be signed because it is fetching public data.
+func (form *form) Voters(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" {
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, vars["formID"],
+		form.orderingSvc.GetStore())
+	if err != nil {
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("failed to get form: %v", err), nil)
+		return
+	}
+
+	offset, limit, err := pageQuery(r, defaultVotersLimit, maxVotersLimit)
+	if err != nil {
+		BadRequestError(w, r, err, nil)
+		return
+	}
+
+	voterIDs, err := formFromStore.VoterIDs(form.context, form.orderingSvc.GetStore(), offset, limit)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get voters: %v", err), nil)
+		return
+	}
+
+	response := ptypes.GetVotersResponse{
+		FormID:   formFromStore.FormID,
+		Offset:   offset,
+		Limit:    limit,
+		Total:    int(formFromStore.VoterCount),
+		VoterIDs: voterIDs,
+	}
+
+	txnmanager.SendResponse(w, response)
+}
+
+// pageQuery returns the "offset" and "limit" query parameters of a paginated
+// request, with the default limit if none is given.
+func pageQuery(r *http.Request, defaultLimit, maxLimit int) (int, int, error) {
+	query := r.URL.Query()
+
+	offset := 0
+	limit := defaultLimit
+
+	var err error
+
+	if query.Get("offset") != "" {
+		offset, err = strconv.Atoi(query.Get("offset"))
+		if err != nil || offset < 0 {
+			return 0, 0, xerrors.Errorf("invalid offset: %s", query.Get("offset"))
+		}
+	}
+
+	if query.Get("limit") != "" {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return 0, 0, xerrors.Errorf("invalid limit, must be between 1 and %d: %s",
+				maxLimit, query.Get("limit"))
+		}
+	}
+
+	return offset, limit, nil
+}
+
+// Record implements proxy.Proxy. It sends the election record of the form,
+// which contains everything needed to verify the election offline. The request
+// should not be signed because it is fetching public data.
+func (form *form) Record(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" {
+		BadRequestError(w, r, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+		return
+	}
+
+	rec, err := record.New(form.context, formFromStore, form.orderingSvc.GetStore())
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to create record: %v", err), nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", formID+"-record.json"))
+
+	err = rec.Write(w)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write record: %v", err), nil)
+		return
+	}
+}
+
+// BallotProof implements proxy.Proxy. It returns the proof that the ballot
+// with the given digest is in the tree of the ballots cast, which a voter can
+// check against the root of the tree kept by the form. The request should not
+// be signed because it is fetching public data.
+func (form *form) BallotProof(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" || vars["digest"] == "" {
+		BadRequestError(w, r, xerrors.Errorf("formID or digest not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	digest, err := hex.DecodeString(vars["digest"])
+	if err != nil {
+		BadRequestError(w, r, xerrors.Errorf("failed to decode digest: %v", err), nil)
+		return
+	}
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+		return
+	}
+
+	digests, err := formFromStore.BallotDigests(form.context, form.orderingSvc.GetStore())
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get ballots digests: %v", err), nil)
+		return
+	}
+
+	index := -1
+
+	for i, d := range digests {
+		if bytes.Equal(d, digest) {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 {
+		NotFoundErr(w, r, xerrors.Errorf("ballot %x not found", digest), nil)
+		return
+	}
+
+	proof, err := types.InclusionProof(digests, index)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get proof: %v", err), nil)
+		return
+	}
+
+	hexProof := make([]string, len(proof))
+	for i, hash := range proof {
+		hexProof[i] = hex.EncodeToString(hash)
+	}
+
+	response := ptypes.GetBallotProofResponse{
+		FormID: formFromStore.FormID,
+		Digest: hex.EncodeToString(digest),
+		Index:  uint32(index),
+		Size:   formFromStore.BallotsTree.Size,
+		Root:   hex.EncodeToString(formFromStore.BallotsTree.CurrentRoot()),
+		Closed: formFromStore.BallotsTree.Root != nil,
+		Proof:  hexProof,
+	}
+
+	txnmanager.SendResponse(w, response)
+}
+
+// Certificate implements proxy.Proxy. It returns the collective signature of
+// the results with everything needed to verify it offline. The request should
+// not be signed because it is fetching public data.
+func (form *form) Certificate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	vars := mux.Vars(r)
+
+	if vars == nil || vars["formID"] == "" {
+		BadRequestError(w, r, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formID := vars["formID"]
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
+	if err != nil {
+		NotFoundErr(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+		return
+	}
+
+	if len(formFromStore.Certificate) == 0 {
+		NotFoundErr(w, r, xerrors.Errorf("the results of the form are not certified"), nil)
+		return
+	}
+
+	digest, err := formFromStore.ResultsDigest()
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to compute digest: %v", err), nil)
+		return
+	}
+
+	roster := make([]string, 0, formFromStore.Roster.Len())
+
+	addrIter := formFromStore.Roster.AddressIterator()
+	for addrIter.HasNext() {
+		roster = append(roster, addrIter.GetNext().String())
+	}
+
+	publicKeys := make([]string, 0, formFromStore.Roster.Len())
+
+	pkIter := formFromStore.Roster.PublicKeyIterator()
+	for pkIter.HasNext() {
+		pkBuf, err := pkIter.GetNext().MarshalBinary()
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to marshal public key: %v", err), nil)
+			return
+		}
+
+		publicKeys = append(publicKeys, hex.EncodeToString(pkBuf))
+	}
+
+	response := ptypes.GetCertificateResponse{
+		FormID:      formFromStore.FormID,
+		Digest:      hex.EncodeToString(digest),
+		Certificate: hex.EncodeToString(formFromStore.Certificate),
+		Roster:      roster,
+		PublicKeys:  publicKeys,
+	}
+
+	txnmanager.SendResponse(w, response)
+}
+
+// Forms implements proxy.Proxy. The forms can be filtered by "status" and by
+// "owner", and paginated with "cursor" and "limit". The summaries maintained by
+// the smart contract are used, so that the forms are not deserialized. The
+// request should not be signed because it is fecthing public data.
+func (form *form) Forms(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	query := r.URL.Query()
+
+	ids, err := form.filterForms(query)
+	if err != nil {
+		BadRequestError(w, r, err, nil)
+		return
+	}
+
+	cursor := 0
+	limit := len(ids)
+
+	if query.Get("cursor") != "" {
+		cursor, err = strconv.Atoi(query.Get("cursor"))
+		if err != nil || cursor < 0 {
+			BadRequestError(w, r, xerrors.Errorf("invalid cursor: %s",
+				query.Get("cursor")), nil)
+			return
+		}
+	}
+
+	if query.Get("limit") != "" {
+		limit, err = strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 || limit > maxFormsLimit {
+			BadRequestError(w, r, xerrors.Errorf("invalid limit, must be "+
+				"between 1 and %d: %s", maxFormsLimit, query.Get("limit")), nil)
+			return
+		}
+	}
+
+	response := ptypes.GetFormsResponse{Forms: []ptypes.LightForm{}}
+
+	if cursor < len(ids) {
+		end := len(ids)
+		if cursor+limit < end {
+			end = cursor + limit
+			response.NextCursor = strconv.Itoa(end)
+		}
+
+		ids = ids[cursor:end]
+	} else {
+		ids = nil
+	}
+
+	for _, id := range ids {
+		summary, err := form.formSummary(id)
+		if err != nil {
+			InternalError(w, r, xerrors.Errorf("failed to get form: %v", err), nil)
+			return
+		}
+
+		info := ptypes.LightForm{
+			FormID: summary.FormID,
+			Title:  summary.Title,
+			Status: uint16(summary.Status),
+			Pubkey: hex.EncodeToString(summary.Pubkey),
+		}
+
+		response.Forms = append(response.Forms, info)
+	}
+
+	txnmanager.SendResponse(w, response)
+}
+
+// filterForms returns the IDs of the forms matching the "status" and "owner"
+// query parameters, in the order of the indexes. Without filter, all the forms
+// are returned in the order they were created.
+func (form *form) filterForms(query url.Values) (types.FormIDs, error) {
+	store := form.orderingSvc.GetStore()
+
+	var filters []types.FormIDs
+
+	if query.Get("status") != "" {
+		status, err := strconv.ParseUint(query.Get("status"), 10, 16)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid status: %s", query.Get("status"))
+		}
+
+		ids, err := types.FormIndexFromStore(store, types.StatusIndexKey(types.Status(status)))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get status index: %v", err)
+		}
+
+		filters = append(filters, ids)
+	}
+
+	if query.Get("owner") != "" {
+		owner, err := types.SciperToInt(query.Get("owner"))
+		if err != nil {
+			return nil, xerrors.Errorf("invalid owner: %v", err)
+		}
+
+		ids, err := types.FormIndexFromStore(store, types.OwnerIndexKey(owner))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get owner index: %v", err)
+		}
+
+		filters = append(filters, ids)
+	}
+
+	if len(filters) == 0 {
+		elecMD, err := form.getFormsMetadata()
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get form metadata: %v", err)
+		}
+
+		ids := types.FormIDs{}
+
+		for _, id := range elecMD.FormsIDs {
+			if id != form.adminListID {
+				ids = append(ids, id)
+			}
+		}
+
+		return ids, nil
+	}
+
+	ids := types.FormIDs{}
+
+	for _, id := range filters[0] {
+		if len(filters) == 1 || filters[1].Contains(id) >= 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// formSummary returns the summary of a form kept by the smart contract, or
+// computes it from the form if it has none.
+func (form *form) formSummary(formID string) (types.FormSummary, error) {
+	summary, found, err := types.FormSummaryFromStore(form.orderingSvc.GetStore(), formID)
+	if err != nil {
+		return summary, xerrors.Errorf("failed to get summary: %v", err)
+	}
+
+	if found {
+		return summary, nil
+	}
+
+	formFromStore, err := types.FormFromStore(form.context, form.formFac, formID, form.orderingSvc.GetStore())
+	if err != nil {
+		return summary, xerrors.Errorf("failed to get form: %v", err)
+	}
+
+	return types.NewFormSummary(formFromStore)
 }
 
 // DeleteForm implements proxy.Proxy
 func (form *form) DeleteForm(w http.ResponseWriter, r *http.Request) {
-	var req ptypes.UpdateFormRequest
+	var req ptypes.DeleteFormRequest
 
 	vars := mux.Vars(r)
 
 	// check if the formID is valid
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -539,26 +1257,26 @@ func (form *form) DeleteForm(w http.ResponseWriter, r *http.Request) {
 
 	elecMD, err := form.getFormsMetadata()
 	if err != nil {
-		http.Error(w, "failed to get form metadata", http.StatusNotFound)
+		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
 		return
 	}
 
 	// check if the form exists
 	if elecMD.FormsIDs.Contains(formID) < 0 {
-		http.Error(w, "the form does not exist", http.StatusNotFound)
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("the form does not exist"), nil)
 		return
 	}
 
 	// get the signed request
 	signed, err := ptypes.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
-	err = signed.GetAndVerify(form.pk, &req)
+	r, err = getAndVerify(signed, form.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
@@ -576,7 +1294,7 @@ func (form *form) DeleteForm(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdDeleteForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -586,7 +1304,7 @@ func (form *form) DeleteForm(w http.ResponseWriter, r *http.Request) {
 
 // POST /addtoadminlist
 func (form *form) AddAdmin(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -605,7 +1323,7 @@ func (form *form) AddAdmin(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdAddAdmin, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -615,7 +1333,7 @@ func (form *form) AddAdmin(w http.ResponseWriter, r *http.Request) {
 
 // POST /removetoadminlist
 func (form *form) RemoveAdmin(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -634,7 +1352,7 @@ func (form *form) RemoveAdmin(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdRemoveAdmin, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -663,7 +1381,7 @@ func (form *form) AdminList(w http.ResponseWriter, r *http.Request) {
 
 // POST /forms/{formID}/addowner
 func (form *form) AddOwnerToForm(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -688,7 +1406,7 @@ func (form *form) AddOwnerToForm(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdAddOwnerForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -697,7 +1415,7 @@ func (form *form) AddOwnerToForm(w http.ResponseWriter, r *http.Request) {
 
 // POST /forms/{formID}/removeowner
 func (form *form) RemoveOwnerToForm(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -722,7 +1440,7 @@ func (form *form) RemoveOwnerToForm(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdRemoveOwnerForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -731,7 +1449,7 @@ func (form *form) RemoveOwnerToForm(w http.ResponseWriter, r *http.Request) {
 
 // POST /forms/{formID}/addvoter
 func (form *form) AddVoterToForm(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -756,7 +1474,7 @@ func (form *form) AddVoterToForm(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdAddVoterForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -765,7 +1483,7 @@ func (form *form) AddVoterToForm(w http.ResponseWriter, r *http.Request) {
 
 // POST /forms/{formID}/removevoter
 func (form *form) RemoveVoterToForm(w http.ResponseWriter, r *http.Request) {
-	req, err := form.getPermissionOpRequest(w, r)
+	req, r, err := form.getPermissionOpRequest(w, r)
 	if err != nil {
 		return
 	}
@@ -790,7 +1508,7 @@ func (form *form) RemoveVoterToForm(w http.ResponseWriter, r *http.Request) {
 	// create the transaction and add it to the pool
 	txnID, lastBlock, err := form.mngr.SubmitTxn(r.Context(), evoting.CmdRemoveVoterForm, evoting.FormArg, data)
 	if err != nil {
-		http.Error(w, "failed to submit txn: "+err.Error(), http.StatusInternalServerError)
+		InternalError(w, r, xerrors.Errorf("failed to submit txn: %v", err), nil)
 		return
 	}
 
@@ -820,23 +1538,25 @@ func (form *form) getFormsMetadata() (types.FormsMetadata, error) {
 	return md, nil
 }
 
-func (form *form) getPermissionOpRequest(w http.ResponseWriter, r *http.Request) (ptypes.PermissionOperationRequest, error) {
+// getPermissionOpRequest returns the verified permission request and the
+// request with the name of the key that signed it in its context.
+func (form *form) getPermissionOpRequest(w http.ResponseWriter, r *http.Request) (ptypes.PermissionOperationRequest, *http.Request, error) {
 	var req ptypes.PermissionOperationRequest
 
 	// get the signed request
 	signed, err := ptypes.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
-		return ptypes.PermissionOperationRequest{}, err
+		BadRequestError(w, r, newSignedErr(err), nil)
+		return ptypes.PermissionOperationRequest{}, r, err
 	}
 
 	// get the request and verify the signature
-	err = signed.GetAndVerify(form.pk, &req)
+	r, err = getAndVerify(signed, form.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
-		return ptypes.PermissionOperationRequest{}, err
+		signedRequestError(w, r, err)
+		return ptypes.PermissionOperationRequest{}, r, err
 	}
-	return req, err
+	return req, r, err
 }
 
 func (form *form) extractAndRetrieveFormID(w http.ResponseWriter, r *http.Request) (string, bool) {
@@ -844,7 +1564,7 @@ func (form *form) extractAndRetrieveFormID(w http.ResponseWriter, r *http.Reques
 
 	// check if the formID is valid
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, ptypes.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return "", true
 	}
 
@@ -852,13 +1572,13 @@ func (form *form) extractAndRetrieveFormID(w http.ResponseWriter, r *http.Reques
 
 	elecMD, err := form.getFormsMetadata()
 	if err != nil {
-		http.Error(w, "failed to get form metadata", http.StatusNotFound)
+		InternalError(w, r, xerrors.Errorf("failed to get form metadata: %v", err), nil)
 		return "", true
 	}
 
 	// check if the form exists
 	if elecMD.FormsIDs.Contains(formID) < 0 {
-		http.Error(w, "the form does not exist", http.StatusNotFound)
+		ErrorResponse(w, r, ptypes.CodeFormNotFound, xerrors.Errorf("the form does not exist"), nil)
 		return "", true
 	}
 	return formID, false
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	sjson "go.dedis.ch/dela/serde/json"
+)
+
+func TestForm_Voters(t *testing.T) {
+	ctx := sjson.NewContext()
+	formID := "deadbeef"
+
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	form := etypes.Form{FormID: formID, Roster: roster, Status: etypes.Open}
+	service := fake.NewService(formID, form, ctx)
+
+	for _, voterID := range []string{"a", "b", "a", "c"} {
+		err := form.CastVote(ctx, service.BallotSnap, voterID, etypes.Ciphervote{})
+		require.NoError(t, err)
+	}
+
+	service.Forms[formID] = form
+
+	h := NewForm(&service, nil, ctx, etypes.NewFormFactory(etypes.CiphervoteFactory{},
+		fake.NewRosterFac(roster)), nil, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/evoting/forms/"+formID+"/voters"+query, nil)
+		r = mux.SetURLVars(r, map[string]string{"formID": formID})
+
+		h.Voters(w, r)
+
+		return w
+	}
+
+	w := get("?offset=1&limit=5")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res ptypes.GetVotersResponse
+
+	err := json.NewDecoder(w.Body).Decode(&res)
+	require.NoError(t, err)
+	require.Equal(t, ptypes.GetVotersResponse{
+		FormID:   formID,
+		Offset:   1,
+		Limit:    5,
+		Total:    3,
+		VoterIDs: []string{"b", "c"},
+	}, res)
+
+	w = get("?limit=0")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "invalid limit, must be between 1 and 1000: 0")
+
+	// the form only gives the number of voters
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/evoting/forms/"+formID, nil)
+	r = mux.SetURLVars(r, map[string]string{"formID": formID})
+
+	h.Form(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var fields map[string]json.RawMessage
+
+	err = json.Unmarshal(w.Body.Bytes(), &fields)
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage("3"), fields["VoterCount"])
+	require.NotContains(t, fields, "Voters")
+}
+
+func TestForm_NewFormVote_MalformedRequest(t *testing.T) {
+	h := NewForm(nil, nil, sjson.NewContext(), nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/evoting/forms/deadbeef/vote", strings.NewReader("{"))
+	r = mux.SetURLVars(r, map[string]string{"formID": "deadbeef"})
+
+	h.NewFormVote(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var res ptypes.HTTPError
+
+	err := json.NewDecoder(w.Body).Decode(&res)
+	require.NoError(t, err)
+	require.Equal(t, ptypes.CodeInvalidRequest, res.ErrorCode)
+	require.Equal(t, uint(http.StatusBadRequest), res.Code)
+}
@@ -0,0 +1,478 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/rs/zerolog"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+// keepAliveInterval is the interval between the comments sent on an idle
+// stream, so that the proxies in between don't close it.
+const keepAliveInterval = 15 * time.Second
+
+// NewEvents returns a new initialized events proxy
+func NewEvents(srv ordering.Service, ctx serde.Context, formFac serde.Factory) Events {
+	logger := dela.Logger.With().Timestamp().Str("role", "evoting-events").Logger()
+
+	return events{
+		logger:      logger,
+		orderingSvc: srv,
+		context:     ctx,
+		formFac:     formFac,
+		txFac:       types.NewTransactionFactory(types.CiphervoteFactory{}),
+	}
+}
+
+// events defines the HTTP handler streaming the events of the evoting smart
+// contract
+//
+// - implements proxy.Events
+type events struct {
+	logger      zerolog.Logger
+	orderingSvc ordering.Service
+	context     serde.Context
+	formFac     serde.Factory
+	txFac       serde.Factory
+}
+
+// sseEvent is an event of a stream with its name.
+type sseEvent struct {
+	name string
+	data interface{}
+}
+
+// eventStream holds the forms a stream is filtered on and the last status of
+// the forms it has seen.
+type eventStream struct {
+	formIDs  map[string]bool
+	statuses map[string]types.Status
+}
+
+func newEventStream(formIDs []string) *eventStream {
+	stream := &eventStream{
+		formIDs:  make(map[string]bool),
+		statuses: make(map[string]types.Status),
+	}
+
+	for _, formID := range formIDs {
+		stream.formIDs[formID] = true
+	}
+
+	return stream
+}
+
+// wants returns true if the events of the form are sent on the stream.
+func (s *eventStream) wants(formID string) bool {
+	return len(s.formIDs) == 0 || s.formIDs[formID]
+}
+
+// statusEvent returns the event of the status of the form, if the stream has
+// not seen the form yet or if its status changed.
+func (s *eventStream) statusEvent(block uint64, formID string,
+	status types.Status) (sseEvent, bool) {
+
+	previous, known := s.statuses[formID]
+	if known && previous == status {
+		return sseEvent{}, false
+	}
+
+	s.statuses[formID] = status
+
+	event := ptypes.StatusEvent{
+		Block:  block,
+		FormID: formID,
+		Status: uint16(status),
+	}
+
+	if known {
+		status := uint16(previous)
+		event.Previous = &status
+	}
+
+	return sseEvent{name: ptypes.StatusEventName, data: event}, true
+}
+
+// Events implements proxy.Events. It streams the events of the forms given by
+// the "formID" query parameters, or of all the forms, until the client goes
+// away.
+func (e events) Events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		InternalError(w, r, xerrors.Errorf("streaming is not supported"), nil)
+		return
+	}
+
+	formIDs := r.URL.Query()["formID"]
+
+	for _, formID := range formIDs {
+		_, err := hex.DecodeString(formID)
+		if err != nil {
+			BadRequestError(w, r, xerrors.Errorf("invalid form ID %q: %v", formID, err), nil)
+			return
+		}
+	}
+
+	stream := newEventStream(formIDs)
+
+	blocks := e.orderingSvc.Watch(r.Context())
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+
+	// The current status of the forms of the filter is sent first.
+	for _, formID := range formIDs {
+		form, err := types.FormFromStore(e.context, e.formFac, formID, e.orderingSvc.GetStore())
+		if err != nil {
+			continue
+		}
+
+		event, ok := stream.statusEvent(0, formID, form.Status)
+		if ok {
+			e.write(w, event)
+		}
+	}
+
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case block, ok := <-blocks:
+			if !ok {
+				return
+			}
+
+			for _, event := range e.blockEvents(stream, block) {
+				e.write(w, event)
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+// write writes the event in the format of the server-sent events.
+func (e events) write(w http.ResponseWriter, event sseEvent) {
+	data, err := json.Marshal(event.data)
+	if err != nil {
+		e.logger.Err(err).Msg("failed to marshal event")
+		return
+	}
+
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, data)
+}
+
+// acceptedTx is an accepted transaction of the evoting contract.
+type acceptedTx struct {
+	formID string
+	msg    serde.Message
+}
+
+// blockEvents returns the events of the stream for the transactions of the
+// block. The events are derived from the accepted transactions, because the
+// store may already be at a later block. Only the data of the forms that
+// doesn't change with the blocks, like the shuffle threshold and the order of
+// the submitted shares, is read from the store.
+func (e events) blockEvents(stream *eventStream, block ordering.Event) []sseEvent {
+	events := []sseEvent{}
+	accepted := []acceptedTx{}
+
+	for _, res := range block.Transactions {
+		tx := res.GetTransaction()
+
+		if string(tx.GetArg(native.ContractArg)) != evoting.ContractName {
+			continue
+		}
+
+		formID := ""
+
+		msg, err := e.txFac.Deserialize(e.context, tx.GetArg(evoting.FormArg))
+		if err != nil {
+			e.logger.Warn().Err(err).Msg("failed to decode transaction")
+		} else {
+			formID = e.transactionFormID(tx, msg)
+		}
+
+		if !stream.wants(formID) {
+			continue
+		}
+
+		ok, reason := res.GetStatus()
+
+		events = append(events, sseEvent{
+			name: ptypes.TransactionEventName,
+			data: ptypes.TransactionEvent{
+				Block:         block.Index,
+				TransactionID: hex.EncodeToString(tx.GetID()),
+				FormID:        formID,
+				Command:       string(tx.GetArg(evoting.CmdArg)),
+				Accepted:      ok,
+				Reason:        reason,
+				ProxyKey:      string(tx.GetArg(evoting.ProxyKeyArg)),
+			},
+		})
+
+		if ok && formID != "" {
+			accepted = append(accepted, acceptedTx{formID: formID, msg: msg})
+		}
+	}
+
+	stored := make(map[string]*types.Form)
+
+	// storedForm returns the form from the store, or nil if it has been
+	// deleted since.
+	storedForm := func(formID string) *types.Form {
+		form, found := stored[formID]
+		if found {
+			return form
+		}
+
+		read, err := types.FormFromStore(e.context, e.formFac, formID, e.orderingSvc.GetStore())
+		if err == nil {
+			form = &read
+		}
+
+		stored[formID] = form
+
+		return form
+	}
+
+	// the forms in the order of their first transaction in the block
+	formIDs := []string{}
+	seen := make(map[string]bool)
+	statuses := make(map[string]types.Status)
+	deleted := make(map[string]bool)
+	cast := make(map[string]int)
+	ballots := []string{}
+
+	for _, tx := range accepted {
+		if !seen[tx.formID] {
+			seen[tx.formID] = true
+			formIDs = append(formIDs, tx.formID)
+		}
+
+		status, known := e.transactionStatus(tx.msg)
+
+		switch msg := tx.msg.(type) {
+		case types.CastVote, types.CastVotes:
+			if cast[tx.formID] == 0 {
+				ballots = append(ballots, tx.formID)
+			}
+
+			votes, ok := msg.(types.CastVotes)
+			if ok {
+				cast[tx.formID] += len(votes.Votes)
+			} else {
+				cast[tx.formID]++
+			}
+		case types.ShuffleBallots:
+			form := storedForm(tx.formID)
+			if form == nil {
+				break
+			}
+
+			// a shuffle is accepted only for the next round
+			count := msg.Round + 1
+
+			status, known = types.Closed, true
+			if count >= form.ShuffleThreshold {
+				status = types.ShuffledBallots
+			}
+
+			events = append(events, sseEvent{
+				name: ptypes.ShuffleEventName,
+				data: ptypes.ShuffleEvent{
+					Block:            block.Index,
+					FormID:           tx.formID,
+					Round:            msg.Round,
+					ShuffleCount:     count,
+					ShuffleThreshold: form.ShuffleThreshold,
+				},
+			})
+		case types.RegisterPubShares:
+			form := storedForm(tx.formID)
+			if form == nil {
+				break
+			}
+
+			// the shares are appended in the order of their submission
+			submitted := 0
+
+			for i, index := range form.PubsharesUnits.Indexes {
+				if index == msg.Index {
+					submitted = i + 1
+					break
+				}
+			}
+
+			if submitted == 0 {
+				break
+			}
+
+			status, known = types.ShuffledBallots, true
+			if submitted >= form.ShuffleThreshold {
+				status = types.PubSharesSubmitted
+			}
+
+			events = append(events, sseEvent{
+				name: ptypes.PubsharesEventName,
+				data: ptypes.PubsharesEvent{
+					Block:     block.Index,
+					FormID:    tx.formID,
+					Index:     msg.Index,
+					Submitted: submitted,
+				},
+			})
+		case types.DeleteForm:
+			delete(statuses, tx.formID)
+			deleted[tx.formID] = true
+		}
+
+		if known {
+			statuses[tx.formID] = status
+		}
+	}
+
+	for _, formID := range ballots {
+		events = append(events, sseEvent{
+			name: ptypes.BallotsEventName,
+			data: ptypes.BallotsEvent{
+				Block:  block.Index,
+				FormID: formID,
+				Cast:   cast[formID],
+			},
+		})
+	}
+
+	for _, formID := range formIDs {
+		if deleted[formID] {
+			delete(stream.statuses, formID)
+			continue
+		}
+
+		status, known := statuses[formID]
+		if !known {
+			continue
+		}
+
+		event, ok := stream.statusEvent(block.Index, formID, status)
+		if ok {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// transactionStatus returns the status of the form after the transaction, if
+// the transaction alone tells it. The status after a shuffle or a submission
+// of shares depends on the form and is not returned.
+func (e events) transactionStatus(msg serde.Message) (types.Status, bool) {
+	switch msg := msg.(type) {
+	case types.CreateForm:
+		return types.Initial, true
+	case types.ImportForm:
+		imported, err := e.formFac.Deserialize(e.context, msg.Form)
+		if err != nil {
+			return 0, false
+		}
+
+		form, ok := imported.(types.Form)
+		if !ok {
+			return 0, false
+		}
+
+		return form.Status, true
+	case types.OpenForm, types.CastVote, types.CastVotes:
+		return types.Open, true
+	case types.CloseForm:
+		return types.Closed, true
+	case types.CombineShares, types.SubmitCertificate:
+		return types.ResultAvailable, true
+	case types.CancelForm:
+		return types.Canceled, true
+	}
+
+	return 0, false
+}
+
+// transactionFormID returns the hex-encoded ID of the form of the
+// transaction, or an empty string if it doesn't concern a form.
+func (e events) transactionFormID(tx txn.Transaction, msg serde.Message) string {
+	switch msg := msg.(type) {
+	case types.CreateForm:
+		// the ID of a new form is the hash of the transaction ID
+		h := sha256.Sum256(tx.GetID())
+		return hex.EncodeToString(h[:])
+	case types.ImportForm:
+		imported, err := e.formFac.Deserialize(e.context, msg.Form)
+		if err != nil {
+			return ""
+		}
+
+		form, ok := imported.(types.Form)
+		if !ok {
+			return ""
+		}
+
+		return form.FormID
+	case types.OpenForm:
+		return msg.FormID
+	case types.CastVote:
+		return msg.FormID
+	case types.CastVotes:
+		return msg.FormID
+	case types.CloseForm:
+		return msg.FormID
+	case types.ShuffleBallots:
+		return msg.FormID
+	case types.RegisterPubShares:
+		return msg.FormID
+	case types.CombineShares:
+		return msg.FormID
+	case types.SubmitCertificate:
+		return msg.FormID
+	case types.CancelForm:
+		return msg.FormID
+	case types.DeleteForm:
+		return msg.FormID
+	case types.AddOwner:
+		return msg.FormID
+	case types.RemoveOwner:
+		return msg.FormID
+	case types.AddVoter:
+		return msg.FormID
+	case types.RemoveVoter:
+		return msg.FormID
+	case types.RepairForm:
+		return msg.FormID
+	case types.RegisterVoterKey:
+		return msg.FormID
+	}
+
+	return ""
+}
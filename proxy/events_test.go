@@ -0,0 +1,224 @@
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/core/validation/simple"
+	"go.dedis.ch/dela/serde"
+	sjson "go.dedis.ch/dela/serde/json"
+)
+
+func TestEvents_BlockEvents(t *testing.T) {
+	ctx := sjson.NewContext()
+	formID := "deadbeef"
+
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	form, err := fake.NewForm(ctx, fake.NewSnapshot(), formID)
+	require.NoError(t, err)
+	form.Roster = roster
+	form.Status = etypes.Open
+	form.BallotCount = 2
+
+	service := fake.NewService(formID, form, ctx)
+
+	ev := NewEvents(&service, ctx, etypes.NewFormFactory(etypes.CiphervoteFactory{},
+		fake.NewRosterFac(roster))).(events)
+
+	vote := etypes.CastVote{FormID: formID, VoterID: "user1", Ballot: etypes.Ciphervote{}}
+	admin := etypes.AddAdmin{TargetUserID: "123456", PerformingUserID: "654321"}
+	create := etypes.CreateForm{UserID: "123456"}
+
+	createTx := makeEventTx(t, ctx, evoting.CmdCreateForm, create, 3)
+	h := sha256.Sum256(createTx.GetTransaction().GetID())
+	createdID := hex.EncodeToString(h[:])
+
+	block := ordering.Event{
+		Index: 4,
+		Transactions: []validation.TransactionResult{
+			makeEventTx(t, ctx, evoting.CmdCastVote, vote, 0),
+			makeEventTx(t, ctx, evoting.CmdCastVote, vote, 1),
+			makeEventTx(t, ctx, evoting.CmdAddAdmin, admin, 2),
+			createTx,
+			simple.NewTransactionResult(fake.Transaction{Id: []byte{0xaa}}, true, ""),
+		},
+	}
+
+	stream := newEventStream(nil)
+
+	sent := ev.blockEvents(stream, block)
+	require.Len(t, sent, 7)
+	require.Equal(t, ptypes.TransactionEvent{
+		Block:         4,
+		TransactionID: hex.EncodeToString(block.Transactions[0].GetTransaction().GetID()),
+		FormID:        formID,
+		Command:       string(evoting.CmdCastVote),
+		Accepted:      true,
+	}, sent[0].data)
+	require.Equal(t, "", sent[2].data.(ptypes.TransactionEvent).FormID)
+	require.Equal(t, createdID, sent[3].data.(ptypes.TransactionEvent).FormID)
+	require.Equal(t, ptypes.BallotsEvent{Block: 4, FormID: formID, Cast: 2}, sent[4].data)
+	require.Equal(t, ptypes.StatusEventName, sent[5].name)
+	require.Equal(t, ptypes.StatusEvent{Block: 4, FormID: formID, Status: 1}, sent[5].data)
+	require.Equal(t, ptypes.StatusEvent{Block: 4, FormID: createdID, Status: 0}, sent[6].data)
+
+	// the status is sent again only when it changes, and it is derived from
+	// the transactions of the block even if the store is at a later block
+	form.Status = etypes.ResultAvailable
+	service.Forms[formID] = form
+
+	closeTx := makeEventTx(t, ctx, evoting.CmdCloseForm,
+		etypes.CloseForm{FormID: formID, UserID: "123456"}, 4)
+
+	sent = ev.blockEvents(stream, ordering.Event{
+		Index:        5,
+		Transactions: []validation.TransactionResult{closeTx},
+	})
+	require.Len(t, sent, 2)
+
+	previous := uint16(etypes.Open)
+	require.Equal(t, ptypes.StatusEvent{Block: 5, FormID: formID, Status: 2, Previous: &previous},
+		sent[1].data)
+
+	sent = ev.blockEvents(stream, ordering.Event{
+		Index:        6,
+		Transactions: []validation.TransactionResult{closeTx},
+	})
+	require.Len(t, sent, 1)
+
+	// a stream filtered on another form ignores the block
+	sent = ev.blockEvents(newEventStream([]string{"beef"}), block)
+	require.Empty(t, sent)
+
+	rejected := simple.NewTransactionResult(closeTx.GetTransaction(), false, "not an owner")
+
+	sent = ev.blockEvents(newEventStream([]string{formID}), ordering.Event{
+		Index:        7,
+		Transactions: []validation.TransactionResult{rejected},
+	})
+	require.Len(t, sent, 1)
+	require.False(t, sent[0].data.(ptypes.TransactionEvent).Accepted)
+	require.Equal(t, "not an owner", sent[0].data.(ptypes.TransactionEvent).Reason)
+
+	signedTx, err := signed.NewTransaction(5, fake.PublicKey{},
+		signed.WithArg(native.ContractArg, []byte(evoting.ContractName)),
+		signed.WithArg(evoting.CmdArg, []byte(evoting.CmdAddAdmin)),
+		signed.WithArg(evoting.FormArg, closeTx.GetTransaction().GetArg(evoting.FormArg)),
+		signed.WithArg(evoting.ProxyKeyArg, []byte("backend-2")))
+	require.NoError(t, err)
+
+	sent = ev.blockEvents(newEventStream(nil), ordering.Event{
+		Index:        8,
+		Transactions: []validation.TransactionResult{simple.NewTransactionResult(signedTx, false, "")},
+	})
+	require.Equal(t, "backend-2", sent[0].data.(ptypes.TransactionEvent).ProxyKey)
+}
+
+func TestEvents_BlockEvents_Shuffle(t *testing.T) {
+	ctx := sjson.NewContext()
+	formID := "deadbeef"
+
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	// the store is already at the end of the decryption
+	form, err := fake.NewForm(ctx, fake.NewSnapshot(), formID)
+	require.NoError(t, err)
+	form.Roster = roster
+	form.Status = etypes.PubSharesSubmitted
+	form.ShuffleThreshold = 2
+	form.PubsharesUnits.Indexes = []int{2, 0}
+
+	service := fake.NewService(formID, form, ctx)
+
+	ev := NewEvents(&service, ctx, etypes.NewFormFactory(etypes.CiphervoteFactory{},
+		fake.NewRosterFac(roster))).(events)
+
+	stream := newEventStream(nil)
+	stream.statuses[formID] = etypes.Closed
+
+	sent := ev.blockEvents(stream, ordering.Event{
+		Index: 10,
+		Transactions: []validation.TransactionResult{
+			makeEventTx(t, ctx, evoting.CmdShuffleBallots,
+				etypes.ShuffleBallots{FormID: formID, Round: 0}, 0),
+		},
+	})
+	require.Len(t, sent, 2)
+	require.Equal(t, ptypes.ShuffleEvent{Block: 10, FormID: formID, Round: 0, ShuffleCount: 1,
+		ShuffleThreshold: 2}, sent[1].data)
+
+	sent = ev.blockEvents(stream, ordering.Event{
+		Index: 11,
+		Transactions: []validation.TransactionResult{
+			makeEventTx(t, ctx, evoting.CmdShuffleBallots,
+				etypes.ShuffleBallots{FormID: formID, Round: 1}, 1),
+			makeEventTx(t, ctx, evoting.CmdRegisterPubShares,
+				etypes.RegisterPubShares{FormID: formID, Index: 2}, 2),
+		},
+	})
+	require.Len(t, sent, 5)
+	require.Equal(t, ptypes.ShuffleEvent{Block: 11, FormID: formID, Round: 1, ShuffleCount: 2,
+		ShuffleThreshold: 2}, sent[2].data)
+	require.Equal(t, ptypes.PubsharesEvent{Block: 11, FormID: formID, Index: 2, Submitted: 1},
+		sent[3].data)
+
+	previous := uint16(etypes.Closed)
+	require.Equal(t, ptypes.StatusEvent{Block: 11, FormID: formID,
+		Status: uint16(etypes.ShuffledBallots), Previous: &previous}, sent[4].data)
+
+	sent = ev.blockEvents(stream, ordering.Event{
+		Index: 12,
+		Transactions: []validation.TransactionResult{
+			makeEventTx(t, ctx, evoting.CmdRegisterPubShares,
+				etypes.RegisterPubShares{FormID: formID, Index: 0}, 3),
+		},
+	})
+	require.Len(t, sent, 3)
+	require.Equal(t, ptypes.PubsharesEvent{Block: 12, FormID: formID, Index: 0, Submitted: 2},
+		sent[1].data)
+
+	previous = uint16(etypes.ShuffledBallots)
+	require.Equal(t, ptypes.StatusEvent{Block: 12, FormID: formID,
+		Status: uint16(etypes.PubSharesSubmitted), Previous: &previous}, sent[2].data)
+}
+
+func TestEvents_InvalidFormID(t *testing.T) {
+	service := fake.NewService("deadbeef", etypes.Form{}, sjson.NewContext())
+
+	ev := NewEvents(&service, sjson.NewContext(), nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/evoting/events?formID=xx", nil)
+
+	ev.Events(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func makeEventTx(t *testing.T, ctx serde.Context, cmd evoting.Command, msg serde.Message,
+	nonce uint64) validation.TransactionResult {
+
+	data, err := msg.Serialize(ctx)
+	require.NoError(t, err)
+
+	tx, err := signed.NewTransaction(nonce, fake.PublicKey{},
+		signed.WithArg(native.ContractArg, []byte(evoting.ContractName)),
+		signed.WithArg(evoting.CmdArg, []byte(cmd)),
+		signed.WithArg(evoting.FormArg, data))
+	require.NoError(t, err)
+
+	return simple.NewTransactionResult(tx, true, "")
+}
@@ -0,0 +1,273 @@
+package proxy
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	dvoting "github.com/dedis/d-voting"
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/proxy/types"
+	dkgSrv "github.com/dedis/d-voting/services/dkg"
+	"github.com/dedis/d-voting/services/health"
+	shuffleSrv "github.com/dedis/d-voting/services/shuffle"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	"go.dedis.ch/dela/core/txn/pool"
+	"golang.org/x/xerrors"
+)
+
+// pingTimeout is the time given to the members of the roster to answer a ping.
+const pingTimeout = 2 * time.Second
+
+// pingInterval is the time during which the result of a ping of the roster is
+// reused, so that the health requests don't ping the roster each time.
+const pingInterval = 10 * time.Second
+
+// Names of the sets of handlers recorded in HandlerSets.
+const (
+	EvotingHandlers     = "evoting"
+	DKGHandlers         = "dkg"
+	ShuffleHandlers     = "shuffle"
+	CertificateHandlers = "certificate"
+)
+
+// requiredHandlers are the sets of handlers that must be registered for the
+// node to be ready.
+var requiredHandlers = []string{EvotingHandlers, DKGHandlers, ShuffleHandlers}
+
+// HandlerSets records the sets of handlers registered on the proxy. It is
+// injected on startup and each registerHandlers command adds its set.
+type HandlerSets struct {
+	sync.Mutex
+	names map[string]struct{}
+}
+
+// NewHandlerSets returns an empty record of the sets of handlers.
+func NewHandlerSets() *HandlerSets {
+	return &HandlerSets{
+		names: make(map[string]struct{}),
+	}
+}
+
+// Add records that the set of handlers is registered.
+func (s *HandlerSets) Add(name string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.names[name] = struct{}{}
+}
+
+// List returns the sorted names of the registered sets of handlers.
+func (s *HandlerSets) List() []string {
+	s.Lock()
+	defer s.Unlock()
+
+	names := make([]string, 0, len(s.names))
+	for name := range s.names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// contains returns true if the set of handlers is registered.
+func (s *HandlerSets) contains(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	_, found := s.names[name]
+	return found
+}
+
+// rosterService is the part of the ordering service that gives the roster.
+type rosterService interface {
+	ordering.Service
+	GetRoster() (authority.Authority, error)
+}
+
+// NewHealth returns the handlers of the health of the node.
+func NewHealth(srv ordering.Service, blocks blockstore.BlockStore, p pool.Pool, pinger *health.Pinger,
+	dkgService dkgSrv.DKG, shuffleActor shuffleSrv.Actor, handlers *HandlerSets) Health {
+
+	return nodeHealth{
+		orderingSvc:  srv,
+		blocks:       blocks,
+		pool:         p,
+		pinger:       pinger,
+		dkgService:   dkgService,
+		shuffleActor: shuffleActor,
+		handlers:     handlers,
+	}
+}
+
+// nodeHealth defines the HTTP handlers reporting the health of the node
+//
+// - implements proxy.Health
+type nodeHealth struct {
+	orderingSvc  ordering.Service
+	blocks       blockstore.BlockStore
+	pool         pool.Pool
+	pinger       *health.Pinger
+	dkgService   dkgSrv.DKG
+	shuffleActor shuffleSrv.Actor
+	handlers     *HandlerSets
+}
+
+// Health implements proxy.Health. It reports the state of the chain, the
+// reachability of the roster and the status of the services.
+func (h nodeHealth) Health(w http.ResponseWriter, r *http.Request) {
+	ready, reasons := h.ready()
+
+	response := types.HealthResponse{
+		Ready:        ready,
+		Reasons:      reasons,
+		Version:      dvoting.Version,
+		BuildTime:    dvoting.BuildTime,
+		Roster:       []types.RosterMember{},
+		PoolSize:     h.pool.Stats().TxCount,
+		DKG:          []types.DKGActorHealth{},
+		ShuffleActor: h.shuffleActor != nil,
+		Handlers:     h.handlers.List(),
+	}
+
+	last, err := h.blocks.Last()
+	if err == nil {
+		response.BlockIndex = last.GetBlock().GetIndex()
+	}
+
+	roster, err := h.getRoster()
+	if err == nil {
+		// the result is shared with the other requests, so it doesn't depend
+		// on the context of this one
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		errs := h.pinger.CachedPing(ctx, roster, pingInterval)
+		cancel()
+
+		iter := roster.AddressIterator()
+		for iter.HasNext() {
+			addr := iter.GetNext().String()
+			member := types.RosterMember{Address: addr, Reachable: errs[addr] == nil}
+
+			if errs[addr] != nil {
+				member.Error = errs[addr].Error()
+			}
+
+			response.Roster = append(response.Roster, member)
+		}
+	}
+
+	formIDs, err := etypes.FormIndexFromStore(h.orderingSvc.GetStore(), etypes.StatusIndexKey(etypes.Open))
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to get the open forms: %v", err), nil)
+		return
+	}
+
+	for _, formID := range formIDs {
+		response.DKG = append(response.DKG, h.dkgHealth(formID))
+	}
+
+	sendHealth(w, r, http.StatusOK, response)
+}
+
+// Ready implements proxy.Health. It answers 503 until the chain and the
+// handlers are set up.
+func (h nodeHealth) Ready(w http.ResponseWriter, r *http.Request) {
+	ready, reasons := h.ready()
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	sendHealth(w, r, status, types.ReadyResponse{Ready: ready, Reasons: reasons})
+}
+
+// ready returns true if the chain and the handlers are set up, otherwise the
+// reasons why they are not.
+func (h nodeHealth) ready() (bool, []string) {
+	var reasons []string
+
+	roster, err := h.getRoster()
+	if err != nil {
+		reasons = append(reasons, "the chain is not set up: "+err.Error())
+	} else if roster.Len() == 0 {
+		reasons = append(reasons, "the chain is not set up: the roster is empty")
+	}
+
+	_, err = h.blocks.Last()
+	if err != nil {
+		reasons = append(reasons, "the chain has no block: "+err.Error())
+	}
+
+	if h.shuffleActor == nil {
+		reasons = append(reasons, "the shuffle actor is not initialized")
+	}
+
+	for _, name := range requiredHandlers {
+		if !h.handlers.contains(name) {
+			reasons = append(reasons, "the "+name+" handlers are not registered")
+		}
+	}
+
+	return len(reasons) == 0, reasons
+}
+
+// getRoster returns the current roster of the chain.
+func (h nodeHealth) getRoster() (authority.Authority, error) {
+	srv, ok := h.orderingSvc.(rosterService)
+	if !ok {
+		return nil, xerrors.New("the ordering service has no roster")
+	}
+
+	roster, err := srv.GetRoster()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get roster: %v", err)
+	}
+
+	return roster, nil
+}
+
+// dkgHealth returns the status of the DKG actor of the form.
+func (h nodeHealth) dkgHealth(formID string) types.DKGActorHealth {
+	res := types.DKGActorHealth{FormID: formID, Status: -1}
+
+	formIDBuf, err := hex.DecodeString(formID)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	actor, found := h.dkgService.GetActor(formIDBuf)
+	if !found {
+		return res
+	}
+
+	status := actor.Status()
+	res.Status = int(status.Status)
+
+	if status.Err != nil {
+		res.Error = status.Err.Error()
+	}
+
+	return res
+}
+
+// sendHealth writes the response with the status.
+func sendHealth(w http.ResponseWriter, r *http.Request, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+	}
+}
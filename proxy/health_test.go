@@ -0,0 +1,86 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/dedis/d-voting/proxy/types"
+	shuffleSrv "github.com/dedis/d-voting/services/shuffle"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/ordering/cosipbft/blockstore"
+	otypes "go.dedis.ch/dela/core/ordering/cosipbft/types"
+	"golang.org/x/xerrors"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	handlers := NewHandlerSets()
+
+	h := NewHealth(&fake.Service{}, fakeBlocks{err: xerrors.New("empty")}, &fake.Pool{}, nil,
+		mockDKGService{}, nil, handlers)
+
+	w := httptest.NewRecorder()
+	h.Ready(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var res types.ReadyResponse
+
+	err := json.NewDecoder(w.Body).Decode(&res)
+	require.NoError(t, err)
+	require.False(t, res.Ready)
+	require.Equal(t, []string{
+		"the chain is not set up: the ordering service has no roster",
+		"the chain has no block: empty",
+		"the shuffle actor is not initialized",
+		"the evoting handlers are not registered",
+		"the dkg handlers are not registered",
+		"the shuffle handlers are not registered",
+	}, res.Reasons)
+
+	handlers.Add(ShuffleHandlers)
+	handlers.Add(EvotingHandlers)
+	handlers.Add(DKGHandlers)
+	require.Equal(t, []string{"dkg", "evoting", "shuffle"}, handlers.List())
+
+	roster := authority.FromAuthority(fake.NewAuthority(2, fake.NewSigner))
+
+	h = NewHealth(&fakeRosterService{roster: roster}, fakeBlocks{}, &fake.Pool{}, nil,
+		mockDKGService{}, fakeShuffleActor{}, handlers)
+
+	w = httptest.NewRecorder()
+	h.Ready(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"Ready":true}`, w.Body.String())
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// fakeRosterService is an ordering service with a roster
+type fakeRosterService struct {
+	fake.Service
+	roster authority.Authority
+}
+
+func (s *fakeRosterService) GetRoster() (authority.Authority, error) {
+	return s.roster, nil
+}
+
+// fakeBlocks is a block store whose last block is missing if err is set
+type fakeBlocks struct {
+	blockstore.BlockStore
+	err error
+}
+
+func (b fakeBlocks) Last() (otypes.BlockLink, error) {
+	return nil, b.err
+}
+
+type fakeShuffleActor struct {
+	shuffleSrv.Actor
+}
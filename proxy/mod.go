@@ -7,22 +7,49 @@
 package proxy
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/dedis/d-voting/proxy/txnmanager"
 	"github.com/dedis/d-voting/proxy/types"
+	"go.dedis.ch/dela"
 	"go.dedis.ch/kyber/v3/suites"
+	"golang.org/x/xerrors"
 )
 
 var suite = suites.MustFind("ed25519")
 
+// maxNonces is the number of nonces of signed requests kept by the proxy, which
+// is the number of signed requests it accepts within twice
+// types.MaxRequestAge.
+const maxNonces = 200000
+
+// nonces are the nonces of the signed requests accepted by all the handlers.
+var nonces = types.NewNonceCache(maxNonces)
+
 // Form defines the public HTTP API for the form smart contract
 type Form interface {
 	// POST /forms
 	NewForm(http.ResponseWriter, *http.Request)
 	// POST /forms/{formID}/vote
 	NewFormVote(http.ResponseWriter, *http.Request)
+	// POST /forms/{formID}/votes
+	NewFormVotes(http.ResponseWriter, *http.Request)
+	// POST /forms/{formID}/voterkeys
+	RegisterVoterKey(http.ResponseWriter, *http.Request)
+	// POST /forms/{formID}/ballots/validate
+	ValidateBallot(http.ResponseWriter, *http.Request)
+	// GET /forms/{formID}/results?offset=&limit=
+	// GET /forms/{formID}/results?format=csv|json|blt
+	Results(http.ResponseWriter, *http.Request)
+	// GET /forms/{formID}/voters?offset=&limit=
+	Voters(http.ResponseWriter, *http.Request)
+	// GET /forms/{formID}/certificate
+	Certificate(http.ResponseWriter, *http.Request)
+	// GET /forms/{formID}/record
+	Record(http.ResponseWriter, *http.Request)
+	// GET /forms/{formID}/ballots/{digest}/proof
+	BallotProof(http.ResponseWriter, *http.Request)
 	// PUT /forms/{formID}
 	EditForm(http.ResponseWriter, *http.Request)
 	// GET /forms
@@ -48,6 +75,13 @@ type Form interface {
 	RemoveVoterToForm(http.ResponseWriter, *http.Request)
 }
 
+// Events defines the public HTTP API streaming the events of the evoting smart
+// contract
+type Events interface {
+	// GET /evoting/events?formID=
+	Events(http.ResponseWriter, *http.Request)
+}
+
 // DKG defines the public HTTP API of the DKG service
 type DKG interface {
 	// POST /services/dkg
@@ -60,92 +94,96 @@ type DKG interface {
 
 // Shuffle defines the public HTTP API of the shuffling service
 type Shuffle interface {
+	// GET /services/shuffle/{formID}
+	ShuffleStatus(http.ResponseWriter, *http.Request)
 	// PUT /services/shuffle/{formID}
 	EditShuffle(http.ResponseWriter, *http.Request)
 }
 
+// Certificate defines the public HTTP API of the certificate service
+type Certificate interface {
+	// PUT /services/certificate/{formID}
+	EditCertificate(http.ResponseWriter, *http.Request)
+}
+
+// Health defines the public HTTP API of the health of the node
+type Health interface {
+	// GET /health
+	Health(http.ResponseWriter, *http.Request)
+	// GET /ready
+	Ready(http.ResponseWriter, *http.Request)
+}
+
 // NotFoundHandler defines a generic handler for 404
 func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
-	err := types.HTTPError{
-		Title:   "Not found",
-		Code:    http.StatusNotFound,
-		Message: "The requested endpoint was not found",
+	types.HTTPError{
+		Title:     "Not found",
+		Code:      http.StatusNotFound,
+		ErrorCode: types.CodeNotFound,
+		Message:   "The requested endpoint was not found",
 		Args: map[string]interface{}{
 			"url":    r.URL.String(),
 			"method": r.Method,
 		},
-	}
-
-	buf, _ := json.MarshalIndent(&err, "", "  ")
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(http.StatusNotFound)
-	fmt.Fprintln(w, string(buf))
+	}.Write(w)
 }
 
 // NotAllowedHandler degines a generic handler for 405
 func NotAllowedHandler(w http.ResponseWriter, r *http.Request) {
-	err := types.HTTPError{
-		Title:   "Not allowed",
-		Code:    http.StatusMethodNotAllowed,
-		Message: "The requested endpoint was not allowed",
+	types.HTTPError{
+		Title:     "Not allowed",
+		Code:      http.StatusMethodNotAllowed,
+		ErrorCode: types.CodeMethodNotAllowed,
+		Message:   "The requested endpoint was not allowed",
 		Args: map[string]interface{}{
 			"url":    r.URL.String(),
 			"method": r.Method,
 		},
-	}
-
-	buf, _ := json.MarshalIndent(&err, "", "  ")
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-	fmt.Fprintln(w, string(buf))
+	}.Write(w)
 }
 
 // InternalError sets an internal server error
 func InternalError(w http.ResponseWriter, r *http.Request, err error, args map[string]interface{}) {
-	httpErr(w, r, err, http.StatusInternalServerError, "Internal server error", args)
+	ErrorResponse(w, r, types.CodeInternal, err, args)
 }
 
 // BadRequestError sets an bad request error
 func BadRequestError(w http.ResponseWriter, r *http.Request, err error, args map[string]interface{}) {
-	httpErr(w, r, err, http.StatusBadRequest, "bad request", args)
+	ErrorResponse(w, r, types.CodeInvalidRequest, err, args)
 }
 
 // ForbiddenError sets a forbidden error error
 func ForbiddenError(w http.ResponseWriter, r *http.Request, err error, args map[string]interface{}) {
-	httpErr(w, r, err, http.StatusForbidden, "not authorized / forbidden", args)
+	ErrorResponse(w, r, types.CodeForbidden, err, args)
 }
 
 // NotFoundErr sets a not found error
 func NotFoundErr(w http.ResponseWriter, r *http.Request, err error, args map[string]interface{}) {
-	httpErr(w, r, err, http.StatusNotFound, "not found", args)
+	ErrorResponse(w, r, types.CodeNotFound, err, args)
 }
 
-func httpErr(w http.ResponseWriter, r *http.Request, err error, code uint, title string, args map[string]interface{}) {
-	if args == nil {
-		args = make(map[string]interface{})
-	}
-
-	args["error"] = err.Error()
-	args["url"] = r.URL.String()
-	args["method"] = r.Method
+// ErrorResponse sets the error of the given code. The code of a codedError
+// wrapped by err takes precedence.
+func ErrorResponse(w http.ResponseWriter, r *http.Request, code types.ErrorCode, err error,
+	args map[string]interface{}) {
 
-	errMsg := types.HTTPError{
-		Title:   title,
-		Code:    code,
-		Message: "A problem occurred on the proxy",
-		Args:    args,
+	var coded codedError
+	if xerrors.As(err, &coded) {
+		code = coded.code
 	}
 
-	buf, _ := json.MarshalIndent(&errMsg, "", "  ")
+	types.NewHTTPError(r, code, err, args).Write(w)
+}
+
+// codedError is an error with the code of the response.
+type codedError struct {
+	code types.ErrorCode
+	err  error
+}
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(int(code))
-	fmt.Fprintln(w, string(buf))
+// Error implements error.
+func (e codedError) Error() string {
+	return e.err.Error()
 }
 
 // AllowCORS defines a basic handler that adds wide Access Control Allow origin
@@ -154,3 +192,53 @@ func AllowCORS(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Headers", "*")
 }
+
+// getAndVerify verifies the signed request with the keys of the ring, checks
+// that it was signed for the route of r, is not stale and is not replayed, and
+// extracts its payload. It returns the request with the name of the key that
+// signed it in its context, which is recorded in the transactions. The payload
+// must match the schema of el, which MUST be a pointer.
+func getAndVerify(signed types.SignedRequest, keys *types.KeyRing, r *http.Request,
+	el interface{}) (*http.Request, error) {
+
+	now := time.Now()
+
+	key, err := keys.Verify(signed, now)
+	if err != nil {
+		return r, codedError{
+			code: types.CodeInvalidSignature,
+			err:  xerrors.Errorf("failed to verify: %v", err),
+		}
+	}
+
+	err = signed.Check(r, nonces, now)
+	if err != nil {
+		code := types.CodeRejectedRequest
+		if xerrors.Is(err, types.ErrCacheFull) {
+			code = types.CodeTooManyRequests
+		}
+
+		return r, codedError{code: code, err: xerrors.Errorf("failed to check: %v", err)}
+	}
+
+	err = validatePayload(signed, el)
+	if err != nil {
+		return r, codedError{
+			code: types.CodeInvalidRequest,
+			err:  xerrors.Errorf("failed to validate: %v", err),
+		}
+	}
+
+	err = signed.GetMessage(el)
+	if err != nil {
+		return r, codedError{
+			code: types.CodeInvalidRequest,
+			err:  xerrors.Errorf("failed to get message: %v", err),
+		}
+	}
+
+	dela.Logger.Info().Str("key", key).Str("route", signed.Route).
+		Msg("signed request verified")
+
+	return r.WithContext(txnmanager.WithProxyKey(r.Context(), key)), nil
+}
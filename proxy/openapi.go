@@ -0,0 +1,430 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dedis/d-voting/proxy/txnmanager"
+	"github.com/dedis/d-voting/proxy/types"
+	"github.com/gorilla/mux"
+	"golang.org/x/xerrors"
+)
+
+// OpenAPIPath is the path of the OpenAPI document of the proxy.
+const OpenAPIPath = "/openapi.json"
+
+// apiVersion is the version of the API in the OpenAPI document.
+const apiVersion = "1.0.0"
+
+// Operation describes a route of the API in the OpenAPI document.
+type Operation struct {
+	Summary string
+	// Request is the message in the body, nil if there is no body
+	Request interface{}
+	// Signed is true if the body is a signed request with the message as
+	// payload
+	Signed bool
+	// Response is the message returned on success, nil if the response is not
+	// a JSON message
+	Response interface{}
+	// Query holds the names of the query parameters
+	Query []string
+}
+
+// operations describes the routes registered by the controllers, by route as
+// given by types.Route with the template of the path.
+var operations = map[string]Operation{
+	"GET /health": {
+		Summary:  "Get the health of the node",
+		Response: types.HealthResponse{},
+	},
+	"GET /ready": {
+		Summary:  "Tell if the node is ready, with a 503 if it is not",
+		Response: types.ReadyResponse{},
+	},
+	"GET " + OpenAPIPath: {
+		Summary: "Get the OpenAPI document of the proxy",
+	},
+	"GET /evoting/events": {
+		Summary: "Stream the events of the forms as server-sent events",
+		Query:   []string{"formID"},
+	},
+	"POST /evoting/addadmin": {
+		Summary:  "Add an admin to the admin list",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/removeadmin": {
+		Summary:  "Remove an admin from the admin list",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"GET /evoting/adminlist": {
+		Summary:  "Get the admin list",
+		Response: types.GetAdminsResponse{},
+	},
+	"POST /evoting/forms/{formID}/addowner": {
+		Summary:  "Add an owner to the form",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/removeowner": {
+		Summary:  "Remove an owner from the form",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/addvoter": {
+		Summary:  "Add a voter to the form",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/removevoter": {
+		Summary:  "Remove a voter from the form",
+		Request:  types.PermissionOperationRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms": {
+		Summary:  "Create a form",
+		Request:  types.CreateFormRequest{},
+		Signed:   true,
+		Response: types.CreateFormResponse{},
+	},
+	"GET /evoting/forms": {
+		Summary:  "List the forms",
+		Query:    []string{"status", "owner", "cursor", "limit"},
+		Response: types.GetFormsResponse{},
+	},
+	"GET /evoting/forms/{formID}": {
+		Summary:  "Get the form",
+		Response: types.GetFormResponse{},
+	},
+	"PUT /evoting/forms/{formID}": {
+		Summary:  "Open, close, combine the shares of or cancel the form",
+		Request:  types.UpdateFormRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"DELETE /evoting/forms/{formID}": {
+		Summary:  "Delete the form",
+		Request:  types.DeleteFormRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/vote": {
+		Summary:  "Cast a ballot",
+		Request:  types.CastVoteRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/votes": {
+		Summary:  "Cast a batch of ballots in a single transaction",
+		Request:  types.CastVotesRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/voterkeys": {
+		Summary:  "Register the key of a voter",
+		Request:  types.RegisterVoterKeyRequest{},
+		Signed:   true,
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/forms/{formID}/ballots/validate": {
+		Summary:  "Validate a ballot against the configuration of the form",
+		Request:  types.ValidateBallotRequest{},
+		Response: types.ValidateBallotResponse{},
+	},
+	"GET /evoting/forms/{formID}/results": {
+		Summary:  "Get the results, or export them with format",
+		Query:    []string{"offset", "limit", "format", "table", "question", "seats"},
+		Response: types.GetResultsResponse{},
+	},
+	"GET /evoting/forms/{formID}/voters": {
+		Summary:  "Get a page of the users who cast a ballot",
+		Query:    []string{"offset", "limit"},
+		Response: types.GetVotersResponse{},
+	},
+	"GET /evoting/forms/{formID}/certificate": {
+		Summary:  "Get the certificate of the results",
+		Response: types.GetCertificateResponse{},
+	},
+	"GET /evoting/forms/{formID}/record": {
+		Summary: "Get the election record of the form",
+	},
+	"GET /evoting/forms/{formID}/ballots/{digest}/proof": {
+		Summary:  "Get the inclusion proof of a ballot",
+		Response: types.GetBallotProofResponse{},
+	},
+	"GET /evoting/transactions/{token}": {
+		Summary:  "Get the status of a transaction",
+		Response: txnmanager.TransactionClientInfo{},
+	},
+	"POST /evoting/services/dkg/actors": {
+		Summary: "Create the DKG actor of a form",
+		Request: types.NewDKGRequest{},
+		Signed:  true,
+	},
+	"GET /evoting/services/dkg/actors/{formID}": {
+		Summary:  "Get the status of the DKG actor",
+		Response: types.GetActorInfo{},
+	},
+	"PUT /evoting/services/dkg/actors/{formID}": {
+		Summary: "Set up the DKG or compute the public shares",
+		Request: types.UpdateDKG{},
+		Signed:  true,
+	},
+	"GET /evoting/services/shuffle/{formID}": {
+		Summary:  "Get the status of the shuffle",
+		Response: types.GetShuffleInfo{},
+	},
+	"PUT /evoting/services/shuffle/{formID}": {
+		Summary: "Shuffle the ballots in the background",
+		Request: types.UpdateShuffle{},
+		Signed:  true,
+	},
+	"PUT /evoting/services/certificate/{formID}": {
+		Summary: "Certify the results",
+		Request: types.UpdateCertificate{},
+		Signed:  true,
+	},
+}
+
+// schemas are the schemas of the messages, used both in the document and to
+// validate the requests.
+var schemas = types.NewSchemas()
+
+// routes are the routes registered by the controllers, in the document.
+var routes = struct {
+	sync.Mutex
+	list map[string]struct{}
+}{
+	list: map[string]struct{}{"GET " + OpenAPIPath: {}},
+}
+
+// pathParam matches the variables of a path template, as in {formID}.
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// AddRoutes adds the routes of the router to the OpenAPI document. It fails
+// if a route is not described in the operations, which keeps the document in
+// line with the routes.
+func AddRoutes(router *mux.Router) error {
+	routes.Lock()
+	defer routes.Unlock()
+
+	return router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return xerrors.Errorf("failed to get path: %v", err)
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			return xerrors.Errorf("failed to get methods of %s: %v", path, err)
+		}
+
+		for _, method := range methods {
+			// the preflight requests of the browsers are not documented
+			if method == http.MethodOptions {
+				continue
+			}
+
+			key := types.Route(method, path)
+
+			_, found := operations[key]
+			if !found {
+				return xerrors.Errorf("route %q is not documented", key)
+			}
+
+			routes.list[key] = struct{}{}
+		}
+
+		return nil
+	})
+}
+
+// OpenAPI serves the OpenAPI document of the routes added with AddRoutes.
+func OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "*")
+
+	if r.Method != http.MethodGet {
+		NotAllowedHandler(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(openAPIDocument())
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write document: %v", err), nil)
+	}
+}
+
+// openAPIDoc is the OpenAPI 3.0 document of the proxy. The payload of a
+// signed request, which is base64 encoded in the body, is described by the
+// x-signed-payload extension of the operation.
+type openAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*types.Schema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	Summary       string                     `json:"summary,omitempty"`
+	Parameters    []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody   *openAPIBody               `json:"requestBody,omitempty"`
+	SignedPayload *types.Schema              `json:"x-signed-payload,omitempty"`
+	Responses     map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"`
+	Required bool          `json:"required,omitempty"`
+	Schema   *types.Schema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                    `json:"required"`
+	Content  map[string]openAPIMedia `json:"content"`
+}
+
+type openAPIMedia struct {
+	Schema *types.Schema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                  `json:"description"`
+	Content     map[string]openAPIMedia `json:"content,omitempty"`
+}
+
+// openAPIDocument returns the document of the registered routes.
+func openAPIDocument() openAPIDoc {
+	routes.Lock()
+	keys := make([]string, 0, len(routes.list))
+	for key := range routes.list {
+		keys = append(keys, key)
+	}
+	routes.Unlock()
+
+	sort.Strings(keys)
+
+	doc := openAPIDoc{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:   "D-Voting proxy",
+			Version: apiVersion,
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+	}
+
+	for _, key := range keys {
+		method, path, _ := strings.Cut(key, " ")
+
+		item := doc.Paths[path]
+		if item == nil {
+			item = make(map[string]*openAPIOperation)
+			doc.Paths[path] = item
+		}
+
+		item[strings.ToLower(method)] = newOpenAPIOperation(path, operations[key])
+	}
+
+	doc.Components.Schemas = schemas.Components()
+
+	return doc
+}
+
+func newOpenAPIOperation(path string, op Operation) *openAPIOperation {
+	operation := &openAPIOperation{
+		Summary: op.Summary,
+		Responses: map[string]openAPIResponse{
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(schemas.SchemaOf(types.HTTPError{})),
+			},
+		},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &types.Schema{Type: "string"},
+		})
+	}
+
+	for _, name := range op.Query {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:   name,
+			In:     "query",
+			Schema: &types.Schema{Type: "string"},
+		})
+	}
+
+	if op.Request != nil {
+		body := schemas.SchemaOf(op.Request)
+
+		if op.Signed {
+			operation.SignedPayload = body
+			body = schemas.SchemaOf(types.SignedRequest{})
+		}
+
+		operation.RequestBody = &openAPIBody{
+			Required: true,
+			Content:  jsonContent(body),
+		}
+	}
+
+	success := openAPIResponse{Description: "OK"}
+
+	if op.Response != nil {
+		success.Content = jsonContent(schemas.SchemaOf(op.Response))
+	}
+
+	operation.Responses["200"] = success
+
+	return operation
+}
+
+func jsonContent(schema *types.Schema) map[string]openAPIMedia {
+	return map[string]openAPIMedia{
+		"application/json": {Schema: schema},
+	}
+}
+
+// validatePayload checks that the payload of the signed request matches the
+// schema of the message el.
+func validatePayload(signed types.SignedRequest, el interface{}) error {
+	payload, err := signed.GetPayload()
+	if err != nil {
+		return xerrors.Errorf("failed to get payload: %v", err)
+	}
+
+	err = schemas.Validate(payload, el)
+	if err != nil {
+		return xerrors.Errorf("invalid request: %v", err)
+	}
+
+	return nil
+}
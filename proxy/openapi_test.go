@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dedis/d-voting/proxy/types"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/evoting/forms", nil).Methods("GET")
+	router.HandleFunc("/evoting/forms", nil).Methods("OPTIONS")
+	router.HandleFunc("/evoting/forms/{formID}/vote", nil).Methods("POST")
+
+	err := AddRoutes(router)
+	require.NoError(t, err)
+
+	router.HandleFunc("/evoting/unknown", nil).Methods("GET")
+
+	err = AddRoutes(router)
+	require.EqualError(t, err, "route \"GET /evoting/unknown\" is not documented")
+
+	w := httptest.NewRecorder()
+	OpenAPI(w, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var doc map[string]interface{}
+
+	err = json.Unmarshal(w.Body.Bytes(), &doc)
+	require.NoError(t, err)
+	require.Equal(t, "3.0.3", doc["openapi"])
+
+	paths := doc["paths"].(map[string]interface{})
+	require.Contains(t, paths, OpenAPIPath)
+	require.Contains(t, paths["/evoting/forms"], "get")
+	require.NotContains(t, paths["/evoting/forms"], "options")
+
+	vote := paths["/evoting/forms/{formID}/vote"].(map[string]interface{})["post"]
+	require.Equal(t, "#/components/schemas/CastVoteRequest",
+		vote.(map[string]interface{})["x-signed-payload"].(map[string]interface{})["$ref"])
+
+	w = httptest.NewRecorder()
+	OpenAPI(w, httptest.NewRequest(http.MethodPost, OpenAPIPath, nil))
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestOpenAPI_Operations(t *testing.T) {
+	for key, op := range operations {
+		method, path, _ := strings.Cut(key, " ")
+
+		require.Contains(t, []string{"GET", "POST", "PUT", "DELETE"}, method, key)
+
+		operation := newOpenAPIOperation(path, op)
+		require.NotEmpty(t, operation.Summary, key)
+		require.Contains(t, operation.Responses, "200", key)
+	}
+
+	doc := openAPIDocument()
+
+	buf, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	// all the references point to a component
+	refs := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`)
+
+	for _, match := range refs.FindAllStringSubmatch(string(buf), -1) {
+		require.Contains(t, doc.Components.Schemas, match[1])
+	}
+
+	// the error of the shuffle is only there when it failed
+	info := doc.Components.Schemas["GetShuffleInfo"]
+	require.NotContains(t, info.Required, "Error")
+	require.True(t, info.Properties["Error"].Nullable)
+}
+
+func TestGetAndVerify_Invalid(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	public := suite.Point().Mul(secret, nil)
+
+	shuffle := NewShuffle(nil, publicKeys(public))
+
+	request := map[string]interface{}{
+		"Action": "shuffle",
+		"Other":  1,
+	}
+
+	signed, err := createSignedRequest(secret, "PUT /evoting/services/shuffle/abcd", request)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPut, "/evoting/services/shuffle/abcd",
+		strings.NewReader(string(signed)))
+
+	shuffle.EditShuffle(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "invalid request: $: unknown field \\\"Other\\\"")
+
+	signed, err = createSignedRequest(secret, "PUT /evoting/services/shuffle/abcd",
+		types.UpdateShuffle{Action: "unknown"})
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPut, "/evoting/services/shuffle/abcd",
+		strings.NewReader(string(signed)))
+	r = mux.SetURLVars(r, map[string]string{"formID": "abcd"})
+
+	shuffle.EditShuffle(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "invalid action: unknown")
+}
@@ -2,21 +2,20 @@ package proxy
 
 import (
 	"encoding/hex"
-	"fmt"
+	"encoding/json"
 	"net/http"
 
 	"github.com/dedis/d-voting/proxy/types"
 	shuffleSrv "github.com/dedis/d-voting/services/shuffle"
 	"github.com/gorilla/mux"
-	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
 
 // NewShuffle returns a new initialized shuffle
-func NewShuffle(actor shuffleSrv.Actor, pk kyber.Point) Shuffle {
+func NewShuffle(actor shuffleSrv.Actor, keys *types.KeyRing) Shuffle {
 	return shuffle{
 		actor: actor,
-		pk:    pk,
+		keys:  keys,
 	}
 }
 
@@ -27,7 +26,55 @@ type shuffle struct {
 	// actor is the shuffle actor
 	actor shuffleSrv.Actor
 	// pk is the public key of the proxy
-	pk kyber.Point
+	keys *types.KeyRing
+}
+
+// ShuffleStatus implements proxy.Shuffle. It sends the status of the shuffle
+// started by this node on the form.
+func (s shuffle) ShuffleStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	// check if the formID is present
+	if vars == nil || vars["formID"] == "" {
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
+		return
+	}
+
+	formIDBuf, err := hex.DecodeString(vars["formID"])
+	if err != nil {
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("failed to decode formID: %v", err), nil)
+		return
+	}
+
+	status := s.actor.Status(formIDBuf)
+
+	response := types.GetShuffleInfo{
+		Status:    int(status.Status),
+		Round:     status.Round,
+		Threshold: status.Threshold,
+		Nodes:     status.Nodes,
+	}
+
+	// if the shuffle failed, return its error
+	if status.Status == shuffleSrv.Failed {
+		response.Error = &types.HTTPError{
+			Title:     "Shuffle failed",
+			Code:      uint(types.CodeInternal.Status()),
+			ErrorCode: types.CodeInternal,
+		}
+
+		if status.Err != nil {
+			response.Error.Message = status.Err.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		InternalError(w, r, xerrors.Errorf("failed to write response: %v", err), nil)
+		return
+	}
 }
 
 // EditShuffle implements proxy.Shuffle
@@ -37,14 +84,14 @@ func (s shuffle) EditShuffle(w http.ResponseWriter, r *http.Request) {
 	// Read the request
 	signed, err := types.NewSignedRequest(r.Body)
 	if err != nil {
-		InternalError(w, r, newSignedErr(err), nil)
+		BadRequestError(w, r, newSignedErr(err), nil)
 		return
 	}
 
 	// Verify the signature and get the request
-	err = signed.GetAndVerify(s.pk, &req)
+	r, err = getAndVerify(signed, s.keys, r, &req)
 	if err != nil {
-		InternalError(w, r, getSignedErr(err), nil)
+		signedRequestError(w, r, err)
 		return
 	}
 
@@ -52,7 +99,7 @@ func (s shuffle) EditShuffle(w http.ResponseWriter, r *http.Request) {
 
 	// check if the formID is present
 	if vars == nil || vars["formID"] == "" {
-		http.Error(w, fmt.Sprintf("formID not found: %v", vars), http.StatusInternalServerError)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("formID not found: %v", vars), nil)
 		return
 	}
 
@@ -61,16 +108,23 @@ func (s shuffle) EditShuffle(w http.ResponseWriter, r *http.Request) {
 
 	formIDBuf, err := hex.DecodeString(formID)
 	if err != nil {
-		http.Error(w, "failed to decode formID: "+formID, http.StatusInternalServerError)
+		ErrorResponse(w, r, types.CodeInvalidFormID, xerrors.Errorf("failed to decode formID: %v", err), nil)
 		return
 	}
 
 	switch req.Action {
 	// shuffle the ballots
 	case "shuffle":
-		err = s.actor.Shuffle(formIDBuf, userID)
+		// As the shuffle can be long, it runs asynchronously. One can fetch
+		// the status of the shuffle to know when it is over.
+		err = s.actor.Start(formIDBuf, userID)
+		if xerrors.Is(err, shuffleSrv.ErrRunning) {
+			ErrorResponse(w, r, types.CodeInvalidStatus, err, nil)
+			return
+		}
+
 		if err != nil {
-			http.Error(w, "failed to shuffle: "+err.Error(), http.StatusInternalServerError)
+			InternalError(w, r, xerrors.Errorf("failed to start shuffle: %v", err), nil)
 			return
 		}
 	default:
@@ -0,0 +1,129 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dedis/d-voting/proxy/types"
+	shuffleSrv "github.com/dedis/d-voting/services/shuffle"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestEditShuffle(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	public := suite.Point().Mul(secret, nil)
+
+	actor := &mockShuffleActor{}
+	shuffle := NewShuffle(actor, publicKeys(public))
+
+	edit := func() *httptest.ResponseRecorder {
+		signed, err := createSignedRequest(secret, "PUT /evoting/services/shuffle/abcd",
+			types.UpdateShuffle{Action: "shuffle"})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/evoting/services/shuffle/abcd",
+			strings.NewReader(string(signed)))
+		r = mux.SetURLVars(r, map[string]string{"formID": "abcd", "userID": "123456"})
+
+		shuffle.EditShuffle(w, r)
+
+		return w
+	}
+
+	w := edit()
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 1, actor.started)
+
+	// the shuffle is already running
+	actor.err = shuffleSrv.ErrRunning
+
+	w = edit()
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Contains(t, w.Body.String(), `"ErrorCode": "invalid_status"`)
+	require.Contains(t, w.Body.String(), "the shuffle of the form is already running")
+
+	actor.err = xerrors.New("oops")
+
+	w = edit()
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Contains(t, w.Body.String(), "failed to start shuffle: oops")
+}
+
+func TestShuffleStatus_Failed(t *testing.T) {
+	actor := &mockShuffleActor{
+		status: shuffleSrv.Status{
+			Status: shuffleSrv.Failed,
+			Round:  1,
+			Err:    xerrors.New("oops"),
+		},
+	}
+
+	shuffle := NewShuffle(actor, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/evoting/services/shuffle/abcd", nil)
+	r = mux.SetURLVars(r, map[string]string{"formID": "abcd"})
+
+	shuffle.ShuffleStatus(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var info types.GetShuffleInfo
+
+	err := json.NewDecoder(w.Body).Decode(&info)
+	require.NoError(t, err)
+	require.Equal(t, int(shuffleSrv.Failed), info.Status)
+	require.Equal(t, 1, info.Round)
+	require.Equal(t, types.CodeInternal, info.Error.ErrorCode)
+	require.Equal(t, uint(http.StatusInternalServerError), info.Error.Code)
+	require.Equal(t, "oops", info.Error.Message)
+
+	// the error is missing from the status of a running shuffle
+	actor.status = shuffleSrv.Status{Status: shuffleSrv.Running, Round: 1}
+
+	w = httptest.NewRecorder()
+	shuffle.ShuffleStatus(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "Error")
+
+	info = types.GetShuffleInfo{}
+
+	err = json.NewDecoder(w.Body).Decode(&info)
+	require.NoError(t, err)
+	require.Nil(t, info.Error)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+// mockShuffleActor is a shuffle actor that counts the shuffles it starts.
+//
+// - implements shuffle.Actor
+type mockShuffleActor struct {
+	started int
+	err     error
+	status  shuffleSrv.Status
+}
+
+func (a *mockShuffleActor) Shuffle(formID []byte, userID string) error {
+	return a.err
+}
+
+func (a *mockShuffleActor) Start(formID []byte, userID string) error {
+	if a.err != nil {
+		return a.err
+	}
+
+	a.started++
+
+	return nil
+}
+
+func (a *mockShuffleActor) Status(formID []byte) shuffleSrv.Status {
+	return a.status
+}
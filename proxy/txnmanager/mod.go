@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/dedis/d-voting/contracts/evoting"
+	ptypes "github.com/dedis/d-voting/proxy/types"
 )
 
 // Manager defines the public HTTP API of the transaction manager
@@ -22,6 +23,23 @@ type Manager interface {
 	SendTransactionInfo(w http.ResponseWriter, txnID []byte, lastBlockIdx uint64, status TransactionStatus) error
 }
 
+// proxyKeyCtxKey is the key of the context at which the name of the proxy key
+// that signed the request is stored.
+type proxyKeyCtxKey struct{}
+
+// WithProxyKey returns a context with the name of the proxy key that signed
+// the request. SubmitTxn records it in the transaction.
+func WithProxyKey(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, proxyKeyCtxKey{}, name)
+}
+
+// ProxyKeyFromContext returns the name of the proxy key of the context, or an
+// empty string.
+func ProxyKeyFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(proxyKeyCtxKey{}).(string)
+	return name
+}
+
 // TransactionStatus is the status of a transaction
 type TransactionStatus byte
 
@@ -52,4 +70,12 @@ type transactionInternalInfo struct {
 type TransactionClientInfo struct {
 	Status TransactionStatus // 0 if not yet included, 1 if included, 2 if rejected
 	Token  string
+	// ErrorCode is the code of the reason why a transaction was rejected
+	ErrorCode ptypes.ErrorCode `json:",omitempty"`
+	// Reason is the reason why a transaction was rejected, as given by the
+	// contract
+	Reason string `json:",omitempty"`
+	// Ballots is the result of each ballot of a batch of votes, once the
+	// transaction is included or rejected
+	Ballots []ptypes.BallotResult `json:",omitempty"`
 }
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/dedis/d-voting/contracts/evoting"
+	"github.com/dedis/d-voting/contracts/evoting/types"
+	ptypes "github.com/dedis/d-voting/proxy/types"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 	"go.dedis.ch/dela"
@@ -23,7 +25,6 @@ import (
 	"go.dedis.ch/dela/core/txn/pool"
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/serde"
-	"go.dedis.ch/kyber/v3"
 	"golang.org/x/xerrors"
 )
 
@@ -31,21 +32,24 @@ const (
 	maxTimeTransactionCheck = 10 * time.Minute
 )
 
-// NewTransactionManager returns a new initialized transaction manager
-func NewTransactionManager(mngr txn.Manager, p pool.Pool,
-	ctx serde.Context, pk kyber.Point, blocks blockstore.BlockStore, signer crypto.Signer, val validation.Service) Manager {
+// NewTransactionManager returns a new initialized transaction manager. ctx is
+// used for the signatures of the tokens, and serdeCtx for the transactions of
+// the contract, which are in the format chosen for the chain.
+func NewTransactionManager(mngr txn.Manager, p pool.Pool, ctx serde.Context, serdeCtx serde.Context,
+	blocks blockstore.BlockStore, signer crypto.Signer, val validation.Service) Manager {
 
 	logger := dela.Logger.With().Timestamp().Str("role", "proxy-txmanager").Logger()
 
 	return &manager{
-		logger:  logger,
-		context: ctx,
-		mngr:    mngr,
-		pool:    p,
-		pk:      pk,
-		blocks:  blocks,
-		signer:  signer,
-		val:     val,
+		logger:    logger,
+		context:   ctx,
+		txContext: serdeCtx,
+		mngr:      mngr,
+		pool:      p,
+		blocks:    blocks,
+		signer:    signer,
+		val:       val,
+		txFac:     types.NewTransactionFactory(types.CiphervoteFactory{}),
 	}
 }
 
@@ -55,14 +59,15 @@ func NewTransactionManager(mngr txn.Manager, p pool.Pool,
 type manager struct {
 	sync.Mutex
 
-	logger  zerolog.Logger
-	context serde.Context
-	mngr    txn.Manager
-	pool    pool.Pool
-	pk      kyber.Point
-	blocks  blockstore.BlockStore
-	signer  crypto.Signer
-	val     validation.Service
+	logger    zerolog.Logger
+	context   serde.Context
+	txContext serde.Context
+	mngr      txn.Manager
+	pool      pool.Pool
+	blocks    blockstore.BlockStore
+	signer    crypto.Signer
+	val       validation.Service
+	txFac     serde.Factory
 }
 
 // StatusHandlerGet checks if the transaction is included in the blockchain
@@ -72,7 +77,7 @@ func (h *manager) StatusHandlerGet(w http.ResponseWriter, r *http.Request) {
 
 	// check if the token is valid
 	if vars == nil || vars["token"] == "" {
-		http.Error(w, fmt.Sprintf("token not found: %v", vars), http.StatusInternalServerError)
+		sendError(w, r, ptypes.CodeInvalidRequest, xerrors.Errorf("token not found: %v", vars))
 		return
 	}
 
@@ -81,7 +86,7 @@ func (h *manager) StatusHandlerGet(w http.ResponseWriter, r *http.Request) {
 	// decode the token
 	marshall, err := b64.URLEncoding.DecodeString(token)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to decode token: %v", err), http.StatusInternalServerError)
+		sendError(w, r, ptypes.CodeInvalidRequest, xerrors.Errorf("failed to decode token: %v", err))
 		return
 	}
 
@@ -89,45 +94,121 @@ func (h *manager) StatusHandlerGet(w http.ResponseWriter, r *http.Request) {
 	var content transactionInternalInfo
 	err = json.Unmarshal(marshall, &content)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to unmarshall token: %v", err), http.StatusInternalServerError)
+		sendError(w, r, ptypes.CodeInvalidRequest, xerrors.Errorf("failed to unmarshall token: %v", err))
 		return
 	}
 
 	err = content.validate(h)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid content: %v", err), http.StatusBadRequest)
+		sendError(w, r, ptypes.CodeInvalidRequest, xerrors.Errorf("Invalid content: %v", err))
 		return
 	}
 
 	// check if if was submited not to long ago
 	if time.Now().Unix()-content.Time > int64(maxTimeTransactionCheck) {
 		// if it was submited to long ago, we reject the transaction
-		err = h.SendTransactionInfo(w, content.TransactionID, 0, RejectedTransaction)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to send transaction info: %v", err), http.StatusInternalServerError)
-			return
-		}
+		h.sendRejected(w, r, content.TransactionID, 0, ptypes.CodeTransactionRejected,
+			"the transaction was not included in time", nil)
 		return
 	}
 
 	// check if the transaction time stamp is possible
 	if time.Now().Unix()-content.Time < 0 {
-		http.Error(w, "the transaction is from the future", http.StatusInternalServerError)
+		sendError(w, r, ptypes.CodeInvalidRequest, xerrors.New("the transaction is from the future"))
 		return
 	}
 
 	// check if the transaction is included in the blockchain
-	newStatus, idx := h.checkTxnIncluded(content.TransactionID, content.LastBlockIdx)
+	newStatus, idx, reason, tx := h.checkTxnIncluded(content.TransactionID, content.LastBlockIdx)
+
+	if newStatus == RejectedTransaction {
+		h.sendRejected(w, r, content.TransactionID, idx, ptypes.ReasonCode(reason), reason,
+			h.ballotResults(tx, false, reason))
+		return
+	}
+
+	response, err := h.CreateTransactionResult(content.TransactionID, idx, newStatus)
+	if err != nil {
+		sendError(w, r, ptypes.CodeInternal, xerrors.Errorf("failed to create transaction info: %v", err))
+		return
+	}
+
+	if newStatus == IncludedTransaction {
+		response.Ballots = h.ballotResults(tx, true, "")
+	}
 
 	// send the transaction info
-	err = h.SendTransactionInfo(w, content.TransactionID, idx, newStatus)
+	err = SendResponse(w, response)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to send transaction info: %v", err), http.StatusInternalServerError)
+		sendError(w, r, ptypes.CodeInternal, xerrors.Errorf("failed to send transaction info: %v", err))
 		return
 	}
 
 }
 
+// sendRejected sends the information of a rejected transaction with the code
+// and the reason of the rejection, and the results of the ballots of a batch.
+func (h *manager) sendRejected(w http.ResponseWriter, r *http.Request, txnID []byte,
+	lastBlockIdx uint64, code ptypes.ErrorCode, reason string, ballots []ptypes.BallotResult) {
+
+	response, err := h.CreateTransactionResult(txnID, lastBlockIdx, RejectedTransaction)
+	if err != nil {
+		sendError(w, r, ptypes.CodeInternal, xerrors.Errorf("failed to create transaction info: %v", err))
+		return
+	}
+
+	response.ErrorCode = code
+	response.Reason = reason
+	response.Ballots = ballots
+
+	SendResponse(w, response)
+}
+
+// ballotResults returns the result of each ballot of a CAST_VOTES transaction,
+// or nil for the other transactions. When the batch is rejected, the ballots
+// listed in the reason are given with their error, and the other ones are not
+// cast.
+func (h *manager) ballotResults(tx txn.Transaction, accepted bool, reason string) []ptypes.BallotResult {
+	if tx == nil || evoting.Command(tx.GetArg(evoting.CmdArg)) != evoting.CmdCastVotes {
+		return nil
+	}
+
+	msg, err := h.txFac.Deserialize(h.txContext, tx.GetArg(evoting.FormArg))
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("failed to deserialize the batch of votes")
+		return nil
+	}
+
+	castVotes, ok := msg.(types.CastVotes)
+	if !ok {
+		return nil
+	}
+
+	ballotErrs, _ := types.ParseBallotErrors(reason)
+
+	results := make([]ptypes.BallotResult, len(castVotes.Votes))
+
+	for i, vote := range castVotes.Votes {
+		results[i] = ptypes.BallotResult{
+			VoterID: vote.VoterID,
+			Cast:    accepted,
+		}
+
+		ballotReason, found := ballotErrs[i]
+		if found {
+			results[i].ErrorCode = ptypes.ReasonCode(ballotReason)
+			results[i].Reason = ballotReason
+		}
+	}
+
+	return results
+}
+
+// sendError sends the error with the given code.
+func sendError(w http.ResponseWriter, r *http.Request, code ptypes.ErrorCode, err error) {
+	ptypes.NewHTTPError(r, code, err, nil).Write(w)
+}
+
 // validate checks if the transaction is valid
 func (content transactionInternalInfo) validate(h *manager) error {
 	// check if the transaction status is unknown
@@ -169,8 +250,12 @@ func (h *manager) checkSignature(Hash []byte, Signature crypto.Signature) bool {
 	return h.signer.GetPublicKey().Verify(Hash, Signature) == nil
 }
 
-// checkTxnIncluded checks if the transaction is included in the blockchain
-func (h *manager) checkTxnIncluded(transactionID []byte, lastBlockIdx uint64) (TransactionStatus, uint64) {
+// checkTxnIncluded checks if the transaction is included in the blockchain. It
+// returns the reason of the contract if the transaction was rejected, and the
+// transaction once it is found.
+func (h *manager) checkTxnIncluded(transactionID []byte,
+	lastBlockIdx uint64) (TransactionStatus, uint64, string, txn.Transaction) {
+
 	// we start at the last block index
 	// which is the index of the last block that was checked
 	// or the last block before the transaction was submited
@@ -182,7 +267,7 @@ func (h *manager) checkTxnIncluded(transactionID []byte, lastBlockIdx uint64) (T
 
 		// if we reached the end of the blockchain
 		if err != nil {
-			return UnknownTransactionStatus, idx - 1
+			return UnknownTransactionStatus, idx - 1, "", nil
 		}
 
 		// check if the transaction is in the block
@@ -193,12 +278,11 @@ func (h *manager) checkTxnIncluded(transactionID []byte, lastBlockIdx uint64) (T
 			if bytes.Equal(txn.GetTransaction().GetID(), transactionID) {
 				accepted, reason := txn.GetStatus()
 				if accepted {
-					return IncludedTransaction, blockLink.GetBlock().GetIndex()
-				} else {
-					println(reason)
-					return RejectedTransaction, blockLink.GetBlock().GetIndex()
+					return IncludedTransaction, blockLink.GetBlock().GetIndex(), "", txn.GetTransaction()
 				}
 
+				return RejectedTransaction, blockLink.GetBlock().GetIndex(), reason, txn.GetTransaction()
+
 			}
 
 		}
@@ -215,7 +299,7 @@ func (h *manager) SubmitTxn(ctx context.Context, cmd evoting.Command,
 	h.Lock()
 	defer h.Unlock()
 
-	tx, err := createTransaction(h.mngr, cmd, cmdArg, payload)
+	tx, err := createTransaction(h.mngr, cmd, cmdArg, payload, ProxyKeyFromContext(ctx))
 	if err != nil {
 		return nil, 0, xerrors.Errorf("failed to create transaction: %v", err)
 	}
@@ -292,17 +376,16 @@ func SendResponse(w http.ResponseWriter, response any) error {
 
 	err := json.NewEncoder(w).Encode(response)
 	if err != nil {
-		http.Error(w, "failed to write in ResponseWriter: "+err.Error(),
-			http.StatusInternalServerError)
-		return nil
+		return xerrors.Errorf("failed to write in ResponseWriter: %v", err)
 	}
 
 	return nil
 }
 
 // createTransaction creates a transaction with the given command and payload.
+// The name of the proxy key is recorded in the transaction, if not empty.
 func createTransaction(manager txn.Manager, commandType evoting.Command,
-	commandArg string, buf []byte) (txn.Transaction, error) {
+	commandArg string, buf []byte, proxyKey string) (txn.Transaction, error) {
 
 	args := []txn.Arg{
 		{
@@ -319,6 +402,13 @@ func createTransaction(manager txn.Manager, commandType evoting.Command,
 		},
 	}
 
+	if proxyKey != "" {
+		args = append(args, txn.Arg{
+			Key:   evoting.ProxyKeyArg,
+			Value: []byte(proxyKey),
+		})
+	}
+
 	tx, err := manager.Make(args...)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create transaction from manager: %v", err)
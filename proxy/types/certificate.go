@@ -0,0 +1,21 @@
+package types
+
+// UpdateCertificate defines the input used to update the certificate
+type UpdateCertificate struct {
+	Action string
+}
+
+// GetCertificateResponse defines the HTTP response when getting the
+// certificate of the results of a form
+type GetCertificateResponse struct {
+	FormID string
+	// Digest is the hex-encoded digest of the results signed by the roster
+	Digest string
+	// Certificate is the serialized collective signature of the digest
+	Certificate string
+	// Roster contains the addresses of the nodes that can sign the results
+	Roster []string
+	// PublicKeys contains the hex-encoded public keys of the roster, in the
+	// same order as the addresses
+	PublicKeys []string
+}
@@ -28,6 +28,46 @@ type CastVoteRequest struct {
 	VoterID string
 	// Marshalled representation of Ciphervote. It contains []{K:,C:}
 	Ballot CiphervoteJSON
+	// Signature is the signature of the voter on the vote digest, required on
+	// the forms with signed ballots
+	Signature []byte `json:",omitempty"`
+	// Nonce is signed with the ballot and must be greater than the one of the
+	// previous ballot of the voter, on the forms with signed ballots
+	Nonce uint64 `json:",omitempty"`
+}
+
+// CastVotesRequest defines the HTTP request for casting a batch of votes at
+// once, in a single transaction
+type CastVotesRequest struct {
+	Votes []CastVoteRequest
+}
+
+// BallotResult is the result of a ballot of a batch of votes, returned with
+// the status of the transaction
+type BallotResult struct {
+	VoterID string
+	// Cast is true if the ballot was cast, which is the case of all the
+	// ballots of an accepted batch
+	Cast bool
+	// ErrorCode is the code of the reason why the ballot is invalid
+	ErrorCode ErrorCode `json:",omitempty"`
+	// Reason is the reason why the ballot is invalid, empty for the valid
+	// ballots of a rejected batch
+	Reason string `json:",omitempty"`
+}
+
+// RegisterVoterKeyRequest defines the HTTP request for registering the public
+// key of a voter on a form with signed ballots
+type RegisterVoterKeyRequest struct {
+	VoterID string
+	// PublicKey is the binary Ed25519 public key of the voter
+	PublicKey []byte
+	// Signature is the signature of the voter on the digest of the
+	// registration, which proves that the voter owns the key
+	Signature []byte
+	// Endorsement is the signature of the voter keys authority of the form on
+	// the digest of the registration
+	Endorsement []byte
 }
 
 // CiphervoteJSON is the JSON representation of a ciphervote
@@ -39,12 +79,31 @@ type EGPairJSON struct {
 	C []byte
 }
 
+// ValidateBallotRequest defines the HTTP request for checking a plaintext
+// ballot against the configuration of a form
+type ValidateBallotRequest struct {
+	// Ballot is the marshalled plaintext ballot, as it would be encrypted, see
+	// docs/ballot_encoding.md
+	Ballot string
+}
+
+// ValidateBallotResponse defines the HTTP response when validating a ballot
+type ValidateBallotResponse struct {
+	Valid  bool
+	Errors []etypes.BallotError
+}
+
 // UpdateFormRequest defines the HTTP request for updating a form
 type UpdateFormRequest struct {
 	Action string
 	UserID string
 }
 
+// DeleteFormRequest defines the HTTP request for deleting a form
+type DeleteFormRequest struct {
+	UserID string
+}
+
 // GetFormResponse defines the HTTP response when getting the form info
 type GetFormResponse struct {
 	// FormID is hex-encoded
@@ -52,11 +111,65 @@ type GetFormResponse struct {
 	Configuration   etypes.Configuration
 	Status          uint16
 	Pubkey          string
-	Result          []etypes.Ballot
 	Roster          []string
 	ChunksPerBallot int
 	BallotSize      int
-	Voters          []string
+	// ResultsCount is the number of decrypted ballots, which are listed by
+	// GET /evoting/forms/{formID}/results
+	ResultsCount uint32
+	// VoterCount is the number of users who cast a ballot, which are listed
+	// by GET /evoting/forms/{formID}/voters
+	VoterCount uint32
+	// SuffragiaHashes are the hex-encoded hashes of the batches of cast
+	// ballots, in the order of the batches
+	SuffragiaHashes []string
+	// BallotsRoot is the hex-encoded root of the tree of the ballots cast. It
+	// is set when the form is closed.
+	BallotsRoot string
+	// Imported is true when the form was copied from another chain
+	Imported bool
+}
+
+// GetResultsResponse defines the HTTP response when getting a page of the
+// decrypted ballots
+type GetResultsResponse struct {
+	// FormID is hex-encoded
+	FormID  string
+	Offset  int
+	Limit   int
+	Total   int
+	Ballots []etypes.Ballot
+}
+
+// GetVotersResponse defines the HTTP response when getting a page of the
+// users who cast a ballot
+type GetVotersResponse struct {
+	// FormID is hex-encoded
+	FormID   string
+	Offset   int
+	Limit    int
+	Total    int
+	VoterIDs []string
+}
+
+// GetBallotProofResponse defines the HTTP response when getting the proof
+// that a ballot is in the tree of the ballots cast
+type GetBallotProofResponse struct {
+	// FormID is hex-encoded
+	FormID string
+	// Digest is the hex-encoded digest of the ballot
+	Digest string
+	// Index is the position of the ballot in the tree
+	Index uint32
+	// Size is the number of ballots in the tree
+	Size uint32
+	// Root is the hex-encoded root of the tree
+	Root string
+	// Closed tells if Root is the final root, set when the form is closed
+	Closed bool
+	// Proof contains the hex-encoded hashes of the audit path, from the
+	// leaf to the root
+	Proof []string
 }
 
 // LightForm represents a light version of the form
@@ -71,14 +184,20 @@ type LightForm struct {
 // infos.
 type GetFormsResponse struct {
 	Forms []LightForm
+	// NextCursor is the cursor of the next page of forms, empty on the last
+	// page
+	NextCursor string `json:",omitempty"`
 }
 
 // HTTPError defines the standard error format
 type HTTPError struct {
-	Title   string
-	Code    uint
-	Message string
-	Args    map[string]interface{}
+	Title string
+	// Code is the HTTP status
+	Code uint
+	// ErrorCode is the machine-readable code of the error
+	ErrorCode ErrorCode `json:",omitempty"`
+	Message   string
+	Args      map[string]interface{}
 }
 
 type GetAdminsResponse struct {
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+)
+
+// ErrorCode is the stable, machine-readable code of an error returned by the
+// proxy, set in the HTTPError. The codes are also given to the transactions
+// rejected by the contract.
+type ErrorCode string
+
+const (
+	// CodeInvalidRequest is the code of a malformed request or of a request
+	// that doesn't match its schema.
+	CodeInvalidRequest ErrorCode = "invalid_request"
+	// CodeInvalidFormID is the code of a missing or malformed form ID.
+	CodeInvalidFormID ErrorCode = "invalid_form_id"
+	// CodeInvalidSignature is the code of a signed request that no valid key
+	// verifies.
+	CodeInvalidSignature ErrorCode = "invalid_signature"
+	// CodeRejectedRequest is the code of a signed request that is stale,
+	// replayed or signed for another route.
+	CodeRejectedRequest ErrorCode = "rejected_request"
+	// CodeTooManyRequests is the code of a request rejected because the proxy
+	// receives too many of them.
+	CodeTooManyRequests ErrorCode = "too_many_requests"
+	// CodeForbidden is the code of an operation that the user is not allowed
+	// to perform.
+	CodeForbidden ErrorCode = "forbidden"
+	// CodeNotFound is the code of an unknown endpoint or resource.
+	CodeNotFound ErrorCode = "not_found"
+	// CodeFormNotFound is the code of an unknown form.
+	CodeFormNotFound ErrorCode = "form_not_found"
+	// CodeMethodNotAllowed is the code of a method not supported by the
+	// endpoint.
+	CodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	// CodeInvalidStatus is the code of an operation that the status of the
+	// form doesn't allow.
+	CodeInvalidStatus ErrorCode = "invalid_status"
+	// CodeInvalidBallot is the code of a ballot rejected by the contract.
+	CodeInvalidBallot ErrorCode = "invalid_ballot"
+	// CodeTransactionRejected is the code of a transaction rejected by the
+	// contract for another reason.
+	CodeTransactionRejected ErrorCode = "transaction_rejected"
+	// CodeInternal is the code of an error of the proxy or of the node.
+	CodeInternal ErrorCode = "internal_error"
+)
+
+// NewHTTPError returns the error of the request with the given code. The error
+// and the request are described in the arguments.
+func NewHTTPError(r *http.Request, code ErrorCode, err error, args map[string]interface{}) HTTPError {
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+
+	args["error"] = err.Error()
+	args["url"] = r.URL.String()
+	args["method"] = r.Method
+
+	return HTTPError{
+		Title:     code.Title(),
+		Code:      uint(code.Status()),
+		ErrorCode: code,
+		Message:   "A problem occurred on the proxy",
+		Args:      args,
+	}
+}
+
+// Write writes the error as the JSON response, with its code as status.
+func (e HTTPError) Write(w http.ResponseWriter) {
+	buf, _ := json.MarshalIndent(&e, "", "  ")
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(int(e.Code))
+	fmt.Fprintln(w, string(buf))
+}
+
+// errorStatuses holds the HTTP status and the title of each code.
+var errorStatuses = map[ErrorCode]struct {
+	status int
+	title  string
+}{
+	CodeInvalidRequest:      {http.StatusBadRequest, "bad request"},
+	CodeInvalidFormID:       {http.StatusBadRequest, "bad request"},
+	CodeInvalidSignature:    {http.StatusUnauthorized, "unauthorized"},
+	CodeRejectedRequest:     {http.StatusUnauthorized, "unauthorized"},
+	CodeTooManyRequests:     {http.StatusTooManyRequests, "too many requests"},
+	CodeForbidden:           {http.StatusForbidden, "not authorized / forbidden"},
+	CodeNotFound:            {http.StatusNotFound, "not found"},
+	CodeFormNotFound:        {http.StatusNotFound, "not found"},
+	CodeMethodNotAllowed:    {http.StatusMethodNotAllowed, "not allowed"},
+	CodeInvalidStatus:       {http.StatusConflict, "conflict"},
+	CodeInvalidBallot:       {http.StatusBadRequest, "bad request"},
+	CodeTransactionRejected: {http.StatusUnprocessableEntity, "unprocessable entity"},
+	CodeInternal:            {http.StatusInternalServerError, "Internal server error"},
+}
+
+// Status returns the HTTP status of the code.
+func (c ErrorCode) Status() int {
+	s, found := errorStatuses[c]
+	if !found {
+		return http.StatusInternalServerError
+	}
+
+	return s.status
+}
+
+// Title returns the title of the HTTPError of the code.
+func (c ErrorCode) Title() string {
+	s, found := errorStatuses[c]
+	if !found {
+		return "Internal server error"
+	}
+
+	return s.title
+}
+
+// rejectionCodes maps the kinds of the rejections of the contract to the
+// codes.
+var rejectionCodes = map[etypes.RejectionKind]ErrorCode{
+	etypes.RejectForbidden:     CodeForbidden,
+	etypes.RejectInvalidStatus: CodeInvalidStatus,
+	etypes.RejectInvalidBallot: CodeInvalidBallot,
+	etypes.RejectFormNotFound:  CodeFormNotFound,
+}
+
+// ReasonCode returns the code of the reason why the contract rejected a
+// transaction. The invalid ballots of a batch and the rejections of the
+// contract carry their kind in the reason, the other reasons have the generic
+// code.
+func ReasonCode(reason string) ErrorCode {
+	_, found := etypes.ParseBallotErrors(reason)
+	if found {
+		return CodeInvalidBallot
+	}
+
+	kind, found := etypes.ParseRejection(reason)
+	if !found {
+		return CodeTransactionRejected
+	}
+
+	code, found := rejectionCodes[kind]
+	if !found {
+		return CodeTransactionRejected
+	}
+
+	return code
+}
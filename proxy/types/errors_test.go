@@ -0,0 +1,58 @@
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+)
+
+func TestErrorCode_Status(t *testing.T) {
+	require.Equal(t, http.StatusUnauthorized, CodeInvalidSignature.Status())
+	require.Equal(t, http.StatusConflict, CodeInvalidStatus.Status())
+	require.Equal(t, http.StatusInternalServerError, ErrorCode("unknown").Status())
+
+	for code := range errorStatuses {
+		require.NotEmpty(t, code.Title(), code)
+	}
+}
+
+func TestHTTPError_Write(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/evoting/forms/abcd", nil)
+
+	w := httptest.NewRecorder()
+	NewHTTPError(r, CodeFormNotFound, xerrors.New("oops"), nil).Write(w)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var res HTTPError
+
+	err := json.Unmarshal(w.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, CodeFormNotFound, res.ErrorCode)
+	require.Equal(t, uint(http.StatusNotFound), res.Code)
+	require.Equal(t, "oops", res.Args["error"])
+	require.Equal(t, "/evoting/forms/abcd", res.Args["url"])
+}
+
+func TestReasonCode(t *testing.T) {
+	tests := map[string]ErrorCode{
+		"failed to execute: [forbidden] The user 123 doesn't have the Voter " +
+			"permission on the form.": CodeForbidden,
+		"failed to cast votes: invalid ballots: {\"0\":\"[forbidden] The user " +
+			"123 doesn't have the Voter permission on the form.\"}": CodeInvalidBallot,
+		"[invalid_status] the form is not open, current status: 0":      CodeInvalidStatus,
+		"failed to get form: [form_not_found] no form found":            CodeFormNotFound,
+		"failed to get form: failed to deserialize Form: oops":          CodeTransactionRejected,
+		"the user doesn't have the Voter permission, current status: 1": CodeTransactionRejected,
+		"something else": CodeTransactionRejected,
+	}
+
+	for reason, expected := range tests {
+		require.Equal(t, expected, ReasonCode(reason), reason)
+	}
+}
@@ -0,0 +1,69 @@
+package types
+
+// The names of the server-sent events of GET /evoting/events.
+const (
+	TransactionEventName = "transaction"
+	StatusEventName      = "status"
+	BallotsEventName     = "ballots"
+	ShuffleEventName     = "shuffle"
+	PubsharesEventName   = "pubshares"
+)
+
+// TransactionEvent is sent when a transaction of the evoting contract is
+// included in a block, accepted or not.
+type TransactionEvent struct {
+	// Block is the index of the block
+	Block uint64
+	// TransactionID is hex-encoded
+	TransactionID string
+	// FormID is hex-encoded, empty for the transactions that don't concern a
+	// form, like the ones on the admin list
+	FormID   string
+	Command  string
+	Accepted bool
+	// Reason is the reason of the rejection
+	Reason string `json:",omitempty"`
+	// ProxyKey is the name of the key of the web backend that signed the
+	// request of the transaction, if it was submitted by a proxy
+	ProxyKey string `json:",omitempty"`
+}
+
+// StatusEvent is sent when a stream sees a form for the first time and every
+// time its status changes.
+type StatusEvent struct {
+	Block  uint64
+	FormID string
+	Status uint16
+	// Previous is the status before the block, missing for the first event of
+	// the form
+	Previous *uint16 `json:",omitempty"`
+}
+
+// BallotsEvent is sent when ballots are cast on a form.
+type BallotsEvent struct {
+	Block  uint64
+	FormID string
+	// Cast is the number of ballots cast on the form in the block
+	Cast int
+}
+
+// ShuffleEvent is sent when a shuffle of the ballots of a form is accepted.
+type ShuffleEvent struct {
+	Block  uint64
+	FormID string
+	// Round is the round of the shuffle
+	Round int
+	// ShuffleCount is the number of shuffles of the form
+	ShuffleCount     int
+	ShuffleThreshold int
+}
+
+// PubsharesEvent is sent when a node submits its public shares.
+type PubsharesEvent struct {
+	Block  uint64
+	FormID string
+	// Index is the index of the node in the DKG
+	Index int
+	// Submitted is the number of nodes that submitted their shares
+	Submitted int
+}
@@ -0,0 +1,43 @@
+package types
+
+// HealthResponse defines the report of GET /health
+type HealthResponse struct {
+	Ready bool
+	// Reasons tell why the node is not ready
+	Reasons   []string `json:",omitempty"`
+	Version   string
+	BuildTime string
+	// BlockIndex is the index of the last block of the chain
+	BlockIndex uint64
+	Roster     []RosterMember
+	// PoolSize is the number of transactions in the pool
+	PoolSize int
+	// DKG is the status of the DKG actor of each open form
+	DKG []DKGActorHealth
+	// ShuffleActor is true if the shuffle actor is initialized
+	ShuffleActor bool
+	// Handlers are the sets of handlers registered on the proxy
+	Handlers []string
+}
+
+// RosterMember defines a member of the roster and whether it answered a ping
+// over mino.
+type RosterMember struct {
+	Address   string
+	Reachable bool
+	Error     string `json:",omitempty"`
+}
+
+// DKGActorHealth defines the status of the DKG actor of an open form. Status
+// is -1 if the node has no actor for the form.
+type DKGActorHealth struct {
+	FormID string
+	Status int
+	Error  string `json:",omitempty"`
+}
+
+// ReadyResponse defines the response of GET /ready
+type ReadyResponse struct {
+	Ready   bool
+	Reasons []string `json:",omitempty"`
+}
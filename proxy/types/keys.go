@@ -0,0 +1,304 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"go.dedis.ch/kyber/v3"
+	"golang.org/x/xerrors"
+)
+
+// ProxyKey is a named public key of a web backend, trusted to sign the
+// requests during its validity period.
+type ProxyKey struct {
+	Name string
+	Key  kyber.Point
+	// NotBefore is the time from which the key is valid, zero if it is valid
+	// since ever
+	NotBefore time.Time
+	// NotAfter is the time from which the key is retired, zero if it is valid
+	// until it is retired
+	NotAfter time.Time
+}
+
+// ValidAt returns true if the key is valid at the given time.
+func (k ProxyKey) ValidAt(t time.Time) bool {
+	if !k.NotBefore.IsZero() && t.Before(k.NotBefore) {
+		return false
+	}
+
+	return k.NotAfter.IsZero() || t.Before(k.NotAfter)
+}
+
+// proxyKeyJSON is the JSON format of a key in the file of a key ring.
+type proxyKeyJSON struct {
+	Name      string
+	Key       string     // hex encoded
+	NotBefore *time.Time `json:",omitempty"`
+	NotAfter  *time.Time `json:",omitempty"`
+}
+
+// KeyRing holds the keys of the web backends that the proxy trusts. Several
+// keys can be valid at the same time, which lets the backends rotate their key
+// without a downtime of the nodes: the new key is added, the backends switch
+// to it, and the old key is retired.
+type KeyRing struct {
+	sync.RWMutex
+
+	// path is the file where the keys are saved, empty if they are only kept
+	// in memory
+	path string
+	keys []ProxyKey
+}
+
+// NewKeyRing returns a new key ring, kept in memory, with the given keys.
+func NewKeyRing(keys ...ProxyKey) *KeyRing {
+	return &KeyRing{
+		keys: keys,
+	}
+}
+
+// LoadKeyRing returns the key ring saved in the file, or an empty one if the
+// file doesn't exist yet. The changes to the key ring are saved in the file.
+func LoadKeyRing(path string) (*KeyRing, error) {
+	ring := &KeyRing{
+		path: path,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ring, nil
+	}
+
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read %s: %v", path, err)
+	}
+
+	var keys []proxyKeyJSON
+
+	err = json.Unmarshal(data, &keys)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal %s: %v", path, err)
+	}
+
+	for _, key := range keys {
+		point, err := DecodeProxyKey(key.Key)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to decode key %q: %v", key.Name, err)
+		}
+
+		proxyKey := ProxyKey{
+			Name: key.Name,
+			Key:  point,
+		}
+
+		if key.NotBefore != nil {
+			proxyKey.NotBefore = *key.NotBefore
+		}
+
+		if key.NotAfter != nil {
+			proxyKey.NotAfter = *key.NotAfter
+		}
+
+		ring.keys = append(ring.keys, proxyKey)
+	}
+
+	return ring, nil
+}
+
+// DecodeProxyKey returns the public key from its hex encoding.
+func DecodeProxyKey(keyHex string) (kyber.Point, error) {
+	buf, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode hex: %v", err)
+	}
+
+	point := suite.Point()
+
+	err = point.UnmarshalBinary(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal point: %v", err)
+	}
+
+	return point, nil
+}
+
+// Keys returns the keys of the ring, including the retired ones.
+func (r *KeyRing) Keys() []ProxyKey {
+	r.RLock()
+	defer r.RUnlock()
+
+	keys := make([]ProxyKey, len(r.keys))
+	copy(keys, r.keys)
+
+	return keys
+}
+
+// Add adds a key to the ring. The name must not be used by another key, even a
+// retired one, so that the name recorded for a request always designates the
+// same key.
+func (r *KeyRing) Add(key ProxyKey) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if key.Name == "" {
+		return xerrors.Errorf("missing key name")
+	}
+
+	if key.Key == nil {
+		return xerrors.Errorf("missing key")
+	}
+
+	if !key.NotAfter.IsZero() && !key.NotAfter.After(key.NotBefore) {
+		return xerrors.Errorf("key %q is retired before it is valid", key.Name)
+	}
+
+	for _, k := range r.keys {
+		if k.Name == key.Name {
+			return xerrors.Errorf("key %q already exists", key.Name)
+		}
+	}
+
+	r.keys = append(r.keys, key)
+
+	err := r.save()
+	if err != nil {
+		r.keys = r.keys[:len(r.keys)-1]
+		return xerrors.Errorf("failed to save: %v", err)
+	}
+
+	return nil
+}
+
+// Retire retires the key at the given time. The requests signed with the key
+// are rejected from that time on.
+func (r *KeyRing) Retire(name string, at time.Time) error {
+	r.Lock()
+	defer r.Unlock()
+
+	for i, k := range r.keys {
+		if k.Name != name {
+			continue
+		}
+
+		if !at.After(k.NotBefore) {
+			return xerrors.Errorf("key %q is retired before it is valid", name)
+		}
+
+		r.keys[i].NotAfter = at
+
+		err := r.save()
+		if err != nil {
+			r.keys[i] = k
+			return xerrors.Errorf("failed to save: %v", err)
+		}
+
+		return nil
+	}
+
+	return xerrors.Errorf("unknown key %q", name)
+}
+
+// Verify verifies the signature of the request with the keys valid at the
+// given time and returns the name of the key that signed it. The request is
+// only verified with the key it names, if any.
+func (r *KeyRing) Verify(signed SignedRequest, now time.Time) (string, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	if signed.KeyID != "" {
+		for _, k := range r.keys {
+			if k.Name != signed.KeyID {
+				continue
+			}
+
+			if !k.ValidAt(now) {
+				return "", xerrors.Errorf("key %q is not valid at %s", k.Name,
+					now.UTC().Format(time.RFC3339))
+			}
+
+			err := signed.Verify(k.Key)
+			if err != nil {
+				return "", xerrors.Errorf("failed to verify with key %q: %v", k.Name, err)
+			}
+
+			return k.Name, nil
+		}
+
+		return "", xerrors.Errorf("unknown key %q", signed.KeyID)
+	}
+
+	var err error
+
+	for _, k := range r.keys {
+		if !k.ValidAt(now) {
+			continue
+		}
+
+		err = signed.Verify(k.Key)
+		if err == nil {
+			return k.Name, nil
+		}
+	}
+
+	if err == nil {
+		return "", xerrors.Errorf("no key is valid at %s", now.UTC().Format(time.RFC3339))
+	}
+
+	return "", xerrors.Errorf("no valid key verifies the request: %v", err)
+}
+
+// save writes the keys in the file of the ring, if any. The lock must be held.
+func (r *KeyRing) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	keys := make([]proxyKeyJSON, len(r.keys))
+
+	for i, k := range r.keys {
+		buf, err := k.Key.MarshalBinary()
+		if err != nil {
+			return xerrors.Errorf("failed to marshal key %q: %v", k.Name, err)
+		}
+
+		keys[i] = proxyKeyJSON{
+			Name: k.Name,
+			Key:  hex.EncodeToString(buf),
+		}
+
+		if !k.NotBefore.IsZero() {
+			notBefore := k.NotBefore
+			keys[i].NotBefore = &notBefore
+		}
+
+		if !k.NotAfter.IsZero() {
+			notAfter := k.NotAfter
+			keys[i].NotAfter = &notAfter
+		}
+	}
+
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("failed to marshal keys: %v", err)
+	}
+
+	// the keys are written to a temporary file first, so that a crash never
+	// leaves a truncated file
+	tmp := r.path + ".tmp"
+
+	err = os.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return xerrors.Errorf("failed to write %s: %v", tmp, err)
+	}
+
+	err = os.Rename(tmp, r.path)
+	if err != nil {
+		return xerrors.Errorf("failed to rename %s: %v", tmp, err)
+	}
+
+	return nil
+}
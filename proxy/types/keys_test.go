@@ -0,0 +1,171 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
+)
+
+func TestProxyKey_ValidAt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	require.True(t, ProxyKey{}.ValidAt(now))
+
+	key := ProxyKey{NotBefore: now, NotAfter: now.Add(time.Hour)}
+	require.False(t, key.ValidAt(now.Add(-time.Second)))
+	require.True(t, key.ValidAt(now))
+	require.True(t, key.ValidAt(now.Add(time.Minute)))
+	require.False(t, key.ValidAt(now.Add(time.Hour)))
+}
+
+func TestKeyRing_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	oldSecret, oldPublic := newKeyPair()
+	newSecret, newPublic := newKeyPair()
+	otherSecret, _ := newKeyPair()
+
+	ring := NewKeyRing(
+		ProxyKey{Name: "old", Key: oldPublic, NotAfter: now.Add(time.Hour)},
+		ProxyKey{Name: "new", Key: newPublic, NotBefore: now.Add(-time.Hour)},
+	)
+
+	signed, err := SignRequest(oldSecret, "POST /evoting/forms", "hello")
+	require.NoError(t, err)
+
+	name, err := ring.Verify(signed, now)
+	require.NoError(t, err)
+	require.Equal(t, "old", name)
+
+	signed, err = SignRequest(newSecret, "POST /evoting/forms", "hello")
+	require.NoError(t, err)
+
+	name, err = ring.Verify(signed, now)
+	require.NoError(t, err)
+	require.Equal(t, "new", name)
+
+	signed.KeyID = "new"
+
+	name, err = ring.Verify(signed, now)
+	require.NoError(t, err)
+	require.Equal(t, "new", name)
+
+	signed.KeyID = "old"
+
+	_, err = ring.Verify(signed, now)
+	require.ErrorContains(t, err, "failed to verify with key \"old\": invalid signature")
+
+	signed.KeyID = "unknown"
+
+	_, err = ring.Verify(signed, now)
+	require.EqualError(t, err, "unknown key \"unknown\"")
+
+	signed, err = SignRequest(oldSecret, "POST /evoting/forms", "hello")
+	require.NoError(t, err)
+
+	_, err = ring.Verify(signed, now.Add(time.Hour))
+	require.ErrorContains(t, err, "no valid key verifies the request")
+
+	signed.KeyID = "old"
+
+	_, err = ring.Verify(signed, now.Add(time.Hour))
+	require.EqualError(t, err, "key \"old\" is not valid at 2023-11-14T23:13:20Z")
+
+	signed, err = SignRequest(otherSecret, "POST /evoting/forms", "hello")
+	require.NoError(t, err)
+
+	_, err = ring.Verify(signed, now)
+	require.ErrorContains(t, err, "no valid key verifies the request")
+
+	_, err = NewKeyRing().Verify(signed, now)
+	require.EqualError(t, err, "no key is valid at 2023-11-14T22:13:20Z")
+}
+
+func TestKeyRing_AddRetire(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	_, public := newKeyPair()
+
+	ring := NewKeyRing()
+
+	err := ring.Add(ProxyKey{Key: public})
+	require.EqualError(t, err, "missing key name")
+
+	err = ring.Add(ProxyKey{Name: "a"})
+	require.EqualError(t, err, "missing key")
+
+	err = ring.Add(ProxyKey{Name: "a", Key: public, NotBefore: now, NotAfter: now})
+	require.EqualError(t, err, "key \"a\" is retired before it is valid")
+
+	err = ring.Add(ProxyKey{Name: "a", Key: public, NotBefore: now})
+	require.NoError(t, err)
+
+	err = ring.Add(ProxyKey{Name: "a", Key: public})
+	require.EqualError(t, err, "key \"a\" already exists")
+
+	err = ring.Retire("b", now)
+	require.EqualError(t, err, "unknown key \"b\"")
+
+	err = ring.Retire("a", now.Add(-time.Hour))
+	require.EqualError(t, err, "key \"a\" is retired before it is valid")
+
+	err = ring.Retire("a", now.Add(time.Hour))
+	require.NoError(t, err)
+
+	keys := ring.Keys()
+	require.Len(t, keys, 1)
+	require.Equal(t, now.Add(time.Hour), keys[0].NotAfter)
+}
+
+func TestKeyRing_Load(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	_, public := newKeyPair()
+
+	path := filepath.Join(t.TempDir(), "proxykeys.json")
+
+	ring, err := LoadKeyRing(path)
+	require.NoError(t, err)
+	require.Empty(t, ring.Keys())
+
+	err = ring.Add(ProxyKey{Name: "a", Key: public})
+	require.NoError(t, err)
+
+	err = ring.Add(ProxyKey{Name: "b", Key: public, NotBefore: now})
+	require.NoError(t, err)
+
+	err = ring.Retire("a", now)
+	require.NoError(t, err)
+
+	loaded, err := LoadKeyRing(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Keys(), 2)
+
+	for i, key := range loaded.Keys() {
+		expected := ring.Keys()[i]
+
+		require.Equal(t, expected.Name, key.Name)
+		require.True(t, expected.Key.Equal(key.Key))
+		require.True(t, expected.NotBefore.Equal(key.NotBefore))
+		require.True(t, expected.NotAfter.Equal(key.NotAfter))
+	}
+
+	err = os.WriteFile(path, []byte("[{\"Name\": \"a\", \"Key\": \"zz\"}]"), 0600)
+	require.NoError(t, err)
+
+	_, err = LoadKeyRing(path)
+	require.ErrorContains(t, err, "failed to decode key \"a\": failed to decode hex")
+
+	err = os.WriteFile(path, []byte("dummy"), 0600)
+	require.NoError(t, err)
+
+	_, err = LoadKeyRing(path)
+	require.ErrorContains(t, err, "failed to unmarshal")
+}
+
+func newKeyPair() (kyber.Scalar, kyber.Point) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	return secret, suite.Point().Mul(secret, nil)
+}
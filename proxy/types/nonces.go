@@ -0,0 +1,79 @@
+package types
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrCacheFull is the error of a nonce that can't be added because the cache
+// is full.
+var ErrCacheFull = xerrors.New("too many requests")
+
+// NonceCache remembers the nonces of the signed requests until the requests
+// would be stale anyway, to reject the replayed ones. It holds a bounded
+// number of nonces and rejects the requests once it is full.
+type NonceCache struct {
+	sync.Mutex
+
+	size     int
+	expiries map[string]time.Time
+	// queue holds the nonces in the order they were added, which is also the
+	// order of their expiry.
+	queue []string
+}
+
+// NewNonceCache returns a new cache that holds at most size nonces.
+func NewNonceCache(size int) *NonceCache {
+	return &NonceCache{
+		size:     size,
+		expiries: make(map[string]time.Time),
+	}
+}
+
+// Add records the nonce of a request received at the given time. It fails if
+// the nonce is already known or if the cache is full.
+func (c *NonceCache) Add(nonce string, now time.Time) error {
+	c.Lock()
+	defer c.Unlock()
+
+	c.prune(now)
+
+	_, found := c.expiries[nonce]
+	if found {
+		return xerrors.Errorf("nonce %s already used", nonce)
+	}
+
+	if len(c.queue) >= c.size {
+		return xerrors.Errorf("the cache of %d nonces is full: %w", c.size, ErrCacheFull)
+	}
+
+	// A request is accepted if it was issued at most MaxRequestAge in the
+	// future and is rejected MaxRequestAge after it was issued, so its nonce
+	// is not needed after twice that time.
+	c.expiries[nonce] = now.Add(2 * MaxRequestAge)
+	c.queue = append(c.queue, nonce)
+
+	return nil
+}
+
+// Len returns the number of nonces in the cache.
+func (c *NonceCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return len(c.queue)
+}
+
+// prune removes the expired nonces.
+func (c *NonceCache) prune(now time.Time) {
+	i := 0
+
+	for i < len(c.queue) && now.After(c.expiries[c.queue[i]]) {
+		delete(c.expiries, c.queue[i])
+		i++
+	}
+
+	c.queue = c.queue[i:]
+}
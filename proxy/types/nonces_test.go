@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceCache_Add(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cache := NewNonceCache(2)
+
+	err := cache.Add("aa", now)
+	require.NoError(t, err)
+
+	err = cache.Add("aa", now)
+	require.EqualError(t, err, "nonce aa already used")
+
+	err = cache.Add("bb", now.Add(time.Second))
+	require.NoError(t, err)
+
+	err = cache.Add("cc", now.Add(time.Second))
+	require.EqualError(t, err, "the cache of 2 nonces is full: too many requests")
+
+	// the first nonce expires, which makes room for a new one
+	err = cache.Add("cc", now.Add(2*MaxRequestAge+time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Len())
+
+	err = cache.Add("dd", now.Add(3*MaxRequestAge))
+	require.NoError(t, err)
+	require.Equal(t, 2, cache.Len())
+
+	err = cache.Add("ee", now.Add(3*MaxRequestAge))
+	require.EqualError(t, err, "the cache of 2 nonces is full: too many requests")
+
+	// an expired nonce can be added again, its request is stale anyway
+	err = cache.Add("aa", now.Add(5*MaxRequestAge+time.Second))
+	require.NoError(t, err)
+	require.Equal(t, 1, cache.Len())
+}
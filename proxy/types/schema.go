@@ -0,0 +1,447 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// componentsPath is the prefix of the references to the named schemas of an
+// OpenAPI document.
+const componentsPath = "#/components/schemas/"
+
+// Schema is a JSON schema, in the subset used by OpenAPI 3.0, that describes a
+// Go type as encoded by encoding/json.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Schemas builds the schemas of Go types and keeps the schemas of the named
+// structures, which are referenced by the others.
+//
+// The schemas are strict: all the fields of a structure are required, except
+// the ones tagged with omitempty, and unknown fields are rejected. Unlike
+// encoding/json, the names of the fields are case sensitive.
+type Schemas struct {
+	sync.Mutex
+
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+// NewSchemas returns a new empty set of schemas.
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// SchemaOf returns the schema of the type of v. A named structure is
+// returned as a reference to its component.
+func (s *Schemas) SchemaOf(v interface{}) *Schema {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.schemaOf(reflect.TypeOf(v))
+}
+
+// Components returns the schemas of the named structures, by name.
+func (s *Schemas) Components() map[string]*Schema {
+	s.Lock()
+	defer s.Unlock()
+
+	components := make(map[string]*Schema, len(s.components))
+	for name, schema := range s.components {
+		components[name] = schema
+	}
+
+	return components
+}
+
+// Validate checks that the JSON data matches the schema of the type of v,
+// which can be a pointer to the message.
+func (s *Schemas) Validate(data []byte, v interface{}) error {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return xerrors.Errorf("invalid json: %v", err)
+	}
+
+	if decoder.More() {
+		return xerrors.Errorf("invalid json: unexpected data after the value")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return s.validate(value, s.schemaOf(t), "$")
+}
+
+func (s *Schemas) schemaOf(t reflect.Type) *Schema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem := s.schemaOf(t.Elem())
+		if elem.Ref != "" {
+			return &Schema{AnyOf: []*Schema{elem}, Nullable: true}
+		}
+
+		elem.Nullable = true
+
+		return elem
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intSchema(t.Bits(), true)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return intSchema(t.Bits(), false)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes the bytes in base64 but also decodes an
+			// array of numbers
+			return &Schema{
+				AnyOf: []*Schema{
+					{Type: "string", Format: "byte"},
+					{Type: "array", Items: intSchema(8, false)},
+				},
+				Nullable: true,
+			}
+		}
+
+		return &Schema{
+			Type:     "array",
+			Items:    s.schemaOf(t.Elem()),
+			Nullable: t.Kind() == reflect.Slice,
+		}
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: s.schemaOf(t.Elem()),
+			Nullable:             true,
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+
+		return &Schema{Ref: componentsPath + s.component(t)}
+	}
+
+	return &Schema{Description: "unsupported type " + t.String()}
+}
+
+// component returns the name of the component of the structure, which is
+// built the first time.
+func (s *Schemas) component(t reflect.Type) string {
+	name, found := s.names[t]
+	if found {
+		return name
+	}
+
+	name = t.Name()
+
+	_, taken := s.components[name]
+	if taken {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + name
+	}
+
+	// the name is reserved first, so that a recursive structure references
+	// itself
+	s.names[t] = name
+	s.components[name] = &Schema{}
+	s.components[name] = s.structSchema(t)
+
+	return name
+}
+
+func (s *Schemas) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	s.addFields(schema, t)
+
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+// addFields adds the exported fields of the structure to the schema. The
+// fields of the embedded structures without a name are promoted, as done by
+// encoding/json.
+func (s *Schemas) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				s.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = s.schemaOf(field.Type)
+
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func intSchema(bits int, signed bool) *Schema {
+	schema := &Schema{Type: "integer", Format: "int64"}
+
+	if bits < 32 || (signed && bits == 32) {
+		schema.Format = "int32"
+	}
+
+	if !signed {
+		min := 0.0
+		schema.Minimum = &min
+	}
+
+	if bits < 64 {
+		var min, max float64
+
+		if signed {
+			min = -math.Pow(2, float64(bits-1))
+			max = math.Pow(2, float64(bits-1)) - 1
+		} else {
+			max = math.Pow(2, float64(bits)) - 1
+		}
+
+		schema.Minimum = &min
+		schema.Maximum = &max
+	}
+
+	return schema
+}
+
+// validate checks the value decoded from JSON against the schema. path is the
+// location of the value, used in the errors.
+func (s *Schemas) validate(value interface{}, schema *Schema, path string) error {
+	if schema.Ref != "" {
+		return s.validate(value, s.components[strings.TrimPrefix(schema.Ref, componentsPath)], path)
+	}
+
+	if value == nil {
+		if schema.Nullable || (schema.Type == "" && schema.AnyOf == nil) {
+			return nil
+		}
+
+		return xerrors.Errorf("%s: must not be null", path)
+	}
+
+	if schema.AnyOf != nil {
+		var first error
+
+		for _, alt := range schema.AnyOf {
+			err := s.validate(value, alt, path)
+			if err == nil {
+				return nil
+			}
+
+			if first == nil {
+				first = err
+			}
+		}
+
+		return first
+	}
+
+	switch schema.Type {
+	case "object":
+		return s.validateObject(value, schema, path)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return xerrors.Errorf("%s: must be an array", path)
+		}
+
+		for i, item := range items {
+			err := s.validate(item, schema.Items, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return xerrors.Errorf("%s: must be a string", path)
+		}
+
+		return validateFormat(str, schema.Format, path)
+	case "boolean":
+		_, ok := value.(bool)
+		if !ok {
+			return xerrors.Errorf("%s: must be a boolean", path)
+		}
+	case "integer", "number":
+		return validateNumber(value, schema, path)
+	}
+
+	return nil
+}
+
+func (s *Schemas) validateObject(value interface{}, schema *Schema, path string) error {
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return xerrors.Errorf("%s: must be an object", path)
+	}
+
+	for _, name := range schema.Required {
+		_, found := fields[name]
+		if !found {
+			return xerrors.Errorf("%s: missing field %q", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	// sorted to always report the same error
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := schema.Properties[name]
+
+		if field == nil {
+			additional, ok := schema.AdditionalProperties.(*Schema)
+			if !ok {
+				return xerrors.Errorf("%s: unknown field %q", path, name)
+			}
+
+			field = additional
+		}
+
+		err := s.validate(fields[name], field, path+"."+name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateFormat(str, format, path string) error {
+	switch format {
+	case "byte":
+		_, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return xerrors.Errorf("%s: must be base64: %v", path, err)
+		}
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, str)
+		if err != nil {
+			return xerrors.Errorf("%s: must be a RFC 3339 time: %v", path, err)
+		}
+	}
+
+	return nil
+}
+
+func validateNumber(value interface{}, schema *Schema, path string) error {
+	number, ok := value.(json.Number)
+	if !ok {
+		return xerrors.Errorf("%s: must be a number", path)
+	}
+
+	var f float64
+
+	if schema.Type == "integer" {
+		if schema.Minimum != nil && *schema.Minimum == 0 {
+			n, err := strconv.ParseUint(number.String(), 10, 64)
+			if err != nil {
+				return xerrors.Errorf("%s: must be an unsigned integer", path)
+			}
+
+			f = float64(n)
+		} else {
+			n, err := strconv.ParseInt(number.String(), 10, 64)
+			if err != nil {
+				return xerrors.Errorf("%s: must be an integer", path)
+			}
+
+			f = float64(n)
+		}
+	} else {
+		n, err := number.Float64()
+		if err != nil {
+			return xerrors.Errorf("%s: must be a number", path)
+		}
+
+		f = n
+	}
+
+	if schema.Minimum != nil && f < *schema.Minimum {
+		return xerrors.Errorf("%s: must be at least %v", path, *schema.Minimum)
+	}
+
+	if schema.Maximum != nil && f > *schema.Maximum {
+		return xerrors.Errorf("%s: must be at most %v", path, *schema.Maximum)
+	}
+
+	return nil
+}
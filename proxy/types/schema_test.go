@@ -0,0 +1,102 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessage struct {
+	Name     string
+	Count    uint8
+	Data     []byte
+	Children []fakeMessage
+	Labels   map[string]int
+	At       time.Time `json:",omitempty"`
+	Renamed  bool      `json:"other,omitempty"`
+	Ignored  string    `json:"-"`
+	hidden   string
+}
+
+func TestSchemas_SchemaOf(t *testing.T) {
+	schemas := NewSchemas()
+
+	schema := schemas.SchemaOf(fakeMessage{})
+	require.Equal(t, "#/components/schemas/fakeMessage", schema.Ref)
+
+	component := schemas.Components()["fakeMessage"]
+	require.Equal(t, "object", component.Type)
+	require.Equal(t, false, component.AdditionalProperties)
+	require.Equal(t, []string{"Children", "Count", "Data", "Labels", "Name"}, component.Required)
+	require.Len(t, component.Properties, 7)
+	require.Equal(t, "#/components/schemas/fakeMessage", component.Properties["Children"].Items.Ref)
+	require.Equal(t, "date-time", component.Properties["At"].Format)
+	require.Equal(t, 255.0, *component.Properties["Count"].Maximum)
+	require.NotNil(t, component.Properties["other"])
+
+	schemas.SchemaOf(CreateFormRequest{})
+
+	components := schemas.Components()
+	require.Contains(t, components, "Configuration")
+	require.Contains(t, components, "Subject")
+}
+
+func TestSchemas_Validate(t *testing.T) {
+	schemas := NewSchemas()
+
+	valid := `{"Name": "a", "Count": 3, "Data": "AQI=", "Labels": {"x": -1},
+		"Children": [{"Name": "b", "Count": 0, "Data": [1, 2], "Labels": null,
+		"Children": null, "At": "2024-01-01T00:00:00Z", "other": true}]}`
+
+	err := schemas.Validate([]byte(valid), &fakeMessage{})
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		`{"Name": "a"`: "invalid json",
+		`{} {}`:        "invalid json: unexpected data after the value",
+		`[]`:           "$: must be an object",
+		`{"Name": "a", "Count": 3, "Data": null, "Labels": null}`:                               `$: missing field "Children"`,
+		`{"Name": "a", "Count": 3, "Data": null, "Labels": null, "Children": null, "name": ""}`: `$: unknown field "name"`,
+		`{"Name": 1, "Count": 3, "Data": null, "Labels": null, "Children": null}`:               "$.Name: must be a string",
+		`{"Name": null, "Count": 3, "Data": null, "Labels": null, "Children": null}`:            "$.Name: must not be null",
+		`{"Name": "", "Count": 256, "Data": null, "Labels": null, "Children": null}`:            "$.Count: must be at most 255",
+		`{"Name": "", "Count": -1, "Data": null, "Labels": null, "Children": null}`:             "$.Count: must be an unsigned integer",
+		`{"Name": "", "Count": 1.5, "Data": null, "Labels": null, "Children": null}`:            "$.Count: must be an unsigned integer",
+		`{"Name": "", "Count": 1, "Data": "!", "Labels": null, "Children": null}`:               "$.Data: must be base64",
+		`{"Name": "", "Count": 1, "Data": null, "Labels": {"x": "y"}, "Children": null}`:        "$.Labels.x: must be a number",
+		`{"Name": "", "Count": 1, "Data": null, "Labels": null, "Children": [{}]}`:              `$.Children[0]: missing field "Children"`,
+		`{"Name": "", "Count": 1, "Data": null, "Labels": null, "Children": null, "At": "x"}`:   "$.At: must be a RFC 3339 time",
+	}
+
+	for data, expected := range tests {
+		err = schemas.Validate([]byte(data), &fakeMessage{})
+		require.ErrorContains(t, err, expected, data)
+	}
+}
+
+func TestSchemas_ValidateRequests(t *testing.T) {
+	schemas := NewSchemas()
+
+	create := `{"UserID": "123456", "Configuration": {"Title": {"En": "a", "Fr": "", "De": "",
+		"URL": ""}, "Scaffold": [{"ID": "s1", "Title": {"En": "", "Fr": "", "De": "", "URL": ""},
+		"Order": ["q1"], "Subjects": [], "Texts": [], "Ranks": [], "Selects": [{"ID": "q1",
+		"Title": {"En": "", "Fr": "", "De": "", "URL": ""}, "MaxN": 1, "MinN": 1,
+		"Choices": [{"Choice": "yes", "URL": ""}], "Hint": {"En": "", "Fr": "", "De": ""}}]}],
+		"AdditionalInfo": ""}}`
+
+	err := schemas.Validate([]byte(create), &CreateFormRequest{})
+	require.NoError(t, err)
+
+	err = schemas.Validate([]byte(`{"UserID": "123456", "Configuration": {"Title": {}}}`),
+		&CreateFormRequest{})
+	require.EqualError(t, err, `$.Configuration: missing field "AdditionalInfo"`)
+
+	err = schemas.Validate([]byte(`{"VoterID": "1", "Ballot": [{"K": [1], "C": "AQ=="}]}`),
+		&CastVoteRequest{})
+	require.NoError(t, err)
+
+	err = schemas.Validate([]byte(`{"VoterID": "1", "Ballot": [], "UserID": "1"}`),
+		&CastVoteRequest{})
+	require.EqualError(t, err, `$: unknown field "UserID"`)
+}
@@ -4,3 +4,14 @@ package types
 type UpdateShuffle struct {
 	Action string
 }
+
+// GetShuffleInfo defines the result of a get shuffle info
+type GetShuffleInfo struct {
+	// Status is 0 if idle, 1 if running, 2 if done and 3 if failed
+	Status    int
+	Round     int
+	Threshold int
+	Nodes     []string
+	// Error is the error of the shuffle, only in the failed status
+	Error *HTTPError `json:",omitempty"`
+}
@@ -1,11 +1,15 @@
 package types
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"time"
 
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
@@ -15,6 +19,14 @@ import (
 
 var suite = suites.MustFind("ed25519")
 
+// MaxRequestAge is how long a signed request is accepted after it was issued.
+// The requests issued further in the future, because of a clock skew, are
+// rejected too.
+const MaxRequestAge = time.Minute
+
+// nonceSize is the number of random bytes of a nonce.
+const nonceSize = 16
+
 // NewSignedRequest returns a new initialized signed request
 func NewSignedRequest(r io.Reader) (SignedRequest, error) {
 	var req SignedRequest
@@ -28,18 +40,87 @@ func NewSignedRequest(r io.Reader) (SignedRequest, error) {
 	return req, nil
 }
 
-// SignedRequest represents a frontend request signed by the web backend.
+// SignedRequest represents a frontend request signed by the web backend. The
+// signature covers the route, the time and the nonce of the request so that it
+// can't be sent again or to another endpoint.
 type SignedRequest struct {
-	Payload   string // url base64 encoded json message
-	Signature string // hex encoded signature on sha256(Payload)
+	Payload string // url base64 encoded json message
+	// IssuedAt is the Unix time, in seconds, at which the request was signed
+	IssuedAt int64
+	// Nonce is a random hex string, never used twice
+	Nonce string
+	// Route is the method and the path of the endpoint, as in
+	// "POST /evoting/forms"
+	Route     string
+	Signature string // hex encoded signature on the Digest
+	// KeyID is the name of the key that signed the request. It is optional,
+	// without it the proxy tries all its valid keys.
+	KeyID string `json:",omitempty"`
+}
+
+// SignRequest returns a request for the route with the JSON message as
+// payload, issued now and signed with the secret.
+func SignRequest(secret kyber.Scalar, route string, msg interface{}) (SignedRequest, error) {
+	jsonMsg, err := json.Marshal(msg)
+	if err != nil {
+		return SignedRequest{}, xerrors.Errorf("failed to marshal json: %v", err)
+	}
+
+	nonce := make([]byte, nonceSize)
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return SignedRequest{}, xerrors.Errorf("failed to generate nonce: %v", err)
+	}
+
+	signed := SignedRequest{
+		Payload:  base64.URLEncoding.EncodeToString(jsonMsg),
+		IssuedAt: time.Now().Unix(),
+		Nonce:    hex.EncodeToString(nonce),
+		Route:    route,
+	}
+
+	signature, err := schnorr.Sign(suite, secret, signed.Digest())
+	if err != nil {
+		return SignedRequest{}, xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	signed.Signature = hex.EncodeToString(signature)
+
+	return signed, nil
+}
+
+// Route returns the route of an endpoint, as in "POST /evoting/forms".
+func Route(method, path string) string {
+	return method + " " + path
+}
+
+// Digest returns the hash that is signed: the sha256 of the route, the
+// issued-at time, the nonce and the payload, each followed by a new line.
+func (s SignedRequest) Digest() []byte {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s\n%d\n%s\n%s\n", s.Route, s.IssuedAt, s.Nonce, s.Payload)
+
+	return hash.Sum(nil)
+}
+
+// GetPayload returns the decoded payload.
+func (s SignedRequest) GetPayload() ([]byte, error) {
+	payloadBuf, err := base64.URLEncoding.DecodeString(s.Payload)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decode base64: %v", err)
+	}
+
+	return payloadBuf, nil
 }
 
 // GetMessage JSON unmarshals the payload to the given element. The given
 // element MUST be a pointer.
 func (s SignedRequest) GetMessage(el interface{}) error {
-	payloadBuf, err := base64.URLEncoding.DecodeString(s.Payload)
+	payloadBuf, err := s.GetPayload()
 	if err != nil {
-		return xerrors.Errorf("failed to decode base64: %v", err)
+		return xerrors.Errorf("failed to get payload: %v", err)
 	}
 
 	err = json.Unmarshal(payloadBuf, el)
@@ -50,24 +131,18 @@ func (s SignedRequest) GetMessage(el interface{}) error {
 	return nil
 }
 
-// Verify checks the signature. The signature should be on the sha256 of the
-// payload.
+// Verify checks the signature. The signature should be on the Digest.
 func (s SignedRequest) Verify(pk kyber.Point) error {
 	if len(s.Payload) == 0 {
 		return xerrors.Errorf("cannot verify empty payload")
 	}
 
-	hash := sha256.New()
-
-	hash.Write([]byte(s.Payload))
-	md := hash.Sum(nil)
-
 	sig, err := hex.DecodeString(s.Signature)
 	if err != nil {
 		return xerrors.Errorf("failed to decode signature: %v", err)
 	}
 
-	err = schnorr.Verify(suite, pk, md, sig)
+	err = schnorr.Verify(suite, pk, s.Digest(), sig)
 	if err != nil {
 		return xerrors.Errorf("invalid signature: %v", err)
 	}
@@ -90,3 +165,35 @@ func (s SignedRequest) GetAndVerify(pk kyber.Point, el interface{}) error {
 
 	return nil
 }
+
+// Check checks that the request was signed for the route of r, that it is not
+// stale and that its nonce was not used yet. The signature must be verified
+// first, so that only the nonces of genuine requests are kept.
+func (s SignedRequest) Check(r *http.Request, nonces *NonceCache, now time.Time) error {
+	route := Route(r.Method, r.URL.Path)
+	if s.Route != route {
+		return xerrors.Errorf("request signed for %q instead of %q", s.Route, route)
+	}
+
+	issuedAt := time.Unix(s.IssuedAt, 0)
+
+	if now.Sub(issuedAt) > MaxRequestAge {
+		return xerrors.Errorf("stale request issued at %s", issuedAt.UTC().Format(time.RFC3339))
+	}
+
+	if issuedAt.Sub(now) > MaxRequestAge {
+		return xerrors.Errorf("request issued in the future at %s",
+			issuedAt.UTC().Format(time.RFC3339))
+	}
+
+	if s.Nonce == "" {
+		return xerrors.Errorf("missing nonce")
+	}
+
+	err := nonces.Add(s.Nonce, now)
+	if err != nil {
+		return xerrors.Errorf("failed to add nonce: %w", err)
+	}
+
+	return nil
+}
@@ -2,10 +2,12 @@ package types
 
 import (
 	"bytes"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.dedis.ch/kyber/v3/sign/schnorr"
@@ -43,7 +45,7 @@ func TestGetMessage_not_base64(t *testing.T) {
 	var req map[string]interface{}
 
 	err := signed.GetMessage(&req)
-	require.EqualError(t, err, "failed to decode base64: illegal base64 data at input byte 3")
+	require.EqualError(t, err, "failed to get payload: failed to decode base64: illegal base64 data at input byte 3")
 }
 
 func TestGetMessage_bad_req(t *testing.T) {
@@ -127,17 +129,14 @@ func TestVerify_ok(t *testing.T) {
 
 	payload := "xx"
 
-	hash := sha256.New()
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
+	signed := SignedRequest{
+		Payload: payload,
+	}
 
-	signature, err := schnorr.Sign(suite, secret, md)
+	signature, err := schnorr.Sign(suite, secret, signed.Digest())
 	require.NoError(t, err)
 
-	signed := SignedRequest{
-		Signature: hex.EncodeToString(signature),
-		Payload:   payload,
-	}
+	signed.Signature = hex.EncodeToString(signature)
 
 	err = signed.Verify(pk)
 	require.NoError(t, err)
@@ -163,17 +162,14 @@ func TestGetAndVerify_wrong_message(t *testing.T) {
 	msg := `{invalid json}`
 	payload := base64.URLEncoding.EncodeToString([]byte(msg))
 
-	hash := sha256.New()
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
+	signed := SignedRequest{
+		Payload: payload,
+	}
 
-	signature, err := schnorr.Sign(suite, secret, md)
+	signature, err := schnorr.Sign(suite, secret, signed.Digest())
 	require.NoError(t, err)
 
-	signed := SignedRequest{
-		Signature: hex.EncodeToString(signature),
-		Payload:   payload,
-	}
+	signed.Signature = hex.EncodeToString(signature)
 
 	var req map[string]interface{}
 
@@ -190,17 +186,14 @@ func TestGetAndVerify_ok(t *testing.T) {
 	msg := `{"Foo": "bar"}`
 	payload := base64.URLEncoding.EncodeToString([]byte(msg))
 
-	hash := sha256.New()
-	hash.Write([]byte(payload))
-	md := hash.Sum(nil)
+	signed := SignedRequest{
+		Payload: payload,
+	}
 
-	signature, err := schnorr.Sign(suite, secret, md)
+	signature, err := schnorr.Sign(suite, secret, signed.Digest())
 	require.NoError(t, err)
 
-	signed := SignedRequest{
-		Signature: hex.EncodeToString(signature),
-		Payload:   payload,
-	}
+	signed.Signature = hex.EncodeToString(signature)
 
 	type dummy struct {
 		Foo string
@@ -217,3 +210,72 @@ func TestGetAndVerify_ok(t *testing.T) {
 
 	require.Equal(t, expected, req)
 }
+
+func TestSignRequest_ok(t *testing.T) {
+	secret := suite.Scalar().Pick(suite.RandomStream())
+	pk := suite.Point().Mul(secret, nil)
+
+	signed, err := SignRequest(secret, Route(http.MethodPost, "/evoting/forms"),
+		map[string]string{"Foo": "bar"})
+	require.NoError(t, err)
+	require.Equal(t, "POST /evoting/forms", signed.Route)
+	require.Len(t, signed.Nonce, 2*nonceSize)
+
+	var req map[string]string
+
+	err = signed.GetAndVerify(pk, &req)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"Foo": "bar"}, req)
+
+	// the route, the time and the nonce are signed
+	tampered := signed
+	tampered.Route = "POST /evoting/addadmin"
+	require.Error(t, tampered.Verify(pk))
+
+	tampered = signed
+	tampered.IssuedAt++
+	require.Error(t, tampered.Verify(pk))
+
+	tampered = signed
+	tampered.Nonce = "beef"
+	require.Error(t, tampered.Verify(pk))
+
+	_, err = SignRequest(secret, "", make(chan int))
+	require.ErrorContains(t, err, "failed to marshal json")
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	nonces := NewNonceCache(10)
+
+	r := httptest.NewRequest(http.MethodPost, "/evoting/forms/beef/vote", nil)
+
+	signed := SignedRequest{
+		Route:    "POST /evoting/forms/beef/vote",
+		IssuedAt: now.Unix(),
+		Nonce:    "aa",
+	}
+
+	err := signed.Check(r, nonces, now)
+	require.NoError(t, err)
+
+	err = signed.Check(r, nonces, now.Add(time.Second))
+	require.EqualError(t, err, "failed to add nonce: nonce aa already used")
+
+	other := httptest.NewRequest(http.MethodPost, "/evoting/forms/dead/vote", nil)
+
+	signed.Nonce = "bb"
+	err = signed.Check(other, nonces, now)
+	require.EqualError(t, err, "request signed for \"POST /evoting/forms/beef/vote\" "+
+		"instead of \"POST /evoting/forms/dead/vote\"")
+
+	err = signed.Check(r, nonces, now.Add(MaxRequestAge+time.Second))
+	require.EqualError(t, err, "stale request issued at 2023-11-14T22:13:20Z")
+
+	err = signed.Check(r, nonces, now.Add(-MaxRequestAge-time.Second))
+	require.EqualError(t, err, "request issued in the future at 2023-11-14T22:13:20Z")
+
+	signed.Nonce = ""
+	err = signed.Check(r, nonces, now)
+	require.EqualError(t, err, "missing nonce")
+}
@@ -0,0 +1,22 @@
+package certificate
+
+import (
+	"go.dedis.ch/dela/core/txn"
+)
+
+// Certificate defines the primitive to start the collective signature of the
+// results of a form
+type Certificate interface {
+	// Listen starts the RPC. This function should be called on each node that
+	// wishes to participate in the signature of the results.
+	Listen(txmngr txn.Manager) (Actor, error)
+}
+
+// Actor defines the primitives to use a certificate protocol
+type Actor interface {
+	// Certify must be called by ONE of the actors once the results of the form
+	// are available. It collects the signatures of the roster over the
+	// results digest and submits the certificate to the chain. Each node of
+	// the roster must first execute Listen().
+	Certify(formID []byte) error
+}
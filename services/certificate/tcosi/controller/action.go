@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/dedis/d-voting/services/certificate"
+	"github.com/gorilla/mux"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/access"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/txn/signed"
+	"go.dedis.ch/dela/core/validation"
+	"go.dedis.ch/dela/mino/proxy"
+	"golang.org/x/xerrors"
+
+	eproxy "github.com/dedis/d-voting/proxy"
+	ptypes "github.com/dedis/d-voting/proxy/types"
+)
+
+// InitAction is an action to initialize the certificate protocol
+//
+// - implements node.ActionTemplate
+type InitAction struct {
+}
+
+// Execute implements node.ActionTemplate. It creates an actor from the TCoSi
+// instance
+func (a *InitAction) Execute(ctx node.Context) error {
+	var tcosiCertificate certificate.Certificate
+
+	err := ctx.Injector.Resolve(&tcosiCertificate)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve certificate: %v", err)
+	}
+
+	keyPath := ctx.Flags.String("signer")
+
+	signer, err := getSigner(keyPath)
+	if err != nil {
+		return xerrors.Errorf("failed to get signer: %v", err)
+	}
+
+	client, err := makeClient(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to make client: %v", err)
+	}
+
+	actor, err := tcosiCertificate.Listen(signed.NewManager(signer, &client))
+	if err != nil {
+		return xerrors.Errorf("failed to initialize the certificate protocol: %v", err)
+	}
+
+	ctx.Injector.Inject(actor)
+	dela.Logger.Info().Msg("The certificate protocol has been initialized successfully")
+
+	return nil
+}
+
+// RegisterHandlersAction is an action that registers the proxy handlers
+//
+// - implements node.ActionTemplate
+type RegisterHandlersAction struct {
+}
+
+// Execute implements node.ActionTemplate. It registers the proxy handlers to
+// certify the results of forms
+func (a *RegisterHandlersAction) Execute(ctx node.Context) error {
+	var proxy proxy.Proxy
+	err := ctx.Injector.Resolve(&proxy)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve proxy: %v", err)
+	}
+
+	var actor certificate.Actor
+	err = ctx.Injector.Resolve(&actor)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve certificate.Actor: %v", err)
+	}
+
+	var proxykeys *ptypes.KeyRing
+	err = ctx.Injector.Resolve(&proxykeys)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
+	}
+
+	router := mux.NewRouter()
+
+	ep := eproxy.NewCertificate(actor, proxykeys)
+
+	router.HandleFunc("/evoting/services/certificate/{formID}", ep.EditCertificate).Methods("PUT")
+
+	router.NotFoundHandler = http.HandlerFunc(eproxy.NotFoundHandler)
+	router.MethodNotAllowedHandler = http.HandlerFunc(eproxy.NotAllowedHandler)
+
+	err = eproxy.AddRoutes(router)
+	if err != nil {
+		return xerrors.Errorf("failed to add the routes to the OpenAPI document: %v", err)
+	}
+
+	proxy.RegisterHandler("/evoting/services/certificate/", router.ServeHTTP)
+
+	var handlers *eproxy.HandlerSets
+	if ctx.Injector.Resolve(&handlers) == nil {
+		handlers.Add(eproxy.CertificateHandlers)
+	}
+
+	dela.Logger.Info().Msg("certificate handler registered")
+
+	return nil
+}
+
+func makeClient(ctx node.Context) (client, error) {
+	var service ordering.Service
+	err := ctx.Injector.Resolve(&service)
+	if err != nil {
+		return client{}, xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	var vs validation.Service
+	err = ctx.Injector.Resolve(&vs)
+	if err != nil {
+		return client{}, xerrors.Errorf("failed to resolve validation.Service: %v", err)
+	}
+
+	client := client{
+		srvc: service,
+		vs:   vs,
+	}
+
+	return client, nil
+}
+
+// client fetches the last nonce used by the client
+//
+// - implements signed.Client
+type client struct {
+	srvc ordering.Service
+	vs   validation.Service
+}
+
+// GetNonce implements signed.Client. It uses the validation service to get the
+// last nonce.
+func (c *client) GetNonce(id access.Identity) (uint64, error) {
+	store := c.srvc.GetStore()
+
+	nonce, err := c.vs.GetNonce(store, id)
+	if err != nil {
+		return 0, xerrors.Errorf("failed to get nonce from validation: %v", err)
+	}
+
+	return nonce, nil
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"encoding"
+	"path/filepath"
+
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/services/certificate/tcosi"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/cli"
+	"go.dedis.ch/dela/cli/node"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/crypto/loader"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const privateKeyFile = "private.key"
+
+// NewController returns a new controller initializer
+func NewController() node.Initializer {
+	return controller{}
+}
+
+// controller is an initializer with a set of commands.
+//
+// - implements node.Initializer
+type controller struct{}
+
+// Build implements node.Initializer.
+func (m controller) SetCommands(builder node.Builder) {
+
+	cmd := builder.SetCommand("certificate")
+	cmd.SetDescription("interact with the CERTIFICATE service")
+
+	sub := cmd.SetSubCommand("init")
+	sub.SetFlags(cli.StringFlag{
+		Name:     "signer",
+		Usage:    "path to the private key",
+		Required: true,
+	})
+	sub.SetDescription("initialize the CERTIFICATE protocol")
+	sub.SetAction(builder.MakeAction(&InitAction{}))
+
+	sub = cmd.SetSubCommand("registerHandlers")
+	sub.SetDescription("register the proxy handlers")
+	sub.SetAction(builder.MakeAction(&RegisterHandlersAction{}))
+}
+
+// OnStart implements node.Initializer. It creates and registers a TCoSi
+// certificate. The node's key is used to sign, as it is the one in the roster.
+func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
+	var no mino.Mino
+	err := inj.Resolve(&no)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve mino.Mino: %v", err)
+	}
+
+	var p pool.Pool
+	err = inj.Resolve(&p)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve pool.Pool: %v", err)
+	}
+
+	var service ordering.Service
+	err = inj.Resolve(&service)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve ordering.Service: %v", err)
+	}
+
+	var rosterFac authority.Factory
+	err = inj.Resolve(&rosterFac)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve authority.Factory")
+	}
+
+	var serdeCtx serde.Context
+	err = inj.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context")
+	}
+
+	signer, err := getNodeSigner(ctx)
+	if err != nil {
+		return xerrors.Errorf("failed to get Signer for the certificate: %v", err)
+	}
+
+	tcosiCertificate := tcosi.NewTCoSi(no, signer, service, p, serdeCtx,
+		etypes.NewFormFactory(etypes.CiphervoteFactory{}, rosterFac))
+
+	inj.Inject(tcosiCertificate)
+
+	return nil
+}
+
+// OnStop implements node.Initializer.
+func (controller) OnStop(node.Injector) error {
+	return nil
+}
+
+// getSigner creates a signer from a file.
+func getSigner(filePath string) (crypto.Signer, error) {
+	l := loader.NewFileLoader(filePath)
+
+	dela.Logger.Info().Msgf("loading private key from %q", filePath)
+
+	signerData, err := l.Load()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load signer: %v", err)
+	}
+
+	signer, err := bls.NewSignerFromBytes(signerData)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to unmarshal signer: %v", err)
+	}
+
+	return signer, nil
+}
+
+// getNodeSigner creates a signer with the node's private key
+func getNodeSigner(flags cli.Flags) (crypto.AggregateSigner, error) {
+	loader := loader.NewFileLoader(filepath.Join(flags.Path("config"), privateKeyFile))
+
+	signerData, err := loader.LoadOrCreate(generator{newFn: blsSigner})
+	if err != nil {
+		return nil, xerrors.Errorf("while loading: %v", err)
+	}
+
+	signer, err := bls.NewSignerFromBytes(signerData)
+	if err != nil {
+		return nil, xerrors.Errorf("while unmarshaling: %v", err)
+	}
+
+	return signer, nil
+}
+
+// generator is an implementation to generate a private key.
+//
+// - implements loader.Generator
+type generator struct {
+	newFn func() encoding.BinaryMarshaler
+}
+
+// Generate implements loader.Generator. It returns the marshaled data of a
+// private key.
+func (g generator) Generate() ([]byte, error) {
+	signer := g.newFn()
+
+	data, err := signer.MarshalBinary()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to marshal signer: %v", err)
+	}
+
+	return data, nil
+}
+
+// blsSigner is a wrapper to use a signer with the primitives to use a BLS
+// signature
+func blsSigner() encoding.BinaryMarshaler {
+	return bls.NewSigner()
+}
@@ -0,0 +1,62 @@
+package json
+
+import (
+	"github.com/dedis/d-voting/services/certificate/tcosi/types"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	types.RegisterMessageFormat(serde.FormatJSON, MsgFormat{})
+}
+
+type CertifyRequest struct {
+	FormID string
+}
+
+type Message struct {
+	CertifyRequest *CertifyRequest `json:",omitempty"`
+}
+
+// MsgFormat is the engine to encode and decode certificate messages in JSON
+// format.
+//
+// - implements serde.FormatEngine
+type MsgFormat struct{}
+
+// Encode implements serde.FormatEngine. It returns the serialized data for the
+// message in JSON format.
+func (f MsgFormat) Encode(ctx serde.Context, msg serde.Message) ([]byte, error) {
+	var m Message
+
+	switch in := msg.(type) {
+	case types.CertifyRequest:
+		m = Message{CertifyRequest: &CertifyRequest{FormID: in.GetFormID()}}
+	default:
+		return nil, xerrors.Errorf("unsupported message of type '%T'", msg)
+	}
+
+	data, err := ctx.Marshal(m)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't marshal: %v", err)
+	}
+
+	return data, nil
+}
+
+// Decode implements serde.FormatEngine. It populates the message from the JSON
+// data if appropriate, otherwise it returns an error.
+func (f MsgFormat) Decode(ctx serde.Context, data []byte) (serde.Message, error) {
+	m := Message{}
+
+	err := ctx.Unmarshal(data, &m)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't deserialize message: %v", err)
+	}
+
+	if m.CertifyRequest != nil {
+		return types.NewCertifyRequest(m.CertifyRequest.FormID), nil
+	}
+
+	return nil, xerrors.New("message is empty")
+}
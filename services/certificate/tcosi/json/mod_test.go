@@ -0,0 +1,40 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/dedis/d-voting/services/certificate/tcosi/types"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/serde"
+)
+
+func TestMessageFormat_CertifyRequest_Encode(t *testing.T) {
+	format := MsgFormat{}
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	_, err := format.Encode(fake.NewBadContext(), types.CertifyRequest{})
+	require.EqualError(t, err, fake.Err("couldn't marshal"))
+
+	_, err = format.Encode(ctx, fake.Message{})
+	require.EqualError(t, err, "unsupported message of type 'fake.Message'")
+
+	data, err := format.Encode(ctx, types.NewCertifyRequest("dummyId"))
+	require.NoError(t, err)
+	require.Equal(t, `{"CertifyRequest":{"FormID":"dummyId"}}`, string(data))
+}
+
+func TestMessageFormat_CertifyRequest_Decode(t *testing.T) {
+	format := MsgFormat{}
+	ctx := serde.NewContext(fake.ContextEngine{})
+
+	_, err := format.Decode(fake.NewBadContext(), []byte(`{}`))
+	require.EqualError(t, err, fake.Err("couldn't deserialize message"))
+
+	_, err = format.Decode(ctx, []byte(`{}`))
+	require.EqualError(t, err, "message is empty")
+
+	msg, err := format.Decode(ctx, []byte(`{"CertifyRequest":{"FormID":"dummyId"}}`))
+	require.NoError(t, err)
+	require.Equal(t, types.NewCertifyRequest("dummyId"), msg)
+}
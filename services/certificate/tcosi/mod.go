@@ -0,0 +1,256 @@
+package tcosi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/dedis/d-voting/contracts/evoting"
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/services/certificate"
+	"github.com/dedis/d-voting/services/certificate/tcosi/types"
+	"go.dedis.ch/dela"
+	"go.dedis.ch/dela/core/execution/native"
+	"go.dedis.ch/dela/core/ordering"
+	"go.dedis.ch/dela/core/txn"
+	"go.dedis.ch/dela/core/txn/pool"
+	"go.dedis.ch/dela/cosi"
+	"go.dedis.ch/dela/cosi/threshold"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/json"
+	"golang.org/x/xerrors"
+)
+
+const (
+	certifyTimeout = time.Second * 30
+	// segment is the mino segment of the collective signing RPC, so that it
+	// does not collide with the one of the ordering service.
+	segment = "certificate"
+)
+
+// TCoSi allows one to collectively sign the results of a form with a threshold
+// collective signature.
+//
+// - implements certificate.Certificate
+type TCoSi struct {
+	mino    mino.Mino
+	signer  crypto.AggregateSigner
+	service ordering.Service
+	p       pool.Pool
+	context serde.Context
+	formFac serde.Factory
+}
+
+// NewTCoSi returns a new TCoSi. The signer must be the one of the node in the
+// roster, so that the collective signature can be verified against the roster
+// public keys.
+func NewTCoSi(m mino.Mino, signer crypto.AggregateSigner, s ordering.Service,
+	p pool.Pool, ctx serde.Context, formFac serde.Factory) *TCoSi {
+
+	return &TCoSi{
+		mino:    m,
+		signer:  signer,
+		service: s,
+		p:       p,
+		context: ctx,
+		formFac: formFac,
+	}
+}
+
+// Listen implements certificate.Certificate. It must be called on each node
+// that participates in the collective signature. Creates the RPC.
+func (t TCoSi) Listen(txmngr txn.Manager) (certificate.Actor, error) {
+	c := threshold.NewThreshold(t.mino.WithSegment(segment), t.signer)
+	c.SetThreshold(threshold.ByzantineThreshold)
+
+	r := reactor{
+		service: t.service,
+		context: t.context,
+		formFac: t.formFac,
+	}
+
+	cosiActor, err := c.Listen(r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to listen: %v", err)
+	}
+
+	a := &Actor{
+		cosi:    cosiActor,
+		service: t.service,
+		p:       t.p,
+		txmngr:  txmngr,
+		context: t.context,
+		formFac: t.formFac,
+	}
+
+	return a, nil
+}
+
+// Actor allows one to certify the results of a form
+//
+// - implements certificate.Actor
+type Actor struct {
+	sync.Mutex
+
+	cosi    cosi.Actor
+	service ordering.Service
+	p       pool.Pool
+	txmngr  txn.Manager
+	context serde.Context
+	formFac serde.Factory
+}
+
+// Certify implements certificate.Actor. It collects the signatures of the
+// roster and waits until the certificate is accepted by the chain.
+func (a *Actor) Certify(formID []byte) error {
+	a.Lock()
+	defer a.Unlock()
+
+	formIDHex := hex.EncodeToString(formID)
+
+	form, err := etypes.FormFromStore(a.context, a.formFac, formIDHex, a.service.GetStore())
+	if err != nil {
+		return xerrors.Errorf("failed to get form: %v", err)
+	}
+
+	if form.Status != etypes.ResultAvailable {
+		return xerrors.Errorf("the results are not available, current status: %d",
+			form.Status)
+	}
+
+	if len(form.Certificate) != 0 {
+		return xerrors.Errorf("the results are already certified")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), certifyTimeout)
+	defer cancel()
+
+	signature, err := a.cosi.Sign(ctx, types.NewCertifyRequest(formIDHex), form.Roster)
+	if err != nil {
+		return xerrors.Errorf("failed to sign: %v", err)
+	}
+
+	certificate, err := signature.Serialize(json.NewContext())
+	if err != nil {
+		return xerrors.Errorf("failed to serialize signature: %v", err)
+	}
+
+	err = a.txmngr.Sync()
+	if err != nil {
+		return xerrors.Errorf("failed to sync manager: %v", err)
+	}
+
+	tx, err := a.makeTx(formIDHex, certificate)
+	if err != nil {
+		return xerrors.Errorf("failed to make transaction: %v", err)
+	}
+
+	watchCtx, cancel := context.WithTimeout(context.Background(), certifyTimeout)
+	defer cancel()
+
+	events := a.service.Watch(watchCtx)
+
+	err = a.p.Add(tx)
+	if err != nil {
+		return xerrors.Errorf("failed to add transaction: %v", err)
+	}
+
+	accepted, msg := watchTx(events, tx.GetID())
+	if !accepted {
+		return xerrors.Errorf("certificate denied: %s", msg)
+	}
+
+	dela.Logger.Info().Msgf("results of form %s certified", formIDHex)
+
+	return nil
+}
+
+// makeTx creates the transaction that submits the certificate.
+func (a *Actor) makeTx(formID string, certificate []byte) (txn.Transaction, error) {
+	submitCertificate := etypes.SubmitCertificate{
+		FormID:      formID,
+		Certificate: certificate,
+	}
+
+	data, err := submitCertificate.Serialize(a.context)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to serialize submit certificate: %v", err)
+	}
+
+	args := []txn.Arg{
+		{Key: native.ContractArg, Value: []byte(evoting.ContractName)},
+		{Key: evoting.CmdArg, Value: []byte(evoting.CmdSubmitCertificate)},
+		{Key: evoting.FormArg, Value: data},
+	}
+
+	tx, err := a.txmngr.Make(args...)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to use manager: %v", err)
+	}
+
+	return tx, nil
+}
+
+// reactor computes the digest signed by a node when it receives a certify
+// request.
+//
+// - implements cosi.Reactor
+type reactor struct {
+	types.MessageFactory
+
+	service ordering.Service
+	context serde.Context
+	formFac serde.Factory
+}
+
+// Invoke implements cosi.Reactor. It returns the results digest of the form
+// read from the local state, so that a node only signs what it has itself.
+func (r reactor) Invoke(addr mino.Address, in serde.Message) ([]byte, error) {
+	req, ok := in.(types.CertifyRequest)
+	if !ok {
+		return nil, xerrors.Errorf("unexpected message of type '%T'", in)
+	}
+
+	form, err := etypes.FormFromStore(r.context, r.formFac, req.GetFormID(), r.service.GetStore())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to get form: %v", err)
+	}
+
+	if form.Status != etypes.ResultAvailable {
+		return nil, xerrors.Errorf("the results are not available, current status: %d",
+			form.Status)
+	}
+
+	digest, err := form.ResultsDigest()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to compute digest: %v", err)
+	}
+
+	return digest, nil
+}
+
+// watchTx checks the transaction to find one that match txID. Return if the
+// transaction has been accepted or not. Will also return false if/when the
+// events chan is closed, which is expected to happen.
+func watchTx(events <-chan ordering.Event, txID []byte) (bool, string) {
+	for event := range events {
+		for _, res := range event.Transactions {
+			if !bytes.Equal(res.GetTransaction().GetID(), txID) {
+				continue
+			}
+
+			accepted, msg := res.GetStatus()
+			if accepted {
+				return true, ""
+			}
+
+			return false, msg
+		}
+	}
+
+	return false, "watch timeout"
+}
@@ -0,0 +1,124 @@
+package tcosi
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	etypes "github.com/dedis/d-voting/contracts/evoting/types"
+	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/dedis/d-voting/services/certificate/tcosi/types"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
+	"go.dedis.ch/dela/cosi"
+	"go.dedis.ch/dela/crypto"
+	"go.dedis.ch/dela/crypto/bls"
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/json"
+)
+
+var serdecontext = json.NewContext()
+
+func TestTCoSi_Listen(t *testing.T) {
+	c := NewTCoSi(fake.Mino{}, bls.NewSigner(), &fake.Service{}, &fake.Pool{}, serdecontext, nil)
+
+	actor, err := c.Listen(fake.Manager{})
+	require.NoError(t, err)
+	require.NotNil(t, actor)
+}
+
+func TestActor_Certify(t *testing.T) {
+	formID := "deadbeef"
+	formIDBuf, err := hex.DecodeString(formID)
+	require.NoError(t, err)
+
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	form, err := fake.NewForm(serdecontext, fake.NewSnapshot(), formID)
+	require.NoError(t, err)
+	form.Roster = roster
+
+	service := fake.NewService(formID, form, serdecontext)
+
+	actor := Actor{
+		cosi:    fakeCosiActor{err: fake.GetError()},
+		service: &service,
+		p:       &fake.Pool{Service: &service},
+		txmngr:  fake.Manager{},
+		context: serdecontext,
+		formFac: etypes.NewFormFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster)),
+	}
+
+	err = actor.Certify([]byte{0xaa})
+	require.ErrorContains(t, err, "failed to get form")
+
+	err = actor.Certify(formIDBuf)
+	require.EqualError(t, err, "the results are not available, current status: 2")
+
+	form.Status = etypes.ResultAvailable
+	form.Certificate = []byte("certificate")
+	service.Forms[formID] = form
+
+	err = actor.Certify(formIDBuf)
+	require.EqualError(t, err, "the results are already certified")
+
+	form.Certificate = nil
+	service.Forms[formID] = form
+
+	err = actor.Certify(formIDBuf)
+	require.EqualError(t, err, fake.Err("failed to sign"))
+
+	actor.cosi = fakeCosiActor{}
+
+	err = actor.Certify(formIDBuf)
+	require.EqualError(t, err, fake.Err("failed to make transaction: failed to use manager"))
+}
+
+func TestReactor_Invoke(t *testing.T) {
+	formID := "deadbeef"
+
+	roster := authority.FromAuthority(fake.NewAuthority(3, fake.NewSigner))
+
+	form, err := fake.NewForm(serdecontext, fake.NewSnapshot(), formID)
+	require.NoError(t, err)
+	form.Roster = roster
+
+	service := fake.NewService(formID, form, serdecontext)
+
+	r := reactor{
+		service: &service,
+		context: serdecontext,
+		formFac: etypes.NewFormFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster)),
+	}
+
+	_, err = r.Invoke(fake.NewAddress(0), fake.Message{})
+	require.EqualError(t, err, "unexpected message of type 'fake.Message'")
+
+	_, err = r.Invoke(fake.NewAddress(0), types.NewCertifyRequest(formID))
+	require.EqualError(t, err, "the results are not available, current status: 2")
+
+	form.Status = etypes.ResultAvailable
+	service.Forms[formID] = form
+
+	digest, err := r.Invoke(fake.NewAddress(0), types.NewCertifyRequest(formID))
+	require.NoError(t, err)
+
+	expected, err := form.ResultsDigest()
+	require.NoError(t, err)
+	require.Equal(t, expected, digest)
+}
+
+// -----------------------------------------------------------------------------
+// Utility functions
+
+type fakeCosiActor struct {
+	err error
+}
+
+func (a fakeCosiActor) Sign(ctx context.Context, msg serde.Message,
+	ca crypto.CollectiveAuthority) (crypto.Signature, error) {
+
+	return fake.Signature{}, a.err
+}
+
+var _ cosi.Actor = fakeCosiActor{}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"go.dedis.ch/dela/serde"
+	"go.dedis.ch/dela/serde/registry"
+	"golang.org/x/xerrors"
+)
+
+var msgFormats = registry.NewSimpleRegistry()
+
+// RegisterMessageFormat register the engine for the provided format.
+func RegisterMessageFormat(c serde.Format, f serde.FormatEngine) {
+	msgFormats.Register(c, f)
+}
+
+// CertifyRequest is the message signed collectively by the roster. Each node
+// answers with its signature of the results digest of the form, computed from
+// its own copy of the state.
+//
+// - implements serde.Message
+type CertifyRequest struct {
+	formID string
+}
+
+// NewCertifyRequest creates a new CertifyRequest message.
+func NewCertifyRequest(formID string) CertifyRequest {
+	return CertifyRequest{
+		formID: formID,
+	}
+}
+
+// GetFormID returns the formID.
+func (c CertifyRequest) GetFormID() string {
+	return c.formID
+}
+
+// Serialize implements serde.Message. It looks up the format and returns the
+// serialized data for the request.
+func (c CertifyRequest) Serialize(ctx serde.Context) ([]byte, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	data, err := format.Encode(ctx, c)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't encode CertifyRequest message: %v", err)
+	}
+
+	return data, nil
+}
+
+// MessageFactory is a message factory for the certificate messages.
+//
+// - implements serde.Factory
+type MessageFactory struct{}
+
+// Deserialize implements serde.Factory.
+func (f MessageFactory) Deserialize(ctx serde.Context, data []byte) (serde.Message, error) {
+	format := msgFormats.Get(ctx.GetFormat())
+
+	msg, err := format.Decode(ctx, data)
+	if err != nil {
+		return nil, xerrors.Errorf("couldn't decode message: %v", err)
+	}
+
+	return msg, nil
+}
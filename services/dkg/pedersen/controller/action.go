@@ -20,14 +20,12 @@ import (
 	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/mino/proxy"
-	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 
 	eproxy "github.com/dedis/d-voting/proxy"
+	ptypes "github.com/dedis/d-voting/proxy/types"
 )
 
-var suite = suites.MustFind("Ed25519")
-
 // initAction is an action to initialize the DKG protocol
 //
 // - implements node.ActionTemplate
@@ -262,23 +260,15 @@ func (a *RegisterHandlersAction) Execute(ctx node.Context) error {
 
 	mngr := signed.NewManager(signer, &client)
 
-	proxykeyHex := ctx.Flags.String("proxykey")
-
-	proxykeyBuf, err := hex.DecodeString(proxykeyHex)
-	if err != nil {
-		return xerrors.Errorf("failed to decode proxykeyHex: %v", err)
-	}
-
-	proxykey := suite.Point()
-
-	err = proxykey.UnmarshalBinary(proxykeyBuf)
+	var proxykeys *ptypes.KeyRing
+	err = ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal proxy key: %v", err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
 	router := mux.NewRouter()
 
-	ep := eproxy.NewDKG(mngr, dkg, proxykey)
+	ep := eproxy.NewDKG(mngr, dkg, proxykeys)
 
 	// Link the request to the proxy
 	router.HandleFunc("/evoting/services/dkg/actors", ep.NewDKGActor).Methods("POST")
@@ -290,8 +280,19 @@ func (a *RegisterHandlersAction) Execute(ctx node.Context) error {
 	router.NotFoundHandler = http.HandlerFunc(eproxy.NotFoundHandler)
 	router.MethodNotAllowedHandler = http.HandlerFunc(eproxy.NotAllowedHandler)
 
+	err = eproxy.AddRoutes(router)
+	if err != nil {
+		return xerrors.Errorf("failed to add the routes to the OpenAPI document: %v", err)
+	}
+
 	proxy.RegisterHandler("/evoting/services/dkg/", router.ServeHTTP)
 
+	// the handler sets are only injected by the e-voting controller
+	var handlers *eproxy.HandlerSets
+	if ctx.Injector.Resolve(&handlers) == nil {
+		handlers.Add(eproxy.DKGHandlers)
+	}
+
 	dela.Logger.Info().Msg("DKG handler registered")
 
 	return nil
@@ -21,6 +21,7 @@ import (
 	"go.dedis.ch/dela/core/store/kv"
 	"go.dedis.ch/dela/cosi/threshold"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
 	"golang.org/x/xerrors"
 
 	etypes "github.com/dedis/d-voting/contracts/evoting/types"
@@ -114,6 +115,12 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to resolve authority.Factory")
 	}
 
+	var serdeCtx serde.Context
+	err = inj.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context")
+	}
+
 	var srvc *cosipbft.Service
 	err = inj.Resolve(&srvc)
 	if err != nil {
@@ -147,7 +154,7 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 
 	formFac := etypes.NewFormFactory(etypes.CiphervoteFactory{}, rosterFac)
 
-	dkg := pedersen.NewPedersen(no, srvc, db, p, formFac, signer)
+	dkg := pedersen.NewPedersen(no, srvc, db, p, serdeCtx, formFac, signer)
 
 	// Use dkgMap to fill the actors map
 	err = dkg.ReadActors(signed.NewManager(signer, &client))
@@ -157,7 +164,7 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 
 	inj.Inject(dkg)
 
-	c := evoting.NewContract(access, dkg, rosterFac)
+	c := evoting.NewContract(access, dkg, rosterFac, serdeCtx)
 	evoting.RegisterContract(exec, c)
 
 	return nil
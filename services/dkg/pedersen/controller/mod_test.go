@@ -17,6 +17,7 @@ import (
 	"go.dedis.ch/dela/core/execution/native"
 	"go.dedis.ch/dela/core/ordering/cosipbft"
 	"go.dedis.ch/dela/cosi/threshold"
+	"go.dedis.ch/dela/serde/json"
 )
 
 func TestMinimal_OnStart(t *testing.T) {
@@ -71,6 +72,12 @@ func TestMinimal_OnStart(t *testing.T) {
 
 	ctx.Injector.Inject(fake.RosterFac{})
 
+	// Should miss serde.Context
+	err = c.OnStart(nil, ctx.Injector)
+	require.EqualError(t, err, "failed to resolve serde.Context")
+
+	ctx.Injector.Inject(json.NewContext())
+
 	// Should miss *cosipbft.Service
 	err = c.OnStart(nil, ctx.Injector)
 	require.EqualError(t, err, "failed to resolve *cosipbft.Service")
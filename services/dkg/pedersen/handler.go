@@ -435,18 +435,17 @@ func (h *Handler) handleDeal(msg types.Deal, out mino.Sender) error {
 // handleDecryptRequest computes the public shares of a form and sends them
 // to the chain to allow decryption to proceed.
 func (h *Handler) handleDecryptRequest(formID string) error {
-	shuffleInstances, err := h.getShuffleIfValid(formID)
+	lastShuffle, err := h.getShuffleIfValid(formID)
 	if err != nil {
 		return xerrors.Errorf("failed to check if the shuffle is over: %v", err)
 	}
 
-	numberOfShuffles := len(shuffleInstances)
-	numberOfBallots := len(shuffleInstances[numberOfShuffles-1].ShuffledBallots)
+	numberOfBallots := len(lastShuffle.ShuffledBallots)
 	publicShares := make([][]etypes.Pubshare, numberOfBallots)
 
 	h.RLock()
 
-	for i, ballot := range shuffleInstances[numberOfShuffles-1].ShuffledBallots {
+	for i, ballot := range lastShuffle.ShuffledBallots {
 		ballotShares := make([]etypes.Pubshare, len(ballot))
 
 		for j, ciphertext := range ballot {
@@ -523,22 +522,28 @@ func (h *Handler) handleDecryptRequest(formID string) error {
 }
 
 // getShuffleIfValid allows checking if enough shuffles have been made on the
-// ballots.
-func (h *Handler) getShuffleIfValid(formID string) ([]etypes.ShuffleInstance, error) {
+// ballots. It returns the last shuffle, which is the only one needed to
+// decrypt the ballots.
+func (h *Handler) getShuffleIfValid(formID string) (etypes.ShuffleInstance, error) {
 	form, err := etypes.FormFromStore(h.context, h.formFac, formID, h.service.GetStore())
 	if err != nil {
-		return nil, xerrors.Errorf("could not get the form: %v", err)
+		return etypes.ShuffleInstance{}, xerrors.Errorf("could not get the form: %v", err)
 	}
 
-	if len(form.ShuffleInstances) == 0 {
-		return nil, xerrors.New("form has no shuffles")
+	if form.ShuffleCount() == 0 {
+		return etypes.ShuffleInstance{}, xerrors.New("form has no shuffles")
 	}
 
 	if form.Status != etypes.ShuffledBallots {
-		return nil, xerrors.New("ballots have not been shuffled")
+		return etypes.ShuffleInstance{}, xerrors.New("ballots have not been shuffled")
 	}
 
-	return form.ShuffleInstances, nil
+	lastShuffle, err := form.LastShuffle(h.context, h.service.GetStore())
+	if err != nil {
+		return etypes.ShuffleInstance{}, xerrors.Errorf("could not get the last shuffle: %v", err)
+	}
+
+	return lastShuffle, nil
 }
 
 // MarshalJSON returns a JSON-encoded bytestring containing all the data in the
@@ -66,13 +66,16 @@ func TestHandler_Stream(t *testing.T) {
 		Status:           formTypes.ShuffledBallots,
 		Pubkey:           nil,
 		BallotSize:       0,
-		ShuffleInstances: make([]formTypes.ShuffleInstance, 1),
 		ShuffleThreshold: 0,
 		PubsharesUnits:   units,
-		DecryptedBallots: nil,
 		Roster:           fake.Authority{},
 	}
 
+	ballotSnap := fake.NewSnapshot()
+
+	err = form.StoreShuffle(json.NewContext(), ballotSnap, formTypes.ShuffleInstance{})
+	require.NoError(t, err)
+
 	Forms := make(map[string]formTypes.Form)
 	Forms[formIDHex] = form
 
@@ -85,7 +88,7 @@ func TestHandler_Stream(t *testing.T) {
 		Status:     false,
 		Channel:    nil,
 		Context:    json.NewContext(),
-		BallotSnap: fake.NewSnapshot(),
+		BallotSnap: ballotSnap,
 	}
 
 	h.context = json.NewContext()
@@ -298,15 +301,12 @@ func TestHandler_HandlerDecryptRequest(t *testing.T) {
 		Status:           formTypes.ShuffledBallots,
 		Pubkey:           nil,
 		BallotSize:       0,
-		ShuffleInstances: make([]formTypes.ShuffleInstance, 1),
 		ShuffleThreshold: 1,
 		PubsharesUnits:   units,
-		DecryptedBallots: nil,
 		Roster:           fake.Authority{},
 	}
 
 	Forms := make(map[string]formTypes.Form)
-	Forms[formIDHex] = form
 
 	h := Handler{}
 
@@ -324,6 +324,11 @@ func TestHandler_HandlerDecryptRequest(t *testing.T) {
 		BallotSnap: fake.NewSnapshot(),
 	}
 
+	err := form.StoreShuffle(service.Context, service.BallotSnap, formTypes.ShuffleInstance{})
+	require.NoError(t, err)
+
+	Forms[formIDHex] = form
+
 	h.context = json.NewContext()
 	h.pubSharesSigner = fake.NewSigner()
 
@@ -340,7 +345,7 @@ func TestHandler_HandlerDecryptRequest(t *testing.T) {
 	// Bad manager:
 	h.txmnger = fake.Manager{}
 
-	err := h.handleDecryptRequest(formIDHex)
+	err = h.handleDecryptRequest(formIDHex)
 	require.EqualError(t, err, fake.Err("failed to make tx: failed to use manager"))
 
 	h.txmnger = signed.NewManager(fake.NewSigner(), fakeClient{})
@@ -371,7 +376,8 @@ func TestHandler_HandlerDecryptRequest(t *testing.T) {
 	shuffledBallots, err := form.Suffragia(service.Context, snap)
 	require.NoError(t, err)
 	shuffleInstance := formTypes.ShuffleInstance{ShuffledBallots: shuffledBallots.Ciphervotes}
-	form.ShuffleInstances = append(form.ShuffleInstances, shuffleInstance)
+	err = form.StoreShuffle(service.Context, service.BallotSnap, shuffleInstance)
+	require.NoError(t, err)
 
 	Forms[formIDHex] = form
 
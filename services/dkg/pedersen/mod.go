@@ -26,7 +26,6 @@ import (
 	"github.com/dedis/d-voting/services/dkg/pedersen/types"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
-	jsonserde "go.dedis.ch/dela/serde/json"
 	"go.dedis.ch/kyber/v3"
 	"go.dedis.ch/kyber/v3/suites"
 	"go.dedis.ch/kyber/v3/util/random"
@@ -69,6 +68,7 @@ type Pedersen struct {
 	mino    mino.Mino
 	factory serde.Factory
 	service ordering.Service
+	context serde.Context
 	formFac serde.Factory
 	pool    pool.Pool
 	signer  crypto.Signer
@@ -78,7 +78,7 @@ type Pedersen struct {
 
 // NewPedersen returns a new DKG Pedersen factory
 func NewPedersen(m mino.Mino, service ordering.Service,
-	db kv.DB, pool pool.Pool,
+	db kv.DB, pool pool.Pool, ctx serde.Context,
 	formFac serde.Factory, signer crypto.Signer) *Pedersen {
 
 	factory := types.NewMessageFactory(m.GetAddressFactory())
@@ -88,6 +88,7 @@ func NewPedersen(m mino.Mino, service ordering.Service,
 		mino:    m,
 		factory: factory,
 		service: service,
+		context: ctx,
 		pool:    pool,
 		actors:  actors,
 		signer:  signer,
@@ -127,7 +128,7 @@ func (s *Pedersen) NewActor(formIDBuf []byte, pool pool.Pool, txmngr txn.Manager
 	// hex-encoded string
 	formID := hex.EncodeToString(formIDBuf)
 
-	ctx := jsonserde.NewContext()
+	ctx := s.context
 
 	status := &dkg.Status{Status: dkg.Initialized}
 
@@ -55,7 +55,7 @@ func init() {
 func TestActor_MarshalJSON(t *testing.T) {
 	initMetrics()
 
-	p := NewPedersen(fake.Mino{}, &fake.Service{}, fake.NewInMemoryDB(), &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &fake.Service{}, fake.NewInMemoryDB(), &fake.Pool{}, serdecontext, fake.Factory{}, fake.Signer{})
 
 	// Create new actor
 	actor1, err := p.NewActor([]byte("deadbeef"), &fake.Pool{},
@@ -141,7 +141,7 @@ func TestPedersen_InitNonEmptyMap(t *testing.T) {
 	require.NoError(t, err)
 
 	// Initialize a Pedersen
-	p := NewPedersen(fake.Mino{}, &fake.Service{}, dkgMap, &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &fake.Service{}, dkgMap, &fake.Pool{}, serdecontext, fake.Factory{}, fake.Signer{})
 
 	err = dkgMap.View(func(tx kv.ReadableTx) error {
 		bucket := tx.GetBucket([]byte("dkgmap"))
@@ -212,7 +212,7 @@ func TestPedersen_SyncDB(t *testing.T) {
 	manager := fake.Manager{}
 
 	// Initialize a Pedersen
-	p := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &pool, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &pool, serdecontext, fake.Factory{}, fake.Signer{})
 
 	// Create actors
 	formID1buf, err := hex.DecodeString(formID1)
@@ -261,7 +261,7 @@ func TestPedersen_SyncDB(t *testing.T) {
 	require.NoError(t, err)
 
 	// Recover them from the map
-	q := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &pool, fake.Factory{}, fake.Signer{})
+	q := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &pool, serdecontext, fake.Factory{}, fake.Signer{})
 
 	err = dkgMap.View(func(tx kv.ReadableTx) error {
 		bucket := tx.GetBucket([]byte("dkgmap"))
@@ -307,7 +307,7 @@ func TestPedersen_Listen(t *testing.T) {
 		etypes.Form{Roster: fake.Authority{}}, serdecontext)
 
 	p := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &fake.Pool{},
-		fake.Factory{}, fake.Signer{})
+		serdecontext, fake.Factory{}, fake.Signer{})
 
 	actor, err := p.Listen(formIDBuf, fake.Manager{})
 	require.NoError(t, err)
@@ -324,7 +324,7 @@ func TestPedersen_TwoListens(t *testing.T) {
 	service := fake.NewService(formID,
 		etypes.Form{Roster: fake.Authority{}}, serdecontext)
 
-	p := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &fake.Pool{}, fake.Factory{}, fake.Signer{})
+	p := NewPedersen(fake.Mino{}, &service, fake.NewInMemoryDB(), &fake.Pool{}, serdecontext, fake.Factory{}, fake.Signer{})
 
 	actor1, err := p.Listen(formIDBuf, fake.Manager{})
 	require.NoError(t, err)
@@ -518,7 +518,7 @@ func TestPedersen_Scenario(t *testing.T) {
 	for i, mino := range minos {
 		fac := etypes.NewFormFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster))
 
-		dkg := NewPedersen(mino, &service, fake.NewInMemoryDB(), &fake.Pool{}, fac, fake.Signer{})
+		dkg := NewPedersen(mino, &service, fake.NewInMemoryDB(), &fake.Pool{}, serdecontext, fac, fake.Signer{})
 
 		actor, err := dkg.Listen(formIDBuf, signed.NewManager(fake.Signer{}, &client{
 			srvc: &fake.Service{},
@@ -556,7 +556,8 @@ func TestPedersen_Scenario(t *testing.T) {
 	require.NoError(t, err)
 	shuffledBallots := suff.Ciphervotes
 	shuffleInstance := etypes.ShuffleInstance{ShuffledBallots: shuffledBallots}
-	form.ShuffleInstances = append(form.ShuffleInstances, shuffleInstance)
+	err = form.StoreShuffle(serdecontext, service.BallotSnap, shuffleInstance)
+	require.NoError(t, err)
 
 	form.ShuffleThreshold = 1
 
@@ -0,0 +1,147 @@
+// Package health implements an RPC that tells if the nodes of a roster are
+// reachable. Each node creates it on startup, so that the other nodes can
+// ping it.
+package health
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
+	"golang.org/x/xerrors"
+)
+
+const rpcName = "health"
+
+// Pinger sends pings to the nodes of a roster. It keeps the result of the last
+// ping so that it can be reused by CachedPing.
+type Pinger struct {
+	rpc mino.RPC
+
+	sync.Mutex
+	lastTime    time.Time
+	lastPlayers string
+	lastErrs    map[string]error
+}
+
+// NewPinger creates the health RPC on the mino instance and returns a pinger
+// using it.
+func NewPinger(m mino.Mino) *Pinger {
+	return &Pinger{
+		rpc: mino.MustCreateRPC(m, rpcName, handler{}, pingFactory{}),
+	}
+}
+
+// Ping sends a ping to the nodes and returns the error of each node by
+// address, nil if the node answered before the context is done.
+func (p *Pinger) Ping(ctx context.Context, players mino.Players) map[string]error {
+	errs := make(map[string]error, players.Len())
+
+	iter := players.AddressIterator()
+	for iter.HasNext() {
+		errs[iter.GetNext().String()] = xerrors.New("no response")
+	}
+
+	resps, err := p.rpc.Call(ctx, ping{}, players)
+	if err != nil {
+		for addr := range errs {
+			errs[addr] = xerrors.Errorf("failed to call: %v", err)
+		}
+
+		return errs
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return errs
+		case resp, more := <-resps:
+			if !more {
+				return errs
+			}
+
+			_, err = resp.GetMessageOrError()
+			errs[resp.GetFrom().String()] = err
+		}
+	}
+}
+
+// CachedPing returns the result of the last ping of the same nodes if it is
+// not older than the interval, otherwise it pings them. The concurrent calls
+// wait for the ping in progress, so that the nodes are not flooded.
+func (p *Pinger) CachedPing(ctx context.Context, players mino.Players,
+	interval time.Duration) map[string]error {
+
+	p.Lock()
+	defer p.Unlock()
+
+	key := playersKey(players)
+
+	if p.lastErrs == nil || key != p.lastPlayers || time.Since(p.lastTime) > interval {
+		p.lastErrs = p.Ping(ctx, players)
+		p.lastPlayers = key
+		p.lastTime = time.Now()
+	}
+
+	errs := make(map[string]error, len(p.lastErrs))
+	for addr, err := range p.lastErrs {
+		errs[addr] = err
+	}
+
+	return errs
+}
+
+// playersKey returns the sorted addresses of the nodes, to tell if a cached
+// ping was sent to the same nodes.
+func playersKey(players mino.Players) string {
+	addrs := make([]string, 0, players.Len())
+
+	iter := players.AddressIterator()
+	for iter.HasNext() {
+		addrs = append(addrs, iter.GetNext().String())
+	}
+
+	sort.Strings(addrs)
+
+	return strings.Join(addrs, ",")
+}
+
+// handler answers the pings.
+//
+// - implements mino.Handler
+type handler struct {
+	mino.UnsupportedHandler
+}
+
+// Process implements mino.Handler. It answers the ping with a ping.
+func (handler) Process(mino.Request) (serde.Message, error) {
+	return ping{}, nil
+}
+
+// ping is the message of the health RPC. It has no content.
+//
+// - implements serde.Message
+type ping struct{}
+
+// Serialize implements serde.Message.
+func (ping) Serialize(serde.Context) ([]byte, error) {
+	return []byte("ping"), nil
+}
+
+// pingFactory deserializes the pings.
+//
+// - implements serde.Factory
+type pingFactory struct{}
+
+// Deserialize implements serde.Factory.
+func (pingFactory) Deserialize(_ serde.Context, data []byte) (serde.Message, error) {
+	if string(data) != "ping" {
+		return nil, xerrors.Errorf("invalid ping: %q", data)
+	}
+
+	return ping{}, nil
+}
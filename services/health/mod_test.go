@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/mino/minoch"
+	"go.dedis.ch/dela/serde/json"
+)
+
+func TestPinger_Ping(t *testing.T) {
+	manager := minoch.NewManager()
+
+	m1 := minoch.MustCreate(manager, "A")
+	m2 := minoch.MustCreate(manager, "B")
+
+	pinger := NewPinger(m1)
+	NewPinger(m2)
+
+	// C was not created with the health RPC
+	m3 := minoch.MustCreate(manager, "C")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errs := pinger.Ping(ctx, mino.NewAddresses(m1.GetAddress(), m2.GetAddress(), m3.GetAddress()))
+	require.Len(t, errs, 3)
+	require.NoError(t, errs[m1.GetAddress().String()])
+	require.NoError(t, errs[m2.GetAddress().String()])
+	require.Error(t, errs[m3.GetAddress().String()])
+}
+
+func TestPinger_CachedPing(t *testing.T) {
+	manager := minoch.NewManager()
+
+	m1 := minoch.MustCreate(manager, "A")
+	m2 := minoch.MustCreate(manager, "B")
+
+	pinger := NewPinger(m1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	players := mino.NewAddresses(m1.GetAddress(), m2.GetAddress())
+
+	errs := pinger.CachedPing(ctx, players, time.Hour)
+	require.Error(t, errs[m2.GetAddress().String()])
+
+	NewPinger(m2)
+
+	// the result of the last ping is reused during the interval
+	errs = pinger.CachedPing(ctx, players, time.Hour)
+	require.Error(t, errs[m2.GetAddress().String()])
+
+	errs = pinger.CachedPing(ctx, players, 0)
+	require.NoError(t, errs[m2.GetAddress().String()])
+
+	// another roster is pinged again
+	errs = pinger.CachedPing(ctx, mino.NewAddresses(m2.GetAddress()), time.Hour)
+	require.Len(t, errs, 1)
+	require.NoError(t, errs[m2.GetAddress().String()])
+}
+
+func TestPingFactory_Deserialize(t *testing.T) {
+	msg, err := pingFactory{}.Deserialize(json.NewContext(), []byte("ping"))
+	require.NoError(t, err)
+	require.Equal(t, ping{}, msg)
+
+	_, err = pingFactory{}.Deserialize(json.NewContext(), []byte("pong"))
+	require.EqualError(t, err, `invalid ping: "pong"`)
+}
@@ -2,8 +2,40 @@ package shuffle
 
 import (
 	"go.dedis.ch/dela/core/txn"
+	"golang.org/x/xerrors"
 )
 
+// ErrRunning is returned when a shuffle is started on a form whose shuffle is
+// already running on the actor.
+var ErrRunning = xerrors.New("the shuffle of the form is already running")
+
+// StatusCode is the type used to define the status of a shuffle
+type StatusCode uint16
+
+const (
+	// Idle is when no shuffle was started on the form by the actor
+	Idle StatusCode = 0
+	// Running is when the shuffle is in progress
+	Running StatusCode = 1
+	// Done is when the shuffle reached the threshold of the form
+	Done StatusCode = 2
+	// Failed is when the shuffle failed
+	Failed StatusCode = 3
+)
+
+// Status defines the state of the shuffle of a form.
+type Status struct {
+	Status StatusCode
+	// Round is the number of shuffles of the form seen by the actor
+	Round int
+	// Threshold is the number of shuffles needed by the form
+	Threshold int
+	// Nodes are the addresses of the nodes asked to shuffle
+	Nodes []string
+	// Err is the last error of the shuffle, if it failed
+	Err error
+}
+
 // Shuffle defines the primitive to start a shuffle protocol
 type Shuffle interface {
 	// Listen starts the RPC. This function should be called on each node that
@@ -16,4 +48,13 @@ type Actor interface {
 	// Shuffle must be called by ONE of the actor to shuffle the list of ElGamal
 	// pairs. Each node represented by a player must first execute Listen().
 	Shuffle(formID []byte, userID string) (err error)
+
+	// Start marks the shuffle of the form as running and runs it in the
+	// background. It returns ErrRunning if the shuffle is already running. The
+	// outcome of the shuffle is given by Status.
+	Start(formID []byte, userID string) error
+
+	// Status returns the status of the last shuffle started by the actor on
+	// the form. formID is NOT hex-encoded.
+	Status(formID []byte) Status
 }
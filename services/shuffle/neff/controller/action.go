@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"encoding/hex"
 	"net/http"
 
 	"github.com/dedis/d-voting/services/shuffle"
@@ -13,14 +12,12 @@ import (
 	"go.dedis.ch/dela/core/txn/signed"
 	"go.dedis.ch/dela/core/validation"
 	"go.dedis.ch/dela/mino/proxy"
-	"go.dedis.ch/kyber/v3/suites"
 	"golang.org/x/xerrors"
 
 	eproxy "github.com/dedis/d-voting/proxy"
+	ptypes "github.com/dedis/d-voting/proxy/types"
 )
 
-var suite = suites.MustFind("ed25519")
-
 // InitAction is an action to initialize the shuffle protocol
 //
 // - implements node.ActionTemplate
@@ -82,31 +79,34 @@ func (a *RegisterHandlersAction) Execute(ctx node.Context) error {
 		return xerrors.Errorf("failed to resolve dkg.DKG: %v", err)
 	}
 
-	proxykeyHex := ctx.Flags.String("proxykey")
-
-	proxykeyBuf, err := hex.DecodeString(proxykeyHex)
-	if err != nil {
-		return xerrors.Errorf("failed to decode proxykeyHex: %v", err)
-	}
-
-	proxykey := suite.Point()
-
-	err = proxykey.UnmarshalBinary(proxykeyBuf)
+	var proxykeys *ptypes.KeyRing
+	err = ctx.Injector.Resolve(&proxykeys)
 	if err != nil {
-		return xerrors.Errorf("failed to unmarshal proxy key: %v", err)
+		return xerrors.Errorf("failed to resolve proxy keys: %v", err)
 	}
 
 	router := mux.NewRouter()
 
-	ep := eproxy.NewShuffle(actor, proxykey)
+	ep := eproxy.NewShuffle(actor, proxykeys)
 
+	router.HandleFunc("/evoting/services/shuffle/{formID}", ep.ShuffleStatus).Methods("GET")
 	router.HandleFunc("/evoting/services/shuffle/{formID}", ep.EditShuffle).Methods("PUT")
 
 	router.NotFoundHandler = http.HandlerFunc(eproxy.NotFoundHandler)
 	router.MethodNotAllowedHandler = http.HandlerFunc(eproxy.NotAllowedHandler)
 
+	err = eproxy.AddRoutes(router)
+	if err != nil {
+		return xerrors.Errorf("failed to add the routes to the OpenAPI document: %v", err)
+	}
+
 	proxy.RegisterHandler("/evoting/services/shuffle/", router.ServeHTTP)
 
+	var handlers *eproxy.HandlerSets
+	if ctx.Injector.Resolve(&handlers) == nil {
+		handlers.Add(eproxy.ShuffleHandlers)
+	}
+
 	dela.Logger.Info().Msg("DKG handler registered")
 
 	return nil
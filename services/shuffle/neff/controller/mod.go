@@ -17,6 +17,7 @@ import (
 	"go.dedis.ch/dela/crypto/bls"
 	"go.dedis.ch/dela/crypto/loader"
 	"go.dedis.ch/dela/mino"
+	"go.dedis.ch/dela/serde"
 	"golang.org/x/xerrors"
 )
 
@@ -85,12 +86,18 @@ func (m controller) OnStart(ctx cli.Flags, inj node.Injector) error {
 		return xerrors.Errorf("failed to resolve authority.Factory")
 	}
 
+	var serdeCtx serde.Context
+	err = inj.Resolve(&serdeCtx)
+	if err != nil {
+		return xerrors.Errorf("failed to resolve serde.Context")
+	}
+
 	signer, err := getNodeSigner(ctx)
 	if err != nil {
 		return xerrors.Errorf("failed to get Signer for the shuffle : %v", err)
 	}
 
-	neffShuffle := neff.NewNeffShuffle(no, service, p, blocks,
+	neffShuffle := neff.NewNeffShuffle(no, service, p, blocks, serdeCtx,
 		etypes.NewFormFactory(etypes.CiphervoteFactory{}, rosterFac), signer)
 
 	inj.Inject(neffShuffle)
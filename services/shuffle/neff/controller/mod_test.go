@@ -60,6 +60,11 @@ func TestController_OnStart(t *testing.T) {
 	err = c.OnStart(make(node.FlagSet), inj)
 	require.EqualError(t, err,
 		"failed to resolve authority.Factory")
+
+	inj.Inject(fake.RosterFac{})
+	err = c.OnStart(make(node.FlagSet), inj)
+	require.EqualError(t, err,
+		"failed to resolve serde.Context")
 }
 
 func TestController_OnStop(t *testing.T) {
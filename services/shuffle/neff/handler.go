@@ -19,6 +19,7 @@ import (
 	"go.dedis.ch/dela/crypto"
 	"go.dedis.ch/dela/mino"
 	"go.dedis.ch/dela/serde"
+	jsondela "go.dedis.ch/dela/serde/json"
 	"go.dedis.ch/kyber/v3/proof"
 	shuffleKyber "go.dedis.ch/kyber/v3/shuffle"
 	"go.dedis.ch/kyber/v3/suites"
@@ -96,7 +97,7 @@ func (h *Handler) handleStartShuffle(formID string, userID string) error {
 			return xerrors.Errorf("failed to get form: %v", err)
 		}
 
-		round := len(form.ShuffleInstances)
+		round := form.ShuffleCount()
 
 		// check if the threshold is reached
 		if round >= form.ShuffleThreshold {
@@ -159,7 +160,7 @@ func (h *Handler) makeTx(form *etypes.Form, userID string) (txn.Transaction, err
 	shuffleBallots := etypes.ShuffleBallots{
 		FormID:          form.FormID,
 		UserID:          userID,
-		Round:           len(form.ShuffleInstances),
+		Round:           form.ShuffleCount(),
 		ShuffledBallots: shuffledBallots,
 	}
 
@@ -209,7 +210,7 @@ func (h *Handler) makeTx(form *etypes.Form, userID string) (txn.Transaction, err
 		return nil, xerrors.Errorf("could not sign the shuffle : %v", err)
 	}
 
-	encodedSignature, err := signature.Serialize(h.context)
+	encodedSignature, err := signature.Serialize(jsondela.NewContext())
 	if err != nil {
 		return nil, xerrors.Errorf("could not encode signature as []byte : %v ", err)
 	}
@@ -256,7 +257,7 @@ func (h *Handler) makeTx(form *etypes.Form, userID string) (txn.Transaction, err
 func (h *Handler) getShuffledBallots(form *etypes.Form) ([]etypes.Ciphervote,
 	func(e []kyber.Scalar) (proof.Prover, error), error) {
 
-	round := len(form.ShuffleInstances)
+	round := form.ShuffleCount()
 
 	var ciphervotes []etypes.Ciphervote
 
@@ -267,7 +268,11 @@ func (h *Handler) getShuffledBallots(form *etypes.Form) ([]etypes.Ciphervote,
 		}
 		ciphervotes = suff.Ciphervotes
 	} else {
-		ciphervotes = form.ShuffleInstances[round-1].ShuffledBallots
+		lastShuffle, err := form.LastShuffle(h.context, h.service.GetStore())
+		if err != nil {
+			return nil, nil, xerrors.Errorf("couldn't get last shuffle: %v", err)
+		}
+		ciphervotes = lastShuffle.ShuffledBallots
 	}
 
 	seqSize := len(ciphervotes[0])
@@ -94,8 +94,6 @@ func TestHandler_StartShuffle(t *testing.T) {
 		FormID:           dummyID,
 		Status:           0,
 		Pubkey:           nil,
-		ShuffleInstances: []etypes.ShuffleInstance{},
-		DecryptedBallots: nil,
 		ShuffleThreshold: 1,
 		BallotSize:       1,
 		Roster:           fake.Authority{},
@@ -209,8 +207,9 @@ func TestHandler_StartShuffle(t *testing.T) {
 	require.NoError(t, err)
 	shuffledBallots := append([]etypes.Ciphervote{}, ciphervotes.Ciphervotes...)
 
-	form.ShuffleInstances = append(form.ShuffleInstances,
+	err = form.StoreShuffle(service.Context, snap,
 		etypes.ShuffleInstance{ShuffledBallots: shuffledBallots})
+	require.NoError(t, err)
 
 	form.ShuffleThreshold = 2
 
@@ -274,8 +273,6 @@ func initFakeForm(ctx serde.Context, snap store.Snapshot, formID string) etypes.
 		FormID:           formID,
 		Status:           etypes.Closed,
 		Pubkey:           pubKey,
-		ShuffleInstances: []etypes.ShuffleInstance{},
-		DecryptedBallots: nil,
 		ShuffleThreshold: 1,
 		BallotSize:       1,
 		Roster:           fake.Authority{},
@@ -18,8 +18,6 @@ import (
 	"go.dedis.ch/dela/serde"
 	"golang.org/x/net/context"
 	"golang.org/x/xerrors"
-
-	"go.dedis.ch/dela/serde/json"
 )
 
 const (
@@ -43,12 +41,11 @@ type NeffShuffle struct {
 
 // NewNeffShuffle returns a new NeffShuffle factory.
 func NewNeffShuffle(m mino.Mino, s ordering.Service, p pool.Pool,
-	blocks *blockstore.InDisk, formFac serde.Factory, signer crypto.Signer) *NeffShuffle {
+	blocks *blockstore.InDisk, ctx serde.Context, formFac serde.Factory,
+	signer crypto.Signer) *NeffShuffle {
 
 	factory := types.NewMessageFactory(m.GetAddressFactory())
 
-	ctx := json.NewContext()
-
 	return &NeffShuffle{
 		mino:       m,
 		factory:    factory,
@@ -93,17 +90,112 @@ type Actor struct {
 
 	context serde.Context
 	formFac serde.Factory
+
+	// statuses holds the status of the shuffles, by hex-encoded formID. It
+	// has its own lock so that the status can be read during a shuffle.
+	statusLock sync.RWMutex
+	statuses   map[string]shuffle.Status
 }
 
 // Shuffle must be called by ONE of the actors to shuffle the list of ElGamal
 // pairs.
 // Each node represented by a player must first execute Listen().
 func (a *Actor) Shuffle(formID []byte, userID string) error {
+	formIDHex := hex.EncodeToString(formID)
+
+	err := a.start(formIDHex)
+	if err != nil {
+		return err
+	}
+
+	return a.run(formIDHex, userID)
+}
+
+// Start implements shuffle.Actor.
+func (a *Actor) Start(formID []byte, userID string) error {
+	formIDHex := hex.EncodeToString(formID)
+
+	err := a.start(formIDHex)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		err := a.run(formIDHex, userID)
+		if err != nil {
+			dela.Logger.Err(err).Msg("failed to shuffle")
+		}
+	}()
+
+	return nil
+}
+
+// run runs the shuffle of the form, which must have been started, and sets its
+// status once it is over.
+func (a *Actor) run(formIDHex string, userID string) error {
 	a.Lock()
 	defer a.Unlock()
 
-	formIDHex := hex.EncodeToString(formID)
+	err := a.shuffle(formIDHex, userID)
+	if err != nil {
+		a.setStatus(formIDHex, func(status *shuffle.Status) {
+			status.Status = shuffle.Failed
+			status.Err = err
+		})
+
+		return err
+	}
 
+	a.setStatus(formIDHex, func(status *shuffle.Status) {
+		status.Status = shuffle.Done
+	})
+
+	return nil
+}
+
+// Status implements shuffle.Actor.
+func (a *Actor) Status(formID []byte) shuffle.Status {
+	a.statusLock.RLock()
+	defer a.statusLock.RUnlock()
+
+	status := a.statuses[hex.EncodeToString(formID)]
+	status.Nodes = append([]string{}, status.Nodes...)
+
+	return status
+}
+
+// start marks the shuffle of the form as running. It returns an error if it is
+// already running.
+func (a *Actor) start(formID string) error {
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+
+	if a.statuses == nil {
+		a.statuses = make(map[string]shuffle.Status)
+	}
+
+	if a.statuses[formID].Status == shuffle.Running {
+		return shuffle.ErrRunning
+	}
+
+	a.statuses[formID] = shuffle.Status{Status: shuffle.Running}
+
+	return nil
+}
+
+// setStatus updates the status of the shuffle of the form.
+func (a *Actor) setStatus(formID string, update func(*shuffle.Status)) {
+	a.statusLock.Lock()
+	defer a.statusLock.Unlock()
+
+	status := a.statuses[formID]
+	update(&status)
+	a.statuses[formID] = status
+}
+
+// shuffle starts the shuffle of the form on the nodes of its roster and waits
+// for it to reach the threshold. formID is hex-encoded.
+func (a *Actor) shuffle(formIDHex string, userID string) error {
 	form, err := etypes.FormFromStore(a.context, a.formFac, formIDHex, a.service.GetStore())
 	if err != nil {
 		return xerrors.Errorf("failed to get form: %v", err)
@@ -131,6 +223,16 @@ func (a *Actor) Shuffle(formID []byte, userID string) error {
 		}
 	}
 
+	nodes := make([]string, len(addrs))
+	for i, addr := range addrs {
+		nodes[i] = addr.String()
+	}
+
+	a.setStatus(formIDHex, func(status *shuffle.Status) {
+		status.Nodes = nodes
+		status.Threshold = form.ShuffleThreshold
+	})
+
 	dela.Logger.Info().Msgf("sending start shuffle to: %v", addrs)
 
 	message := types.NewStartShuffle(formIDHex, userID, addrs)
@@ -163,9 +265,13 @@ func (a *Actor) waitAndCheckShuffling(formID string, rosterLen int) error {
 			return xerrors.Errorf("failed to get form: %v", err)
 		}
 
-		round := len(form.ShuffleInstances)
+		round := form.ShuffleCount()
 		dela.Logger.Info().Msgf("SHUFFLE / ROUND : %d", round)
 
+		a.setStatus(formID, func(status *shuffle.Status) {
+			status.Round = round
+		})
+
 		// if the threshold is reached that means we have enough shuffling.
 		if round >= form.ShuffleThreshold {
 			dela.Logger.Info().Msgf("shuffle done with round n°%d", round)
@@ -182,5 +288,5 @@ func (a *Actor) waitAndCheckShuffling(formID string, rosterLen int) error {
 	}
 
 	return xerrors.Errorf("threshold of shuffling not reached: %d < %d",
-		len(form.ShuffleInstances), form.ShuffleThreshold)
+		form.ShuffleCount(), form.ShuffleThreshold)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"strings"
 	"testing"
+	"time"
 
 	"go.dedis.ch/dela"
 	"go.dedis.ch/dela/core/ordering/cosipbft/authority"
@@ -15,6 +16,7 @@ import (
 
 	etypes "github.com/dedis/d-voting/contracts/evoting/types"
 	"github.com/dedis/d-voting/internal/testing/fake"
+	"github.com/dedis/d-voting/services/shuffle"
 	"github.com/dedis/d-voting/services/shuffle/neff/types"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
@@ -35,7 +37,7 @@ func init() {
 
 func TestNeffShuffle_Listen(t *testing.T) {
 
-	NeffShuffle := NewNeffShuffle(fake.Mino{}, &fake.Service{}, &fake.Pool{}, nil, fakeAuthorityFactory{}, fake.NewSigner())
+	NeffShuffle := NewNeffShuffle(fake.Mino{}, &fake.Service{}, &fake.Pool{}, nil, serdecontext, fakeAuthorityFactory{}, fake.NewSigner())
 
 	actor, err := NeffShuffle.Listen(fake.Manager{})
 	require.NoError(t, err)
@@ -60,7 +62,8 @@ func TestNeffShuffle_Shuffle(t *testing.T) {
 	suff, err := form.Suffragia(serdecontext, st)
 	require.NoError(t, err)
 	shuffledBallots := append([]etypes.Ciphervote{}, suff.Ciphervotes...)
-	form.ShuffleInstances = append(form.ShuffleInstances, etypes.ShuffleInstance{ShuffledBallots: shuffledBallots})
+	err = form.StoreShuffle(serdecontext, st, etypes.ShuffleInstance{ShuffledBallots: shuffledBallots})
+	require.NoError(t, err)
 
 	form.ShuffleThreshold = 1
 
@@ -74,9 +77,15 @@ func TestNeffShuffle_Shuffle(t *testing.T) {
 		formFac: etypes.NewFormFactory(etypes.CiphervoteFactory{}, fake.NewRosterFac(roster)),
 	}
 
+	require.Equal(t, shuffle.Idle, actor.Status(formIDBuf).Status)
+
 	err = actor.Shuffle(formIDBuf, "123456")
 	require.EqualError(t, err, fake.Err("failed to stream"))
 
+	status := actor.Status(formIDBuf)
+	require.Equal(t, shuffle.Failed, status.Status)
+	require.EqualError(t, status.Err, fake.Err("failed to stream"))
+
 	rpc := fake.NewStreamRPC(fake.NewReceiver(), fake.NewBadSender())
 	actor.rpc = rpc
 
@@ -93,6 +102,39 @@ func TestNeffShuffle_Shuffle(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, strings.Contains(out.String(), "failed to start shuffle"), out.String())
 
+	status = actor.Status(formIDBuf)
+	require.Equal(t, shuffle.Done, status.Status)
+	require.Equal(t, 1, status.Round)
+	require.Equal(t, 1, status.Threshold)
+	require.Len(t, status.Nodes, rosterLen)
+	require.NoError(t, status.Err)
+
+	actor.statuses[formID] = shuffle.Status{Status: shuffle.Running}
+
+	err = actor.Shuffle(formIDBuf, "123456")
+	require.Equal(t, shuffle.ErrRunning, err)
+
+	err = actor.Start(formIDBuf, "123456")
+	require.Equal(t, shuffle.ErrRunning, err)
+
+	actor.statuses[formID] = shuffle.Status{}
+
+	// the shuffle is marked as running before Start returns, while it is
+	// blocked by the lock of the actor
+	actor.Lock()
+
+	err = actor.Start(formIDBuf, "123456")
+	require.NoError(t, err)
+
+	err = actor.Start(formIDBuf, "123456")
+	require.Equal(t, shuffle.ErrRunning, err)
+
+	actor.Unlock()
+
+	require.Eventually(t, func() bool {
+		return actor.Status(formIDBuf).Status == shuffle.Done
+	}, 5*time.Second, 10*time.Millisecond)
+
 	rpc = fake.NewStreamRPC(fake.NewBadReceiver(), fake.Sender{})
 	actor.rpc = rpc
 
@@ -15,14 +15,22 @@ export const delaRouter = express.Router();
 
 initEnforcer().catch((e) => console.error(`Couldn't initialize enforcerer: ${e}`));
 
-// get payload creates a payload with a signature on it
-function getPayload(dataStr: string) {
+// get payload creates a payload for the route, as in "POST /evoting/forms",
+// with a signature on it. The signature also covers the route, the time and a
+// random nonce so that the proxy rejects the request if it is sent again.
+function getPayload(dataStr: string, route: string) {
   let dataStrB64 = Buffer.from(dataStr).toString('base64url');
   while (dataStrB64.length % 4 !== 0) {
     dataStrB64 += '=';
   }
 
-  const hash: Buffer = crypto.createHash('sha256').update(dataStrB64).digest();
+  const issuedAt = Math.floor(Date.now() / 1000);
+  const nonce = crypto.randomBytes(16).toString('hex');
+
+  const hash: Buffer = crypto
+    .createHash('sha256')
+    .update(`${route}\n${issuedAt}\n${nonce}\n${dataStrB64}\n`)
+    .digest();
 
   const edCurve = kyber.curve.newCurve('edwards25519');
 
@@ -39,14 +47,19 @@ function getPayload(dataStr: string) {
 
   return {
     Payload: dataStrB64,
+    IssuedAt: issuedAt,
+    Nonce: nonce,
+    Route: route,
     Signature: sign.toString('hex'),
+    // the name of the key on the proxies, optional
+    KeyID: process.env.PROXY_KEY_ID || undefined,
   };
 }
 
 // sendToDela signs the message and sends it to the dela proxy. It makes no
 // authentication check.
 function sendToDela(dataStr: string, req: express.Request, res: express.Response) {
-  let payload = getPayload(dataStr);
+  let data = dataStr;
 
   // we strip the `/api` part: /api/form/xxx => /form/xxx
   let uri = process.env.DELA_PROXY_URL + req.baseUrl.slice(4);
@@ -56,16 +69,14 @@ function sendToDela(dataStr: string, req: express.Request, res: express.Response
   // in case this is a DKG init request, we must also update the payload.
   const dkgInitRegex = /\/evoting\/services\/dkg\/actors$/;
   if (uri.match(dkgInitRegex)) {
-    const dataStr2 = JSON.stringify({ FormID: req.body.FormID });
-    payload = getPayload(dataStr2);
+    data = JSON.stringify({ FormID: req.body.FormID });
     redirectToDefaultProxy = false;
   }
 
   // in case this is a DKG setup request, we must update the payload.
   const dkgSetupRegex = /\/evoting\/services\/dkg\/actors\/.*$/;
   if (uri.match(dkgSetupRegex)) {
-    const dataStr2 = JSON.stringify({ Action: req.body.Action });
-    payload = getPayload(dataStr2);
+    data = JSON.stringify({ Action: req.body.Action });
 
     // If setup don't redirect to default proxy, if 'computePubshares' then keep
     // default proxy
@@ -85,6 +96,8 @@ function sendToDela(dataStr: string, req: express.Request, res: express.Response
     uri = proxy + req.baseUrl.slice(4);
   }
 
+  const payload = getPayload(data, `${req.method} ${new URL(uri).pathname}`);
+
   console.log('sending payload:', JSON.stringify(payload), 'to', uri);
 
   axios({
@@ -264,8 +277,18 @@ delaRouter.use('/*', (req, res) => {
     }
   }
 
-  // UserID for permission
-  bodyData.UserID = req.session.userId.toString();
+  // the voters register their own key, the ID is the one of the session
+  if (req.baseUrl.match('/api/evoting/forms/(.*)/voterkeys')) {
+    bodyData.VoterID = req.session.userId.toString();
+  }
+
+  // UserID for permission, only on the requests to create, update and delete
+  // a form as the proxy rejects the unknown fields
+  if (req.baseUrl.match('^/api/evoting/forms(/[^/]+)?$')) {
+    bodyData.UserID = req.session.userId.toString();
+  } else {
+    delete bodyData.UserID;
+  }
 
   const dataStr = JSON.stringify(bodyData);
 
@@ -31,6 +31,10 @@ export const getProxiesAddresses = '/api/proxies';
 export const form = (proxy: string, FormID: string) =>
   new URL(`/evoting/forms/${FormID}`, proxy).href;
 export const forms = (proxy: string) => new URL('/evoting/forms', proxy).href;
+export const voters = (proxy: string, FormID: string, offset: number, limit: number) =>
+  new URL(`/evoting/forms/${FormID}/voters?offset=${offset}&limit=${limit}`, proxy).href;
+export const results = (proxy: string, FormID: string, offset: number, limit: number) =>
+  new URL(`/evoting/forms/${FormID}/results?offset=${offset}&limit=${limit}`, proxy).href;
 export const adminlist = (proxy: string) => new URL('/evoting/adminlist', proxy).href;
 
 // get the default proxy address
@@ -11,7 +11,8 @@ const useFillFormInfo = (formData: FormInfo) => {
   const [chunksPerBallot, setChunksPerBallot] = useState<number>(0);
   const [ballotSize, setBallotSize] = useState<number>(0);
   const [configObj, setConfigObj] = useState(null);
-  const [voters, setVoters] = useState<string[]>(null);
+  const [voterCount, setVoterCount] = useState<number>(null);
+  const [resultsCount, setResultsCount] = useState<number>(0);
   const [isResultSet, setIsResultSet] = useState<boolean>(false);
 
   useEffect(() => {
@@ -22,15 +23,11 @@ const useFillFormInfo = (formData: FormInfo) => {
     setStatus(formData.Status);
     setPubKey(formData.Pubkey);
     setRoster(formData.Roster);
-    setResult(formData.Result);
     setChunksPerBallot(formData.ChunksPerBallot);
     setBallotSize(formData.BallotSize);
     setConfigObj(formData.Configuration);
-    setVoters(formData.Voters);
-
-    if (formData.Result.length > 0) {
-      setIsResultSet(true);
-    }
+    setVoterCount(formData.VoterCount);
+    setResultsCount(formData.ResultsCount);
   }, [formData]);
 
   return {
@@ -46,7 +43,8 @@ const useFillFormInfo = (formData: FormInfo) => {
     configObj,
     isResultSet,
     setIsResultSet,
-    voters,
+    voterCount,
+    resultsCount,
   };
 };
 
@@ -35,7 +35,8 @@ const useForm = (formID: ID) => {
     configObj,
     isResultSet,
     setIsResultSet,
-    voters,
+    voterCount,
+    resultsCount,
   } = useFillFormInfo(data);
 
   return {
@@ -52,7 +53,8 @@ const useForm = (formID: ID) => {
     configObj,
     isResultSet,
     setIsResultSet,
-    voters,
+    voterCount,
+    resultsCount,
     error,
   };
 };
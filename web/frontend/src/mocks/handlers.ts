@@ -120,6 +120,35 @@ export const handlers = [
     );
   }),
 
+  rest.get(new URL('/evoting/forms/:FormID/voters', defaultProxy).href, async (req, res, ctx) => {
+    const { FormID } = req.params;
+    const Total = mockForms.get(FormID as ID).VoterCount;
+    const Offset = Number(req.url.searchParams.get('offset') ?? 0);
+    const Limit = Number(req.url.searchParams.get('limit') ?? 100);
+
+    await new Promise((r) => setTimeout(r, RESPONSE_TIME));
+
+    const VoterIDs = [];
+    for (let i = Offset; i < Math.min(Offset + Limit, Total); i++) {
+      VoterIDs.push('userID' + (i + 1));
+    }
+
+    return res(ctx.status(200), ctx.json({ FormID, Offset, Limit, Total, VoterIDs }));
+  }),
+
+  rest.get(new URL('/evoting/forms/:FormID/results', defaultProxy).href, async (req, res, ctx) => {
+    const { FormID } = req.params;
+    const Total = mockForms.get(FormID as ID).ResultsCount;
+    const Offset = Number(req.url.searchParams.get('offset') ?? 0);
+    const Limit = Number(req.url.searchParams.get('limit') ?? 100);
+
+    await new Promise((r) => setTimeout(r, RESPONSE_TIME));
+
+    const Ballots = mockResults.get(FormID as ID).slice(Offset, Math.min(Offset + Limit, Total));
+
+    return res(ctx.status(200), ctx.json({ FormID, Offset, Limit, Total, Ballots }));
+  }),
+
   rest.post(endpoints.newForm, async (req, res, ctx) => {
     await new Promise((r) => setTimeout(r, RESPONSE_TIME));
 
@@ -136,12 +165,9 @@ export const handlers = [
 
     await new Promise((r) => setTimeout(r, RESPONSE_TIME));
 
-    const Voters = mockForms.get(FormID as string).Voters;
-    Voters.push('userID' + (Voters.length + 1));
-
     mockForms.set(FormID as string, {
       ...mockForms.get(FormID as string),
-      Voters,
+      VoterCount: mockForms.get(FormID as string).VoterCount + 1,
     });
 
     const BallotID = uid();
@@ -159,7 +185,7 @@ export const handlers = [
     const body = req.body as EditFormBody;
     const { FormID } = req.params;
     let status = Status.Initial;
-    const Result = [];
+    let ResultsCount = 0;
 
     await new Promise((r) => setTimeout(r, RESPONSE_TIME));
 
@@ -176,7 +202,7 @@ export const handlers = [
         break;
       case Action.CombineShares:
         status = Status.ResultAvailable;
-        mockResults.get(FormID as string).forEach((result) => Result.push(result));
+        ResultsCount = mockResults.get(FormID as string).length;
         break;
       case Action.Cancel:
         status = Status.Canceled;
@@ -190,7 +216,7 @@ export const handlers = [
         mockForms.set(FormID as string, {
           ...mockForms.get(FormID as string),
           Status: status,
-          Result,
+          ResultsCount,
         }),
       CHANGE_STATUS_TIMER
     );
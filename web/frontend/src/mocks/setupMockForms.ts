@@ -49,12 +49,12 @@ const setupMockForm = () => {
     FormID: formID1,
     Status: Status.Initial,
     Pubkey: 'XL4V6EMIICW',
-    Result: [],
     Roster: mockRoster,
     Configuration: unmarshalConfig(mockForm1),
     BallotSize: 174,
     ChunksPerBallot: 6,
-    Voters: [],
+    ResultsCount: 0,
+    VoterCount: 0,
   });
 
   mockResults.set(formID1, [mockFormResult11, mockFormResult12]);
@@ -65,12 +65,12 @@ const setupMockForm = () => {
     FormID: formID2,
     Status: Status.ResultAvailable,
     Pubkey: 'XL4V6EMIICW',
-    Result: [mockFormResult21, mockFormResult22, mockFormResult23],
     Roster: mockRoster,
     Configuration: unmarshalConfig(mockForm2),
     BallotSize: 174,
     ChunksPerBallot: 6,
-    Voters: ['aefae', 'ozeivn', 'ovaeop'],
+    ResultsCount: 3,
+    VoterCount: 3,
   });
 
   mockResults.set(formID2, [mockFormResult21, mockFormResult22, mockFormResult23]);
@@ -80,12 +80,12 @@ const setupMockForm = () => {
     FormID: formID3,
     Status: Status.Open,
     Pubkey: 'XL4V6EMIICW',
-    Result: [],
     Roster: mockRoster,
     Configuration: unmarshalConfig(mockForm3),
     BallotSize: 291,
     ChunksPerBallot: 11,
-    Voters: [],
+    ResultsCount: 0,
+    VoterCount: 0,
   });
 
   mockResults.set(formID3, [mockFormResult31, mockFormResult32, mockFormResult33]);
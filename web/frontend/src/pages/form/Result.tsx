@@ -1,10 +1,11 @@
 import { FC, useEffect, useState } from 'react';
 import PropTypes from 'prop-types';
 import { useTranslation } from 'react-i18next';
-import { RankResults, SelectResults, TextResults } from 'types/form';
+import { RankResults, Results, SelectResults, TextResults } from 'types/form';
 import { ID } from 'types/configuration';
 import { useParams } from 'react-router-dom';
 import useForm from 'components/utils/useForm';
+import useGetResults from './components/utils/useGetResults';
 import { useConfigurationOnly } from 'components/utils/useConfiguration';
 import Loading from 'pages/Loading';
 import ResultExplanation from './components/ResultExplanation';
@@ -20,9 +21,20 @@ const FormResult: FC = () => {
   const { t } = useTranslation();
   const { formId } = useParams();
 
-  const { loading, result, configObj } = useForm(formId);
+  const { loading, configObj } = useForm(formId);
   const configuration = useConfigurationOnly(configObj);
 
+  const [result, setResult] = useState<Results[]>(null);
+  const [, setIsResultSet] = useState(false);
+  const [, setError] = useState(null);
+  const { getResults } = useGetResults();
+
+  // The decrypted ballots are not in the form, they are fetched by pages
+  useEffect(() => {
+    getResults(formId, setError, setResult, setIsResultSet).then();
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [formId]);
+
   const [rankResult, setRankResult] = useState<RankResults>(null);
   const [selectResult, setSelectResult] = useState<SelectResults>(null);
   const [textResult, setTextResult] = useState<TextResults>(null);
@@ -84,7 +96,7 @@ const FormResult: FC = () => {
   }, [result]);
   return (
     <div className="w-[60rem] font-sans px-4 pt-8 pb-4">
-      {!loading ? (
+      {!loading && result !== null ? (
         <div>
           <div className="flex items-center">
             <h1 className="text-2xl font-bold leading-7 text-gray-900 sm:text-3xl sm:truncate">
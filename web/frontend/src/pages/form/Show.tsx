@@ -31,7 +31,7 @@ const FormShow: FC = () => {
     setResult,
     configObj,
     setIsResultSet,
-    voters,
+    voterCount,
     error,
   } = useForm(formId);
 
@@ -237,9 +237,9 @@ const FormShow: FC = () => {
           <div className="pt-2 break-all">Form ID : {formId}</div>
           {status >= Status.Open &&
             status <= Status.Canceled &&
-            voters !== null &&
-            voters !== undefined && (
-              <div className="break-all">{t('numVotes', { num: voters.length })}</div>
+            voterCount !== null &&
+            voterCount !== undefined && (
+              <div className="break-all">{t('numVotes', { num: voterCount })}</div>
             )}
           <div className="py-6 pl-2">
             <div className="font-bold uppercase text-lg text-gray-700">{t('status')}</div>
@@ -278,11 +278,11 @@ const FormShow: FC = () => {
               )}
             </div>
           </div>
-          {voters !== null && voters !== undefined && voters.length > 0 && (
+          {voterCount > 0 && (
             <div className="py-4 pl-2 pb-8">
               <div className="font-bold uppercase text-lg text-gray-700 pb-2">{t('userID')}</div>
               <div className="px-2">
-                <UserIDTable userIDs={voters} />
+                <UserIDTable formID={formID} voterCount={voterCount} />
               </div>
             </div>
           )}
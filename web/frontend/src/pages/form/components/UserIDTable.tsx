@@ -1,26 +1,43 @@
-import { FC, useEffect, useState } from 'react';
+import { FC, useContext, useEffect, useState } from 'react';
 import { useTranslation } from 'react-i18next';
+import { ID } from 'types/configuration';
+import * as endpoints from 'components/utils/Endpoints';
+import { ProxyContext } from 'index';
 
 const USERS_PER_PAGE = 5;
 
 type UserIDTableProps = {
-  userIDs: string[];
+  formID: ID;
+  // number of users who cast a ballot
+  voterCount: number;
 };
 
-const UserIDTable: FC<UserIDTableProps> = ({ userIDs }) => {
+// UserIDTable shows the users who cast a ballot. Each page is fetched from the
+// proxy, the whole list is never loaded.
+const UserIDTable: FC<UserIDTableProps> = ({ formID, voterCount }) => {
   const { t } = useTranslation();
+  const pctx = useContext(ProxyContext);
 
-  const [userToDisplay, setUserToDisplay] = useState([]);
+  const [userToDisplay, setUserToDisplay] = useState<string[]>([]);
   const [pageIndex, setPageIndex] = useState(0);
 
-  const partitionArray = (array: string[], size: number) =>
-    array.map((_v, i) => (i % size === 0 ? array.slice(i, i + size) : null)).filter((v) => v);
+  const pageCount = Math.ceil(voterCount / USERS_PER_PAGE);
 
   useEffect(() => {
-    if (userIDs.length) {
-      setUserToDisplay(partitionArray(userIDs, USERS_PER_PAGE)[pageIndex]);
+    if (!voterCount) {
+      return;
     }
-  }, [userIDs, pageIndex]);
+
+    fetch(endpoints.voters(pctx.getProxy(), formID, pageIndex * USERS_PER_PAGE, USERS_PER_PAGE))
+      .then((response) => {
+        if (!response.ok) {
+          throw Error(response.statusText);
+        }
+        return response.json();
+      })
+      .then((data) => setUserToDisplay(data.VoterIDs))
+      .catch(console.error);
+  }, [pctx, formID, voterCount, pageIndex]);
 
   const handlePrevious = (): void => {
     if (pageIndex > 0) {
@@ -29,7 +46,7 @@ const UserIDTable: FC<UserIDTableProps> = ({ userIDs }) => {
   };
 
   const handleNext = (): void => {
-    if (partitionArray(userIDs, USERS_PER_PAGE).length > pageIndex + 1) {
+    if (pageCount > pageIndex + 1) {
       setPageIndex(pageIndex + 1);
     }
   };
@@ -63,8 +80,8 @@ const UserIDTable: FC<UserIDTableProps> = ({ userIDs }) => {
           <div className="hidden sm:block text-sm text-gray-700">
             {t('showingNOverMOfXResults', {
               n: pageIndex + 1,
-              m: partitionArray(userIDs, USERS_PER_PAGE).length,
-              x: userIDs.length,
+              m: pageCount,
+              x: voterCount,
             })}
           </div>
           <div className="flex-1 flex justify-between sm:justify-end">
@@ -75,7 +92,7 @@ const UserIDTable: FC<UserIDTableProps> = ({ userIDs }) => {
               {t('previous')}
             </button>
             <button
-              disabled={partitionArray(userIDs, USERS_PER_PAGE).length <= pageIndex + 1}
+              disabled={pageCount <= pageIndex + 1}
               onClick={handleNext}
               className="ml-3 relative inline-flex items-center px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50">
               {t('next')}
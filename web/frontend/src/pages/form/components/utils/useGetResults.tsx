@@ -4,9 +4,13 @@ import * as endpoints from 'components/utils/Endpoints';
 import { useContext } from 'react';
 import { ProxyContext } from 'index';
 
+// number of decrypted ballots fetched per request
+const RESULTS_PAGE_SIZE = 1000;
+
 const useGetResults = () => {
   const pctx = useContext(ProxyContext);
 
+  // getResults fetches all the pages of the decrypted ballots of the form
   async function getResults(
     formID: ID,
     setError: React.Dispatch<any>,
@@ -18,15 +22,30 @@ const useGetResults = () => {
     };
 
     try {
-      const response = await fetch(endpoints.form(pctx.getProxy(), formID), request);
-
-      if (!response.ok) {
-        throw Error(response.statusText);
-      } else {
-        let data = await response.json();
-        setResult(data.Result);
-        setIsResultSet(true);
-      }
+      const results: Results[] = [];
+      let total = 0;
+
+      do {
+        const response = await fetch(
+          endpoints.results(pctx.getProxy(), formID, results.length, RESULTS_PAGE_SIZE),
+          request
+        );
+
+        if (!response.ok) {
+          throw Error(response.statusText);
+        }
+
+        const data = await response.json();
+        if (!data.Ballots || data.Ballots.length === 0) {
+          break;
+        }
+
+        results.push(...data.Ballots);
+        total = data.Total;
+      } while (results.length < total);
+
+      setResult(results);
+      setIsResultSet(true);
     } catch (error) {
       setError(error);
     }
@@ -49,12 +49,12 @@ interface FormInfo {
   FormID: ID;
   Status: Status;
   Pubkey: string;
-  Result: any;
   Roster: string[];
   ChunksPerBallot: number;
   BallotSize: number;
+  ResultsCount: number;
   Configuration: any;
-  Voters: string[];
+  VoterCount: number;
 }
 
 interface LightFormInfo {
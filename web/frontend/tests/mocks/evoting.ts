@@ -77,6 +77,17 @@ export async function mockFormsFormID(page: Page, formStatus: number) {
   });
 }
 
+export async function mockFormsResults(page: Page) {
+  const url = `${process.env.DELA_PROXY_URL}/evoting/forms/${FORMID}/results*`;
+  // clear current mock
+  await page.unroute(url);
+  await page.route(url, async (route) => {
+    await route.fulfill({
+      path: './tests/json/evoting/results/combined.json',
+    });
+  });
+}
+
 export async function mockDKGActors(page: Page, dkgActorsStatus: number, initialized: boolean) {
   for (const worker of [Worker0, Worker1, Worker2, Worker3]) {
     await page.route(`${worker.Proxy}/evoting/services/dkg/actors/${FORMID}`, async (route) => {
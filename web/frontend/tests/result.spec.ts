@@ -2,8 +2,9 @@ import { expect, test } from '@playwright/test';
 import { default as i18n } from 'i18next';
 import { assertHasFooter, assertHasNavBar, initI18n, setUp } from './shared';
 import { FORMID } from './mocks/shared';
-import { mockFormsFormID } from './mocks/evoting';
+import { mockFormsFormID, mockFormsResults } from './mocks/evoting';
 import Form from './json/evoting/forms/combined.json';
+import Results from './json/evoting/results/combined.json';
 
 initI18n();
 
@@ -11,6 +12,7 @@ test.beforeEach(async ({ page }) => {
   // TODO integrate localisation
   i18n.changeLanguage('en'); // force 'en' for these tests
   await mockFormsFormID(page, 5); // mock clear election result per default
+  await mockFormsResults(page);
   await setUp(page, `/forms/${FORMID}/result`);
 });
 
@@ -29,7 +31,7 @@ test('Assert footer is present', async ({ page }) => {
 test('Assert form titles are displayed correctly', async ({ page }) => {
   await expect(page.getByText(i18n.t('navBarResult'))).toBeVisible();
   await expect(
-    page.getByText(i18n.t('totalNumberOfVotes', { votes: Form.Result.length }))
+    page.getByText(i18n.t('totalNumberOfVotes', { votes: Results.Ballots.length }))
   ).toBeVisible();
   const content = await page.getByTestId('content');
   await expect(content.locator('xpath=./div/div/div[2]/h3')).toContainText(